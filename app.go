@@ -25,6 +25,26 @@ type App struct {
 	Ports       []PortProto  `json:"ports,omitempty"`
 	Source      string       `json:"source"`
 	EPAFeatures []EPAFeature `json:"epafeatures,omitempty"`
+	Config      *AppConfig   `json:"config,omitempty"`
+	Probes      *AppProbes   `json:"probes,omitempty"`
+	// RestartPolicy is always (the default), on_failure or never. Only
+	// always is supported in the Kubernetes modes.
+	RestartPolicy string `json:"restart_policy,omitempty"`
+	// DiskSize is the size in GB of the persistent volume a VM app's image is
+	// imported to in Kubernetes mode. If it is 0, the image is run as an
+	// ephemeral container disk.
+	DiskSize int `json:"disk_size,omitempty"`
+
+	// Digest is the digest of the image, as sha256:<hex>. The node rejects an
+	// image that does not match it.
+	Digest string `json:"digest,omitempty"`
+	// Signature is a detached signature of the image by the publisher.
+	Signature []byte `json:"signature,omitempty"`
+	// PublisherID is the trusted publisher that signed the image.
+	PublisherID string `json:"publisher_id,omitempty"`
+	// PublisherKey is the public key of the publisher the signature was
+	// verified with, sent to the node along with the signature.
+	PublisherKey string `json:"publisher_key,omitempty"`
 }
 
 // PortProto is a port and protocol combination. It is typically used to represent the ports and protocols that an
@@ -103,13 +123,74 @@ func (app *App) Validate() error { // nolint: gocyclo
 	if _, err := url.ParseRequestURI(app.Source); err != nil {
 		return errors.New("source cannot be parsed as a URI")
 	}
+	if err := app.validateEPAFeatures(); err != nil {
+		return err
+	}
+	if app.Config != nil {
+		if err := app.Config.Validate(); err != nil {
+			return fmt.Errorf("config: %v", err)
+		}
+	}
+	if app.Probes != nil {
+		if err := app.Probes.Validate(); err != nil {
+			return err
+		}
+	}
+	switch app.RestartPolicy {
+	case "", RestartAlways, RestartOnFailure, RestartNever:
+	default:
+		return fmt.Errorf("restart_policy must be one of [%s, %s, %s]",
+			RestartAlways, RestartOnFailure, RestartNever)
+	}
+	if app.DiskSize < 0 {
+		return errors.New("disk_size cannot be negative")
+	}
+	if app.DiskSize > 0 && app.Type != "vm" {
+		return errors.New("disk_size requires a vm app")
+	}
+	if app.Digest != "" && !digestRegexp.MatchString(app.Digest) {
+		return errors.New("digest must be sha256:<64 hex digits>")
+	}
+	if len(app.Signature) > 0 && app.Digest == "" {
+		return errors.New("signature requires a digest")
+	}
+	if len(app.Signature) > 0 && app.PublisherID == "" {
+		return errors.New("signature requires a publisher_id")
+	}
+	if app.PublisherID != "" && !uuid.IsValid(app.PublisherID) {
+		return errors.New("publisher_id not a valid uuid")
+	}
+	if app.PublisherID != "" && len(app.Signature) == 0 {
+		return errors.New("publisher_id requires a signature")
+	}
+
+	return nil
+}
 
+// ValidateOrchestration validates the app for an orchestration mode. In the
+// Kubernetes modes apps run as deployments, which always restart their pods,
+// and VM apps as virtual machines, which have no probes or device plugins.
+func (app *App) ValidateOrchestration(mode OrchestrationMode) error {
+	if mode == OrchestrationModeNative {
+		return nil
+	}
+	if app.RestartPolicy != "" && app.RestartPolicy != RestartAlways {
+		return fmt.Errorf("restart_policy %s is not supported in kubernetes mode", app.RestartPolicy)
+	}
+	if app.Type == "vm" && app.Probes != nil {
+		return errors.New("probes are not supported for vm apps in kubernetes mode")
+	}
+	if app.Type == "vm" && len(app.EPAResourcesToK8s()) > 0 {
+		return errors.New("epafeatures resources are not supported for vm apps in kubernetes mode")
+	}
 	return nil
 }
 
 // FilterFields returns the filterable fields for this model.
 func (*App) FilterFields() []string {
-	return []string{}
+	return []string{
+		"publisher_id",
+	}
 }
 
 func (app *App) String() string {
@@ -125,6 +206,7 @@ App[
     Ports: %s
     Source: %s
     EPAFeatures: %s
+    Digest: %s
 ]`),
 		app.ID,
 		app.Name,
@@ -135,7 +217,8 @@ App[
 		app.Memory,
 		app.Ports,
 		app.Source,
-		app.EPAFeatures)
+		app.EPAFeatures,
+		app.Digest)
 }
 
 // EPAValidate returns error if provided nodeFeatures do not fulfill app.EPAFeatures
@@ -0,0 +1,194 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+)
+
+// MaxConfigFileSize is the maximum size (in bytes) of the content of a config
+// file of an app.
+const MaxConfigFileSize = 16 * 1024
+
+var (
+	envNameRegexp    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	secretNameRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$`)
+	secretKeyRegexp  = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+)
+
+// AppConfig is the runtime configuration of an app. The configuration of a
+// node app overrides the one of its app.
+type AppConfig struct {
+	Env []EnvVar `json:"env,omitempty"`
+	// Command replaces the entrypoint of the image.
+	Command []string `json:"command,omitempty"`
+	// Args replaces the arguments of the entrypoint of the image.
+	Args        []string     `json:"args,omitempty"`
+	ConfigFiles []ConfigFile `json:"config_files,omitempty"`
+	Secrets     []SecretRef  `json:"secrets,omitempty"`
+}
+
+// EnvVar is an environment variable set in the app.
+type EnvVar struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// ConfigFile is a read-only file mounted in the app at Path.
+type ConfigFile struct {
+	Path    string `json:"path"`
+	Content string `json:"content"`
+}
+
+// SecretRef references the Key of the secret Name, which is managed on the
+// cluster or the node rather than the controller. The secret is exposed to the
+// app either as the environment variable Env or as a file mounted at Path.
+type SecretRef struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+	Env  string `json:"env,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+// Validate validates the configuration.
+func (c *AppConfig) Validate() error { // nolint: gocyclo
+	envs := make(map[string]bool)
+	addEnv := func(name string) error {
+		if !envNameRegexp.MatchString(name) {
+			return fmt.Errorf("env name %q is invalid", name)
+		}
+		if envs[name] {
+			return fmt.Errorf("env %s is set more than once", name)
+		}
+		envs[name] = true
+		return nil
+	}
+	paths := make(map[string]bool)
+	addPath := func(p string) error {
+		if !path.IsAbs(p) || path.Clean(p) != p || p == "/" {
+			return fmt.Errorf("path %q must be an absolute file path", p)
+		}
+		if paths[p] {
+			return fmt.Errorf("path %s is mounted more than once", p)
+		}
+		paths[p] = true
+		return nil
+	}
+
+	for _, env := range c.Env {
+		if err := addEnv(env.Name); err != nil {
+			return err
+		}
+	}
+	for _, file := range c.ConfigFiles {
+		if err := addPath(file.Path); err != nil {
+			return err
+		}
+		if len(file.Content) > MaxConfigFileSize {
+			return fmt.Errorf("config file %s is larger than %d bytes", file.Path, MaxConfigFileSize)
+		}
+	}
+	for _, secret := range c.Secrets {
+		if !secretNameRegexp.MatchString(secret.Name) {
+			return fmt.Errorf("secret name %q is invalid", secret.Name)
+		}
+		if !secretKeyRegexp.MatchString(secret.Key) {
+			return fmt.Errorf("secret key %q is invalid", secret.Key)
+		}
+		switch {
+		case secret.Env != "" && secret.Path != "":
+			return fmt.Errorf("secret %s/%s must set either env or path, not both", secret.Name, secret.Key)
+		case secret.Env != "":
+			if err := addEnv(secret.Env); err != nil {
+				return err
+			}
+		case secret.Path != "":
+			if err := addPath(secret.Path); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("secret %s/%s must set env or path", secret.Name, secret.Key)
+		}
+	}
+
+	return nil
+}
+
+// Merge returns the configuration with the overrides applied. Environment
+// variables, config files and secrets are overridden by name or target, the
+// command and arguments as a whole.
+func (c *AppConfig) Merge(overrides *AppConfig) *AppConfig {
+	if c == nil && overrides == nil {
+		return nil
+	}
+	var base AppConfig
+	if c != nil {
+		base = *c
+	}
+	if overrides == nil {
+		return &base
+	}
+	merged := base
+
+	// Secrets exposed as environment variables override those too, and
+	// conversely
+	envs := make(map[string]bool)
+	paths := make(map[string]bool)
+	for _, env := range overrides.Env {
+		envs[env.Name] = true
+	}
+	for _, file := range overrides.ConfigFiles {
+		paths[file.Path] = true
+	}
+	for _, secret := range overrides.Secrets {
+		if secret.Env != "" {
+			envs[secret.Env] = true
+		}
+		if secret.Path != "" {
+			paths[secret.Path] = true
+		}
+	}
+
+	merged.Env = nil
+	for _, env := range base.Env {
+		if !envs[env.Name] {
+			merged.Env = append(merged.Env, env)
+		}
+	}
+	merged.Env = append(merged.Env, overrides.Env...)
+
+	merged.ConfigFiles = nil
+	for _, file := range base.ConfigFiles {
+		if !paths[file.Path] {
+			merged.ConfigFiles = append(merged.ConfigFiles, file)
+		}
+	}
+	merged.ConfigFiles = append(merged.ConfigFiles, overrides.ConfigFiles...)
+
+	merged.Secrets = nil
+	for _, secret := range base.Secrets {
+		if !envs[secret.Env] && !paths[secret.Path] {
+			merged.Secrets = append(merged.Secrets, secret)
+		}
+	}
+	merged.Secrets = append(merged.Secrets, overrides.Secrets...)
+
+	if len(overrides.Command) > 0 {
+		merged.Command = overrides.Command
+	}
+	if len(overrides.Args) > 0 {
+		merged.Args = overrides.Args
+	}
+	return &merged
+}
+
+// Configured returns a copy of the app whose configuration is overridden by
+// overrides, as deployed to a node.
+func (app *App) Configured(overrides *AppConfig) *App {
+	configured := *app
+	configured.Config = app.Config.Merge(overrides)
+	return &configured
+}
@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("App config", func() {
+	var (
+		config *cce.AppConfig
+	)
+
+	BeforeEach(func() {
+		config = &cce.AppConfig{
+			Env: []cce.EnvVar{
+				{Name: "LOG_LEVEL", Value: "info"},
+				{Name: "MODE", Value: "edge"},
+			},
+			Command: []string{"/bin/server"},
+			Args:    []string{"--config", "/etc/app/app.conf"},
+			ConfigFiles: []cce.ConfigFile{
+				{Path: "/etc/app/app.conf", Content: "port = 80"},
+			},
+			Secrets: []cce.SecretRef{
+				{Name: "app-credentials", Key: "token", Env: "TOKEN"},
+				{Name: "app-credentials", Key: "tls.key", Path: "/etc/app/tls.key"},
+			},
+		}
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for a valid config", func() {
+			Expect(config.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if an env name is invalid", func() {
+			config.Env[0].Name = "LOG-LEVEL"
+			Expect(config.Validate()).To(MatchError(`env name "LOG-LEVEL" is invalid`))
+		})
+
+		It("Should return an error if an env is set twice", func() {
+			config.Secrets[0].Env = "MODE"
+			Expect(config.Validate()).To(MatchError("env MODE is set more than once"))
+		})
+
+		It("Should return an error if a config file path is relative", func() {
+			config.ConfigFiles[0].Path = "etc/app.conf"
+			Expect(config.Validate()).To(MatchError(`path "etc/app.conf" must be an absolute file path`))
+		})
+
+		It("Should return an error if a config file path is not clean", func() {
+			config.ConfigFiles[0].Path = "/etc/app/../app.conf"
+			Expect(config.Validate()).To(MatchError(
+				`path "/etc/app/../app.conf" must be an absolute file path`))
+		})
+
+		It("Should return an error if a path is mounted twice", func() {
+			config.Secrets[1].Path = "/etc/app/app.conf"
+			Expect(config.Validate()).To(MatchError("path /etc/app/app.conf is mounted more than once"))
+		})
+
+		It("Should return an error if a config file is too large", func() {
+			config.ConfigFiles[0].Content = strings.Repeat("a", cce.MaxConfigFileSize+1)
+			Expect(config.Validate()).To(MatchError("config file /etc/app/app.conf is larger than 16384 bytes"))
+		})
+
+		It("Should return an error if a secret name is invalid", func() {
+			config.Secrets[0].Name = "App_Credentials"
+			Expect(config.Validate()).To(MatchError(`secret name "App_Credentials" is invalid`))
+		})
+
+		It("Should return an error if a secret key is invalid", func() {
+			config.Secrets[0].Key = "a/b"
+			Expect(config.Validate()).To(MatchError(`secret key "a/b" is invalid`))
+		})
+
+		It("Should return an error if a secret has no target", func() {
+			config.Secrets[0].Env = ""
+			Expect(config.Validate()).To(MatchError("secret app-credentials/token must set env or path"))
+		})
+
+		It("Should return an error if a secret has two targets", func() {
+			config.Secrets[0].Path = "/etc/app/token"
+			Expect(config.Validate()).To(MatchError(
+				"secret app-credentials/token must set either env or path, not both"))
+		})
+	})
+
+	Describe("Merge", func() {
+		It("Should return the config if there are no overrides", func() {
+			Expect(config.Merge(nil)).To(Equal(config))
+		})
+
+		It("Should return the overrides if there is no config", func() {
+			config = nil
+			overrides := &cce.AppConfig{Args: []string{"--verbose"}}
+			Expect(config.Merge(overrides)).To(Equal(overrides))
+			Expect(config.Merge(nil)).To(BeNil())
+		})
+
+		It("Should override the config by name and target", func() {
+			merged := config.Merge(&cce.AppConfig{
+				Env: []cce.EnvVar{
+					{Name: "LOG_LEVEL", Value: "debug"},
+					{Name: "TOKEN", Value: "test"},
+				},
+				Args: []string{"--verbose"},
+				Secrets: []cce.SecretRef{
+					{Name: "node-config", Key: "app.conf", Path: "/etc/app/app.conf"},
+				},
+			})
+
+			Expect(merged).To(Equal(&cce.AppConfig{
+				Env: []cce.EnvVar{
+					{Name: "MODE", Value: "edge"},
+					{Name: "LOG_LEVEL", Value: "debug"},
+					{Name: "TOKEN", Value: "test"},
+				},
+				Command: []string{"/bin/server"},
+				Args:    []string{"--verbose"},
+				Secrets: []cce.SecretRef{
+					{Name: "app-credentials", Key: "tls.key", Path: "/etc/app/tls.key"},
+					{Name: "node-config", Key: "app.conf", Path: "/etc/app/app.conf"},
+				},
+			}))
+			Expect(merged.Validate()).To(Succeed())
+
+			By("Leaving the config as is")
+			Expect(config.Env).To(HaveLen(2))
+			Expect(config.ConfigFiles).To(HaveLen(1))
+		})
+	})
+
+	Describe("Configured", func() {
+		It("Should return a copy of the app with the overrides applied", func() {
+			app := &cce.App{ID: "efcece3c-6b58-4993-8d45-bde6239d4baa", Config: config}
+			configured := app.Configured(&cce.AppConfig{Command: []string{"/bin/sh"}})
+
+			Expect(configured.ID).To(Equal(app.ID))
+			Expect(configured.Config.Command).To(Equal([]string{"/bin/sh"}))
+			Expect(app.Config.Command).To(Equal([]string{"/bin/server"}))
+		})
+	})
+})
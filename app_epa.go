@@ -0,0 +1,122 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"fmt"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// Namespaces of the keys of the EPA features of an app. nfd: features are
+// required of the node the app is deployed to. The others are requested of
+// Kubernetes for the pod of the app:
+//
+//	resource:intel.com/intel_fec_5g=1 requests a device-plugin resource
+//	hugepages-1Gi=2Gi requests hugepages, mounted at /hugepages
+//	cpu:pinned=true pins the cores of the app
+//	network:sriov-net=net1 attaches the pod to a Multus network as net1
+//
+// Features with other keys are ignored.
+const (
+	EPAPrefixNFD       = "nfd:"
+	EPAPrefixResource  = "resource:"
+	EPAPrefixHugepages = coreV1.ResourceHugePagesPrefix
+	EPAPrefixCPU       = "cpu:"
+	EPAPrefixNetwork   = "network:"
+)
+
+// EPAKeyCPUPinned is the key of the EPA feature pinning the cores of an app.
+const EPAKeyCPUPinned = EPAPrefixCPU + "pinned"
+
+// validateEPAFeatures validates the EPA features requested of Kubernetes.
+func (app *App) validateEPAFeatures() error {
+	for _, f := range app.EPAFeatures {
+		switch {
+		case strings.HasPrefix(f.Key, EPAPrefixResource):
+			name := strings.TrimPrefix(f.Key, EPAPrefixResource)
+			if !strings.Contains(name, "/") || len(validation.IsQualifiedName(name)) > 0 {
+				return fmt.Errorf("EPA feature [%s] must name a device-plugin resource as <vendor>/<name>", f.Key)
+			}
+			q, err := resource.ParseQuantity(f.Value)
+			if err != nil || q.Sign() <= 0 || q.MilliValue()%1000 != 0 {
+				return fmt.Errorf("EPA feature [%s] value must be a positive integer", f.Key)
+			}
+		case strings.HasPrefix(f.Key, EPAPrefixHugepages):
+			if _, err := resource.ParseQuantity(strings.TrimPrefix(f.Key, EPAPrefixHugepages)); err != nil {
+				return fmt.Errorf("EPA feature [%s] must name a page size, e.g. hugepages-2Mi", f.Key)
+			}
+			q, err := resource.ParseQuantity(f.Value)
+			if err != nil || q.Sign() <= 0 {
+				return fmt.Errorf("EPA feature [%s] value must be a positive quantity, e.g. 1Gi", f.Key)
+			}
+		case f.Key == EPAKeyCPUPinned:
+			if f.Value != "true" && f.Value != "false" {
+				return fmt.Errorf("EPA feature [%s] value must be true or false", f.Key)
+			}
+		case strings.HasPrefix(f.Key, EPAPrefixCPU):
+			return fmt.Errorf("EPA feature [%s] is unknown", f.Key)
+		case strings.HasPrefix(f.Key, EPAPrefixNetwork):
+			if !networkNameRegexp.MatchString(strings.TrimPrefix(f.Key, EPAPrefixNetwork)) {
+				return fmt.Errorf("EPA feature [%s] must name a network as [<namespace>/]<name>", f.Key)
+			}
+			if !interfaceNameRegexp.MatchString(f.Value) {
+				return fmt.Errorf("EPA feature [%s] value must be an interface name", f.Key)
+			}
+		}
+	}
+	return nil
+}
+
+// EPAResourcesToK8s returns the device-plugin resources and hugepages
+// requested by the EPA features of the app.
+func (app *App) EPAResourcesToK8s() coreV1.ResourceList {
+	resources := coreV1.ResourceList{}
+	for _, f := range app.EPAFeatures {
+		var name string
+		switch {
+		case strings.HasPrefix(f.Key, EPAPrefixResource):
+			name = strings.TrimPrefix(f.Key, EPAPrefixResource)
+		case strings.HasPrefix(f.Key, EPAPrefixHugepages):
+			name = f.Key
+		default:
+			continue
+		}
+		if q, err := resource.ParseQuantity(f.Value); err == nil {
+			resources[coreV1.ResourceName(name)] = q
+		}
+	}
+	if len(resources) == 0 {
+		return nil
+	}
+	return resources
+}
+
+// EPAPinnedCPUs reports whether the EPA features of the app pin its cores.
+func (app *App) EPAPinnedCPUs() bool {
+	for _, f := range app.EPAFeatures {
+		if f.Key == EPAKeyCPUPinned {
+			return f.Value == "true"
+		}
+	}
+	return false
+}
+
+// EPANetworks returns the Multus networks the EPA features of the app attach
+// it to.
+func (app *App) EPANetworks() []NetworkAttachment {
+	var networks []NetworkAttachment
+	for _, f := range app.EPAFeatures {
+		if strings.HasPrefix(f.Key, EPAPrefixNetwork) {
+			networks = append(networks, NetworkAttachment{
+				Name:      strings.TrimPrefix(f.Key, EPAPrefixNetwork),
+				Interface: f.Value,
+			})
+		}
+	}
+	return networks
+}
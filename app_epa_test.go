@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+var _ = Describe("App EPA features", func() {
+	var (
+		app *cce.App
+	)
+
+	BeforeEach(func() {
+		app = &cce.App{
+			ID:      "f8c4e3a0-6c6d-4a3b-8e2b-2d1f0a7b9c5e",
+			Type:    "container",
+			Name:    "bbdev",
+			Vendor:  "vendor",
+			Version: "1.0",
+			Cores:   4,
+			Memory:  512,
+			Source:  "http://www.test.com/bbdev.tar.gz",
+			EPAFeatures: []cce.EPAFeature{
+				{Key: "nfd:cpu-cpuid.AVX512F", Value: "true"},
+				{Key: "resource:intel.com/intel_fec_5g", Value: "1"},
+				{Key: "hugepages-1Gi", Value: "2Gi"},
+				{Key: "cpu:pinned", Value: "true"},
+				{Key: "network:sriov-net", Value: "net1"},
+			},
+		}
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for valid features", func() {
+			Expect(app.Validate()).To(Succeed())
+		})
+
+		DescribeTable("Should return an error for an invalid feature",
+			func(key, value, msg string) {
+				app.EPAFeatures = []cce.EPAFeature{{Key: key, Value: value}}
+				Expect(app.Validate()).To(MatchError(msg))
+			},
+			Entry("resource without a vendor", "resource:intel_fec_5g", "1",
+				"EPA feature [resource:intel_fec_5g] must name a device-plugin resource as <vendor>/<name>"),
+			Entry("fractional resource", "resource:intel.com/intel_fec_5g", "500m",
+				"EPA feature [resource:intel.com/intel_fec_5g] value must be a positive integer"),
+			Entry("hugepages without a page size", "hugepages-huge", "2Gi",
+				"EPA feature [hugepages-huge] must name a page size, e.g. hugepages-2Mi"),
+			Entry("zero hugepages", "hugepages-2Mi", "0",
+				"EPA feature [hugepages-2Mi] value must be a positive quantity, e.g. 1Gi"),
+			Entry("non-boolean pinning", "cpu:pinned", "yes",
+				"EPA feature [cpu:pinned] value must be true or false"),
+			Entry("unknown cpu feature", "cpu:isolated", "true",
+				"EPA feature [cpu:isolated] is unknown"),
+			Entry("invalid network", "network:SRIOV_net", "net1",
+				"EPA feature [network:SRIOV_net] must name a network as [<namespace>/]<name>"),
+			Entry("invalid interface", "network:sriov-net", "a very long interface name",
+				"EPA feature [network:sriov-net] value must be an interface name"),
+		)
+	})
+
+	Describe("ToK8s", func() {
+		It("Should translate the features requested of Kubernetes", func() {
+			Expect(app.EPAResourcesToK8s()).To(Equal(coreV1.ResourceList{
+				"intel.com/intel_fec_5g": resource.MustParse("1"),
+				"hugepages-1Gi":          resource.MustParse("2Gi"),
+			}))
+			Expect(app.EPAPinnedCPUs()).To(BeTrue())
+			Expect(app.EPANetworks()).To(Equal([]cce.NetworkAttachment{{Name: "sriov-net", Interface: "net1"}}))
+		})
+
+		It("Should request nothing of Kubernetes for node features", func() {
+			app.EPAFeatures = app.EPAFeatures[:1]
+			Expect(app.EPAResourcesToK8s()).To(BeNil())
+			Expect(app.EPAPinnedCPUs()).To(BeFalse())
+			Expect(app.EPANetworks()).To(BeEmpty())
+		})
+	})
+})
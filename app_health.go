@@ -0,0 +1,168 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Restart policies of an app, always by default.
+const (
+	RestartAlways    = "always"
+	RestartOnFailure = "on_failure"
+	RestartNever     = "never"
+)
+
+// Readiness conditions of an app deployed to a node.
+const (
+	Ready            = "ready"
+	NotReady         = "not_ready"
+	ReadinessUnknown = "unknown"
+)
+
+// AppProbes are the health probes of an app. The liveness probe restarts the
+// app when it fails, the readiness probe tells whether the app can serve and
+// the startup probe holds the other two back until the app has started.
+type AppProbes struct {
+	Liveness  *Probe `json:"liveness,omitempty"`
+	Readiness *Probe `json:"readiness,omitempty"`
+	Startup   *Probe `json:"startup,omitempty"`
+}
+
+// Probe checks the health of an app with exactly one of an HTTP request, a
+// TCP connection or a command run in the app. The zero values of the timings
+// select the defaults of the node.
+type Probe struct {
+	HTTPGet   *HTTPGetAction   `json:"http_get,omitempty"`
+	TCPSocket *TCPSocketAction `json:"tcp_socket,omitempty"`
+	Exec      *ExecAction      `json:"exec,omitempty"`
+
+	InitialDelaySeconds int `json:"initial_delay_seconds,omitempty"`
+	PeriodSeconds       int `json:"period_seconds,omitempty"`
+	TimeoutSeconds      int `json:"timeout_seconds,omitempty"`
+	FailureThreshold    int `json:"failure_threshold,omitempty"`
+}
+
+// HTTPGetAction probes an app with a GET request, succeeding on a status code
+// in [200..400).
+type HTTPGetAction struct {
+	Path   string `json:"path"`
+	Port   uint32 `json:"port"`
+	Scheme string `json:"scheme,omitempty"` // http (the default) or https
+}
+
+// TCPSocketAction probes an app by opening a TCP connection.
+type TCPSocketAction struct {
+	Port uint32 `json:"port"`
+}
+
+// ExecAction probes an app by running a command in it, succeeding if it exits
+// with 0.
+type ExecAction struct {
+	Command []string `json:"command"`
+}
+
+// Validate validates the probes.
+func (ps *AppProbes) Validate() error {
+	names := []string{"liveness", "readiness", "startup"}
+	for i, p := range []*Probe{ps.Liveness, ps.Readiness, ps.Startup} {
+		if p == nil {
+			continue
+		}
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("%s probe: %v", names[i], err)
+		}
+	}
+	return nil
+}
+
+// Validate validates the probe.
+func (p *Probe) Validate() error { // nolint: gocyclo
+	actions := 0
+	if p.HTTPGet != nil {
+		actions++
+		if !strings.HasPrefix(p.HTTPGet.Path, "/") {
+			return errors.New("http_get path must start with /")
+		}
+		if p.HTTPGet.Port < 1 || p.HTTPGet.Port > MaxPort {
+			return fmt.Errorf("http_get port must be in [1..%d]", MaxPort)
+		}
+		switch p.HTTPGet.Scheme {
+		case "", "http", "https":
+		default:
+			return errors.New("http_get scheme must be http or https")
+		}
+	}
+	if p.TCPSocket != nil {
+		actions++
+		if p.TCPSocket.Port < 1 || p.TCPSocket.Port > MaxPort {
+			return fmt.Errorf("tcp_socket port must be in [1..%d]", MaxPort)
+		}
+	}
+	if p.Exec != nil {
+		actions++
+		if len(p.Exec.Command) == 0 {
+			return errors.New("exec command cannot be empty")
+		}
+	}
+	if actions != 1 {
+		return errors.New("must set exactly one of http_get, tcp_socket or exec")
+	}
+
+	if p.InitialDelaySeconds < 0 || p.PeriodSeconds < 0 || p.TimeoutSeconds < 0 || p.FailureThreshold < 0 {
+		return errors.New("timings cannot be negative")
+	}
+	return nil
+}
+
+// ToK8s converts the probe into a Kubernetes container probe.
+func (p *Probe) ToK8s() *coreV1.Probe {
+	if p == nil {
+		return nil
+	}
+
+	probe := &coreV1.Probe{
+		InitialDelaySeconds: int32(p.InitialDelaySeconds),
+		PeriodSeconds:       int32(p.PeriodSeconds),
+		TimeoutSeconds:      int32(p.TimeoutSeconds),
+		FailureThreshold:    int32(p.FailureThreshold),
+	}
+	switch {
+	case p.HTTPGet != nil:
+		probe.HTTPGet = &coreV1.HTTPGetAction{
+			Path:   p.HTTPGet.Path,
+			Port:   intstr.FromInt(int(p.HTTPGet.Port)),
+			Scheme: coreV1.URISchemeHTTP,
+		}
+		if p.HTTPGet.Scheme == "https" {
+			probe.HTTPGet.Scheme = coreV1.URISchemeHTTPS
+		}
+	case p.TCPSocket != nil:
+		probe.TCPSocket = &coreV1.TCPSocketAction{
+			Port: intstr.FromInt(int(p.TCPSocket.Port)),
+		}
+	case p.Exec != nil:
+		probe.Exec = &coreV1.ExecAction{
+			Command: p.Exec.Command,
+		}
+	}
+	return probe
+}
+
+// RestartPolicyToK8s converts a restart policy into a Kubernetes one.
+func RestartPolicyToK8s(policy string) coreV1.RestartPolicy {
+	switch policy {
+	case RestartOnFailure:
+		return coreV1.RestartPolicyOnFailure
+	case RestartNever:
+		return coreV1.RestartPolicyNever
+	default:
+		return coreV1.RestartPolicyAlways
+	}
+}
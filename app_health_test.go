@@ -0,0 +1,129 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+	coreV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+var _ = Describe("App health", func() {
+	var (
+		probes *cce.AppProbes
+	)
+
+	BeforeEach(func() {
+		probes = &cce.AppProbes{
+			Liveness: &cce.Probe{
+				HTTPGet:       &cce.HTTPGetAction{Path: "/healthz", Port: 8080},
+				PeriodSeconds: 5,
+			},
+			Readiness: &cce.Probe{
+				TCPSocket:           &cce.TCPSocketAction{Port: 80},
+				InitialDelaySeconds: 2,
+			},
+			Startup: &cce.Probe{
+				Exec:             &cce.ExecAction{Command: []string{"cat", "/tmp/started"}},
+				FailureThreshold: 30,
+			},
+		}
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for valid probes", func() {
+			Expect(probes.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if a probe has no action", func() {
+			probes.Startup.Exec = nil
+			Expect(probes.Validate()).To(MatchError(
+				"startup probe: must set exactly one of http_get, tcp_socket or exec"))
+		})
+
+		It("Should return an error if a probe has two actions", func() {
+			probes.Liveness.TCPSocket = &cce.TCPSocketAction{Port: 8080}
+			Expect(probes.Validate()).To(MatchError(
+				"liveness probe: must set exactly one of http_get, tcp_socket or exec"))
+		})
+
+		It("Should return an error if an HTTP path is relative", func() {
+			probes.Liveness.HTTPGet.Path = "healthz"
+			Expect(probes.Validate()).To(MatchError("liveness probe: http_get path must start with /"))
+		})
+
+		It("Should return an error if an HTTP port is out of range", func() {
+			probes.Liveness.HTTPGet.Port = 65536
+			Expect(probes.Validate()).To(MatchError("liveness probe: http_get port must be in [1..65535]"))
+		})
+
+		It("Should return an error if an HTTP scheme is invalid", func() {
+			probes.Liveness.HTTPGet.Scheme = "ftp"
+			Expect(probes.Validate()).To(MatchError("liveness probe: http_get scheme must be http or https"))
+		})
+
+		It("Should return an error if a TCP port is out of range", func() {
+			probes.Readiness.TCPSocket.Port = 0
+			Expect(probes.Validate()).To(MatchError("readiness probe: tcp_socket port must be in [1..65535]"))
+		})
+
+		It("Should return an error if a command is empty", func() {
+			probes.Startup.Exec.Command = nil
+			Expect(probes.Validate()).To(MatchError("startup probe: exec command cannot be empty"))
+		})
+
+		It("Should return an error if a timing is negative", func() {
+			probes.Readiness.TimeoutSeconds = -1
+			Expect(probes.Validate()).To(MatchError("readiness probe: timings cannot be negative"))
+		})
+	})
+
+	Describe("ToK8s", func() {
+		It("Should convert the probes", func() {
+			Expect(probes.Liveness.ToK8s()).To(Equal(&coreV1.Probe{
+				Handler: coreV1.Handler{
+					HTTPGet: &coreV1.HTTPGetAction{
+						Path:   "/healthz",
+						Port:   intstr.FromInt(8080),
+						Scheme: coreV1.URISchemeHTTP,
+					},
+				},
+				PeriodSeconds: 5,
+			}))
+			Expect(probes.Readiness.ToK8s()).To(Equal(&coreV1.Probe{
+				Handler: coreV1.Handler{
+					TCPSocket: &coreV1.TCPSocketAction{Port: intstr.FromInt(80)},
+				},
+				InitialDelaySeconds: 2,
+			}))
+			Expect(probes.Startup.ToK8s()).To(Equal(&coreV1.Probe{
+				Handler: coreV1.Handler{
+					Exec: &coreV1.ExecAction{Command: []string{"cat", "/tmp/started"}},
+				},
+				FailureThreshold: 30,
+			}))
+		})
+
+		It("Should convert an HTTPS probe", func() {
+			probes.Liveness.HTTPGet.Scheme = "https"
+			Expect(probes.Liveness.ToK8s().HTTPGet.Scheme).To(Equal(coreV1.URISchemeHTTPS))
+		})
+
+		It("Should return nil for no probe", func() {
+			var probe *cce.Probe
+			Expect(probe.ToK8s()).To(BeNil())
+		})
+	})
+
+	Describe("RestartPolicyToK8s", func() {
+		It("Should convert the restart policies", func() {
+			Expect(cce.RestartPolicyToK8s("")).To(Equal(coreV1.RestartPolicyAlways))
+			Expect(cce.RestartPolicyToK8s(cce.RestartAlways)).To(Equal(coreV1.RestartPolicyAlways))
+			Expect(cce.RestartPolicyToK8s(cce.RestartOnFailure)).To(Equal(coreV1.RestartPolicyOnFailure))
+			Expect(cce.RestartPolicyToK8s(cce.RestartNever)).To(Equal(coreV1.RestartPolicyNever))
+		})
+	})
+})
@@ -0,0 +1,79 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// DigestPrefix prefixes the hex-encoded SHA-256 digest of an app image.
+const DigestPrefix = "sha256:"
+
+var digestRegexp = regexp.MustCompile(`^sha256:[0-9a-f]{64}$`)
+
+// ComputeDigest returns the digest of the image read from r.
+func ComputeDigest(r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	return DigestPrefix + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// ParsePublicKey parses a PEM-encoded RSA or ECDSA public key.
+func ParsePublicKey(pemKey string) (crypto.PublicKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("cannot be decoded as PEM")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.New("cannot be parsed as a public key")
+	}
+	switch key.(type) {
+	case *rsa.PublicKey, *ecdsa.PublicKey:
+		return key, nil
+	default:
+		return nil, errors.New("must be an RSA or ECDSA key")
+	}
+}
+
+// VerifySignature checks the signature of the app against its digest with
+// publicKey. The signature is a detached SHA-256 signature of the image, as
+// made by openssl dgst -sha256 -sign, so only the digest is needed to verify
+// it.
+func (app *App) VerifySignature(publicKey string) error {
+	key, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return fmt.Errorf("public_key %v", err)
+	}
+	hashed, err := hex.DecodeString(strings.TrimPrefix(app.Digest, DigestPrefix))
+	if err != nil || !digestRegexp.MatchString(app.Digest) {
+		return errors.New("digest must be sha256:<64 hex digits>")
+	}
+
+	switch key := key.(type) {
+	case *rsa.PublicKey:
+		err = rsa.VerifyPKCS1v15(key, crypto.SHA256, hashed, app.Signature)
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(key, hashed, app.Signature) {
+			err = errors.New("ecdsa verification failure")
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("signature verification failed: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+func publicKeyPEM(key crypto.PublicKey) string {
+	der, err := x509.MarshalPKIXPublicKey(key)
+	Expect(err).ToNot(HaveOccurred())
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
+
+var _ = Describe("App integrity", func() {
+	const image = "hello"
+
+	var (
+		app    *cce.App
+		hashed [32]byte
+	)
+
+	BeforeEach(func() {
+		app = &cce.App{
+			ID:     "efcece3c-6b58-4993-8d45-bde6239d4baa",
+			Digest: "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
+		}
+		hashed = sha256.Sum256([]byte(image))
+	})
+
+	Describe("ComputeDigest", func() {
+		It("Should return the SHA-256 digest of the image", func() {
+			Expect(cce.ComputeDigest(strings.NewReader(image))).To(Equal(app.Digest))
+		})
+
+		It("Should return an error if the image cannot be read", func() {
+			_, err := cce.ComputeDigest(failingReader{})
+			Expect(err).To(MatchError("connection reset"))
+		})
+	})
+
+	Describe("VerifySignature", func() {
+		It("Should verify an RSA signature", func() {
+			key, err := rsa.GenerateKey(rand.Reader, 2048)
+			Expect(err).ToNot(HaveOccurred())
+			app.Signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(app.VerifySignature(publicKeyPEM(&key.PublicKey))).To(Succeed())
+
+			By("Rejecting the signature for another digest")
+			app.Digest = "sha256:486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7"
+			Expect(app.VerifySignature(publicKeyPEM(&key.PublicKey))).To(MatchError(
+				"signature verification failed: crypto/rsa: verification error"))
+		})
+
+		It("Should verify an ECDSA signature", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			app.Signature, err = ecdsa.SignASN1(rand.Reader, key, hashed[:])
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(app.VerifySignature(publicKeyPEM(&key.PublicKey))).To(Succeed())
+
+			By("Rejecting the signature with another key")
+			other, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(app.VerifySignature(publicKeyPEM(&other.PublicKey))).To(MatchError(
+				"signature verification failed: ecdsa verification failure"))
+		})
+
+		It("Should return an error if the key is not PEM", func() {
+			Expect(app.VerifySignature("key")).To(MatchError("public_key cannot be decoded as PEM"))
+		})
+	})
+})
@@ -0,0 +1,55 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// SameRelease returns whether other is a version of the same app, that is it
+// has the same type, name and vendor.
+func (app *App) SameRelease(other *App) bool {
+	return app.Type == other.Type && app.Name == other.Name && app.Vendor == other.Vendor
+}
+
+// AppVersions returns the versions of the app's release sorted from the
+// oldest to the newest.
+func AppVersions(ctx context.Context, ps PersistenceService, app *App) ([]*App, error) {
+	apps, err := ps.ReadAll(ctx, &App{})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch apps from DB: %v", err)
+	}
+
+	var versions []*App
+	for _, a := range apps {
+		if app.SameRelease(a.(*App)) {
+			versions = append(versions, a.(*App))
+		}
+	}
+	sort.SliceStable(versions, func(i, j int) bool {
+		return compareVersions(versions[i].Version, versions[j].Version) < 0
+	})
+	return versions, nil
+}
+
+// compareVersions compares two dot-separated versions, numerically where both
+// parts are numbers.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil && an != bn:
+			return an - bn
+		case (aErr != nil || bErr != nil) && as[i] != bs[i]:
+			return strings.Compare(as[i], bs[i])
+		}
+	}
+	return len(as) - len(bs)
+}
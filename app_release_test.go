@@ -0,0 +1,54 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/internal/stubs"
+)
+
+var _ = Describe("App releases", func() {
+	version := func(id, version string) *cce.App {
+		return &cce.App{
+			ID:      id,
+			Type:    "container",
+			Name:    "test-app",
+			Vendor:  "test-vendor",
+			Version: version,
+		}
+	}
+
+	Describe("SameRelease", func() {
+		It("Should match apps of the same type, name and vendor", func() {
+			app := version("c9b4bc07-0d6b-4a6e-8e36-d1e0e7d6d7a1", "1.0")
+			Expect(app.SameRelease(version("", "2.0"))).To(BeTrue())
+
+			other := version("", "1.0")
+			other.Vendor = "other-vendor"
+			Expect(app.SameRelease(other)).To(BeFalse())
+		})
+	})
+
+	Describe("AppVersions", func() {
+		It("Should return the versions of the release sorted by version", func() {
+			ctx := context.Background()
+			ps := stubs.NewMemoryPersistenceService()
+
+			v1 := version("c9b4bc07-0d6b-4a6e-8e36-d1e0e7d6d7a1", "1.9")
+			v2 := version("5e1f3b0a-48a2-4c8e-9f4d-6f0b2a9c3e12", "1.10")
+			v3 := version("8a6d2c4e-1b3f-4e5a-9c7d-0e2f4a6b8c23", "1.9.1")
+			other := version("2f4e6a8c-0b1d-4f3e-8a5c-7e9b1d3f5a34", "1.0")
+			other.Name = "other-app"
+			for _, app := range []*cce.App{v2, other, v1, v3} {
+				Expect(ps.Create(ctx, app)).To(Succeed())
+			}
+
+			Expect(cce.AppVersions(ctx, ps, v1)).To(Equal([]*cce.App{v1, v3, v2}))
+		})
+	})
+})
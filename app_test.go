@@ -31,6 +31,7 @@ var _ = Describe("Entities: App", func() {
 				{Port: 443, Protocol: "tcp"},
 			},
 			Source: "https://path/to/file.zip",
+			Digest: "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824",
 		}
 	})
 
@@ -127,6 +128,89 @@ var _ = Describe("Entities: App", func() {
 			app.Source = "invalid.url"
 			Expect(app.Validate()).To(MatchError("source cannot be parsed as a URI"))
 		})
+
+		It("Should return an error if Config is invalid", func() {
+			app.Config = &cce.AppConfig{Env: []cce.EnvVar{{Name: "1ST"}}}
+			Expect(app.Validate()).To(MatchError(`config: env name "1ST" is invalid`))
+		})
+
+		It("Should return an error if Probes are invalid", func() {
+			app.Probes = &cce.AppProbes{Readiness: &cce.Probe{}}
+			Expect(app.Validate()).To(MatchError(
+				"readiness probe: must set exactly one of http_get, tcp_socket or exec"))
+		})
+
+		It("Should return an error if RestartPolicy is invalid", func() {
+			app.RestartPolicy = "sometimes"
+			Expect(app.Validate()).To(MatchError("restart_policy must be one of [always, on_failure, never]"))
+		})
+
+		It("Should return an error if DiskSize is negative", func() {
+			app.Type = "vm"
+			app.DiskSize = -1
+			Expect(app.Validate()).To(MatchError("disk_size cannot be negative"))
+		})
+
+		It("Should return an error if DiskSize is set for a container app", func() {
+			app.Type = "container"
+			app.DiskSize = 10
+			Expect(app.Validate()).To(MatchError("disk_size requires a vm app"))
+		})
+
+		It("Should return an error if Digest is not a SHA-256 digest", func() {
+			app.Digest = "md5:5d41402abc4b2a76b9719d911017c592"
+			Expect(app.Validate()).To(MatchError("digest must be sha256:<64 hex digits>"))
+		})
+
+		It("Should return an error if Signature is set without a Digest", func() {
+			app.Digest = ""
+			app.Signature = []byte("signature")
+			Expect(app.Validate()).To(MatchError("signature requires a digest"))
+		})
+
+		It("Should return an error if Signature is set without a PublisherID", func() {
+			app.Signature = []byte("signature")
+			Expect(app.Validate()).To(MatchError("signature requires a publisher_id"))
+		})
+
+		It("Should return an error if PublisherID is not a UUID", func() {
+			app.Signature = []byte("signature")
+			app.PublisherID = "123"
+			Expect(app.Validate()).To(MatchError("publisher_id not a valid uuid"))
+		})
+
+		It("Should return an error if PublisherID is set without a Signature", func() {
+			app.PublisherID = "9d740ba5-6ba9-4e9b-a0fc-9ea5d84e1a4b"
+			Expect(app.Validate()).To(MatchError("publisher_id requires a signature"))
+		})
+	})
+
+	Describe("ValidateOrchestration", func() {
+		It("Should accept any restart policy in native mode", func() {
+			app.RestartPolicy = cce.RestartNever
+			Expect(app.ValidateOrchestration(cce.OrchestrationModeNative)).To(Succeed())
+		})
+
+		It("Should only accept the always restart policy in kubernetes mode", func() {
+			app.RestartPolicy = cce.RestartAlways
+			Expect(app.ValidateOrchestration(cce.OrchestrationModeKubernetes)).To(Succeed())
+			app.RestartPolicy = cce.RestartOnFailure
+			Expect(app.ValidateOrchestration(cce.OrchestrationModeKubernetesOVN)).To(MatchError(
+				"restart_policy on_failure is not supported in kubernetes mode"))
+		})
+
+		It("Should reject the probes and resources of vm apps in kubernetes mode", func() {
+			app.Type = "vm"
+			app.Probes = &cce.AppProbes{}
+			Expect(app.ValidateOrchestration(cce.OrchestrationModeNative)).To(Succeed())
+			Expect(app.ValidateOrchestration(cce.OrchestrationModeKubernetes)).To(MatchError(
+				"probes are not supported for vm apps in kubernetes mode"))
+
+			app.Probes = nil
+			app.EPAFeatures = []cce.EPAFeature{{Key: cce.EPAPrefixResource + "intel.com/sriov", Value: "1"}}
+			Expect(app.ValidateOrchestration(cce.OrchestrationModeKubernetes)).To(MatchError(
+				"epafeatures resources are not supported for vm apps in kubernetes mode"))
+		})
 	})
 
 	Describe("String", func() {
@@ -143,6 +227,7 @@ App[
     Ports: [80/tcp 443/tcp]
     Source: https://path/to/file.zip
     EPAFeatures: []
+    Digest: sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824
 ]`,
 			)))
 		})
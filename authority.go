@@ -3,7 +3,10 @@
 
 package cce
 
-import "crypto/x509"
+import (
+	"crypto/x509"
+	"crypto/x509/pkix"
+)
 
 // AuthorityService manages digital certificates.
 type AuthorityService interface {
@@ -12,4 +15,7 @@ type AuthorityService interface {
 	CAChain() ([]*x509.Certificate, error)
 	// SignCSR signs a ASN.1 DER encoded certificate signing request.
 	SignCSR(der []byte, template *x509.Certificate) (*x509.Certificate, error)
+	// CRL creates a ASN.1 DER encoded certificate revocation list of the
+	// revoked certificates, signed by the issuing CA.
+	CRL(revoked []pkix.RevokedCertificate) ([]byte, error)
 }
@@ -61,6 +61,35 @@ type Controller struct {
 	// EdgeNodeCreds are the transport credentials for connecting to an edge
 	// node. The server name will be overridden.
 	EdgeNodeCreds *tls.Config
+
+	// NodeConns caches connections to edge nodes. If it is nil, a new
+	// connection is dialed for every request.
+	NodeConns NodeConnectionService
+
+	// AppImageHosts are the hosts the images of apps may be fetched from to
+	// verify them. If it is empty, images are only fetched from public
+	// addresses.
+	AppImageHosts []string
+}
+
+// NodeConnectionService caches connections to edge nodes. The controller
+// drops them when a node's address or credentials change and on shutdown.
+type NodeConnectionService interface {
+	// Get returns a connection to the agent of a node listening on addr and
+	// port. It must be released when the caller is done with it.
+	Get(ctx context.Context, nodeID, addr, port string, conf *tls.Config) (NodeConn, error)
+	// Invalidate closes all cached connections to a node.
+	Invalidate(nodeID string)
+	// Close closes all cached connections.
+	Close() error
+}
+
+// NodeConn is a connection handed out by a NodeConnectionService. Its
+// concrete type is defined by the implementing package.
+type NodeConn interface {
+	// Release tells the service that the caller no longer uses the
+	// connection, which may be closed from then on.
+	Release()
 }
 
 // PersistenceService manages entity persistence. The methods with zv parameters take a zero-value Persistable for
@@ -0,0 +1,94 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/swagger"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func postConfiguredApps(config *cce.AppConfig) (int, string) {
+	By("Sending a POST /apps request")
+	return postJSON("http://127.0.0.1:8080/apps", swagger.AppDetail{
+		AppSummary: swagger.AppSummary{
+			Type:    "container",
+			Name:    "configured app",
+			Version: "latest",
+			Vendor:  "smart edge",
+		},
+		Cores:  1,
+		Memory: 1024,
+		Source: "http://www.test.com/configured_app.tar.gz",
+		Config: config,
+	})
+}
+
+var _ = Describe("App runtime configuration", func() {
+	var (
+		nodeCfg *nodeConfig
+		config  *cce.AppConfig
+	)
+
+	BeforeEach(func() {
+		clearGRPCTargetsTable()
+		nodeCfg = createAndRegisterNode()
+
+		config = &cce.AppConfig{
+			Env:         []cce.EnvVar{{Name: "MODE", Value: "edge"}},
+			Args:        []string{"--config", "/etc/app/app.conf"},
+			ConfigFiles: []cce.ConfigFile{{Path: "/etc/app/app.conf", Content: "port = 80"}},
+			Secrets:     []cce.SecretRef{{Name: "app-credentials", Key: "token", Env: "TOKEN"}},
+		}
+	})
+
+	It("Should deploy an app with its config and the node app overrides", func() {
+		code, body := postConfiguredApps(config)
+		Expect(code).To(Equal(http.StatusCreated))
+
+		var rb respBody
+		Expect(json.Unmarshal([]byte(body), &rb)).To(Succeed())
+		Expect(getApp(rb.ID).Config).To(Equal(config))
+
+		overrides := &cce.AppConfig{Env: []cce.EnvVar{{Name: "MODE", Value: "test"}}}
+		By("Sending a POST /nodes/{node_id}/apps request")
+		code, _ = postJSON(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps", nodeCfg.nodeID),
+			swagger.NodeAppRequest{ID: rb.ID, Config: overrides})
+		Expect(code).To(Equal(http.StatusOK))
+
+		Expect(getNodeApp(nodeCfg.nodeID, rb.ID).Config).To(Equal(overrides))
+	})
+
+	It("Should reject an invalid app config", func() {
+		config.ConfigFiles[0].Path = "app.conf"
+		code, body := postConfiguredApps(config)
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(Equal(`Validation failed: config: path "app.conf" must be an absolute file path`))
+	})
+
+	It("Should reject invalid node app overrides", func() {
+		code, body := postConfiguredApps(config)
+		Expect(code).To(Equal(http.StatusCreated))
+
+		var rb respBody
+		Expect(json.Unmarshal([]byte(body), &rb)).To(Succeed())
+
+		By("Sending a POST /nodes/{node_id}/apps request")
+		code, body = postJSON(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps", nodeCfg.nodeID),
+			swagger.NodeAppRequest{
+				ID:     rb.ID,
+				Config: &cce.AppConfig{Secrets: []cce.SecretRef{{Name: "app-credentials", Key: "token"}}},
+			})
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(Equal("Validation failed: config: secret app-credentials/token must set env or path"))
+	})
+})
@@ -0,0 +1,70 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"net/http"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/swagger"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func postProbedApps(probes *cce.AppProbes, restartPolicy string) (int, string) {
+	By("Sending a POST /apps request")
+	return postJSON("http://127.0.0.1:8080/apps", swagger.AppDetail{
+		AppSummary: swagger.AppSummary{
+			Type:    "container",
+			Name:    "probed app",
+			Version: "latest",
+			Vendor:  "smart edge",
+		},
+		Cores:         1,
+		Memory:        1024,
+		Source:        "http://www.test.com/probed_app.tar.gz",
+		Probes:        probes,
+		RestartPolicy: restartPolicy,
+	})
+}
+
+var _ = Describe("App health", func() {
+	var (
+		probes *cce.AppProbes
+	)
+
+	BeforeEach(func() {
+		probes = &cce.AppProbes{
+			Liveness:  &cce.Probe{HTTPGet: &cce.HTTPGetAction{Path: "/healthz", Port: 8080}},
+			Readiness: &cce.Probe{TCPSocket: &cce.TCPSocketAction{Port: 80}, PeriodSeconds: 5},
+		}
+	})
+
+	It("Should create an app with its probes and restart policy", func() {
+		code, body := postProbedApps(probes, cce.RestartOnFailure)
+		Expect(code).To(Equal(http.StatusCreated))
+
+		var rb respBody
+		Expect(json.Unmarshal([]byte(body), &rb)).To(Succeed())
+
+		app := getApp(rb.ID)
+		Expect(app.Probes).To(Equal(probes))
+		Expect(app.RestartPolicy).To(Equal(cce.RestartOnFailure))
+	})
+
+	It("Should reject an invalid probe", func() {
+		probes.Liveness.HTTPGet.Port = 0
+		code, body := postProbedApps(probes, "")
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(Equal("Validation failed: liveness probe: http_get port must be in [1..65535]"))
+	})
+
+	It("Should reject an invalid restart policy", func() {
+		code, body := postProbedApps(probes, "sometimes")
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(Equal("Validation failed: restart_policy must be one of [always, on_failure, never]"))
+	})
+})
@@ -0,0 +1,246 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const (
+	image       = "hello"
+	imageDigest = "sha256:2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+)
+
+func postJSON(url string, reqBody interface{}) (int, string) {
+	reqJSON, err := json.Marshal(reqBody)
+	Expect(err).ToNot(HaveOccurred())
+
+	resp, err := apiCli.Post(url, "application/json", strings.NewReader(string(reqJSON)))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+	return resp.StatusCode, string(body)
+}
+
+func postPublishers(name string, key *rsa.PrivateKey) (id string) {
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	Expect(err).ToNot(HaveOccurred())
+
+	By("Sending a POST /publishers request")
+	code, body := postJSON("http://127.0.0.1:8080/publishers", map[string]string{
+		"name":       name,
+		"public_key": string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	})
+
+	By("Verifying a 201 Created response")
+	Expect(code).To(Equal(http.StatusCreated))
+
+	var rb respBody
+	Expect(json.Unmarshal([]byte(body), &rb)).To(Succeed())
+	return rb.ID
+}
+
+func getPublisher(id string) *swagger.PublisherDetail {
+	By("Sending a GET /publishers/{publisher_id} request")
+	resp, err := apiCli.Get(fmt.Sprintf("http://127.0.0.1:8080/publishers/%s", id))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Verifying a 200 OK response")
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+	var publisher swagger.PublisherDetail
+	Expect(json.NewDecoder(resp.Body).Decode(&publisher)).To(Succeed())
+	return &publisher
+}
+
+func postSignedApps(source, digest, publisherID string, signature []byte) (int, string) {
+	By("Sending a POST /apps request")
+	return postJSON("http://127.0.0.1:8080/apps", swagger.AppDetail{
+		AppSummary: swagger.AppSummary{
+			Type:    "container",
+			Name:    "signed app",
+			Version: "latest",
+			Vendor:  "smart edge",
+		},
+		Cores:       1,
+		Memory:      1024,
+		Source:      source,
+		Digest:      digest,
+		Signature:   signature,
+		PublisherID: publisherID,
+	})
+}
+
+func postAppVerify(appID string) (int, string) {
+	By("Sending a POST /apps/{app_id}/verify request")
+	resp, err := apiCli.Post(
+		fmt.Sprintf("http://127.0.0.1:8080/apps/%s/verify", appID), "application/json", nil)
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+	return resp.StatusCode, string(body)
+}
+
+var _ = Describe("App integrity", func() {
+	var (
+		server      *httptest.Server
+		key         *rsa.PrivateKey
+		signature   []byte
+		publisherID string
+	)
+
+	BeforeEach(func() {
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/image.tar.gz" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(image))
+		}))
+
+		var err error
+		key, err = rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		hashed := sha256.Sum256([]byte(image))
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+		Expect(err).ToNot(HaveOccurred())
+
+		publisherID = postPublishers("publisher "+uuid.New(), key)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("Should verify a signed app", func() {
+		code, body := postSignedApps(server.URL+"/image.tar.gz", imageDigest, publisherID, signature)
+		Expect(code).To(Equal(http.StatusCreated))
+
+		var rb respBody
+		Expect(json.Unmarshal([]byte(body), &rb)).To(Succeed())
+
+		app := getApp(rb.ID)
+		Expect(app.Digest).To(Equal(imageDigest))
+		Expect(app.Signature).To(Equal(signature))
+		Expect(app.PublisherID).To(Equal(publisherID))
+
+		code, body = postAppVerify(rb.ID)
+		Expect(code).To(Equal(http.StatusOK))
+
+		var verification swagger.AppVerification
+		Expect(json.Unmarshal([]byte(body), &verification)).To(Succeed())
+		Expect(verification).To(Equal(swagger.AppVerification{
+			ID:     rb.ID,
+			Digest: imageDigest,
+			Signed: true,
+		}))
+
+		By("Refusing to delete the publisher of the app")
+		code, body = deleteURL(fmt.Sprintf("http://127.0.0.1:8080/publishers/%s", publisherID))
+		Expect(code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(body).To(Equal(fmt.Sprintf(
+			"cannot delete publisher_id %s: record in use in apps", publisherID)))
+	})
+
+	It("Should reject an image that does not match the digest", func() {
+		digest := "sha256:486ea46224d1bb4fb680f34f7c9ad96a8f24ec88be73ea8e5a6c65260e9cb8a7"
+		code, body := postSignedApps(server.URL+"/image.tar.gz", digest, "", nil)
+		Expect(code).To(Equal(http.StatusCreated))
+
+		var rb respBody
+		Expect(json.Unmarshal([]byte(body), &rb)).To(Succeed())
+
+		code, body = postAppVerify(rb.ID)
+		Expect(code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(body).To(Equal(fmt.Sprintf("digest mismatch: expected %s, got %s", digest, imageDigest)))
+	})
+
+	It("Should return an error if the image cannot be fetched", func() {
+		code, body := postSignedApps(server.URL+"/missing.tar.gz", imageDigest, "", nil)
+		Expect(code).To(Equal(http.StatusCreated))
+
+		var rb respBody
+		Expect(json.Unmarshal([]byte(body), &rb)).To(Succeed())
+
+		code, body = postAppVerify(rb.ID)
+		Expect(code).To(Equal(http.StatusBadGateway))
+		Expect(body).To(Equal("error fetching source: 404 Not Found"))
+	})
+
+	It("Should refuse to fetch the image from a host not allowed", func() {
+		source := strings.Replace(server.URL, "127.0.0.1", "localhost", 1) + "/image.tar.gz"
+		code, body := postSignedApps(source, imageDigest, "", nil)
+		Expect(code).To(Equal(http.StatusCreated))
+
+		var rb respBody
+		Expect(json.Unmarshal([]byte(body), &rb)).To(Succeed())
+
+		code, body = postAppVerify(rb.ID)
+		Expect(code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(body).To(Equal(fmt.Sprintf("source host localhost of app_id %s is not allowed", rb.ID)))
+	})
+
+	It("Should return an error if the app has no digest", func() {
+		id := postApps("container")
+
+		code, body := postAppVerify(id)
+		Expect(code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(body).To(Equal(fmt.Sprintf("app_id %s has no digest to verify", id)))
+	})
+
+	It("Should reject an app whose signature does not match", func() {
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+		otherID := postPublishers("publisher "+uuid.New(), other)
+
+		code, body := postSignedApps(server.URL+"/image.tar.gz", imageDigest, otherID, signature)
+		Expect(code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(body).To(Equal("signature verification failed: crypto/rsa: verification error"))
+
+		By("Deleting the unused publisher")
+		code, _ = deleteURL(fmt.Sprintf("http://127.0.0.1:8080/publishers/%s", otherID))
+		Expect(code).To(Equal(http.StatusOK))
+	})
+
+	It("Should reject an app of an unknown publisher", func() {
+		unknownID := uuid.New()
+		code, body := postSignedApps(server.URL+"/image.tar.gz", imageDigest, unknownID, signature)
+		Expect(code).To(Equal(http.StatusNotFound))
+		Expect(body).To(Equal(fmt.Sprintf("publisher_id %s not found", unknownID)))
+	})
+
+	It("Should reject a publisher with a duplicate name", func() {
+		name := "publisher " + uuid.New()
+		postPublishers(name, key)
+
+		code, body := postJSON("http://127.0.0.1:8080/publishers", map[string]string{
+			"name":       name,
+			"public_key": getPublisher(publisherID).PublicKey,
+		})
+		Expect(code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(body).To(Equal(fmt.Sprintf("duplicate record in publishers detected for name %s", name)))
+	})
+})
@@ -0,0 +1,131 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func labelNode(nodeID string, labels map[string]string) {
+	node := getNode(nodeID)
+	node.Labels = labels
+	reqBody, err := json.Marshal(node)
+	Expect(err).ToNot(HaveOccurred())
+
+	By("Sending a PATCH /nodes/{id} request")
+	resp, err := apiCli.Patch(
+		fmt.Sprintf("http://127.0.0.1:8080/nodes/%s", nodeID),
+		"application/json",
+		strings.NewReader(string(reqBody)))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Verifying a 200 OK response")
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+}
+
+func postAppSchedule(appID, reqBody string) (int, *swagger.ScheduleResult) {
+	By("Sending a POST /apps/{app_id}/schedule request")
+	resp, err := apiCli.Post(
+		fmt.Sprintf("http://127.0.0.1:8080/apps/%s/schedule", appID),
+		"application/json",
+		strings.NewReader(reqBody))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Reading the response body")
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+
+	var result swagger.ScheduleResult
+	if resp.Header.Get("Content-Type") == "application/json" {
+		By("Unmarshaling the response")
+		Expect(json.Unmarshal(body, &result)).To(Succeed())
+	}
+	return resp.StatusCode, &result
+}
+
+var _ = Describe("POST /apps/{app_id}/schedule", func() {
+	var (
+		nodeCfg *nodeConfig
+		appID   string
+		rack    string
+	)
+
+	BeforeEach(func() {
+		clearGRPCTargetsTable()
+		nodeCfg = createAndRegisterNode()
+		appID = postApps("container")
+		rack = uuid.New()
+		labelNode(nodeCfg.nodeID, map[string]string{"rack": rack})
+		Expect(getNode(nodeCfg.nodeID).Labels).To(Equal(map[string]string{"rack": rack}))
+	})
+
+	It("Should deploy the app to a node matching the selector", func() {
+		code, result := postAppSchedule(appID, fmt.Sprintf(`{"node_selector": {"rack": "%s"}}`, rack))
+		Expect(code).To(Equal(http.StatusCreated))
+		Expect(result.AppID).To(Equal(appID))
+		Expect(result.Scheduled).To(HaveLen(1))
+		Expect(result.Scheduled[0].NodeID).To(Equal(nodeCfg.nodeID))
+		Expect(result.Scheduled[0].Error).To(BeEmpty())
+
+		Expect(getNodeApps(nodeCfg.nodeID).NodeApps).To(ContainElement(
+			swagger.NodeAppSummary{ID: appID}))
+
+		By("Scheduling the app again")
+		code, result = postAppSchedule(appID, fmt.Sprintf(`{"node_selector": {"rack": "%s"}}`, rack))
+		Expect(code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(result.Excluded).To(ContainElement(swagger.ExcludedNode{
+			NodeID:  nodeCfg.nodeID,
+			Reasons: []string{"app is already deployed to the node"},
+		}))
+	})
+
+	It("Should only report the chosen node for a dry run", func() {
+		code, result := postAppSchedule(appID,
+			fmt.Sprintf(`{"node_selector": {"rack": "%s"}, "dry_run": true}`, rack))
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(result.Scheduled).To(HaveLen(1))
+		Expect(result.Scheduled[0].NodeID).To(Equal(nodeCfg.nodeID))
+
+		Expect(getNodeApps(nodeCfg.nodeID).NodeApps).To(BeEmpty())
+	})
+
+	It("Should explain why no node fits", func() {
+		code, result := postAppSchedule(appID,
+			fmt.Sprintf(`{"node_selector": {"rack": "%s"}, "replicas": 2}`, rack))
+		Expect(code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(result.Scheduled).To(HaveLen(1))
+
+		code, result = postAppSchedule(appID, `{"node_selector": {"rack": "none"}}`)
+		Expect(code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(result.Scheduled).To(BeEmpty())
+		Expect(result.Excluded).To(ContainElement(swagger.ExcludedNode{
+			NodeID:  nodeCfg.nodeID,
+			Reasons: []string{fmt.Sprintf("label rack=none required, node has rack=%s", rack)},
+		}))
+
+		Expect(getNodeApps(nodeCfg.nodeID).NodeApps).To(BeEmpty())
+	})
+
+	It("Should return 400 for an invalid strategy", func() {
+		code, _ := postAppSchedule(appID, `{"strategy": "random"}`)
+		Expect(code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("Should return 404 if the app does not exist", func() {
+		code, _ := postAppSchedule(uuid.New(), `{}`)
+		Expect(code).To(Equal(http.StatusNotFound))
+	})
+})
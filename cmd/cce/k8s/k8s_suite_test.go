@@ -414,7 +414,8 @@ func postNodeApps(nodeID, appID string) {
 			NodeAppSummary: swagger.NodeAppSummary{
 				ID: appID,
 			},
-			Status: "deployed",
+			Status:    "deployed",
+			Readiness: "not_ready",
 		},
 	))
 }
@@ -459,7 +460,7 @@ func deployApp(nodeID, appID string) {
 	By("Verifying app start call to Kubernetes API successful")
 	ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
-	err = k8sCli.Start(ctx, nodeID, appID)
+	err = k8sCli.Start(ctx, nodeID, appID, 1)
 	Expect(err).ToNot(HaveOccurred())
 
 	// revert image pull policy back to default value: never pull
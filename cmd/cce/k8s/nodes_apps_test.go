@@ -121,7 +121,8 @@ var _ = Describe("/nodes/{node_id}/apps for k8s", func() {
 						NodeAppSummary: swagger.NodeAppSummary{
 							ID: appID,
 						},
-						Status: "deployed",
+						Status:    "deployed",
+						Readiness: "not_ready",
 					},
 				))
 
@@ -135,7 +136,8 @@ var _ = Describe("/nodes/{node_id}/apps for k8s", func() {
 						NodeAppSummary: swagger.NodeAppSummary{
 							ID: app2ID,
 						},
-						Status: "deployed",
+						Status:    "deployed",
+						Readiness: "not_ready",
 					},
 				))
 			},
@@ -157,7 +159,8 @@ var _ = Describe("/nodes/{node_id}/apps for k8s", func() {
 						NodeAppSummary: swagger.NodeAppSummary{
 							ID: appID,
 						},
-						Status: "deployed",
+						Status:    "deployed",
+						Readiness: "not_ready",
 					},
 				))
 			},
@@ -238,7 +241,8 @@ var _ = Describe("/nodes/{node_id}/apps for k8s", func() {
 				}
 				`,
 				&swagger.NodeAppDetail{
-					Status: "deployed",
+					Status:    "deployed",
+					Readiness: "not_ready",
 				},
 			),
 			Entry(
@@ -17,6 +17,7 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+	"strings"
 
 	"github.com/gorilla/handlers"
 	"golang.org/x/sync/errgroup"
@@ -33,11 +34,16 @@ import (
 	cce "github.com/open-ness/edgecontroller"
 	"github.com/open-ness/edgecontroller/gorilla"
 	"github.com/open-ness/edgecontroller/grpc"
+	gclients "github.com/open-ness/edgecontroller/grpc/clients"
+	"github.com/open-ness/edgecontroller/grpc/node"
 	"github.com/open-ness/edgecontroller/http"
+	"github.com/open-ness/edgecontroller/inventory"
 	"github.com/open-ness/edgecontroller/jose"
 	"github.com/open-ness/edgecontroller/k8s"
+	"github.com/open-ness/edgecontroller/liveness"
 	"github.com/open-ness/edgecontroller/mysql"
 	"github.com/open-ness/edgecontroller/pki"
+	"github.com/open-ness/edgecontroller/rollout"
 	"github.com/open-ness/edgecontroller/telemetry"
 )
 
@@ -60,6 +66,26 @@ var (
 	statsdOut  string
 	orchMode   string
 	k8sClient  k8s.Client
+
+	k8sNamespaceCores    int
+	k8sNamespaceMemory   int
+	k8sNetworkNamespaces string
+	k8sExternalIPRanges  string
+
+	caCertPath     string
+	caKeyPath      string
+	caChainPath    string
+	intermediateCA bool
+
+	nodeCertLifetime    time.Duration
+	nodeProbeInterval   time.Duration
+	nodeProbeTimeout    time.Duration
+	inventoryInterval   time.Duration
+	rolloutInterval     time.Duration
+	nodeConnIdleTimeout time.Duration
+	nodeCalls           = gclients.DefaultCallConfig()
+
+	appImageHosts string
 )
 
 func init() {
@@ -74,6 +100,35 @@ func init() {
 	flag.IntVar(&statsdPort, "statsdPort", 8125, "Telemetry ingress port for statsd")
 	flag.StringVar(&syslogOut, "syslog-path", "./syslog.log", "Syslog output file path")
 	flag.StringVar(&statsdOut, "statsd-path", "./statsd.log", "StatsD output file path")
+	flag.StringVar(&caCertPath, "ca-cert", "", "Intermediate CA certificate path (default: local CA)")
+	flag.StringVar(&caKeyPath, "ca-key", "", "Intermediate CA private key path")
+	flag.StringVar(&caChainPath, "ca-chain", "", "Issuers of the intermediate CA certificate, ending with the root CA")
+	flag.BoolVar(&intermediateCA, "intermediate-ca", false,
+		"Issue certificates from an intermediate CA under the local root CA")
+	flag.DurationVar(&nodeCertLifetime, "node-cert-lifetime", pki.DefaultCertLifetime,
+		"Validity of node certificates (0 for until the CA expires)")
+	flag.DurationVar(&nodeProbeInterval, "node-probe-interval", liveness.DefaultInterval,
+		"Interval between node liveness probes")
+	flag.DurationVar(&nodeProbeTimeout, "node-probe-timeout", liveness.DefaultTimeout,
+		"Timeout of a single node liveness probe")
+	flag.DurationVar(&inventoryInterval, "inventory-interval", inventory.DefaultInterval,
+		"Interval between collections of node inventories")
+	flag.DurationVar(&rolloutInterval, "rollout-interval", rollout.DefaultInterval,
+		"Interval between two steps of the app rollouts")
+	flag.DurationVar(&nodeConnIdleTimeout, "node-conn-idle-timeout", node.DefaultIdleTimeout,
+		"Time an unused connection to a node is kept open")
+	flag.DurationVar(&nodeCalls.Timeout, "node-rpc-timeout", nodeCalls.Timeout,
+		"Deadline of a single attempt of an RPC to a node")
+	flag.IntVar(&nodeCalls.MaxAttempts, "node-rpc-max-attempts", nodeCalls.MaxAttempts,
+		"Attempts made for idempotent RPCs to a node")
+	flag.DurationVar(&nodeCalls.Backoff, "node-rpc-backoff", nodeCalls.Backoff,
+		"Initial backoff between retries of an RPC to a node")
+	flag.DurationVar(&nodeCalls.MaxBackoff, "node-rpc-max-backoff", nodeCalls.MaxBackoff,
+		"Maximum backoff between retries of an RPC to a node")
+	flag.IntVar(&nodeCalls.BreakerThreshold, "node-breaker-threshold", nodeCalls.BreakerThreshold,
+		"Consecutive failed RPCs after which calls to a node fail fast (0 disables)")
+	flag.DurationVar(&nodeCalls.BreakerCooldown, "node-breaker-cooldown", nodeCalls.BreakerCooldown,
+		"Time calls to a node fail fast before a trial call is let through")
 
 	// application orchestration mode
 	flag.StringVar(&orchMode, "orchestration-mode", "native", "Orchestration mode."+
@@ -86,20 +141,45 @@ func init() {
 	flag.StringVar(&k8sClient.Host, "k8s-master-host", "", "Kubernetes master host")
 	flag.StringVar(&k8sClient.APIPath, "k8s-api-path", "", "Kubernetes api path")
 	flag.StringVar(&k8sClient.Username, "k8s-master-user", "", "Kubernetes default user")
+	flag.IntVar(&k8sNamespaceCores, "k8s-namespace-cores", 0,
+		"Cores quota of each tenant namespace (default: unlimited)")
+	flag.IntVar(&k8sNamespaceMemory, "k8s-namespace-memory", 0,
+		"Memory quota (in MB) of each tenant namespace (default: unlimited)")
+	flag.StringVar(&k8sNetworkNamespaces, "k8s-network-namespaces", "",
+		"Comma-separated namespaces of the networks apps of any namespace can attach to")
+	flag.StringVar(&k8sExternalIPRanges, "k8s-external-ip-ranges", "",
+		"Comma-separated CIDRs the external IPs of app services must be in (default: none allowed)")
+	flag.StringVar(&appImageHosts, "app-image-hosts", "",
+		"Comma-separated hosts app images are verified from (default: any public address)")
 }
 
 func setupOrchestrator() (cce.OrchestrationMode, error) {
 	var orchestrationMode cce.OrchestrationMode
 	var err error
 
+	if k8sNetworkNamespaces != "" {
+		k8sClient.NetworkNamespaces = strings.Split(k8sNetworkNamespaces, ",")
+	}
+	if k8sExternalIPRanges != "" {
+		for _, cidr := range strings.Split(k8sExternalIPRanges, ",") {
+			_, ipNet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return orchestrationMode, fmt.Errorf("invalid external IP range %s: %v", cidr, err)
+			}
+			k8sClient.ExternalIPRanges = append(k8sClient.ExternalIPRanges, ipNet)
+		}
+	}
+
 	switch orchMode {
 	case "native":
 		orchestrationMode = cce.OrchestrationModeNative
 	case "kubernetes":
 		orchestrationMode = cce.OrchestrationModeKubernetes
+		k8sClient.NamespaceQuota = k8s.NamespaceQuota(k8sNamespaceCores, k8sNamespaceMemory)
 		err = k8sClient.Ping()
 	case "kubernetes-ovn":
 		orchestrationMode = cce.OrchestrationModeKubernetesOVN
+		k8sClient.NamespaceQuota = k8s.NamespaceQuota(k8sNamespaceCores, k8sNamespaceMemory)
 		err = k8sClient.Ping()
 	default:
 		err = errors.New("Invalid orchestration mode " + orchMode)
@@ -138,12 +218,13 @@ func main() {
 	// Connect to the db and verify
 	db := connectDB(dsn)
 
-	// Initialize self-signed root CA
-	rootCA, err := pki.InitRootCA(filepath.Join(certsDir, "ca"))
+	// Initialize the CA issuing certificates
+	rootCA, err := initCA()
 	if err != nil {
 		log.Alertf("Error initializing Controller CA: %v", err)
 		os.Exit(1)
 	}
+	rootCA.CertLifetime = nodeCertLifetime
 	log.Info("Initialized Controller CA")
 
 	// TODO: Replace printing to STDERR with writing to a file or making the
@@ -164,6 +245,20 @@ func main() {
 		ELAPort:           strconv.Itoa(elaPort),
 		EVAPort:           strconv.Itoa(evaPort),
 		EdgeNodeCreds:     newClientTLSConf(rootCA, "controller.openness"),
+		NodeConns:         node.NewConnManager(nodeConnIdleTimeout, nodeCalls),
+	}
+	if appImageHosts != "" {
+		controller.AppImageHosts = strings.Split(appImageHosts, ",")
+	}
+
+	// Migrate enrolled nodes from MD5 to SHA-256 serials
+	migrated, err := cce.MigrateNodeSerials(context.Background(), controller.PersistenceService)
+	if err != nil {
+		log.Alertf("Error migrating node serials: %v", err)
+		os.Exit(1)
+	}
+	if migrated > 0 {
+		log.Noticef("Migrated %d nodes to SHA-256 serials", migrated)
 	}
 
 	// Create an error group to manage server goroutines
@@ -191,8 +286,53 @@ func main() {
 	statsdAddr := fmt.Sprintf(":%d", statsdPort)
 	eg.Go(serveHTTP(ctx, controller, httpAddr))
 	eg.Go(serveGRPC(ctx, controller, grpcAddr, getGRPCTLS(rootCA)))
-	eg.Go(serveTelemetry(ctx, syslogOut, syslogAddr, newTLSConf(rootCA, telemetry.SyslogSNI)))
-	eg.Go(serveTelemetry(ctx, statsdOut, statsdAddr, newTLSConf(rootCA, telemetry.StatsdSNI)))
+	eg.Go(serveTelemetry(ctx, controller, syslogOut, syslogAddr, newTLSConf(rootCA, telemetry.SyslogSNI)))
+	eg.Go(serveTelemetry(ctx, controller, statsdOut, statsdAddr, newTLSConf(rootCA, telemetry.StatsdSNI)))
+
+	// Monitor node liveness
+	monitor := &liveness.Monitor{
+		Controller: controller,
+		Interval:   nodeProbeInterval,
+		Timeout:    nodeProbeTimeout,
+	}
+	eg.Go(func() error { return monitor.Run(ctx) })
+
+	// Collect node inventories
+	collector := &inventory.Collector{
+		Controller: controller,
+		Interval:   inventoryInterval,
+	}
+	eg.Go(func() error { return collector.Run(ctx) })
+
+	// Advance app rollouts
+	rollouts := &rollout.Manager{
+		Controller: controller,
+		Operator:   &gorilla.NodeAppOperator{Controller: controller},
+		Interval:   rolloutInterval,
+	}
+	eg.Go(func() error { return rollouts.Run(ctx) })
+
+	// Record the changes of the status of Kubernetes apps
+	if orchestrationMode != cce.OrchestrationModeNative {
+		events := k8sClient.Events()
+		eg.Go(func() error {
+			defer k8sClient.Close()
+			for {
+				select {
+				case <-ctx.Done():
+					return nil
+				case e := <-events:
+					msg := fmt.Sprintf("app %s status changed to %s (ready: %t)", e.AppID, e.Status, e.Ready)
+					log.Infof("Node %s: %s", e.NodeID, msg)
+					if err := cce.RaiseEvent(
+						ctx, controller.PersistenceService, e.NodeID, cce.EventAppStatusChanged, msg,
+					); err != nil {
+						log.Errf("Error raising event for node %s: %v", e.NodeID, err)
+					}
+				}
+			}
+		})
+	}
 
 	log.Info("Controller CE ready")
 
@@ -200,7 +340,14 @@ func main() {
 	// shutting down unexpectedly or a SIGINT/SIGTERM being received, causing
 	// all running servers to start shutting down, but Wait does not return
 	// until all shutdowns have completed.
-	if err := eg.Wait(); err != nil && err != errSignalShutdown {
+	err = eg.Wait()
+
+	// No handler can use a node connection anymore, so close them all
+	if cerr := controller.NodeConns.Close(); cerr != nil {
+		log.Errf("Error closing node connections: %v", cerr)
+	}
+
+	if err != nil && err != errSignalShutdown {
 		log.Alert(err)
 		os.Exit(1)
 	}
@@ -234,14 +381,30 @@ func connectDB(dsn string) *sql.DB {
 	return db
 }
 
-// Encode self-signed Controller CA. This is used to manually configure the
-// Appliance by adding the Controller to its trust anchor pool for TLS
-// connections.
+// Initialize the CA issuing certificates: an intermediate CA supplied by the
+// operator, or the self-signed root CA, optionally with an intermediate CA
+// under it.
+func initCA() (*pki.RootCA, error) {
+	if caCertPath != "" {
+		return pki.LoadIntermediateCA(caCertPath, caKeyPath, caChainPath)
+	}
+
+	rootCA, err := pki.InitRootCA(filepath.Join(certsDir, "ca"))
+	if err != nil || !intermediateCA {
+		return rootCA, err
+	}
+	return pki.InitIntermediateCA(filepath.Join(certsDir, "intermediate"), rootCA)
+}
+
+// Encode the self-signed root CA certificate, which is the end of the CA
+// chain. This is used to manually configure the Appliance by adding the
+// Controller to its trust anchor pool for TLS connections.
 func encodeCA(rootCA *pki.RootCA) string {
+	chain, _ := rootCA.CAChain()
 	return string(pem.EncodeToMemory(
 		&pem.Block{
 			Type:  "CERTIFICATE",
-			Bytes: rootCA.Cert.Raw,
+			Bytes: chain[len(chain)-1].Raw,
 		},
 	))
 }
@@ -351,15 +514,21 @@ func serveGRPC(ctx context.Context, controller *cce.Controller, addr string, con
 	}
 }
 
-func serveTelemetry(ctx context.Context, outfile, addr string, conf *tls.Config) func() error {
+func serveTelemetry(
+	ctx context.Context,
+	controller *cce.Controller,
+	outfile, addr string,
+	conf *tls.Config,
+) func() error {
 	lis, err := net.Listen("tcp", addr)
 	if err != nil {
 		log.Alertf("Could not listen on %q: %v", addr, err)
 		os.Exit(1)
 	}
 
-	// Upgrade to TLS
+	// Upgrade to TLS, rejecting revoked node certificates
 	conf.ClientAuth = tls.RequireAndVerifyClientCert
+	conf.VerifyPeerCertificate = cce.VerifyNotRevoked(controller.PersistenceService)
 	lis = tls.NewListener(lis, conf)
 
 	// Shutdown syslog server on exit signal
@@ -396,8 +565,8 @@ func serveTelemetry(ctx context.Context, outfile, addr string, conf *tls.Config)
 
 // Generate a TLS config that handles two server names:
 //
-//     controller.openness: requires and verifies peer cert
-//     enroll.controller.openness: no peer cert required
+//	controller.openness: requires and verifies peer cert
+//	enroll.controller.openness: no peer cert required
 //
 // In the gRPC server the servername will be considered for the particular RPCs
 // authorized to the client.
@@ -451,8 +620,14 @@ func newTLSConf(rootCA *pki.RootCA, sni string) *tls.Config {
 	for _, caCert := range tlsCAChain {
 		tlsChain = append(tlsChain, caCert.Raw)
 	}
+	// Only accept client certificates from the issuing CA, not from any CA
+	// under the same root. The local root CA is trusted as well, as it issued
+	// the certificates of nodes enrolled before -intermediate-ca was set.
 	tlsRoots := x509.NewCertPool()
-	tlsRoots.AddCert(tlsCAChain[len(tlsCAChain)-1])
+	tlsRoots.AddCert(tlsCAChain[0])
+	if caCertPath == "" && len(tlsCAChain) > 1 {
+		tlsRoots.AddCert(tlsCAChain[len(tlsCAChain)-1])
+	}
 	return &tls.Config{
 		Certificates: []tls.Certificate{{
 			Certificate: tlsChain,
@@ -91,6 +91,40 @@ var _ = Describe("Node Auth Service", func() {
 		})
 	})
 
+	Describe("Legacy MD5 serial", func() {
+		It("Should approve the node and migrate its serial to SHA-256", func() {
+			clearGRPCTargetsTable()
+
+			By("Generating node private key")
+			key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Creating a CSR with private key")
+			csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, key)
+			Expect(err).ToNot(HaveOccurred())
+			certReq, err := x509.ParseCertificateRequest(csrDER)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Pre-approving Node by MD5 serial")
+			nodeID := postNodesSerial(cce.LegacyKeyFingerprint(certReq.RawSubjectPublicKeyInfo))
+			Expect(getNode(nodeID).SerialAlgorithm).To(Equal(cce.FingerprintMD5))
+
+			By("Requesting credentials from auth service")
+			_, err = authSvcCli.RequestCredentials(
+				context.TODO(),
+				&authpb.Identity{
+					Csr: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})),
+				},
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Verifying the Node's serial was migrated")
+			node := getNode(nodeID)
+			Expect(node.Serial).To(Equal(cce.KeyFingerprint(certReq.RawSubjectPublicKeyInfo)))
+			Expect(node.SerialAlgorithm).To(Equal(cce.FingerprintSHA256))
+		})
+	})
+
 	Describe("Errors", func() {
 		It("Should return an error if payload is empty", func() {
 			By("Requesting credentials from auth service")
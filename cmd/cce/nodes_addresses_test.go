@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"net"
+	"time"
+
+	cce "github.com/open-ness/edgecontroller"
+	cceGRPC "github.com/open-ness/edgecontroller/grpc"
+	authpb "github.com/open-ness/edgecontroller/pb/auth"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// enrollFrom requests credentials for the key from a local address.
+func enrollFrom(localIP string, key *ecdsa.PrivateKey) {
+	By("Creating a CSR with private key")
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, key)
+	Expect(err).ToNot(HaveOccurred())
+	csrPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})
+
+	caPool := x509.NewCertPool()
+	Expect(caPool.AppendCertsFromPEM(controllerRootPEM)).To(BeTrue())
+
+	By("Connecting to the auth service from " + localIP)
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	dialer := &net.Dialer{LocalAddr: &net.TCPAddr{IP: net.ParseIP(localIP)}}
+	conn, err := grpc.DialContext(
+		ctx,
+		net.JoinHostPort("127.0.0.1", "8081"),
+		grpc.WithTransportCredentials(credentials.NewClientTLSFromCert(caPool, cceGRPC.EnrollmentSNI)),
+		grpc.WithContextDialer(func(ctx context.Context, addr string) (net.Conn, error) {
+			return dialer.DialContext(ctx, "tcp", addr)
+		}),
+		grpc.WithBlock())
+	Expect(err).ToNot(HaveOccurred())
+	defer conn.Close()
+
+	By("Requesting credentials from auth service")
+	_, err = authpb.NewAuthServiceClient(conn).RequestCredentials(
+		context.TODO(),
+		&authpb.Identity{Csr: string(csrPEM)},
+	)
+	Expect(err).ToNot(HaveOccurred())
+}
+
+var _ = Describe("Node addresses", func() {
+	It("Should update the address of a node enrolling again from another address", func() {
+		clearGRPCTargetsTable()
+		nodeCfg := createAndRegisterNode()
+		Expect(getNode(nodeCfg.nodeID).Address).To(Equal("127.0.0.1"))
+
+		enrollFrom("127.0.0.2", nodeCfg.key)
+
+		By("Verifying the current and previous addresses")
+		node := getNode(nodeCfg.nodeID)
+		Expect(node.Address).To(Equal("127.0.0.2"))
+		Expect(node.PreviousAddresses).To(HaveLen(1))
+		Expect(node.PreviousAddresses[0].Address).To(Equal("127.0.0.1"))
+		Expect(node.PreviousAddresses[0].Until).To(BeTemporally("~", time.Now(), 5*time.Second))
+
+		By("Enrolling again from the same address")
+		enrollFrom("127.0.0.2", nodeCfg.key)
+		Expect(getNode(nodeCfg.nodeID).PreviousAddresses).To(HaveLen(1))
+	})
+
+	It("Should free the address of a node when another node takes it over", func() {
+		clearGRPCTargetsTable()
+		nodeCfg := createAndRegisterNode()
+
+		By("Adding a node enrolling from the same address")
+		key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		publicKeyInfo, err := x509.MarshalPKIXPublicKey(key.Public())
+		Expect(err).ToNot(HaveOccurred())
+		otherNodeID := postNodesSerial(cce.KeyFingerprint(publicKeyInfo))
+
+		enrollFrom("127.0.0.1", key)
+
+		By("Verifying the address moved to the other node")
+		Expect(getNode(otherNodeID).Address).To(Equal("127.0.0.1"))
+		Expect(getNode(nodeCfg.nodeID).Address).To(BeEmpty())
+	})
+})
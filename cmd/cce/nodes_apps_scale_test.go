@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/open-ness/edgecontroller/swagger"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Node app namespaces and replicas", func() {
+	var (
+		nodeCfg *nodeConfig
+		appID   string
+	)
+
+	BeforeEach(func() {
+		clearGRPCTargetsTable()
+		nodeCfg = createAndRegisterNode()
+		appID = postApps("container")
+	})
+
+	It("Should reject a namespace in native mode", func() {
+		By("Sending a POST /nodes/{node_id}/apps request")
+		code, body := postJSON(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps", nodeCfg.nodeID),
+			swagger.NodeAppRequest{ID: appID, Namespace: "tenant-a"})
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(Equal("namespace is only supported in kubernetes mode"))
+	})
+
+	It("Should reject replicas in native mode", func() {
+		By("Sending a POST /nodes/{node_id}/apps request")
+		code, body := postJSON(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps", nodeCfg.nodeID),
+			swagger.NodeAppRequest{ID: appID, Replicas: 3})
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(Equal("replicas are only supported in kubernetes mode"))
+	})
+
+	It("Should reject an invalid namespace", func() {
+		By("Sending a POST /nodes/{node_id}/apps request")
+		code, body := postJSON(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps", nodeCfg.nodeID),
+			swagger.NodeAppRequest{ID: appID, Namespace: "kube-system"})
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(Equal("Validation failed: namespace kube-system is reserved"))
+	})
+
+	It("Should reject a scale command without replicas", func() {
+		postNodeApps(nodeCfg.nodeID, appID)
+
+		code, body := patchNodeAppVersion(nodeCfg.nodeID, appID, `{"command": "scale"}`)
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(Equal("Validation failed: replicas cannot be empty for scale"))
+	})
+
+	It("Should reject a scale command in native mode", func() {
+		postNodeApps(nodeCfg.nodeID, appID)
+
+		code, body := patchNodeAppVersion(nodeCfg.nodeID, appID, `{"command": "scale", "replicas": 2}`)
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(Equal("scale is only supported in kubernetes mode"))
+		Expect(getNodeApp(nodeCfg.nodeID, appID).Replicas).To(BeZero())
+	})
+})
@@ -0,0 +1,171 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func postAppVersion(name, version string) (id string) {
+	By("Sending a POST /apps request")
+	resp, err := apiCli.Post(
+		"http://127.0.0.1:8080/apps",
+		"application/json",
+		strings.NewReader(fmt.Sprintf(`
+			{
+				"type": "container",
+				"name": "%s",
+				"version": "%s",
+				"vendor": "smart edge",
+				"description": "my versioned app",
+				"cores": 2,
+				"memory": 1024,
+				"ports": [{"port": 80, "protocol": "tcp"}],
+				"source": "http://www.test.com/my_app_%s.tar.gz"
+			}`, name, version, version)))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Verifying a 201 Created response")
+	Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+	var rb respBody
+	Expect(json.NewDecoder(resp.Body).Decode(&rb)).To(Succeed())
+	return rb.ID
+}
+
+func patchNodeAppVersion(nodeID, appID, reqBody string) (int, string) {
+	By("Sending a PATCH /nodes/{node_id}/apps/{app_id} request")
+	resp, err := apiCli.Patch(
+		fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps/%s", nodeID, appID),
+		"application/json",
+		strings.NewReader(reqBody))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+	return resp.StatusCode, string(body)
+}
+
+func getNodeAppHistory(nodeID, appID string) *swagger.NodeAppHistory {
+	By("Sending a GET /nodes/{node_id}/apps/{app_id}/history request")
+	resp, err := apiCli.Get(
+		fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps/%s/history", nodeID, appID))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Verifying a 200 OK response")
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+	var history swagger.NodeAppHistory
+	Expect(json.NewDecoder(resp.Body).Decode(&history)).To(Succeed())
+	return &history
+}
+
+var _ = Describe("App versions", func() {
+	var (
+		nodeCfg *nodeConfig
+		v1ID    string
+		v2ID    string
+	)
+
+	BeforeEach(func() {
+		clearGRPCTargetsTable()
+		nodeCfg = createAndRegisterNode()
+
+		name := "app " + uuid.New()
+		v2ID = postAppVersion(name, "1.10")
+		v1ID = postAppVersion(name, "1.9")
+		postNodeApps(nodeCfg.nodeID, v1ID)
+	})
+
+	It("Should list the versions of an app", func() {
+		By("Sending a GET /apps/{app_id}/versions request")
+		resp, err := apiCli.Get(fmt.Sprintf("http://127.0.0.1:8080/apps/%s/versions", v2ID))
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		var versions swagger.AppList
+		Expect(json.NewDecoder(resp.Body).Decode(&versions)).To(Succeed())
+		Expect(versions.Apps).To(HaveLen(2))
+		Expect(versions.Apps[0].ID).To(Equal(v1ID))
+		Expect(versions.Apps[1].ID).To(Equal(v2ID))
+	})
+
+	It("Should upgrade the app and roll it back", func() {
+		code, _ := patchNodeAppVersion(nodeCfg.nodeID, v1ID, `{"command": "upgrade", "version": "1.10"}`)
+		Expect(code).To(Equal(http.StatusOK))
+
+		Expect(getNodeApps(nodeCfg.nodeID).NodeApps).To(ConsistOf(swagger.NodeAppSummary{ID: v2ID}))
+		Expect(getNodeApp(nodeCfg.nodeID, v2ID).Status).To(Equal("deployed"))
+
+		history := getNodeAppHistory(nodeCfg.nodeID, v2ID)
+		Expect(history.ID).To(Equal(v2ID))
+		Expect(history.Version).To(Equal("1.10"))
+		Expect(history.Deployments).To(HaveLen(2))
+		Expect(history.Deployments[0].Command).To(Equal("upgrade"))
+		Expect(history.Deployments[1].ID).To(Equal(v1ID))
+		Expect(history.Deployments[1].Command).To(Equal("deploy"))
+
+		By("Starting the upgraded app")
+		code, _ = patchNodeAppVersion(nodeCfg.nodeID, v2ID, `{"command": "start"}`)
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(getNodeApp(nodeCfg.nodeID, v2ID).Status).To(Equal("running"))
+
+		By("Rolling the app back")
+		code, _ = patchNodeAppVersion(nodeCfg.nodeID, v2ID, `{"command": "rollback"}`)
+		Expect(code).To(Equal(http.StatusOK))
+
+		Expect(getNodeApps(nodeCfg.nodeID).NodeApps).To(ConsistOf(swagger.NodeAppSummary{ID: v1ID}))
+		history = getNodeAppHistory(nodeCfg.nodeID, v1ID)
+		Expect(history.Version).To(Equal("1.9"))
+		Expect(history.Deployments).To(HaveLen(3))
+		Expect(history.Deployments[0].Command).To(Equal("rollback"))
+	})
+
+	It("Should keep the version the app rolls back to", func() {
+		code, _ := patchNodeAppVersion(nodeCfg.nodeID, v1ID, `{"command": "upgrade", "version": "1.10"}`)
+		Expect(code).To(Equal(http.StatusOK))
+
+		By("Sending a DELETE /apps/{app_id} request")
+		resp, err := apiCli.Delete(fmt.Sprintf("http://127.0.0.1:8080/apps/%s", v1ID))
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(Equal(fmt.Sprintf("cannot delete app_id %s: record in use in nodes_apps", v1ID)))
+	})
+
+	It("Should reject invalid upgrades and rollbacks", func() {
+		code, body := patchNodeAppVersion(nodeCfg.nodeID, v1ID, `{"command": "upgrade"}`)
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(Equal("Validation failed: version cannot be empty for upgrade"))
+
+		code, body = patchNodeAppVersion(nodeCfg.nodeID, v1ID, `{"command": "upgrade", "version": "2.0"}`)
+		Expect(code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(body).To(Equal(fmt.Sprintf("version 2.0 of app_id %s not found", v1ID)))
+
+		code, body = patchNodeAppVersion(nodeCfg.nodeID, v1ID, `{"command": "upgrade", "version": "1.9"}`)
+		Expect(code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(body).To(Equal(fmt.Sprintf("app_id %s is already at version 1.9", v1ID)))
+
+		code, body = patchNodeAppVersion(nodeCfg.nodeID, v1ID, `{"command": "rollback"}`)
+		Expect(code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(body).To(Equal(fmt.Sprintf("app_id %s has no previous version to roll back to", v1ID)))
+	})
+})
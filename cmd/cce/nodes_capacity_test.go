@@ -0,0 +1,110 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/swagger"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func setNodeAllocatable(nodeID string, allocatable *cce.Resources) {
+	node := getNode(nodeID)
+	node.Allocatable = allocatable
+	reqBody, err := json.Marshal(node)
+	Expect(err).ToNot(HaveOccurred())
+
+	By("Sending a PATCH /nodes/{id} request")
+	resp, err := apiCli.Patch(
+		fmt.Sprintf("http://127.0.0.1:8080/nodes/%s", nodeID),
+		"application/json",
+		strings.NewReader(string(reqBody)))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Verifying a 200 OK response")
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+}
+
+var _ = Describe("Node capacity", func() {
+	var (
+		nodeCfg *nodeConfig
+	)
+
+	BeforeEach(func() {
+		clearGRPCTargetsTable()
+		nodeCfg = createAndRegisterNode()
+		setNodeAllocatable(nodeCfg.nodeID, &cce.Resources{Cores: 6, Memory: 4096})
+	})
+
+	It("Should report the allocatable and committed resources of the node", func() {
+		postNodeApps(nodeCfg.nodeID, postApps("container"))
+
+		node := getNode(nodeCfg.nodeID)
+		Expect(node.Allocatable).To(Equal(&cce.Resources{Cores: 6, Memory: 4096}))
+		Expect(node.Committed).To(Equal(cce.Resources{Cores: 4, Memory: 1024}))
+
+		By("Sending a GET /nodes request")
+		resp, err := apiCli.Get("http://127.0.0.1:8080/nodes")
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		var nodes swagger.NodeList
+		Expect(json.NewDecoder(resp.Body).Decode(&nodes)).To(Succeed())
+		Expect(nodes.Nodes).To(ContainElement(node.NodeSummary))
+	})
+
+	It("Should reject a deployment that over-commits the node", func() {
+		postNodeApps(nodeCfg.nodeID, postApps("container"))
+		appID := postApps("vm")
+
+		By("Sending a POST /nodes/{node_id}/apps request")
+		resp, err := apiCli.Post(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/apps", nodeCfg.nodeID),
+			"application/json",
+			strings.NewReader(fmt.Sprintf(`{"id": "%s"}`, appID)))
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+
+		By("Verifying a 409 Conflict response")
+		Expect(resp.StatusCode).To(Equal(http.StatusConflict))
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(Equal(fmt.Sprintf(
+			"cannot deploy app_id %s to node_id %s: insufficient cores: 4 required, 2 free",
+			appID, nodeCfg.nodeID)))
+
+		Expect(getNode(nodeCfg.nodeID).Committed).To(Equal(cce.Resources{Cores: 4, Memory: 1024}))
+	})
+
+	It("Should reject invalid allocatable resources", func() {
+		node := getNode(nodeCfg.nodeID)
+		node.Allocatable = &cce.Resources{Cores: 0, Memory: 4096}
+		reqBody, err := json.Marshal(node)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Sending a PATCH /nodes/{id} request")
+		resp, err := apiCli.Patch(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s", nodeCfg.nodeID),
+			"application/json",
+			strings.NewReader(string(reqBody)))
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+
+		By("Verifying a 400 Bad Request response")
+		Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(Equal("Validation failed: allocatable.cores must be greater than 0"))
+	})
+})
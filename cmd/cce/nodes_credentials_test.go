@@ -0,0 +1,322 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	cceGRPC "github.com/open-ness/edgecontroller/grpc"
+	authpb "github.com/open-ness/edgecontroller/pb/auth"
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+// asNode returns the transport credentials of the node.
+func asNode(nodeCfg *nodeConfig) grpc.DialOption {
+	keyDER, err := x509.MarshalECPrivateKey(nodeCfg.key)
+	Expect(err).ToNot(HaveOccurred())
+	cert, err := tls.X509KeyPair(
+		[]byte(nodeCfg.creds.Certificate),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}))
+	Expect(err).ToNot(HaveOccurred())
+
+	caPool := x509.NewCertPool()
+	Expect(caPool.AppendCertsFromPEM(controllerRootPEM)).To(BeTrue())
+
+	return grpc.WithTransportCredentials(credentials.NewTLS(&tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      caPool,
+		ServerName:   cceGRPC.SNI,
+	}))
+}
+
+// dialAsNode connects to the Controller with the node's credentials.
+func dialAsNode(nodeCfg *nodeConfig) *grpc.ClientConn {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	conn, err := grpc.DialContext(
+		ctx,
+		net.JoinHostPort("127.0.0.1", "8081"),
+		asNode(nodeCfg),
+		grpc.WithBlock())
+	Expect(err).ToNot(HaveOccurred())
+	return conn
+}
+
+// csrPEM creates a PEM-encoded CSR for the key.
+func csrPEM(key *ecdsa.PrivateKey) string {
+	csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, key)
+	Expect(err).ToNot(HaveOccurred())
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER}))
+}
+
+func parseCertificatePEM(certPEM string) *x509.Certificate {
+	block, _ := pem.Decode([]byte(certPEM))
+	Expect(block).ToNot(BeNil())
+	cert, err := x509.ParseCertificate(block.Bytes)
+	Expect(err).ToNot(HaveOccurred())
+	return cert
+}
+
+func getNodeCredentials(nodeID string) (int, *swagger.NodeCredentials) {
+	By("Sending a GET /nodes/{id}/credentials request")
+	resp, err := apiCli.Get(
+		fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/credentials", nodeID))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+
+	var creds swagger.NodeCredentials
+	By("Unmarshaling the response")
+	Expect(json.NewDecoder(resp.Body).Decode(&creds)).To(Succeed())
+	return resp.StatusCode, &creds
+}
+
+var _ = Describe("Node credentials renewal", func() {
+	Describe("RenewCredentials", func() {
+		It("Should issue a new certificate for the same node", func() {
+			clearGRPCTargetsTable()
+			nodeCfg := createAndRegisterNode()
+			oldCert := parseCertificatePEM(nodeCfg.creds.Certificate)
+
+			conn := dialAsNode(nodeCfg)
+			defer conn.Close()
+
+			By("Creating a CSR with a new private key")
+			key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, key)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Renewing the credentials")
+			creds, err := authpb.NewAuthServiceClient(conn).RenewCredentials(
+				context.TODO(),
+				&authpb.Identity{
+					Csr: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})),
+				},
+			)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Verifying the new certificate")
+			newCert := parseCertificatePEM(creds.Certificate)
+			Expect(newCert.Subject.CommonName).To(Equal(nodeCfg.nodeID))
+			Expect(newCert.SerialNumber).ToNot(Equal(oldCert.SerialNumber))
+			Expect(creds.CaChain).ToNot(BeEmpty())
+
+			By("Verifying the credentials were rotated")
+			statusCode, nodeCreds := getNodeCredentials(nodeCfg.nodeID)
+			Expect(statusCode).To(Equal(http.StatusOK))
+			Expect(nodeCreds.Serial).To(Equal(newCert.SerialNumber.Text(16)))
+			Expect(nodeCreds.NotAfter).To(BeTemporally("~", newCert.NotAfter, time.Second))
+			Expect(nodeCreds.Revoked).To(BeFalse())
+		})
+
+		It("Should not be authorized on the enrollment server name", func() {
+			_, err := authSvcCli.RenewCredentials(context.TODO(), &authpb.Identity{Csr: "123"})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("RequestCredentials", func() {
+		It("Should enroll a node again", func() {
+			clearGRPCTargetsTable()
+			nodeCfg := createAndRegisterNode()
+
+			By("Requesting credentials again")
+			csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, nodeCfg.key)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = authSvcCli.RequestCredentials(
+				context.TODO(),
+				&authpb.Identity{
+					Csr: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER})),
+				},
+			)
+			Expect(err).ToNot(HaveOccurred())
+		})
+	})
+
+	Describe("GET /nodes/{id}/credentials", func() {
+		It("Should return 404 if the node never enrolled", func() {
+			statusCode, _ := getNodeCredentials(postNodesSerial("abc-credentials"))
+			Expect(statusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+
+	Describe("GET /credentials/expiring", func() {
+		getExpiring := func(query string) (int, *swagger.NodeCredentialsList) {
+			By("Sending a GET /credentials/expiring request")
+			resp, err := apiCli.Get("http://127.0.0.1:8080/credentials/expiring" + query)
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+
+			if resp.StatusCode != http.StatusOK {
+				return resp.StatusCode, nil
+			}
+
+			var list swagger.NodeCredentialsList
+			By("Unmarshaling the response")
+			Expect(json.NewDecoder(resp.Body).Decode(&list)).To(Succeed())
+			return resp.StatusCode, &list
+		}
+
+		nodeIDs := func(list *swagger.NodeCredentialsList) []string {
+			var ids []string
+			for _, c := range list.Credentials {
+				ids = append(ids, c.NodeID)
+			}
+			return ids
+		}
+
+		It("Should report nodes whose certificate expires within the window", func() {
+			clearGRPCTargetsTable()
+			nodeCfg := createAndRegisterNode()
+
+			statusCode, list := getExpiring("?within=87600h")
+			Expect(statusCode).To(Equal(http.StatusOK))
+			Expect(nodeIDs(list)).To(ContainElement(nodeCfg.nodeID))
+
+			statusCode, list = getExpiring("?within=1m")
+			Expect(statusCode).To(Equal(http.StatusOK))
+			Expect(nodeIDs(list)).ToNot(ContainElement(nodeCfg.nodeID))
+		})
+
+		It("Should return 400 if the window is not a duration", func() {
+			statusCode, _ := getExpiring("?within=soon")
+			Expect(statusCode).To(Equal(http.StatusBadRequest))
+		})
+	})
+})
+
+var _ = Describe("/nodes/{id}/credentials/revoke", func() {
+	revoke := func(nodeID string) (int, []byte) {
+		By("Sending a POST /nodes/{id}/credentials/revoke request")
+		resp, err := apiCli.Post(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/credentials/revoke", nodeID),
+			"application/json",
+			strings.NewReader(""))
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+
+		By("Reading the response body")
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		return resp.StatusCode, body
+	}
+
+	Describe("POST /nodes/{id}/credentials/revoke", func() {
+		It("Should revoke the node certificate and list it in the CRL", func() {
+			clearGRPCTargetsTable()
+			nodeCfg := createAndRegisterNode()
+
+			cert := parseCertificatePEM(nodeCfg.creds.Certificate)
+
+			statusCode, body := revoke(nodeCfg.nodeID)
+
+			By("Verifying a 200 OK response")
+			Expect(statusCode).To(Equal(http.StatusOK))
+
+			var revoked swagger.RevokedCredentials
+			By("Unmarshaling the response")
+			Expect(json.Unmarshal(body, &revoked)).To(Succeed())
+			Expect(revoked.NodeID).To(Equal(nodeCfg.nodeID))
+			Expect(revoked.Serial).To(Equal(cert.SerialNumber.Text(16)))
+
+			By("Sending a GET /crl request")
+			resp, err := apiCli.Get("http://127.0.0.1:8080/crl")
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+			body, err = ioutil.ReadAll(resp.Body)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Verifying the certificate is listed in the CRL")
+			block, _ := pem.Decode(body)
+			Expect(block).ToNot(BeNil())
+			Expect(block.Type).To(Equal("X509 CRL"))
+			crl, err := x509.ParseRevocationList(block.Bytes)
+			Expect(err).ToNot(HaveOccurred())
+			var serials []string
+			for _, e := range crl.RevokedCertificateEntries {
+				serials = append(serials, e.SerialNumber.Text(16))
+			}
+			Expect(serials).To(ContainElement(revoked.Serial))
+		})
+
+		It("Should reject the revoked certificate", func() {
+			clearGRPCTargetsTable()
+			nodeCfg := createAndRegisterNode()
+
+			statusCode, _ := revoke(nodeCfg.nodeID)
+			Expect(statusCode).To(Equal(http.StatusOK))
+
+			By("Calling an RPC with the revoked certificate")
+			// The handshake itself may fail, so the connection is not awaited
+			conn, err := grpc.Dial(net.JoinHostPort("127.0.0.1", "8081"), asNode(nodeCfg))
+			Expect(err).ToNot(HaveOccurred())
+			defer conn.Close()
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			defer cancel()
+			_, err = authpb.NewAuthServiceClient(conn).RenewCredentials(
+				ctx, &authpb.Identity{Csr: csrPEM(nodeCfg.key)})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Should not enroll the node again until it is approved again", func() {
+			clearGRPCTargetsTable()
+			nodeCfg := createAndRegisterNode()
+
+			statusCode, _ := revoke(nodeCfg.nodeID)
+			Expect(statusCode).To(Equal(http.StatusOK))
+
+			By("Requesting credentials with the approved key")
+			_, err := authSvcCli.RequestCredentials(context.TODO(), &authpb.Identity{Csr: csrPEM(nodeCfg.key)})
+			Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+
+			By("Approving the node again with an enrollment token")
+			code, token := postNodeEnrollmentTokens(nodeCfg.nodeID, "")
+			Expect(code).To(Equal(http.StatusCreated))
+			ctx := metadata.AppendToOutgoingContext(context.TODO(), cceGRPC.EnrollmentTokenKey, token.Token)
+			_, err = authSvcCli.RequestCredentials(ctx, &authpb.Identity{Csr: csrPEM(nodeCfg.key)})
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Requesting credentials with the approved key again")
+			_, err = authSvcCli.RequestCredentials(context.TODO(), &authpb.Identity{Csr: csrPEM(nodeCfg.key)})
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should return 404 if the node does not exist", func() {
+			statusCode, _ := revoke(uuid.New())
+			Expect(statusCode).To(Equal(http.StatusNotFound))
+		})
+
+		It("Should return 404 if the node never enrolled", func() {
+			statusCode, _ := revoke(postNodesSerial("abc-revoke"))
+			Expect(statusCode).To(Equal(http.StatusNotFound))
+		})
+	})
+})
@@ -0,0 +1,100 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("/nodes/{id}/decommission", func() {
+	decommission := func(nodeID, query string) (int, *swagger.NodeDecommission) {
+		By("Sending a POST /nodes/{id}/decommission request")
+		resp, err := apiCli.Post(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/decommission%s", nodeID, query),
+			"application/json",
+			strings.NewReader(""))
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+
+		By("Reading the response body")
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+
+		if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusBadGateway {
+			return resp.StatusCode, nil
+		}
+
+		var report swagger.NodeDecommission
+		By("Unmarshaling the response")
+		Expect(json.Unmarshal(body, &report)).To(Succeed())
+		return resp.StatusCode, &report
+	}
+
+	Describe("POST /nodes/{id}/decommission", func() {
+		DescribeTable("200 OK",
+			func(query string) {
+				clearGRPCTargetsTable()
+				nodeCfg := createAndRegisterNode()
+				appID := postApps("container")
+				postNodeApps(nodeCfg.nodeID, appID)
+				patchNodeDNS(nodeCfg.nodeID)
+
+				statusCode, report := decommission(nodeCfg.nodeID, query)
+
+				By("Verifying a 200 OK response")
+				Expect(statusCode).To(Equal(http.StatusOK))
+
+				By("Verifying all steps were done")
+				Expect(report.NodeID).To(Equal(nodeCfg.nodeID))
+				Expect(report.Completed).To(BeTrue())
+				Expect(report.Steps).To(HaveLen(6))
+				for _, step := range report.Steps {
+					Expect(step.Status).To(Equal("done"), step.Name)
+				}
+
+				By("Verifying the node was deleted")
+				resp, err := apiCli.Get(
+					fmt.Sprintf("http://127.0.0.1:8080/nodes/%s", nodeCfg.nodeID))
+				Expect(err).ToNot(HaveOccurred())
+				defer resp.Body.Close()
+				Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("POST /nodes/{id}/decommission", ""),
+			Entry("POST /nodes/{id}/decommission?force", "?force"),
+		)
+
+		DescribeTable("400 Bad Request",
+			func() {
+				nodeID := postNodesSerial("abc-decommission")
+
+				statusCode, _ := decommission(nodeID, "?force=maybe")
+
+				By("Verifying a 400 Bad Request response")
+				Expect(statusCode).To(Equal(http.StatusBadRequest))
+			},
+			Entry("POST /nodes/{id}/decommission with invalid force"),
+		)
+
+		DescribeTable("404 Not Found",
+			func() {
+				statusCode, _ := decommission(uuid.New(), "")
+
+				By("Verifying a 404 Not Found response")
+				Expect(statusCode).To(Equal(http.StatusNotFound))
+			},
+			Entry("POST /nodes/{id}/decommission with nonexistent ID"),
+		)
+	})
+})
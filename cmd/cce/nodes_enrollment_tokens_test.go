@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	cce "github.com/open-ness/edgecontroller"
+	cceGRPC "github.com/open-ness/edgecontroller/grpc"
+	authpb "github.com/open-ness/edgecontroller/pb/auth"
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func postNodeEnrollmentTokens(nodeID, query string) (int, *swagger.EnrollmentToken) {
+	By("Sending a POST /nodes/{id}/enrollment-tokens request")
+	resp, err := apiCli.Post(
+		fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/enrollment-tokens%s", nodeID, query),
+		"application/json",
+		nil)
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return resp.StatusCode, nil
+	}
+
+	By("Reading the response body")
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+
+	var token swagger.EnrollmentToken
+	By("Unmarshaling the response")
+	Expect(json.Unmarshal(body, &token)).To(Succeed())
+	return resp.StatusCode, &token
+}
+
+var _ = Describe("Enrollment Tokens", func() {
+	var (
+		key    *ecdsa.PrivateKey
+		csrPEM string
+		serial string
+	)
+
+	BeforeEach(func() {
+		var err error
+		By("Generating node private key")
+		key, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+
+		By("Creating a CSR with private key")
+		csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, key)
+		Expect(err).ToNot(HaveOccurred())
+		csrPEM = string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE REQUEST", Bytes: csrDER}))
+
+		certReq, err := x509.ParseCertificateRequest(csrDER)
+		Expect(err).ToNot(HaveOccurred())
+		serial = cce.KeyFingerprint(certReq.RawSubjectPublicKeyInfo)
+	})
+
+	requestCredentials := func(token string) (*authpb.Credentials, error) {
+		By("Requesting credentials with the enrollment token")
+		ctx := metadata.AppendToOutgoingContext(context.TODO(), cceGRPC.EnrollmentTokenKey, token)
+		return authSvcCli.RequestCredentials(ctx, &authpb.Identity{Csr: csrPEM})
+	}
+
+	Describe("POST /nodes/{node_id}/enrollment-tokens", func() {
+		It("Should enroll a node without a registered serial", func() {
+			clearGRPCTargetsTable()
+			nodeID := postNodesSerial("")
+
+			code, token := postNodeEnrollmentTokens(nodeID, "?ttl=10m")
+			Expect(code).To(Equal(http.StatusCreated))
+			Expect(token.NodeID).To(Equal(nodeID))
+			Expect(token.Token).ToNot(BeEmpty())
+			Expect(token.ExpiresAt).To(BeTemporally("~", time.Now().Add(10*time.Minute), 5*time.Second))
+
+			creds, err := requestCredentials(token.Token)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Verifying the certificate was issued to the node")
+			Expect(parseCertificatePEM(creds.Certificate).Subject.CommonName).To(Equal(nodeID))
+
+			By("Verifying the node is bound to its key")
+			Expect(getNode(nodeID).Serial).To(Equal(serial))
+		})
+
+		It("Should only accept a token once", func() {
+			clearGRPCTargetsTable()
+			nodeID := postNodesSerial("")
+
+			code, token := postNodeEnrollmentTokens(nodeID, "")
+			Expect(code).To(Equal(http.StatusCreated))
+
+			_, err := requestCredentials(token.Token)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Reusing the enrollment token")
+			_, err = requestCredentials(token.Token)
+			Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+		})
+
+		It("Should not bind a key already bound to another node", func() {
+			clearGRPCTargetsTable()
+			postNodesSerial(serial)
+			nodeID := postNodesSerial("")
+
+			code, token := postNodeEnrollmentTokens(nodeID, "")
+			Expect(code).To(Equal(http.StatusCreated))
+
+			_, err := requestCredentials(token.Token)
+			Expect(status.Code(err)).To(Equal(codes.AlreadyExists))
+			Expect(getNode(nodeID).Serial).To(BeEmpty())
+		})
+
+		It("Should reject an unknown token", func() {
+			_, err := requestCredentials("unknown")
+			Expect(status.Code(err)).To(Equal(codes.Unauthenticated))
+		})
+
+		DescribeTable("400 Bad Request",
+			func(query string) {
+				nodeID := postNodesSerial("")
+				code, _ := postNodeEnrollmentTokens(nodeID, query)
+				Expect(code).To(Equal(http.StatusBadRequest))
+			},
+			Entry("invalid ttl", "?ttl=soon"),
+			Entry("zero ttl", "?ttl=0s"),
+			Entry("ttl over the maximum", "?ttl=25h"),
+		)
+
+		It("Should return 404 if the node does not exist", func() {
+			code, _ := postNodeEnrollmentTokens(uuid.New(), "")
+			Expect(code).To(Equal(http.StatusNotFound))
+		})
+	})
+})
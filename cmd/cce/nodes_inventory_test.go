@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func getNodeInventory(nodeID, query string) (int, *swagger.NodeInventory) {
+	By("Sending a GET /nodes/{id}/inventory request")
+	resp, err := apiCli.Get(
+		fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/inventory%s", nodeID, query))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return resp.StatusCode, nil
+	}
+
+	By("Reading the response body")
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+
+	var inv swagger.NodeInventory
+	By("Unmarshaling the response")
+	Expect(json.Unmarshal(body, &inv)).To(Succeed())
+	return resp.StatusCode, &inv
+}
+
+var _ = Describe("GET /nodes/{node_id}/inventory", func() {
+	It("Should collect and cache the inventory of a node", func() {
+		clearGRPCTargetsTable()
+		nodeCfg := createAndRegisterNode()
+
+		code, inv := getNodeInventory(nodeCfg.nodeID, "?refresh=true")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(inv.NodeID).To(Equal(nodeCfg.nodeID))
+		Expect(inv.CollectedAt).ToNot(BeZero())
+		Expect(inv.Interfaces).ToNot(BeEmpty())
+		Expect(inv.Interfaces[0].ID).To(Equal("if0"))
+		Expect(inv.Apps).To(BeEmpty())
+		Expect(inv.History).To(BeEmpty())
+
+		By("Getting the cached inventory")
+		code, cached := getNodeInventory(nodeCfg.nodeID, "")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(cached.CollectedAt).To(Equal(inv.CollectedAt))
+		Expect(cached.Interfaces).To(Equal(inv.Interfaces))
+	})
+
+	It("Should return 400 for an invalid refresh value", func() {
+		clearGRPCTargetsTable()
+		nodeCfg := createAndRegisterNode()
+
+		code, _ := getNodeInventory(nodeCfg.nodeID, "?refresh=maybe")
+		Expect(code).To(Equal(http.StatusBadRequest))
+	})
+
+	It("Should return 404 if the node does not exist", func() {
+		code, _ := getNodeInventory(uuid.New(), "")
+		Expect(code).To(Equal(http.StatusNotFound))
+	})
+})
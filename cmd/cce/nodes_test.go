@@ -10,6 +10,7 @@ import (
 	"net/http"
 	"strings"
 
+	cce "github.com/open-ness/edgecontroller"
 	"github.com/open-ness/edgecontroller/swagger"
 	"github.com/open-ness/edgecontroller/uuid"
 
@@ -100,14 +101,6 @@ var _ = Describe("/nodes", func() {
 					"serial": "abc123"
 				}`,
 				"Validation failed: location cannot be empty"),
-			Entry(
-				"POST /nodes without serial",
-				`
-				{
-					"name": "node123",
-					"location": "smart edge lab"
-				}`,
-				"Validation failed: serial cannot be empty"),
 		)
 	})
 
@@ -143,10 +136,12 @@ var _ = Describe("/nodes", func() {
 				By("Verifying the 2 created nodes were returned")
 				Expect(nodes.Nodes).To(ContainElement(
 					swagger.NodeSummary{
-						ID:       nodeCfg.nodeID,
-						Name:     "Test Node 1",
-						Location: "Localhost port 42101",
-						Serial:   nodeCfg.serial,
+						ID:              nodeCfg.nodeID,
+						Name:            "Test Node 1",
+						Location:        "Localhost port 42101",
+						Serial:          nodeCfg.serial,
+						SerialAlgorithm: cce.FingerprintSHA256,
+						Status:          "unknown",
 					}))
 			},
 			Entry("GET /nodes"),
@@ -164,11 +159,14 @@ var _ = Describe("/nodes", func() {
 				Expect(node).To(Equal(
 					&swagger.NodeDetail{
 						NodeSummary: swagger.NodeSummary{
-							ID:       nodeCfg.nodeID,
-							Name:     "Test Node 1",
-							Location: "Localhost port 42101",
-							Serial:   nodeCfg.serial,
+							ID:              nodeCfg.nodeID,
+							Name:            "Test Node 1",
+							Location:        "Localhost port 42101",
+							Serial:          nodeCfg.serial,
+							SerialAlgorithm: cce.FingerprintSHA256,
+							Status:          "unknown",
 						},
+						Address: "127.0.0.1",
 					},
 				))
 			},
@@ -277,15 +275,6 @@ var _ = Describe("/nodes", func() {
 				}
 				`,
 				"Validation failed: location cannot be empty"),
-			Entry("PATCH /nodes/{node_id} without serial",
-				`
-				{
-					"id": "%s",
-					"name": "node123",
-					"location": "smart edge lab"
-				}
-				`,
-				"Validation failed: serial cannot be empty"),
 		)
 
 		DescribeTable("404 Not Found",
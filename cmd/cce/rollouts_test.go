@@ -0,0 +1,181 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+func postRollouts(reqBody string) (int, string) {
+	By("Sending a POST /rollouts request")
+	resp, err := apiCli.Post(
+		"http://127.0.0.1:8080/rollouts",
+		"application/json",
+		strings.NewReader(reqBody))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+	return resp.StatusCode, string(body)
+}
+
+func getRollout(id string) *swagger.RolloutDetail {
+	resp, err := apiCli.Get(fmt.Sprintf("http://127.0.0.1:8080/rollouts/%s", id))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+	var rollout swagger.RolloutDetail
+	Expect(json.NewDecoder(resp.Body).Decode(&rollout)).To(Succeed())
+	return &rollout
+}
+
+func postRolloutState(id, action string) (int, string) {
+	By(fmt.Sprintf("Sending a POST /rollouts/{rollout_id}/%s request", action))
+	resp, err := apiCli.Post(
+		fmt.Sprintf("http://127.0.0.1:8080/rollouts/%s/%s", id, action), "application/json", nil)
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+	return resp.StatusCode, string(body)
+}
+
+var _ = Describe("Rollouts", func() {
+	var (
+		nodeIDs []string
+		v1ID    string
+		v2ID    string
+	)
+
+	BeforeEach(func() {
+		clearGRPCTargetsTable()
+		nodeIDs = []string{createAndRegisterNode().nodeID}
+
+		name := "app " + uuid.New()
+		v1ID = postAppVersion(name, "1.0")
+		v2ID = postAppVersion(name, "2.0")
+		postNodeApps(nodeIDs[0], v1ID)
+	})
+
+	It("Should roll out a version to the nodes running the app", func() {
+		code, body := postRollouts(fmt.Sprintf(`{"app_id": "%s", "canary": 1}`, v2ID))
+		Expect(code).To(Equal(http.StatusCreated))
+
+		var rb respBody
+		Expect(json.Unmarshal([]byte(body), &rb)).To(Succeed())
+
+		By("Waiting for the rollout to complete")
+		Eventually(func() string {
+			return getRollout(rb.ID).State
+		}, 15*time.Second, 500*time.Millisecond).Should(Equal("completed"))
+
+		rollout := getRollout(rb.ID)
+		Expect(rollout.Waves).To(Equal(1))
+		Expect(rollout.Targets).To(HaveLen(1))
+		Expect(rollout.Targets[0].NodeID).To(Equal(nodeIDs[0]))
+		Expect(rollout.Targets[0].FromAppID).To(Equal(v1ID))
+		Expect(rollout.Targets[0].Status).To(Equal("succeeded"))
+		Expect(rollout.Targets[0].Command).To(Equal("upgrade"))
+
+		Expect(getNodeApps(nodeIDs[0]).NodeApps).To(ConsistOf(swagger.NodeAppSummary{ID: v2ID}))
+		Expect(getNodeApp(nodeIDs[0], v2ID).Status).To(Equal("running"))
+
+		By("Refusing to pause a completed rollout")
+		code, body = postRolloutState(rb.ID, "pause")
+		Expect(code).To(Equal(http.StatusConflict))
+		Expect(body).To(Equal(fmt.Sprintf("rollout_id %s is completed, not running", rb.ID)))
+	})
+
+	It("Should pause, resume and delete a rollout", func() {
+		code, body := postRollouts(fmt.Sprintf(`{"app_id": "%s", "node_ids": ["%s"]}`, v2ID, nodeIDs[0]))
+		Expect(code).To(Equal(http.StatusCreated))
+
+		var rb respBody
+		Expect(json.Unmarshal([]byte(body), &rb)).To(Succeed())
+
+		code, _ = postRolloutState(rb.ID, "pause")
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(getRollout(rb.ID).State).To(Equal("paused"))
+
+		By("Refusing another rollout of the app")
+		code, body = postRollouts(fmt.Sprintf(`{"app_id": "%s"}`, v1ID))
+		Expect(code).To(Equal(http.StatusConflict))
+		Expect(body).To(Equal(fmt.Sprintf("rollout_id %s of app_id %s is paused", rb.ID, v2ID)))
+
+		code, _ = postRolloutState(rb.ID, "resume")
+		Expect(code).To(Equal(http.StatusOK))
+		Eventually(func() string {
+			return getRollout(rb.ID).State
+		}, 15*time.Second, 500*time.Millisecond).Should(Equal("completed"))
+
+		By("Sending a DELETE /rollouts/{rollout_id} request")
+		resp, err := apiCli.Delete(fmt.Sprintf("http://127.0.0.1:8080/rollouts/%s", rb.ID))
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		resp, err = apiCli.Get(fmt.Sprintf("http://127.0.0.1:8080/rollouts/%s", rb.ID))
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusNotFound))
+	})
+
+	It("Should list the rollouts", func() {
+		code, body := postRollouts(fmt.Sprintf(`{"app_id": "%s"}`, v2ID))
+		Expect(code).To(Equal(http.StatusCreated))
+
+		var rb respBody
+		Expect(json.Unmarshal([]byte(body), &rb)).To(Succeed())
+
+		By("Sending a GET /rollouts request")
+		resp, err := apiCli.Get("http://127.0.0.1:8080/rollouts")
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+		var rollouts swagger.RolloutList
+		Expect(json.NewDecoder(resp.Body).Decode(&rollouts)).To(Succeed())
+		Expect(rollouts.Rollouts).To(ContainElement(WithTransform(
+			func(r swagger.RolloutSummary) string { return r.ID }, Equal(rb.ID))))
+	})
+
+	DescribeTable("Rejected rollouts",
+		func(reqBody func() string, expectedCode int, expectedBody func() string) {
+			code, body := postRollouts(reqBody())
+			Expect(code).To(Equal(expectedCode))
+			Expect(body).To(Equal(expectedBody()))
+		},
+		Entry("POST /rollouts with an invalid app ID",
+			func() string { return `{"app_id": "123"}` },
+			http.StatusBadRequest,
+			func() string { return "Validation failed: app_id not a valid uuid" }),
+		Entry("POST /rollouts with an invalid on_failure",
+			func() string { return fmt.Sprintf(`{"app_id": "%s", "on_failure": "retry"}`, v2ID) },
+			http.StatusBadRequest,
+			func() string { return "Validation failed: on_failure must be one of [pause, rollback]" }),
+		Entry("POST /rollouts with an unknown node",
+			func() string { return fmt.Sprintf(`{"app_id": "%s", "node_ids": ["%s"]}`, v2ID, v1ID) },
+			http.StatusNotFound,
+			func() string { return fmt.Sprintf("node_id %s not found", v1ID) }),
+		Entry("POST /rollouts of the version already deployed",
+			func() string { return fmt.Sprintf(`{"app_id": "%s"}`, v1ID) },
+			http.StatusUnprocessableEntity,
+			func() string { return fmt.Sprintf("no node to roll out app_id %s to", v1ID) }),
+	)
+})
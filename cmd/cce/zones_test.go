@@ -0,0 +1,292 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package main_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/open-ness/edgecontroller/uuid"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func postZones(name string) (id string) {
+	By("Sending a POST /zones request")
+	resp, err := apiCli.Post(
+		"http://127.0.0.1:8080/zones",
+		"application/json",
+		strings.NewReader(fmt.Sprintf(`
+			{
+				"name": "%s",
+				"description": "%s zone"
+			}`, name, name)))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Verifying a 201 Created response")
+	Expect(resp.StatusCode).To(Equal(http.StatusCreated))
+
+	By("Reading the response body")
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+
+	var rb respBody
+
+	By("Unmarshaling the response")
+	Expect(json.Unmarshal(body, &rb)).To(Succeed())
+
+	return rb.ID
+}
+
+func getZone(id string) *swagger.ZoneDetail {
+	By("Sending a GET /zones/{id} request")
+	resp, err := apiCli.Get(fmt.Sprintf("http://127.0.0.1:8080/zones/%s", id))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Verifying a 200 OK response")
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+	By("Reading the response body")
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+
+	var zone swagger.ZoneDetail
+
+	By("Unmarshaling the response")
+	Expect(json.Unmarshal(body, &zone)).To(Succeed())
+
+	return &zone
+}
+
+func postNodeZones(nodeID, zoneID string) int {
+	By("Sending a POST /nodes/{node_id}/zones request")
+	resp, err := apiCli.Post(
+		fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/zones", nodeID),
+		"application/json",
+		strings.NewReader(fmt.Sprintf(`{"id": "%s"}`, zoneID)))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	return resp.StatusCode
+}
+
+func getNodeZones(nodeID string) *swagger.ZoneList {
+	By("Sending a GET /nodes/{node_id}/zones request")
+	resp, err := apiCli.Get(fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/zones", nodeID))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	By("Verifying a 200 OK response")
+	Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+	By("Reading the response body")
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+
+	var zones swagger.ZoneList
+
+	By("Unmarshaling the response")
+	Expect(json.Unmarshal(body, &zones)).To(Succeed())
+
+	return &zones
+}
+
+func deleteURL(url string) (int, string) {
+	resp, err := apiCli.Delete(url)
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+
+	return resp.StatusCode, string(body)
+}
+
+// patchNodeInterfaceZones sets the zones of the first interface of the node.
+func patchNodeInterfaceZones(nodeID string, zones []string) (int, string) {
+	ifaces := getNodeInterfaces(nodeID)
+	ifaces.Interfaces[0].Zones = zones
+	reqBody, err := json.Marshal(ifaces)
+	Expect(err).ToNot(HaveOccurred())
+
+	By("Sending a PATCH /nodes/{node_id}/interfaces request")
+	resp, err := apiCli.Patch(
+		fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/interfaces", nodeID),
+		"application/json",
+		strings.NewReader(string(reqBody)))
+	Expect(err).ToNot(HaveOccurred())
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	Expect(err).ToNot(HaveOccurred())
+
+	return resp.StatusCode, string(body)
+}
+
+var _ = Describe("/zones", func() {
+	Describe("POST /zones", func() {
+		It("Should create a zone", func() {
+			name := "zone-" + uuid.New()
+			zoneID := postZones(name)
+
+			zone := getZone(zoneID)
+			Expect(zone).To(Equal(&swagger.ZoneDetail{
+				ZoneSummary: swagger.ZoneSummary{
+					ID:          zoneID,
+					Name:        name,
+					Description: name + " zone",
+				},
+				Nodes: []string{},
+			}))
+		})
+
+		It("Should return 422 for a duplicate name", func() {
+			name := "zone-" + uuid.New()
+			postZones(name)
+
+			resp, err := apiCli.Post(
+				"http://127.0.0.1:8080/zones",
+				"application/json",
+				strings.NewReader(fmt.Sprintf(`{"name": "%s"}`, name)))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+
+			By("Verifying a 422 response")
+			Expect(resp.StatusCode).To(Equal(http.StatusUnprocessableEntity))
+		})
+
+		It("Should return 400 without a name", func() {
+			resp, err := apiCli.Post(
+				"http://127.0.0.1:8080/zones",
+				"application/json",
+				strings.NewReader(`{"description": "no name"}`))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+
+			By("Verifying a 400 response")
+			Expect(resp.StatusCode).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Describe("PATCH /zones/{zone_id}", func() {
+		It("Should update the zone on the nodes it is assigned to", func() {
+			clearGRPCTargetsTable()
+			nodeCfg := createAndRegisterNode()
+			name := "zone-" + uuid.New()
+			zoneID := postZones(name)
+			Expect(postNodeZones(nodeCfg.nodeID, zoneID)).To(Equal(http.StatusCreated))
+
+			By("Sending a PATCH /zones/{zone_id} request")
+			resp, err := apiCli.Patch(
+				fmt.Sprintf("http://127.0.0.1:8080/zones/%s", zoneID),
+				"application/json",
+				strings.NewReader(fmt.Sprintf(`{"name": "%s", "description": "updated"}`, name)))
+			Expect(err).ToNot(HaveOccurred())
+			defer resp.Body.Close()
+
+			By("Verifying a 200 OK response")
+			Expect(resp.StatusCode).To(Equal(http.StatusOK))
+
+			zone := getZone(zoneID)
+			Expect(zone.Description).To(Equal("updated"))
+			Expect(zone.Nodes).To(Equal([]string{nodeCfg.nodeID}))
+		})
+	})
+
+	Describe("DELETE /zones/{zone_id}", func() {
+		It("Should delete an unassigned zone", func() {
+			zoneID := postZones("zone-" + uuid.New())
+
+			code, _ := deleteURL(fmt.Sprintf("http://127.0.0.1:8080/zones/%s", zoneID))
+			Expect(code).To(Equal(http.StatusOK))
+
+			code, _ = deleteURL(fmt.Sprintf("http://127.0.0.1:8080/zones/%s", zoneID))
+			Expect(code).To(Equal(http.StatusNotFound))
+		})
+
+		It("Should return 422 if the zone is assigned to a node", func() {
+			clearGRPCTargetsTable()
+			nodeCfg := createAndRegisterNode()
+			zoneID := postZones("zone-" + uuid.New())
+			Expect(postNodeZones(nodeCfg.nodeID, zoneID)).To(Equal(http.StatusCreated))
+
+			code, body := deleteURL(fmt.Sprintf("http://127.0.0.1:8080/zones/%s", zoneID))
+			Expect(code).To(Equal(http.StatusUnprocessableEntity))
+			Expect(body).To(Equal(fmt.Sprintf(
+				"cannot delete zone_id %s: record in use in nodes_zones", zoneID)))
+		})
+	})
+})
+
+var _ = Describe("/nodes/{node_id}/zones", func() {
+	var (
+		nodeCfg *nodeConfig
+		zoneID  string
+	)
+
+	BeforeEach(func() {
+		clearGRPCTargetsTable()
+		nodeCfg = createAndRegisterNode()
+		zoneID = postZones("zone-" + uuid.New())
+	})
+
+	It("Should assign a zone to a node", func() {
+		Expect(postNodeZones(nodeCfg.nodeID, zoneID)).To(Equal(http.StatusCreated))
+
+		zones := getNodeZones(nodeCfg.nodeID)
+		Expect(zones.Zones).To(HaveLen(1))
+		Expect(zones.Zones[0].ID).To(Equal(zoneID))
+
+		By("Sending a GET /nodes/{node_id}/zones/{zone_id} request")
+		resp, err := apiCli.Get(
+			fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/zones/%s", nodeCfg.nodeID, zoneID))
+		Expect(err).ToNot(HaveOccurred())
+		defer resp.Body.Close()
+		Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	})
+
+	It("Should return 422 if the zone is already assigned", func() {
+		Expect(postNodeZones(nodeCfg.nodeID, zoneID)).To(Equal(http.StatusCreated))
+		Expect(postNodeZones(nodeCfg.nodeID, zoneID)).To(Equal(http.StatusUnprocessableEntity))
+	})
+
+	It("Should return 404 if the zone does not exist", func() {
+		Expect(postNodeZones(nodeCfg.nodeID, uuid.New())).To(Equal(http.StatusNotFound))
+	})
+
+	It("Should only allow interfaces in zones of the node", func() {
+		By("Adding an interface to an unassigned zone")
+		code, body := patchNodeInterfaceZones(nodeCfg.nodeID, []string{zoneID})
+		Expect(code).To(Equal(http.StatusBadRequest))
+		Expect(body).To(Equal(fmt.Sprintf(
+			"Validation failed: network_interfaces[0].zones[0] %s is not a zone of the node", zoneID)))
+
+		By("Adding an interface to an assigned zone")
+		Expect(postNodeZones(nodeCfg.nodeID, zoneID)).To(Equal(http.StatusCreated))
+		code, _ = patchNodeInterfaceZones(nodeCfg.nodeID, []string{zoneID})
+		Expect(code).To(Equal(http.StatusOK))
+		Expect(getNodeInterfaces(nodeCfg.nodeID).Interfaces[0].Zones).To(Equal([]string{zoneID}))
+
+		By("Removing the zone while the interface is in it")
+		url := fmt.Sprintf("http://127.0.0.1:8080/nodes/%s/zones/%s", nodeCfg.nodeID, zoneID)
+		code, body = deleteURL(url)
+		Expect(code).To(Equal(http.StatusUnprocessableEntity))
+		Expect(body).To(Equal(fmt.Sprintf(
+			"cannot delete zone_id %s: zone in use by network interface if0", zoneID)))
+
+		By("Removing the zone once the interface left it")
+		code, _ = patchNodeInterfaceZones(nodeCfg.nodeID, nil)
+		Expect(code).To(Equal(http.StatusOK))
+		code, _ = deleteURL(url)
+		Expect(code).To(Equal(http.StatusNoContent))
+		Expect(getNodeZones(nodeCfg.nodeID).Zones).To(BeEmpty())
+	})
+})
@@ -16,11 +16,12 @@ import (
 var log = logger.DefaultLogger.WithField("nfd-master", nil)
 
 var (
-	dsn        string
-	grpcPort   int
-	caCertPath string
-	caKeyPath  string
-	sni        string
+	dsn         string
+	grpcPort    int
+	caCertPath  string
+	caKeyPath   string
+	caChainPath string
+	sni         string
 )
 
 func init() {
@@ -28,6 +29,8 @@ func init() {
 	flag.IntVar(&grpcPort, "grpcPort", 8082, "NFD Server gRPC port")
 	flag.StringVar(&caCertPath, "caCertPath", "/ca/cert.pem", "Root CA certificate file path")
 	flag.StringVar(&caKeyPath, "caKeyPath", "/ca/key.pem", "Root CA private key file path")
+	flag.StringVar(&caChainPath, "caChainPath", "",
+		"Issuers of the CA certificate when it is an intermediate CA, ending with the root CA")
 	flag.StringVar(&sni, "sni", "nfd-master.openness", "Server name for NFD-master certificate certificate")
 }
 
@@ -48,11 +51,12 @@ func main() {
 	}()
 
 	nfdSrv := &nfd.ServerNFD{
-		Endpoint:   grpcPort,
-		CaCertPath: caCertPath,
-		CaKeyPath:  caKeyPath,
-		Sni:        sni,
-		Dsn:        dsn,
+		Endpoint:    grpcPort,
+		CaCertPath:  caCertPath,
+		CaKeyPath:   caKeyPath,
+		CaChainPath: caChainPath,
+		Sni:         sni,
+		Dsn:         dsn,
 	}
 
 	err := nfdSrv.ServeGRPC(ctx)
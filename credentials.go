@@ -4,23 +4,45 @@
 package cce
 
 import (
-	"crypto/md5" //nolint:gosec
+	"context"
 	"crypto/x509"
-	"encoding/base64"
+	"crypto/x509/pkix"
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"math/big"
 	"strings"
+	"time"
 )
 
+// ErrCertificateRevoked is returned when a node presents a revoked
+// certificate.
+var ErrCertificateRevoked = errors.New("certificate revoked")
+
+// revocationCheckTimeout bounds the lookup of a certificate's revocation
+// during a TLS handshake.
+const revocationCheckTimeout = 5 * time.Second
+
 // Credentials defines a response for a request to obtain authentication
 // credentials. These credentials may be used to further communicate with
 // endpoint(s) that are protected by a form of authentication.
 type Credentials struct {
-	// ID is the base64-encoded MD5 hash of the certificate's public key.
+	// ID is the fingerprint of the certificate's public key, see
+	// KeyFingerprint. Legacy MD5 fingerprints are accepted as well.
 	ID string `json:"id"`
 	// Certificate is a PEM-encoded X.509 certificate.
 	Certificate string `json:"certificate"`
+	// Revoked lists the certificates issued to the node that were revoked.
+	// It is kept when the node enrolls again.
+	Revoked []RevokedCertificate `json:"revoked,omitempty"`
+}
+
+// RevokedCertificate is a node certificate that is no longer accepted.
+type RevokedCertificate struct {
+	// Serial is the hex-encoded serial number of the certificate.
+	Serial string `json:"serial"`
+	// RevokedAt is the time the certificate was revoked.
+	RevokedAt time.Time `json:"revoked_at"`
 }
 
 // GetTableName returns the name of the table this entity is saved in.
@@ -62,10 +84,7 @@ func (c *Credentials) Validate() error {
 		return errors.New("certificate public key not a valid public key")
 	}
 
-	// gosec: not hashing user input/passwords
-	hash := md5.Sum(pubKey) //nolint:gosec
-
-	if c.ID != base64.RawURLEncoding.EncodeToString(hash[:]) {
+	if !MatchesKeyFingerprint(c.ID, pubKey) {
 		return errors.New("id not derived from certificate public key")
 	}
 
@@ -82,3 +101,124 @@ Credentials[
 		c.Certificate,
 	)
 }
+
+// ParseCertificate parses the PEM-encoded certificate.
+func (c *Credentials) ParseCertificate() (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(c.Certificate))
+	if block == nil {
+		return nil, errors.New("certificate not PEM-encoded")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// Revoke revokes the current certificate. Revoking it again has no effect.
+func (c *Credentials) Revoke(at time.Time) (*RevokedCertificate, error) {
+	cert, err := c.ParseCertificate()
+	if err != nil {
+		return nil, err
+	}
+
+	serial := cert.SerialNumber.Text(16)
+	for i := range c.Revoked {
+		if c.Revoked[i].Serial == serial {
+			return &c.Revoked[i], nil
+		}
+	}
+	c.Revoked = append(c.Revoked, RevokedCertificate{Serial: serial, RevokedAt: at})
+	return &c.Revoked[len(c.Revoked)-1], nil
+}
+
+// IsRevoked reports whether the certificate with the serial number was
+// revoked.
+func (c *Credentials) IsRevoked(serial *big.Int) bool {
+	for _, r := range c.Revoked {
+		if r.Serial == serial.Text(16) {
+			return true
+		}
+	}
+	return false
+}
+
+// RevokeCredentials revokes the current certificate of a node. It returns nil
+// if no certificate was issued to the node.
+func RevokeCredentials(
+	ctx context.Context,
+	ps PersistenceService,
+	nodeID string,
+) (*RevokedCertificate, error) {
+	persisted, err := ps.Read(ctx, nodeID, &Credentials{})
+	if err != nil || persisted == nil {
+		return nil, err
+	}
+
+	creds := persisted.(*Credentials)
+	revoked, err := creds.Revoke(time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	if err = ps.BulkUpdate(ctx, []Persistable{creds}); err != nil {
+		return nil, err
+	}
+
+	if err = RaiseEvent(ctx, ps, nodeID, EventNodeCredentialsRevoked,
+		fmt.Sprintf("certificate %s revoked", revoked.Serial)); err != nil {
+		return nil, err
+	}
+	return revoked, nil
+}
+
+// CheckRevocation returns ErrCertificateRevoked if the node certificate was
+// revoked. The node is identified by the certificate's Common Name.
+func CheckRevocation(ctx context.Context, ps PersistenceService, cert *x509.Certificate) error {
+	persisted, err := ps.Read(ctx, cert.Subject.CommonName, &Credentials{})
+	if err != nil {
+		return fmt.Errorf("error checking certificate revocation: %v", err)
+	}
+	if persisted == nil {
+		return nil
+	}
+	if persisted.(*Credentials).IsRevoked(cert.SerialNumber) {
+		return ErrCertificateRevoked
+	}
+	return nil
+}
+
+// VerifyNotRevoked returns a function for tls.Config.VerifyPeerCertificate
+// that rejects revoked node certificates. It must be used with a ClientAuth
+// that verifies the chain, as only verified chains are checked.
+func VerifyNotRevoked(
+	ps PersistenceService,
+) func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+	return func(rawCerts [][]byte, verifiedChains [][]*x509.Certificate) error {
+		if len(verifiedChains) == 0 || len(verifiedChains[0]) == 0 {
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), revocationCheckTimeout)
+		defer cancel()
+		return CheckRevocation(ctx, ps, verifiedChains[0][0])
+	}
+}
+
+// RevocationList returns all revoked node certificates for a CRL.
+func RevocationList(ctx context.Context, ps PersistenceService) ([]pkix.RevokedCertificate, error) {
+	persisted, err := ps.ReadAll(ctx, &Credentials{})
+	if err != nil {
+		return nil, err
+	}
+
+	var revoked []pkix.RevokedCertificate
+	for _, p := range persisted {
+		for _, r := range p.(*Credentials).Revoked {
+			serial, ok := new(big.Int).SetString(r.Serial, 16)
+			if !ok {
+				return nil, fmt.Errorf("bad serial number %q of revoked certificate", r.Serial)
+			}
+			revoked = append(revoked, pkix.RevokedCertificate{
+				SerialNumber:   serial,
+				RevocationTime: r.RevokedAt,
+			})
+		}
+	}
+	return revoked, nil
+}
@@ -4,11 +4,15 @@
 package cce_test
 
 import (
+	"context"
+	"crypto/x509"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/internal/stubs"
 )
 
 var _ = Describe("Entities: Credentials", func() {
@@ -87,6 +91,13 @@ HjqhNuXFDM1RVkkcBuAD0lQZHQJdXJGmqNju
 				"certificate not a valid certificate"))
 		})
 
+		It("Should accept an ID that is the SHA-256 fingerprint of the public key", func() {
+			cert, err := creds.ParseCertificate()
+			Expect(err).ToNot(HaveOccurred())
+			creds.ID = cce.KeyFingerprint(cert.RawSubjectPublicKeyInfo)
+			Expect(creds.Validate()).To(Succeed())
+		})
+
 		It("Should return an error if ID is not derived from Certificate public key", func() {
 			creds.ID = "123"
 			Expect(creds.Validate()).To(MatchError(
@@ -94,6 +105,79 @@ HjqhNuXFDM1RVkkcBuAD0lQZHQJdXJGmqNju
 		})
 	})
 
+	Describe("Revoke", func() {
+		It("Should revoke the current certificate once", func() {
+			at := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+
+			revoked, err := creds.Revoke(at)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(*revoked).To(Equal(cce.RevokedCertificate{Serial: "1405c2e1243506bc", RevokedAt: at}))
+
+			By("Revoking it again")
+			revoked, err = creds.Revoke(at.Add(time.Hour))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(revoked.RevokedAt).To(Equal(at))
+			Expect(creds.Revoked).To(HaveLen(1))
+		})
+
+		It("Should return an error if Certificate is not PEM-encoded", func() {
+			creds.Certificate = "123"
+			_, err := creds.Revoke(time.Now())
+			Expect(err).To(MatchError("certificate not PEM-encoded"))
+		})
+	})
+
+	Describe("Revocation", func() {
+		var (
+			ctx context.Context
+			ps  *stubs.MemoryPersistenceService
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			ps = stubs.NewMemoryPersistenceService()
+
+			// Credentials are stored by the node ID, the certificate's CN
+			creds.ID = "cJl_0X_uNWvMGiYNS4_PSA"
+			Expect(ps.Create(ctx, creds)).To(Succeed())
+		})
+
+		It("Should reject a certificate once it is revoked", func() {
+			cert, err := creds.ParseCertificate()
+			Expect(err).ToNot(HaveOccurred())
+			verify := cce.VerifyNotRevoked(ps)
+
+			Expect(cce.CheckRevocation(ctx, ps, cert)).To(Succeed())
+			Expect(verify(nil, nil)).To(Succeed())
+
+			By("Revoking the credentials")
+			revoked, err := cce.RevokeCredentials(ctx, ps, creds.ID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(revoked.Serial).To(Equal("1405c2e1243506bc"))
+
+			Expect(cce.CheckRevocation(ctx, ps, cert)).To(Equal(cce.ErrCertificateRevoked))
+			Expect(verify(nil, [][]*x509.Certificate{{cert}})).To(Equal(cce.ErrCertificateRevoked))
+
+			By("Listing the revoked certificate")
+			list, err := cce.RevocationList(ctx, ps)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(list).To(HaveLen(1))
+			Expect(list[0].SerialNumber).To(Equal(cert.SerialNumber))
+
+			By("Raising an event")
+			events, err := ps.ReadAll(ctx, &cce.Event{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].(*cce.Event).Type).To(Equal(cce.EventNodeCredentialsRevoked))
+		})
+
+		It("Should not revoke anything for a node without credentials", func() {
+			revoked, err := cce.RevokeCredentials(ctx, ps, "unknown-node")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(revoked).To(BeNil())
+		})
+	})
+
 	Describe("String", func() {
 		It("Should return the string value", func() {
 			Expect(creds.String()).To(Equal(strings.TrimSpace(`
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+const (
+	// DefaultEnrollmentTokenTTL is the lifetime of an enrollment token if
+	// none is requested.
+	DefaultEnrollmentTokenTTL = time.Hour
+	// MaxEnrollmentTokenTTL is the longest lifetime of an enrollment token.
+	MaxEnrollmentTokenTTL = 24 * time.Hour
+)
+
+// EnrollmentToken lets a node enroll without its public key being registered
+// beforehand. Only a hash of the token is stored, and the token can be used
+// once before it expires.
+type EnrollmentToken struct {
+	ID     string `json:"id"`
+	NodeID string `json:"node_id"`
+	// TokenHash is the hex-encoded SHA-256 hash of the token.
+	TokenHash string    `json:"token_hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewEnrollmentToken generates a token for a node valid for ttl. The token
+// itself is returned next to the entity to persist, and can't be recovered
+// from it.
+func NewEnrollmentToken(nodeID string, ttl time.Duration) (string, *EnrollmentToken, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", nil, err
+	}
+	token := base64.RawURLEncoding.EncodeToString(b)
+
+	return token, &EnrollmentToken{
+		ID:        uuid.New(),
+		NodeID:    nodeID,
+		TokenHash: HashEnrollmentToken(token),
+		ExpiresAt: time.Now().Add(ttl).UTC(),
+	}, nil
+}
+
+// HashEnrollmentToken returns the hash an enrollment token is stored by.
+func HashEnrollmentToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// Expired reports whether the token can no longer be used.
+func (t *EnrollmentToken) Expired() bool {
+	return !time.Now().Before(t.ExpiresAt)
+}
+
+// GetTableName returns the name of the persistence table.
+func (*EnrollmentToken) GetTableName() string {
+	return "enrollment_tokens"
+}
+
+// GetID gets the ID.
+func (t *EnrollmentToken) GetID() string {
+	return t.ID
+}
+
+// SetID sets the ID.
+func (t *EnrollmentToken) SetID(id string) {
+	t.ID = id
+}
+
+// GetNodeID gets the node ID.
+func (t *EnrollmentToken) GetNodeID() string {
+	return t.NodeID
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*EnrollmentToken) FilterFields() []string {
+	return []string{
+		"node_id",
+		"token_hash",
+	}
+}
+
+func (t *EnrollmentToken) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+EnrollmentToken[
+    ID: %s
+    NodeID: %s
+    ExpiresAt: %s
+]`),
+		t.ID,
+		t.NodeID,
+		t.ExpiresAt,
+	)
+}
@@ -0,0 +1,109 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+var _ = Describe("Entities: EnrollmentToken", func() {
+	var (
+		token *cce.EnrollmentToken
+	)
+
+	BeforeEach(func() {
+		token = &cce.EnrollmentToken{
+			ID:        "ca0fa495-1020-405b-a78c-9a1884349078",
+			NodeID:    "48606c73-3905-47e0-864f-14bc7466f5bb",
+			TokenHash: cce.HashEnrollmentToken("token"),
+			ExpiresAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+		}
+	})
+
+	Describe("NewEnrollmentToken", func() {
+		It("Should generate a token stored by its hash", func() {
+			secret, t, err := cce.NewEnrollmentToken("48606c73-3905-47e0-864f-14bc7466f5bb", time.Hour)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(uuid.IsValid(t.ID)).To(BeTrue())
+			Expect(t.NodeID).To(Equal("48606c73-3905-47e0-864f-14bc7466f5bb"))
+			Expect(t.TokenHash).To(Equal(cce.HashEnrollmentToken(secret)))
+			Expect(t.TokenHash).ToNot(ContainSubstring(secret))
+			Expect(t.ExpiresAt).To(BeTemporally("~", time.Now().Add(time.Hour), time.Second))
+			Expect(t.Expired()).To(BeFalse())
+		})
+
+		It("Should generate a different token each time", func() {
+			secret1, _, err := cce.NewEnrollmentToken("48606c73-3905-47e0-864f-14bc7466f5bb", time.Hour)
+			Expect(err).ToNot(HaveOccurred())
+			secret2, _, err := cce.NewEnrollmentToken("48606c73-3905-47e0-864f-14bc7466f5bb", time.Hour)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(secret1).ToNot(Equal(secret2))
+		})
+	})
+
+	Describe("Expired", func() {
+		It("Should return true once the token expired", func() {
+			Expect(token.Expired()).To(BeTrue())
+		})
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "enrollment_tokens"`, func() {
+			Expect(token.GetTableName()).To(Equal("enrollment_tokens"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(token.GetID()).To(Equal(
+				"ca0fa495-1020-405b-a78c-9a1884349078"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			token.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(token.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("GetNodeID", func() {
+		It("Should return the node ID", func() {
+			Expect(token.GetNodeID()).To(Equal(
+				"48606c73-3905-47e0-864f-14bc7466f5bb"))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(token.FilterFields()).To(Equal([]string{
+				"node_id",
+				"token_hash",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value without the token hash", func() {
+			Expect(token.String()).To(Equal(strings.TrimSpace(`
+EnrollmentToken[
+    ID: ca0fa495-1020-405b-a78c-9a1884349078
+    NodeID: 48606c73-3905-47e0-864f-14bc7466f5bb
+    ExpiresAt: 2020-01-01 00:00:00 +0000 UTC
+]`,
+			)))
+		})
+	})
+})
@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+// Event types raised by the controller.
+const (
+	// EventNodeStatusChanged is raised when a node's liveness changes
+	EventNodeStatusChanged = "node.status_changed"
+	// EventNodeCredentialsRevoked is raised when a node's certificate is
+	// revoked
+	EventNodeCredentialsRevoked = "node.credentials_revoked"
+	// EventNodeCredentialsRenewed is raised when a node renews its
+	// certificate
+	EventNodeCredentialsRenewed = "node.credentials_renewed"
+	// EventNodeAddressChanged is raised when a node enrolls from a new
+	// address
+	EventNodeAddressChanged = "node.address_changed"
+	// EventNodeInterfacesChanged is raised when the network interfaces
+	// collected from a node change
+	EventNodeInterfacesChanged = "node.interfaces_changed"
+	// EventAppStatusChanged is raised when the status of an app deployed by
+	// Kubernetes changes
+	EventAppStatusChanged = "app.status_changed"
+)
+
+// Event is a notable change in the state of an entity managed by the
+// controller.
+type Event struct {
+	ID      string    `json:"id"`
+	NodeID  string    `json:"node_id"`
+	Type    string    `json:"type"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*Event) GetTableName() string {
+	return "events"
+}
+
+// GetID gets the ID.
+func (e *Event) GetID() string {
+	return e.ID
+}
+
+// SetID sets the ID.
+func (e *Event) SetID(id string) {
+	e.ID = id
+}
+
+// GetNodeID gets the node ID.
+func (e *Event) GetNodeID() string {
+	return e.NodeID
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*Event) FilterFields() []string {
+	return []string{
+		"node_id",
+		"type",
+	}
+}
+
+func (e *Event) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+Event[
+    ID: %s
+    NodeID: %s
+    Type: %s
+    Message: %s
+    Time: %s
+]`),
+		e.ID,
+		e.NodeID,
+		e.Type,
+		e.Message,
+		e.Time.Format(time.RFC3339))
+}
+
+// MaxEventsPerNode is the number of events kept for a node. Older events are
+// deleted as new ones are raised.
+const MaxEventsPerNode = 1000
+
+// RaiseEvent persists a new event for a node and deletes the oldest events of
+// the node beyond MaxEventsPerNode.
+func RaiseEvent(ctx context.Context, ps PersistenceService, nodeID, eventType, message string) error {
+	if err := ps.Create(ctx, &Event{
+		ID:      uuid.New(),
+		NodeID:  nodeID,
+		Type:    eventType,
+		Message: message,
+		Time:    time.Now().UTC(),
+	}); err != nil {
+		return err
+	}
+
+	es, err := ps.Filter(ctx, &Event{}, []Filter{{Field: "node_id", Value: nodeID}})
+	if err != nil {
+		return err
+	}
+	if len(es) <= MaxEventsPerNode {
+		return nil
+	}
+	sort.SliceStable(es, func(i, j int) bool {
+		return es[i].(*Event).Time.Before(es[j].(*Event).Time)
+	})
+	for _, e := range es[:len(es)-MaxEventsPerNode] {
+		if _, err := ps.Delete(ctx, e.GetID(), &Event{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/internal/stubs"
+)
+
+var _ = Describe("Entities: Event", func() {
+	var (
+		event *cce.Event
+	)
+
+	BeforeEach(func() {
+		event = &cce.Event{
+			ID:      "ca0fa495-1020-405b-a78c-9a1884349078",
+			NodeID:  "48606c73-3905-47e0-864f-14bc7466f5bb",
+			Type:    cce.EventNodeStatusChanged,
+			Message: "node status changed from online to offline",
+			Time:    time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "events"`, func() {
+			Expect(event.GetTableName()).To(Equal("events"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(event.GetID()).To(Equal(
+				"ca0fa495-1020-405b-a78c-9a1884349078"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			event.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(event.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("GetNodeID", func() {
+		It("Should return the node ID", func() {
+			Expect(event.GetNodeID()).To(Equal(
+				"48606c73-3905-47e0-864f-14bc7466f5bb"))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(event.FilterFields()).To(Equal([]string{
+				"node_id",
+				"type",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(event.String()).To(Equal(strings.TrimSpace(`
+Event[
+    ID: ca0fa495-1020-405b-a78c-9a1884349078
+    NodeID: 48606c73-3905-47e0-864f-14bc7466f5bb
+    Type: node.status_changed
+    Message: node status changed from online to offline
+    Time: 2020-01-02T03:04:05Z
+]`,
+			)))
+		})
+	})
+
+	Describe("RaiseEvent", func() {
+		It("Should persist the event", func() {
+			ps := stubs.NewMemoryPersistenceService()
+			Expect(cce.RaiseEvent(context.TODO(), ps,
+				"48606c73-3905-47e0-864f-14bc7466f5bb",
+				cce.EventNodeStatusChanged, "went offline")).To(Succeed())
+
+			events, err := ps.ReadAll(context.TODO(), &cce.Event{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(events).To(HaveLen(1))
+			Expect(events[0].(*cce.Event).NodeID).To(Equal("48606c73-3905-47e0-864f-14bc7466f5bb"))
+			Expect(events[0].(*cce.Event).Type).To(Equal(cce.EventNodeStatusChanged))
+			Expect(events[0].(*cce.Event).Message).To(Equal("went offline"))
+			Expect(events[0].(*cce.Event).ID).ToNot(BeEmpty())
+		})
+
+		It("Should keep the latest events of a node", func() {
+			var (
+				ps     = stubs.NewMemoryPersistenceService()
+				nodeID = "48606c73-3905-47e0-864f-14bc7466f5bb"
+			)
+			for i := 0; i <= cce.MaxEventsPerNode; i++ {
+				Expect(cce.RaiseEvent(context.TODO(), ps, nodeID,
+					cce.EventNodeStatusChanged, fmt.Sprintf("change %d", i))).To(Succeed())
+			}
+			Expect(cce.RaiseEvent(context.TODO(), ps, "7ef1b1b7-6f0c-4e43-9e49-bd2cfd1ea7c3",
+				cce.EventNodeStatusChanged, "went offline")).To(Succeed())
+
+			events, err := ps.Filter(context.TODO(), &cce.Event{}, []cce.Filter{{Field: "node_id", Value: nodeID}})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(events).To(HaveLen(cce.MaxEventsPerNode))
+			for _, e := range events {
+				Expect(e.(*cce.Event).Message).ToNot(Equal("change 0"))
+			}
+
+			events, err = ps.ReadAll(context.TODO(), &cce.Event{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(events).To(HaveLen(cce.MaxEventsPerNode + 1))
+		})
+	})
+})
@@ -0,0 +1,101 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// appImageFetchTimeout bounds fetching the image of an app to verify it
+	appImageFetchTimeout = 30 * time.Minute
+	// maxAppImageSize is the size of the largest app image verified
+	maxAppImageSize = 16 << 30
+	// maxAppImageRedirects is the number of redirects followed to an image
+	maxAppImageRedirects = 10
+)
+
+var (
+	// appImageTransport connects to any address of the hosts allowed
+	appImageTransport = newAppImageTransport(nil)
+	// publicAppImageTransport refuses to connect to the loopback, link-local
+	// and unspecified addresses, so that API users cannot reach the services
+	// of the controller host or the metadata service of a cloud through it
+	publicAppImageTransport = newAppImageTransport(checkPublicAddress)
+)
+
+func newAppImageTransport(
+	control func(network, address string, c syscall.RawConn) error,
+) *http.Transport {
+	return &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 30 * time.Second,
+			Control: control,
+		}).DialContext,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: time.Minute,
+	}
+}
+
+// checkPublicAddress refuses to connect to an address that is not public. It
+// is checked once resolved, so a host name cannot point at such an address.
+func checkPublicAddress(network, address string, c syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsUnspecified() || ip.IsMulticast() {
+		return fmt.Errorf("address %s not allowed", host)
+	}
+	return nil
+}
+
+// appImageClient returns the client fetching app images. If hosts is not
+// empty, images are fetched from these hosts only, at any address. Otherwise
+// they are fetched from public addresses only.
+func appImageClient(hosts []string) *http.Client {
+	if len(hosts) == 0 {
+		return &http.Client{
+			Timeout:   appImageFetchTimeout,
+			Transport: publicAppImageTransport,
+		}
+	}
+
+	return &http.Client{
+		Timeout:   appImageFetchTimeout,
+		Transport: appImageTransport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if !allowedHost(req.URL, hosts) {
+				return fmt.Errorf("redirect to host %s not allowed", req.URL.Hostname())
+			}
+			if len(via) >= maxAppImageRedirects {
+				return errors.Errorf("stopped after %d redirects", maxAppImageRedirects)
+			}
+			return nil
+		},
+	}
+}
+
+// allowedHost returns whether the host of u is one of hosts. Any host is
+// allowed if hosts is empty.
+func allowedHost(u *url.URL, hosts []string) bool {
+	if len(hosts) == 0 {
+		return true
+	}
+	for _, host := range hosts {
+		if u.Hostname() == host {
+			return true
+		}
+	}
+	return false
+}
@@ -11,10 +11,11 @@ import (
 )
 
 func handleCreateNodesApps(ctx context.Context, ps cce.PersistenceService, e cce.Persistable) error {
-	app, err := ps.Read(ctx, e.(*cce.NodeApp).AppID, &cce.App{})
+	persisted, err := ps.Read(ctx, e.(*cce.NodeApp).AppID, &cce.App{})
 	if err != nil {
 		return fmt.Errorf("Error fetching app from DB: %v", err)
 	}
+	app := persisted.(*cce.App).Configured(e.(*cce.NodeApp).Config)
 
 	log.Debugf("Loaded app %s\n%+v", app.GetID(), app)
 
@@ -29,7 +30,7 @@ func handleCreateNodesApps(ctx context.Context, ps cce.PersistenceService, e cce
 	}
 	defer disconnectNode(nodeCC)
 
-	if err := nodeCC.AppDeploySvcCli.Deploy(ctx, app.(*cce.App)); err != nil {
+	if err := nodeCC.AppDeploySvcCli.Deploy(ctx, app); err != nil {
 		return err
 	}
 
@@ -38,7 +39,7 @@ func handleCreateNodesApps(ctx context.Context, ps cce.PersistenceService, e cce
 		err := ctrl.KubernetesClient.Deploy(
 			ctx,
 			e.(*cce.NodeApp).GetNodeID(),
-			toK8SApp(app.(*cce.App)))
+			toK8SNodeApp(app, e.(*cce.NodeApp)))
 		if err != nil {
 			return err
 		}
@@ -124,3 +125,28 @@ func handleCreateNodesDNSConfigsWithAliases(
 
 	return nil
 }
+
+func handleCreateNodesZones(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) error {
+	zone, err := ps.Read(ctx, e.(*cce.NodeZone).ZoneID, &cce.Zone{})
+	if err != nil {
+		return err
+	}
+	log.Debugf("Loaded zone %s\n%+v", zone.GetID(), zone)
+
+	ctrl := getController(ctx)
+	nodePort := ctrl.ELAPort
+	if nodePort == "" {
+		nodePort = defaultELAPort
+	}
+	nodeCC, err := connectNode(ctx, ps, e.(*cce.NodeZone), nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return err
+	}
+	defer disconnectNode(nodeCC)
+
+	return nodeCC.ZoneSvcCli.Create(ctx, toNetworkZone(zone.(*cce.Zone)))
+}
@@ -111,3 +111,178 @@ func checkDBCreateNodesDNSConfigs(
 
 	return 0, nil
 }
+
+func checkDBCreateZones(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) (statusCode int, err error) {
+	var es []cce.Persistable
+
+	if es, err = ps.Filter(
+		ctx,
+		&cce.Zone{},
+		[]cce.Filter{
+			{
+				Field: "name",
+				Value: e.(*cce.Zone).Name,
+			},
+		},
+	); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	for _, zone := range es {
+		if zone.GetID() != e.GetID() {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"duplicate record in %s detected for name %s",
+				e.(*cce.Zone).GetTableName(),
+				e.(*cce.Zone).Name)
+		}
+	}
+
+	return 0, nil
+}
+
+func checkDBCreateNodesZones(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) (statusCode int, err error) {
+	var es []cce.Persistable
+
+	if es, err = ps.Filter(
+		ctx,
+		&cce.NodeZone{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: e.(*cce.NodeZone).NodeID,
+			},
+			{
+				Field: "zone_id",
+				Value: e.(*cce.NodeZone).ZoneID,
+			},
+		},
+	); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if len(es) != 0 {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"duplicate record in %s detected for node_id %s and zone_id %s",
+			e.(*cce.NodeZone).GetTableName(),
+			e.(*cce.NodeZone).NodeID,
+			e.(*cce.NodeZone).ZoneID)
+	}
+
+	return 0, nil
+}
+
+func checkDBCreateRollouts(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) (statusCode int, err error) {
+	var p cce.Persistable
+
+	if p, err = ps.Read(ctx, e.(*cce.Rollout).AppID, &cce.App{}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if p == nil {
+		return http.StatusNotFound, fmt.Errorf("app_id %s not found", e.(*cce.Rollout).AppID)
+	}
+	app := p.(*cce.App)
+
+	for _, nodeID := range e.(*cce.Rollout).NodeIDs {
+		if p, err = ps.Read(ctx, nodeID, &cce.Node{}); err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if p == nil {
+			return http.StatusNotFound, fmt.Errorf("node_id %s not found", nodeID)
+		}
+	}
+
+	// Only one version of an app is rolled out at a time
+	var es []cce.Persistable
+	if es, err = ps.ReadAll(ctx, &cce.Rollout{}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, r := range es {
+		if !r.(*cce.Rollout).Active() {
+			continue
+		}
+		if p, err = ps.Read(ctx, r.(*cce.Rollout).AppID, &cce.App{}); err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if p != nil && app.SameRelease(p.(*cce.App)) {
+			return http.StatusConflict, fmt.Errorf(
+				"rollout_id %s of app_id %s is %s", r.GetID(), r.(*cce.Rollout).AppID, r.(*cce.Rollout).State)
+		}
+	}
+
+	return 0, nil
+}
+
+func checkDBCreateApps(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) (statusCode int, err error) {
+	app := e.(*cce.App)
+
+	if err = app.ValidateOrchestration(getController(ctx).OrchestrationMode); err != nil {
+		return http.StatusUnprocessableEntity, err
+	}
+
+	// The publisher key is only ever set from a trusted publisher
+	app.PublisherKey = ""
+	if app.PublisherID == "" {
+		return 0, nil
+	}
+
+	var p cce.Persistable
+	if p, err = ps.Read(ctx, app.PublisherID, &cce.Publisher{}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if p == nil {
+		return http.StatusNotFound, fmt.Errorf("publisher_id %s not found", app.PublisherID)
+	}
+
+	if err = app.VerifySignature(p.(*cce.Publisher).PublicKey); err != nil {
+		return http.StatusUnprocessableEntity, err
+	}
+	app.PublisherKey = p.(*cce.Publisher).PublicKey
+
+	return 0, nil
+}
+
+func checkDBCreatePublishers(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) (statusCode int, err error) {
+	var es []cce.Persistable
+
+	if es, err = ps.Filter(
+		ctx,
+		&cce.Publisher{},
+		[]cce.Filter{
+			{
+				Field: "name",
+				Value: e.(*cce.Publisher).Name,
+			},
+		},
+	); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if len(es) != 0 {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"duplicate record in %s detected for name %s",
+			e.(*cce.Publisher).GetTableName(),
+			e.(*cce.Publisher).Name)
+	}
+
+	return 0, nil
+}
@@ -85,23 +85,30 @@ func checkDBDeleteApps(
 			id)
 	}
 
-	if es, err = ps.Filter(
-		ctx,
-		&cce.NodeApp{},
-		[]cce.Filter{
-			{
-				Field: "app_id",
-				Value: id,
-			},
-		},
-	); err != nil {
+	// Node apps also keep the versions they are known by and roll back to
+	if es, err = ps.ReadAll(ctx, &cce.NodeApp{}); err != nil {
 		return http.StatusInternalServerError, err
 	}
 
-	if len(es) > 0 {
-		return http.StatusUnprocessableEntity, fmt.Errorf(
-			"cannot delete app_id %s: record in use in nodes_apps",
-			id)
+	for _, e := range es {
+		if e.(*cce.NodeApp).References(id) {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"cannot delete app_id %s: record in use in nodes_apps",
+				id)
+		}
+	}
+
+	// Finished rollouts are history and do not keep the app
+	if es, err = ps.ReadAll(ctx, &cce.Rollout{}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	for _, e := range es {
+		if ro := e.(*cce.Rollout); ro.Active() && ro.References(id) {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"cannot delete app_id %s: record in use in rollouts",
+				id)
+		}
 	}
 
 	return 0, nil
@@ -215,3 +222,61 @@ func checkDBDeleteDNSConfigs(
 
 	return 0, nil
 }
+
+func checkDBDeleteZones(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	id string,
+) (statusCode int, err error) {
+	var es []cce.Persistable
+
+	if es, err = ps.Filter(
+		ctx,
+		&cce.NodeZone{},
+		[]cce.Filter{
+			{
+				Field: "zone_id",
+				Value: id,
+			},
+		},
+	); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if len(es) > 0 {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"cannot delete zone_id %s: record in use in nodes_zones",
+			id)
+	}
+
+	return 0, nil
+}
+
+func checkDBDeletePublishers(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	id string,
+) (statusCode int, err error) {
+	var es []cce.Persistable
+
+	if es, err = ps.Filter(
+		ctx,
+		&cce.App{},
+		[]cce.Filter{
+			{
+				Field: "publisher_id",
+				Value: id,
+			},
+		},
+	); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	if len(es) > 0 {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"cannot delete publisher_id %s: record in use in apps",
+			id)
+	}
+
+	return 0, nil
+}
@@ -0,0 +1,278 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/swagger"
+	"github.com/pkg/errors"
+)
+
+// Decommission steps, in the order they run
+const (
+	stepUndeployApps       = "undeploy_apps"
+	stepClearIfacePolicies = "clear_interface_policies"
+	stepClearDNS           = "clear_dns"
+	stepRevokeCredentials  = "revoke_credentials"
+	stepRemoveTarget       = "remove_grpc_target"
+	stepDeleteNode         = "delete_node"
+)
+
+// Decommission step statuses
+const (
+	stepPending = "pending"
+	stepDone    = "done"
+	stepSkipped = "skipped"
+	stepFailed  = "failed"
+)
+
+// nodeError is an error of a step that needs the node to be reachable. With
+// force such errors are reported and the decommission goes on.
+type nodeError struct {
+	error
+}
+
+// decommissioner removes a node and everything deployed to it.
+type decommissioner struct {
+	ctrl   *cce.Controller
+	nodeID string
+	force  bool
+	report *swagger.NodeDecommission
+}
+
+func newDecommissioner(ctrl *cce.Controller, nodeID string, force bool) *decommissioner {
+	d := &decommissioner{
+		ctrl:   ctrl,
+		nodeID: nodeID,
+		force:  force,
+		report: &swagger.NodeDecommission{NodeID: nodeID},
+	}
+	for _, name := range []string{
+		stepUndeployApps,
+		stepClearIfacePolicies,
+		stepClearDNS,
+		stepRevokeCredentials,
+		stepRemoveTarget,
+		stepDeleteNode,
+	} {
+		d.report.Steps = append(d.report.Steps, swagger.DecommissionStep{Name: name, Status: stepPending})
+	}
+	return d
+}
+
+// run runs all steps and returns the HTTP status code of the outcome.
+func (d *decommissioner) run(ctx context.Context) (int, error) {
+	steps := []func(context.Context) error{
+		d.undeployApps,
+		d.clearIfacePolicies,
+		d.clearDNS,
+		d.revokeCredentials,
+		d.removeTarget,
+		d.deleteNode,
+	}
+	for i, step := range steps {
+		err := step(ctx)
+		if err == nil {
+			d.setStep(i, stepDone, nil)
+			continue
+		}
+		if _, ok := err.(nodeError); ok && d.force {
+			log.Noticef("Decommission of node %s: skipping unreachable node in %s: %v",
+				d.nodeID, d.report.Steps[i].Name, err)
+			d.setStep(i, stepSkipped, err)
+			continue
+		}
+		d.setStep(i, stepFailed, err)
+		if _, ok := err.(nodeError); ok {
+			return http.StatusBadGateway, err
+		}
+		return http.StatusInternalServerError, err
+	}
+	d.report.Completed = true
+	return http.StatusOK, nil
+}
+
+func (d *decommissioner) setStep(i int, status string, err error) {
+	d.report.Steps[i].Status = status
+	if err != nil {
+		d.report.Steps[i].Error = err.Error()
+	}
+	log.Infof("Decommission of node %s: %s %s", d.nodeID, d.report.Steps[i].Name, status)
+}
+
+func (d *decommissioner) filter(ctx context.Context, zv cce.Filterable, field, value string) ([]cce.Persistable, error) {
+	return d.ctrl.PersistenceService.Filter(ctx, zv, []cce.Filter{{Field: field, Value: value}})
+}
+
+func (d *decommissioner) delete(ctx context.Context, e cce.Persistable) error {
+	if _, err := d.ctrl.PersistenceService.Delete(ctx, e.GetID(), e); err != nil {
+		return errors.Wrapf(err, "could not delete %s %s", e.GetTableName(), e.GetID())
+	}
+	return nil
+}
+
+// undeployApps undeploys every app on the node from EVA (and Kubernetes),
+// clears its policies and removes it from the node.
+func (d *decommissioner) undeployApps(ctx context.Context) error {
+	nodeApps, err := d.filter(ctx, &cce.NodeApp{}, "node_id", d.nodeID)
+	if err != nil {
+		return err
+	}
+
+	var nodeErr error
+	for _, na := range nodeApps {
+		nodeApp := na.(*cce.NodeApp)
+
+		if err = handleDeleteNodesApps(ctx, d.ctrl.PersistenceService, nodeApp); err != nil {
+			if !d.force {
+				return nodeError{errors.Wrapf(err, "could not undeploy app %s", nodeApp.AppID)}
+			}
+			nodeErr = err
+		}
+
+		policies, err := d.filter(ctx, &cce.NodeAppTrafficPolicy{}, "nodes_apps_id", nodeApp.ID)
+		if err != nil {
+			return err
+		}
+		if len(policies) > 0 && d.ctrl.OrchestrationMode == cce.OrchestrationModeKubernetesOVN {
+			if err = d.ctrl.KubernetesClient.DeleteNetworkPolicy(ctx, d.nodeID, nodeApp.RemoteAppID()); err != nil {
+				if !d.force {
+					return nodeError{errors.Wrapf(err, "could not delete policy of app %s", nodeApp.AppID)}
+				}
+				nodeErr = err
+			}
+		}
+		for _, p := range policies {
+			if err = d.delete(ctx, p); err != nil {
+				return err
+			}
+		}
+		if err = d.delete(ctx, nodeApp); err != nil {
+			return err
+		}
+	}
+
+	if nodeErr != nil {
+		return nodeError{nodeErr}
+	}
+	return nil
+}
+
+// clearIfacePolicies removes the traffic policies of the node's interfaces.
+func (d *decommissioner) clearIfacePolicies(ctx context.Context) error {
+	policies, err := d.filter(ctx, &cce.NodeInterfaceTrafficPolicy{}, "node_id", d.nodeID)
+	if err != nil || len(policies) == 0 {
+		return err
+	}
+
+	var nodeErr error
+	nodeCC, err := connectNode(ctx, d.ctrl.PersistenceService, &cce.Node{ID: d.nodeID},
+		d.port(d.ctrl.ELAPort, defaultELAPort), d.ctrl.EdgeNodeCreds)
+	if err != nil {
+		if !d.force {
+			return nodeError{err}
+		}
+		nodeErr = err
+	} else {
+		defer disconnectNode(nodeCC)
+	}
+
+	for _, p := range policies {
+		ifaceID := p.(*cce.NodeInterfaceTrafficPolicy).NetworkInterfaceID
+		if nodeCC != nil {
+			if err = nodeCC.IfacePolicySvcCli.Delete(ctx, ifaceID); err != nil {
+				if !d.force {
+					return nodeError{err}
+				}
+				nodeErr = err
+			}
+		}
+		if err = d.delete(ctx, p); err != nil {
+			return err
+		}
+	}
+
+	if nodeErr != nil {
+		return nodeError{nodeErr}
+	}
+	return nil
+}
+
+// clearDNS removes the node's DNS configuration.
+func (d *decommissioner) clearDNS(ctx context.Context) error {
+	nodeDNSConfigs, err := d.filter(ctx, &cce.NodeDNSConfig{}, "node_id", d.nodeID)
+	if err != nil {
+		return err
+	}
+
+	var nodeErr error
+	for _, nodeDNS := range nodeDNSConfigs {
+		if err = handleDeleteNodesDNSConfigs(ctx, d.ctrl.PersistenceService, nodeDNS); err != nil {
+			if !d.force {
+				return nodeError{errors.Wrap(err, "could not clear DNS configuration")}
+			}
+			nodeErr = err
+		}
+		if err = d.delete(ctx, nodeDNS); err != nil {
+			return err
+		}
+	}
+
+	if nodeErr != nil {
+		return nodeError{nodeErr}
+	}
+	return nil
+}
+
+// revokeCredentials revokes the certificate issued to the node. The
+// credentials are kept so the revocation stays enforced.
+func (d *decommissioner) revokeCredentials(ctx context.Context) error {
+	if _, err := cce.RevokeCredentials(ctx, d.ctrl.PersistenceService, d.nodeID); err != nil {
+		return errors.Wrap(err, "could not revoke credentials")
+	}
+	return nil
+}
+
+// removeTarget forgets the node's address and drops any connection to it.
+func (d *decommissioner) removeTarget(ctx context.Context) error {
+	targets, err := d.filter(ctx, &cce.NodeGRPCTarget{}, "node_id", d.nodeID)
+	if err != nil {
+		return err
+	}
+	for _, t := range targets {
+		if err = d.delete(ctx, t); err != nil {
+			return err
+		}
+	}
+
+	// The proxy listener keeps hosts registered for its lifetime, but without
+	// a target no connection to the node is dialed again.
+	if d.ctrl.NodeConns != nil {
+		d.ctrl.NodeConns.Invalidate(d.nodeID)
+	}
+	return nil
+}
+
+// deleteNode deletes the node itself.
+func (d *decommissioner) deleteNode(ctx context.Context) error {
+	ok, err := d.ctrl.PersistenceService.Delete(ctx, d.nodeID, &cce.Node{})
+	if err != nil {
+		return errors.Wrap(err, "could not delete node")
+	}
+	if !ok {
+		return fmt.Errorf("node %s was already deleted", d.nodeID)
+	}
+	return nil
+}
+
+func (d *decommissioner) port(port, defaultPort string) string {
+	if port == "" {
+		return defaultPort
+	}
+	return port
+}
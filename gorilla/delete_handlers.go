@@ -5,45 +5,46 @@ package gorilla
 
 import (
 	"context"
+	"fmt"
+	"net/http"
 
 	cce "github.com/open-ness/edgecontroller"
 )
 
 func handleDeleteNodesApps(ctx context.Context, ps cce.PersistenceService, e cce.Persistable) error {
-	app, err := ps.Read(
-		ctx,
-		e.(*cce.NodeApp).AppID,
-		&cce.App{})
-	if err != nil {
-		return err
-	}
+	nodeApp := e.(*cce.NodeApp)
 
 	ctrl := getController(ctx)
 	nodePort := ctrl.EVAPort
 	if nodePort == "" {
 		nodePort = defaultEVAPort
 	}
-	nodeCC, err := connectNode(ctx, ps, e.(*cce.NodeApp), nodePort, ctrl.EdgeNodeCreds)
+	nodeCC, err := connectNode(ctx, ps, nodeApp, nodePort, ctrl.EdgeNodeCreds)
 	if err != nil {
 		return err
 	}
 	defer disconnectNode(nodeCC)
 
-	// if kubernetes un-deploy application
+	// if kubernetes un-deploy application and the images of all its versions
 	if ctrl.OrchestrationMode == cce.OrchestrationModeKubernetes ||
 		ctrl.OrchestrationMode == cce.OrchestrationModeKubernetesOVN {
 		if err = ctrl.KubernetesClient.Undeploy(
 			ctx,
-			e.(*cce.NodeApp).NodeID,
-			e.(*cce.NodeApp).AppID,
+			nodeApp.NodeID,
+			nodeApp.RemoteAppID(),
 		); err != nil {
 			return err
 		}
-	}
 
-	err = nodeCC.AppDeploySvcCli.Undeploy(ctx, app.GetID())
+		for _, id := range nodeApp.AppIDs() {
+			if err = nodeCC.AppDeploySvcCli.Undeploy(ctx, id); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
 
-	return err
+	return nodeCC.AppDeploySvcCli.Undeploy(ctx, nodeApp.RemoteAppID())
 }
 
 func handleDeleteNodesDNSConfigs(
@@ -66,6 +67,7 @@ func handleDeleteNodesDNSConfigs(
 	if err != nil {
 		return err
 	}
+	defer disconnectNode(nodeCC)
 
 	for _, aRecord := range dnsConfig.(*cce.DNSConfig).ARecords {
 		if err := nodeCC.DNSSvcCli.DeleteA(ctx, aRecord); err != nil {
@@ -120,3 +122,41 @@ func handleDeleteNodesDNSConfigsWithAliases(
 
 	return nil
 }
+
+func handleDeleteNodesZones(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Persistable,
+) (statusCode int, err error) {
+	ctrl := getController(ctx)
+	nodePort := ctrl.ELAPort
+	if nodePort == "" {
+		nodePort = defaultELAPort
+	}
+	nodeCC, err := connectNode(ctx, ps, e.(*cce.NodeZone), nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	defer disconnectNode(nodeCC)
+
+	// An interface of the node still in the zone would be left dangling
+	nis, err := nodeCC.IfaceSvcCli.GetAll(ctx)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	for _, ni := range nis {
+		for _, zoneID := range ni.Zones {
+			if zoneID == e.(*cce.NodeZone).ZoneID {
+				return http.StatusUnprocessableEntity, fmt.Errorf(
+					"cannot delete zone_id %s: zone in use by network interface %s",
+					zoneID, ni.ID)
+			}
+		}
+	}
+
+	if err = nodeCC.ZoneSvcCli.Delete(ctx, e.(*cce.NodeZone).ZoneID); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	return 0, nil
+}
@@ -51,11 +51,12 @@ func handleGetNodesApps(ctx context.Context, ps cce.PersistenceService, e cce.Pe
 	}
 	defer disconnectNode(nodeCC)
 
-	s, err := nodeCC.AppLifeSvcCli.GetStatus(ctx, e.(*cce.NodeApp).AppID)
+	s, err := nodeCC.AppLifeSvcCli.GetStatus(ctx, e.(*cce.NodeApp).RemoteAppID())
 	if err != nil {
 		return nil, err
 	}
 
+	// The node does not report the readiness of native apps
 	if ctrl.OrchestrationMode == cce.OrchestrationModeNative {
 		return &cce.NodeAppResp{
 			NodeApp: *e.(*cce.NodeApp),
@@ -68,18 +69,41 @@ func handleGetNodesApps(ctx context.Context, ps cce.PersistenceService, e cce.Pe
 	switch s {
 	case cce.Unknown, cce.Deploying, cce.Error:
 		return &cce.NodeAppResp{
-			NodeApp: *e.(*cce.NodeApp),
-			Status:  s.String(),
+			NodeApp:   *e.(*cce.NodeApp),
+			Status:    s.String(),
+			Readiness: cce.ReadinessUnknown,
 		}, nil
 	}
 
-	k8sStatus, err := ctrl.KubernetesClient.Status(ctx, e.(*cce.NodeApp).NodeID, e.(*cce.NodeApp).AppID)
+	k8sStatus, err := ctrl.KubernetesClient.Status(ctx, e.(*cce.NodeApp).NodeID, e.(*cce.NodeApp).RemoteAppID())
+	if err != nil {
+		return nil, err
+	}
+
+	ready, err := ctrl.KubernetesClient.Ready(ctx, e.(*cce.NodeApp).NodeID, e.(*cce.NodeApp).RemoteAppID())
+	if err != nil {
+		return nil, err
+	}
+	readiness := cce.NotReady
+	if ready {
+		readiness = cce.Ready
+	}
+
+	networks, err := ctrl.KubernetesClient.Networks(ctx, e.(*cce.NodeApp).NodeID, e.(*cce.NodeApp).RemoteAppID())
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints, err := ctrl.KubernetesClient.Endpoints(ctx, e.(*cce.NodeApp).NodeID, e.(*cce.NodeApp).RemoteAppID())
 	if err != nil {
 		return nil, err
 	}
 
 	return &cce.NodeAppResp{
-		NodeApp: *e.(*cce.NodeApp),
-		Status:  string(k8sStatus),
+		NodeApp:       *e.(*cce.NodeApp),
+		Status:        string(k8sStatus),
+		Readiness:     readiness,
+		NetworkStatus: fromK8SNetworkStatus(networks),
+		Endpoints:     fromK8SEndpoints(endpoints),
 	}, nil
 }
@@ -29,6 +29,8 @@ type Gorilla struct {
 	trafficPoliciesHandler        *handler
 	trafficPoliciesKubeOVNHandler *handler
 	dnsConfigsHandler             *handler
+	zonesHandler                  *handler
+	publishersHandler             *handler
 
 	// join routes handlers
 	dnsConfigsAppAliasesHandler *handler
@@ -56,6 +58,7 @@ func NewGorilla( //nolint:gocyclo
 		},
 		appsHandler: &handler{
 			model:         &cce.App{},
+			checkDBCreate: checkDBCreateApps,
 			checkDBDelete: checkDBDeleteApps,
 		},
 		trafficPoliciesHandler: &handler{
@@ -70,6 +73,19 @@ func NewGorilla( //nolint:gocyclo
 			model:         &cce.DNSConfig{},
 			checkDBDelete: checkDBDeleteDNSConfigs,
 		},
+		zonesHandler: &handler{
+			model: &cce.Zone{},
+
+			checkDBCreate: checkDBCreateZones,
+			checkDBDelete: checkDBDeleteZones,
+
+			handleUpdate: handleUpdateZones,
+		},
+		publishersHandler: &handler{
+			model:         &cce.Publisher{},
+			checkDBCreate: checkDBCreatePublishers,
+			checkDBDelete: checkDBDeletePublishers,
+		},
 
 		// join routes handlers
 		dnsConfigsAppAliasesHandler: &handler{
@@ -135,12 +151,42 @@ func NewGorilla( //nolint:gocyclo
 		"PATCH    /nodes/{node_id}": g.swagPATCHNodeByID,
 		"DELETE   /nodes/{node_id}": g.swagDELETENodeByID,
 
+		"POST     /nodes/{node_id}/decommission": g.swagPOSTNodeDecommission,
+
+		"GET      /nodes/{node_id}/credentials":        g.swagGETNodeCredentials,
+		"POST     /nodes/{node_id}/credentials/revoke": g.swagPOSTNodeCredentialsRevoke,
+		"GET      /credentials/expiring":               g.swagGETExpiringCredentials,
+		"POST     /nodes/{node_id}/enrollment-tokens":  g.swagPOSTNodeEnrollmentTokens,
+		"GET      /crl": g.swagGETCRL,
+
 		"GET      /apps":          g.swagGETApps,
 		"POST     /apps":          g.swagPOSTApps,
 		"GET      /apps/{app_id}": g.swagGETAppByID,
 		"PATCH    /apps/{app_id}": g.swagPATCHAppByID,
 		"DELETE   /apps/{app_id}": g.swagDELETEAppByID,
 
+		"GET      /apps/{app_id}/versions": g.swagGETAppVersions,
+		"POST     /apps/{app_id}/schedule": g.swagPOSTAppSchedule,
+		"POST     /apps/{app_id}/verify":   g.swagPOSTAppVerify,
+
+		"GET      /publishers":                g.swagGETPublishers,
+		"POST     /publishers":                g.swagPOSTPublishers,
+		"GET      /publishers/{publisher_id}": g.swagGETPublisherByID,
+		"DELETE   /publishers/{publisher_id}": g.swagDELETEPublisherByID,
+
+		"GET      /zones":           g.swagGETZones,
+		"POST     /zones":           g.swagPOSTZones,
+		"GET      /zones/{zone_id}": g.swagGETZoneByID,
+		"PATCH    /zones/{zone_id}": g.swagPATCHZoneByID,
+		"DELETE   /zones/{zone_id}": g.swagDELETEZoneByID,
+
+		"GET      /rollouts":                     g.swagGETRollouts,
+		"POST     /rollouts":                     g.swagPOSTRollouts,
+		"GET      /rollouts/{rollout_id}":        g.swagGETRolloutByID,
+		"DELETE   /rollouts/{rollout_id}":        g.swagDELETERolloutByID,
+		"POST     /rollouts/{rollout_id}/pause":  g.swagPOSTRolloutPause,
+		"POST     /rollouts/{rollout_id}/resume": g.swagPOSTRolloutResume,
+
 		"GET      /nodes/{node_id}/dns": g.swagGETNodeDNS,
 		"PATCH    /nodes/{node_id}/dns": g.swagPATCHNodeDNS,
 		"DELETE   /nodes/{node_id}/dns": g.swagDELETENodeDNS,
@@ -149,13 +195,24 @@ func NewGorilla( //nolint:gocyclo
 		"PATCH    /nodes/{node_id}/interfaces":                g.swagPATCHInterfaces,
 		"GET      /nodes/{node_id}/interfaces/{interface_id}": g.swagGETInterfaceByID,
 
+		"GET      /nodes/{node_id}/zones":           g.swagGETNodeZones,
+		"POST     /nodes/{node_id}/zones":           g.swagPOSTNodeZone,
+		"GET      /nodes/{node_id}/zones/{zone_id}": g.swagGETNodeZoneByID,
+		"DELETE   /nodes/{node_id}/zones/{zone_id}": g.swagDELETENodeZoneByID,
+
+		"GET      /nodes/{node_id}/inventory": g.swagGETNodeInventory,
+
 		"GET      /nodes/{node_id}/apps":          g.swagGETNodeApps,
 		"POST     /nodes/{node_id}/apps":          g.swagPOSTNodeApp,
 		"GET      /nodes/{node_id}/apps/{app_id}": g.swagGETNodeAppsByID,
 		"PATCH    /nodes/{node_id}/apps/{app_id}": g.swagPATCHNodeAppsByID,
 		"DELETE   /nodes/{node_id}/apps/{app_id}": g.swagDELETENodeAppByID,
 
+		"GET      /nodes/{node_id}/apps/{app_id}/history": g.swagGETNodeAppHistory,
+
 		"GET      /nodes/{node_id}/nfd": g.swagGETNodeNFDTags,
+
+		"GET      /nodes/{node_id}/events": g.swagGETNodeEvents,
 	}
 
 	if controller.OrchestrationMode == cce.OrchestrationModeKubernetesOVN {
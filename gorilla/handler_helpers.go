@@ -7,16 +7,26 @@ import (
 	"context"
 	"crypto/tls"
 	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
 
 	cce "github.com/open-ness/edgecontroller"
 	"github.com/open-ness/edgecontroller/grpc/node"
 	"github.com/open-ness/edgecontroller/k8s"
+	elapb "github.com/open-ness/edgecontroller/pb/ela"
+	"github.com/open-ness/edgecontroller/swagger"
 	"github.com/pkg/errors"
+	coreV1 "k8s.io/api/core/v1"
 )
 
 const (
 	defaultELAPort = "42101"
 	defaultEVAPort = "42102"
+
+	// defaultExpiryWindow is how far ahead GET /credentials/expiring looks
+	defaultExpiryWindow = 30 * 24 * time.Hour
 )
 
 func connectNode(
@@ -50,16 +60,22 @@ func connectNode(
 		conf.ServerName = e.GetNodeID()
 	}
 
+	// Reuse a pooled connection if the controller keeps them
+	var conns cce.NodeConnectionService
+	if ctrl, ok := ctx.Value(contextKey("controller")).(*cce.Controller); ok {
+		conns = ctrl.NodeConns
+	}
+
 	log.Debugf("connectNode(%v): connecting to %v", e.GetNodeID(), target)
 
-	nodeCC := node.ClientConn{Addr: addr, Port: port, TLS: conf}
-	if err := nodeCC.Connect(ctx); err != nil {
+	nodeCC, err := node.Dial(ctx, conns, e.GetNodeID(), addr, port, conf)
+	if err != nil {
 		log.Noticef("Could not connect to node: %v", err)
 		return nil, errors.Wrap(err, "could not connect to node")
 	}
 	log.Debugf("Connection to node %s established: %s", e.GetNodeID(), addr)
 
-	return &nodeCC, nil
+	return nodeCC, nil
 }
 
 func disconnectNode(nodeCC *node.ClientConn) {
@@ -80,11 +96,319 @@ func toK8SApp(app *cce.App) k8s.App {
 		})
 	}
 
-	return k8s.App{
-		ID:     app.ID,
-		Image:  app.ID + ":latest",
-		Cores:  app.Cores,
-		Memory: app.Memory,
-		Ports:  ports,
+	k8sApp := k8s.App{
+		ID:            app.ID,
+		Image:         app.ID + ":latest",
+		Cores:         app.Cores,
+		Memory:        app.Memory,
+		Ports:         ports,
+		RestartPolicy: cce.RestartPolicyToK8s(app.RestartPolicy),
+		Resources:     app.EPAResourcesToK8s(),
+		PinnedCPUs:    app.EPAPinnedCPUs(),
+		Networks:      toK8SNetworks(app.EPANetworks()),
+	}
+	if app.Type == "vm" {
+		k8sApp.VM = &k8s.VM{Source: app.Source, DiskSize: app.DiskSize}
+	}
+	if app.Probes != nil {
+		k8sApp.LivenessProbe = app.Probes.Liveness.ToK8s()
+		k8sApp.ReadinessProbe = app.Probes.Readiness.ToK8s()
+		k8sApp.StartupProbe = app.Probes.Startup.ToK8s()
+	}
+	if app.Config == nil {
+		return k8sApp
 	}
+
+	k8sApp.Command = app.Config.Command
+	k8sApp.Args = app.Config.Args
+	for _, env := range app.Config.Env {
+		k8sApp.Env = append(k8sApp.Env, k8s.EnvVar{Name: env.Name, Value: env.Value})
+	}
+	for _, file := range app.Config.ConfigFiles {
+		k8sApp.ConfigFiles = append(k8sApp.ConfigFiles, k8s.ConfigFile{Path: file.Path, Content: file.Content})
+	}
+	for _, secret := range app.Config.Secrets {
+		k8sApp.Secrets = append(k8sApp.Secrets, k8s.SecretRef{
+			Name: secret.Name,
+			Key:  secret.Key,
+			Env:  secret.Env,
+			Path: secret.Path,
+		})
+	}
+	return k8sApp
+}
+
+// toK8SNodeApp converts app to the Kubernetes app deployed as nodeApp, in its
+// namespace and attached to its networks.
+func toK8SNodeApp(app *cce.App, nodeApp *cce.NodeApp) k8s.App {
+	k8sApp := toK8SApp(app)
+	k8sApp.Namespace = nodeApp.Namespace
+	k8sApp.Networks = append(k8sApp.Networks, toK8SNetworks(nodeApp.Networks)...)
+	if nodeApp.Service != nil {
+		k8sApp.Service = &k8s.Service{
+			Type:        coreV1.ServiceTypeClusterIP,
+			ExternalIPs: nodeApp.Service.ExternalIPs,
+		}
+		if nodeApp.Service.Type == cce.ServiceNodePort {
+			k8sApp.Service.Type = coreV1.ServiceTypeNodePort
+		}
+	}
+	return k8sApp
+}
+
+func toK8SNetworks(networks []cce.NetworkAttachment) []k8s.NetworkAttachment {
+	var k8sNetworks []k8s.NetworkAttachment
+	for _, network := range networks {
+		k8sNetworks = append(k8sNetworks, k8s.NetworkAttachment{
+			Name:      network.Name,
+			Interface: network.Interface,
+			IPs:       network.IPs,
+			MAC:       network.MAC,
+		})
+	}
+	return k8sNetworks
+}
+
+func fromK8SEndpoints(endpoints []k8s.Endpoint) []cce.AppEndpoint {
+	var appEndpoints []cce.AppEndpoint
+	for _, endpoint := range endpoints {
+		appEndpoints = append(appEndpoints, cce.AppEndpoint{
+			IP:       endpoint.IP,
+			Port:     int(endpoint.Port),
+			Protocol: endpoint.Protocol,
+		})
+	}
+	return appEndpoints
+}
+
+func fromK8SNetworkStatus(statuses []k8s.NetworkStatus) []cce.NetworkStatus {
+	var networkStatus []cce.NetworkStatus
+	for _, status := range statuses {
+		networkStatus = append(networkStatus, cce.NetworkStatus(status))
+	}
+	return networkStatus
+}
+
+// toNodeCredentials describes the current certificate of a node.
+func toNodeCredentials(c *cce.Credentials) (swagger.NodeCredentials, error) {
+	cert, err := c.ParseCertificate()
+	if err != nil {
+		return swagger.NodeCredentials{}, err
+	}
+	return swagger.NodeCredentials{
+		NodeID:    c.ID,
+		Serial:    cert.SerialNumber.Text(16),
+		NotBefore: cert.NotBefore,
+		NotAfter:  cert.NotAfter,
+		Revoked:   c.IsRevoked(cert.SerialNumber),
+	}, nil
+}
+
+// toInterfaceSummaries describes network interfaces.
+func toInterfaceSummaries(ifaces []*cce.NetworkInterface) []swagger.InterfaceSummary {
+	summaries := []swagger.InterfaceSummary{}
+	for _, iface := range ifaces {
+		summaries = append(summaries, swagger.InterfaceSummary{
+			ID:                iface.ID,
+			Description:       iface.Description,
+			Driver:            iface.Driver,
+			Type:              iface.Type,
+			MACAddress:        iface.MACAddress,
+			VLAN:              iface.VLAN,
+			Zones:             iface.Zones,
+			FallbackInterface: iface.FallbackInterface,
+		})
+	}
+	return summaries
+}
+
+// getNodeZone returns the assignment of a zone to a node or nil if the zone is
+// not assigned to it.
+func getNodeZone(ctx context.Context, ps cce.PersistenceService, nodeID, zoneID string) (*cce.NodeZone, error) {
+	nodeZones, err := ps.Filter(
+		ctx,
+		&cce.NodeZone{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+			{
+				Field: "zone_id",
+				Value: zoneID,
+			},
+		})
+	if err != nil {
+		return nil, err
+	}
+	if len(nodeZones) == 0 {
+		return nil, nil
+	}
+	return nodeZones[0].(*cce.NodeZone), nil
+}
+
+// getNodeZoneIDs returns the IDs of the zones assigned to a node.
+func getNodeZoneIDs(ctx context.Context, ps cce.PersistenceService, nodeID string) ([]string, error) {
+	nodeZones, err := ps.Filter(
+		ctx,
+		&cce.NodeZone{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	zoneIDs := []string{}
+	for _, nz := range nodeZones {
+		zoneIDs = append(zoneIDs, nz.(*cce.NodeZone).ZoneID)
+	}
+	return zoneIDs, nil
+}
+
+// toZoneSummary describes a network zone.
+func toZoneSummary(zone *cce.Zone) swagger.ZoneSummary {
+	return swagger.ZoneSummary{
+		ID:          zone.ID,
+		Name:        zone.Name,
+		Description: zone.Description,
+	}
+}
+
+// toNetworkZone converts a zone to the network zone of the ELA.
+func toNetworkZone(zone *cce.Zone) *elapb.NetworkZone {
+	return &elapb.NetworkZone{
+		Id:          zone.ID,
+		Description: zone.Description,
+	}
+}
+
+// toNodeInventory describes the inventory of a node.
+func toNodeInventory(inv *cce.NodeInventory) swagger.NodeInventory {
+	resp := swagger.NodeInventory{
+		NodeID:      inv.NodeID,
+		CollectedAt: inv.CollectedAt,
+		Interfaces:  toInterfaceSummaries(inv.Interfaces),
+		Features:    inv.Features,
+		Apps:        inv.Apps,
+		History:     []swagger.InterfaceChange{},
+	}
+	if resp.Features == nil {
+		resp.Features = map[string]string{}
+	}
+	if resp.Apps == nil {
+		resp.Apps = []string{}
+	}
+	for _, change := range inv.History {
+		c := swagger.InterfaceChange{At: change.At}
+		if len(change.Added) > 0 {
+			c.Added = toInterfaceSummaries(change.Added)
+		}
+		if len(change.Removed) > 0 {
+			c.Removed = toInterfaceSummaries(change.Removed)
+		}
+		if len(change.Changed) > 0 {
+			c.Changed = toInterfaceSummaries(change.Changed)
+		}
+		resp.History = append(resp.History, c)
+	}
+	return resp
+}
+
+// toRolloutSummary describes a rollout.
+func toRolloutSummary(r *cce.Rollout) swagger.RolloutSummary {
+	return swagger.RolloutSummary{
+		ID:    r.ID,
+		AppID: r.AppID,
+		State: r.State,
+		Wave:  r.Wave,
+		Waves: r.Waves(),
+	}
+}
+
+// toRolloutDetail describes a rollout and its targets.
+func toRolloutDetail(r *cce.Rollout) swagger.RolloutDetail {
+	resp := swagger.RolloutDetail{
+		RolloutSummary:   toRolloutSummary(r),
+		Canary:           r.Canary,
+		BatchSize:        r.BatchSize,
+		FailureThreshold: r.FailureThreshold,
+		OnFailure:        r.OnFailure,
+		VerifyTimeout:    r.VerifyTimeout,
+		Reason:           r.Reason,
+		Targets:          []swagger.RolloutTarget{},
+		CreatedAt:        r.CreatedAt,
+		UpdatedAt:        r.UpdatedAt,
+	}
+	for _, t := range r.Targets {
+		resp.Targets = append(resp.Targets, swagger.RolloutTarget{
+			NodeID:    t.NodeID,
+			Wave:      t.Wave,
+			FromAppID: t.FromAppID,
+			Status:    t.Status,
+			Command:   t.Cmd,
+			StartedAt: t.StartedAt,
+			Error:     t.Error,
+		})
+	}
+	return resp
+}
+
+// verifyAppImage fetches the image of an app from its source and checks it
+// against the digest and, for a signed app, the signature of the publisher.
+func verifyAppImage(ctx context.Context, app *cce.App) (statusCode int, err error) {
+	if app.Digest == "" {
+		return http.StatusUnprocessableEntity, fmt.Errorf("app_id %s has no digest to verify", app.ID)
+	}
+	if len(app.Signature) > 0 {
+		if err = app.VerifySignature(app.PublisherKey); err != nil {
+			return http.StatusUnprocessableEntity, err
+		}
+	}
+
+	u, err := url.Parse(app.Source)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return http.StatusUnprocessableEntity, fmt.Errorf("source of app_id %s cannot be fetched", app.ID)
+	}
+	hosts := getController(ctx).AppImageHosts
+	if !allowedHost(u, hosts) {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"source host %s of app_id %s is not allowed", u.Hostname(), app.ID)
+	}
+	req, err := http.NewRequest(http.MethodGet, app.Source, nil)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	resp, err := appImageClient(hosts).Do(req.WithContext(ctx))
+	if err != nil {
+		return http.StatusBadGateway, errors.Wrap(err, "error fetching source")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return http.StatusBadGateway, errors.Errorf("error fetching source: %s", resp.Status)
+	}
+	if resp.ContentLength > maxAppImageSize {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"image of app_id %s is larger than %d bytes", app.ID, int64(maxAppImageSize))
+	}
+
+	// Read one more byte than allowed to tell an image that is too large
+	body := &io.LimitedReader{R: resp.Body, N: maxAppImageSize + 1}
+	var digest string
+	if digest, err = cce.ComputeDigest(body); err != nil {
+		return http.StatusBadGateway, errors.Wrap(err, "error fetching source")
+	}
+	if body.N == 0 {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"image of app_id %s is larger than %d bytes", app.ID, int64(maxAppImageSize))
+	}
+	if digest != app.Digest {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"digest mismatch: expected %s, got %s", app.Digest, digest)
+	}
+
+	return 0, nil
 }
@@ -0,0 +1,73 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package gorilla
+
+import (
+	"context"
+	"fmt"
+
+	cce "github.com/open-ness/edgecontroller"
+)
+
+// NodeAppOperator operates the apps deployed to nodes for rollouts, making
+// the same checks and remote calls as the node apps endpoints.
+type NodeAppOperator struct {
+	Controller *cce.Controller
+}
+
+// Deploy deploys app to the node.
+func (o *NodeAppOperator) Deploy(ctx context.Context, nodeID string, app *cce.App) error {
+	_, _, err := deployNodeApp(o.context(ctx), o.Controller.PersistenceService, nodeID, app)
+	return err
+}
+
+// SetVersion replaces the version of the node app with app. Cmd is upgrade or
+// rollback.
+func (o *NodeAppOperator) SetVersion(ctx context.Context, nodeApp *cce.NodeApp, app *cce.App, cmd string) error {
+	ctx = o.context(ctx)
+	ps := o.Controller.PersistenceService
+
+	current, err := ps.Read(ctx, nodeApp.AppID, &cce.App{})
+	if err != nil {
+		return err
+	}
+	if current == nil {
+		return fmt.Errorf("app_id %s not found", nodeApp.AppID)
+	}
+
+	_, err = setNodeAppVersion(ctx, ps, nodeApp, current.(*cce.App), app, cmd)
+	return err
+}
+
+// Undeploy removes the app from the node.
+func (o *NodeAppOperator) Undeploy(ctx context.Context, nodeApp *cce.NodeApp) error {
+	ctx = o.context(ctx)
+	ps := o.Controller.PersistenceService
+
+	if _, err := checkDBDeleteNodesApps(ctx, ps, nodeApp.ID); err != nil {
+		return err
+	}
+	return undeployNodeApp(ctx, ps, nodeApp)
+}
+
+// Start starts the app on the node.
+func (o *NodeAppOperator) Start(ctx context.Context, nodeApp *cce.NodeApp) error {
+	_, err := handleUpdateNodesApps(o.context(ctx), o.Controller.PersistenceService,
+		&cce.NodeAppReq{NodeApp: *nodeApp, Cmd: "start"})
+	return err
+}
+
+// Status returns the lifecycle status of the app on the node.
+func (o *NodeAppOperator) Status(ctx context.Context, nodeApp *cce.NodeApp) (string, error) {
+	resp, err := handleGetNodesApps(o.context(ctx), o.Controller.PersistenceService, nodeApp)
+	if err != nil {
+		return "", err
+	}
+	return resp.(*cce.NodeAppResp).Status, nil
+}
+
+// context gives the handlers access to the controller.
+func (o *NodeAppOperator) context(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contextKey("controller"), o.Controller)
+}
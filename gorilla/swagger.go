@@ -20,14 +20,23 @@ package gorilla
 import (
 	"context"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
+	"time"
 
 	"github.com/gorilla/mux"
 	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/inventory"
+	"github.com/open-ness/edgecontroller/liveness"
 	"github.com/open-ness/edgecontroller/nfd-master"
+	"github.com/open-ness/edgecontroller/rollout"
+	"github.com/open-ness/edgecontroller/scheduler"
 	"github.com/open-ness/edgecontroller/swagger"
 	"github.com/open-ness/edgecontroller/uuid"
+	"github.com/pkg/errors"
 )
 
 // The following handlers are compliant to our published Swagger (OpenAPI 3.0) schema.
@@ -44,15 +53,52 @@ func (g *Gorilla) swagGETNodes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Fetch the node statuses and index them by node
+	statuses, err := ctrl.PersistenceService.ReadAll(r.Context(), &cce.NodeStatus{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	statusByNode := make(map[string]*cce.NodeStatus)
+	for _, s := range statuses {
+		statusByNode[s.(*cce.NodeStatus).NodeID] = s.(*cce.NodeStatus)
+	}
+
+	// Fetch the NFD features and the committed resources of the nodes
+	features, err := ctrl.PersistenceService.ReadAll(r.Context(), &nfd.NodeFeatureNFD{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	featuresByNode := make(map[string]map[string]string)
+	for _, f := range features {
+		feature := f.(*nfd.NodeFeatureNFD)
+		if featuresByNode[feature.NodeID] == nil {
+			featuresByNode[feature.NodeID] = make(map[string]string)
+		}
+		featuresByNode[feature.NodeID][feature.NfdID] = feature.NfdValue
+	}
+	committed, err := cce.CommittedResources(r.Context(), ctrl.PersistenceService)
+	if err != nil {
+		log.Errf("Error reading committed resources: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	// Construct the response object
 	nodes := swagger.NodeList{Nodes: []swagger.NodeSummary{}}
 	for _, n := range persisted {
 		node := swagger.NodeSummary{
-			ID:       n.(*cce.Node).ID,
-			Name:     n.(*cce.Node).Name,
-			Location: n.(*cce.Node).Location,
-			Serial:   n.(*cce.Node).Serial,
+			ID:              n.(*cce.Node).ID,
+			Name:            n.(*cce.Node).Name,
+			Location:        n.(*cce.Node).Location,
+			Serial:          n.(*cce.Node).Serial,
+			SerialAlgorithm: cce.FingerprintAlgorithm(n.(*cce.Node).Serial),
+			Labels:          n.(*cce.Node).Labels,
+			Allocatable:     cce.Allocatable(n.(*cce.Node), featuresByNode[n.GetID()]),
+			Committed:       committed[n.GetID()],
 		}
+		setNodeStatus(&node, statusByNode[node.ID])
 		nodes.Nodes = append(nodes.Nodes, node)
 	}
 
@@ -89,15 +135,58 @@ func (g *Gorilla) swagGETNodeByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Fetch the node status
+	status, err := liveness.GetStatus(r.Context(), ctrl.PersistenceService, persisted.GetID())
+	if err != nil {
+		log.Errf("Error reading node status: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Fetch the NFD features and the committed resources of the node
+	features, err := getNfdFeatures(r.Context(), persisted.GetID())
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	committed, err := cce.CommittedResources(r.Context(), ctrl.PersistenceService)
+	if err != nil {
+		log.Errf("Error reading committed resources: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
 	// Construct the response object
 	node := swagger.NodeDetail{
 		NodeSummary: swagger.NodeSummary{
-			ID:       persisted.(*cce.Node).ID,
-			Name:     persisted.(*cce.Node).Name,
-			Location: persisted.(*cce.Node).Location,
-			Serial:   persisted.(*cce.Node).Serial,
+			ID:              persisted.(*cce.Node).ID,
+			Name:            persisted.(*cce.Node).Name,
+			Location:        persisted.(*cce.Node).Location,
+			Serial:          persisted.(*cce.Node).Serial,
+			SerialAlgorithm: cce.FingerprintAlgorithm(persisted.(*cce.Node).Serial),
+			Labels:          persisted.(*cce.Node).Labels,
+			Allocatable:     cce.Allocatable(persisted.(*cce.Node), features),
+			Committed:       committed[persisted.GetID()],
 		},
 	}
+	setNodeStatus(&node.NodeSummary, status)
+	if status != nil {
+		node.Latency = status.Latency
+	}
+
+	// Fetch the node addresses
+	targets, err := ctrl.PersistenceService.Filter(r.Context(), &cce.NodeGRPCTarget{}, []cce.Filter{{
+		Field: "node_id",
+		Value: persisted.GetID(),
+	}})
+	if err != nil {
+		log.Errf("Error reading node address: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(targets) > 0 {
+		setNodeAddresses(&node, targets[0].(*cce.NodeGRPCTarget))
+	}
 
 	// Marshal the response object to JSON
 	nodeJSON, err := json.Marshal(node)
@@ -127,10 +216,12 @@ func (g *Gorilla) swagPATCHNodeByID(w http.ResponseWriter, r *http.Request) {
 
 	// Convert it to a persistable object
 	persisted := cce.Node{
-		ID:       mux.Vars(r)["node_id"],
-		Name:     node.Name,
-		Location: node.Location,
-		Serial:   node.Serial,
+		ID:          mux.Vars(r)["node_id"],
+		Name:        node.Name,
+		Location:    node.Location,
+		Serial:      node.Serial,
+		Labels:      node.Labels,
+		Allocatable: node.Allocatable,
 	}
 
 	// Validate the object
@@ -196,6 +287,265 @@ func (g *Gorilla) swagDELETENodeByID(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+
+	if ctrl.NodeConns != nil {
+		ctrl.NodeConns.Invalidate(mux.Vars(r)["node_id"])
+	}
+}
+
+// Used for POST /nodes/{node_id}/decommission endpoint
+func (g *Gorilla) swagPOSTNodeDecommission(w http.ResponseWriter, r *http.Request) {
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	nodeID := mux.Vars(r)["node_id"]
+
+	// Unreachable-node steps are skipped with ?force or ?force=true
+	force := false
+	if v, ok := r.URL.Query()["force"]; ok {
+		force = true
+		if len(v) > 0 && v[0] != "" {
+			var err error
+			if force, err = strconv.ParseBool(v[0]); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+		}
+	}
+
+	// Fetch the node from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), nodeID, &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	d := newDecommissioner(ctrl, nodeID, force)
+	statusCode, err := d.run(r.Context())
+	if err != nil {
+		log.Errf("Error decommissioning node %s: %v", nodeID, err)
+	}
+
+	// Report the progress, also when a step failed
+	reportJSON, err := json.Marshal(d.report)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if _, err = w.Write(reportJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for POST /nodes/{node_id}/credentials/revoke endpoint
+func (g *Gorilla) swagPOSTNodeCredentialsRevoke(w http.ResponseWriter, r *http.Request) {
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	nodeID := mux.Vars(r)["node_id"]
+
+	// Fetch the node from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), nodeID, &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	revoked, err := cce.RevokeCredentials(r.Context(), ctrl.PersistenceService, nodeID)
+	if err != nil {
+		log.Errf("Error revoking credentials of node %s: %v", nodeID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if revoked == nil {
+		// The node never enrolled
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	log.Infof("Revoked certificate %s of node %s", revoked.Serial, nodeID)
+
+	revokedJSON, err := json.Marshal(swagger.RevokedCredentials{
+		NodeID:    nodeID,
+		Serial:    revoked.Serial,
+		RevokedAt: revoked.RevokedAt,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(revokedJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /nodes/{node_id}/credentials endpoint
+func (g *Gorilla) swagGETNodeCredentials(w http.ResponseWriter, r *http.Request) {
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	nodeID := mux.Vars(r)["node_id"]
+
+	// Fetch the credentials from persistence and check if they're there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), nodeID, &cce.Credentials{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	creds, err := toNodeCredentials(persisted.(*cce.Credentials))
+	if err != nil {
+		log.Errf("Error reading credentials of node %s: %v", nodeID, err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	credsJSON, err := json.Marshal(creds)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(credsJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /credentials/expiring endpoint
+func (g *Gorilla) swagGETExpiringCredentials(w http.ResponseWriter, r *http.Request) {
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Report certificates expiring within ?within=<duration>
+	within := defaultExpiryWindow
+	if v := r.URL.Query().Get("within"); v != "" {
+		var err error
+		if within, err = time.ParseDuration(v); err != nil || within < 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	deadline := time.Now().Add(within)
+
+	persisted, err := ctrl.PersistenceService.ReadAll(r.Context(), &cce.Credentials{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object, soonest expiry first. Revoked
+	// certificates will not be renewed, so they are left out.
+	list := swagger.NodeCredentialsList{Credentials: []swagger.NodeCredentials{}}
+	for _, p := range persisted {
+		creds, err := toNodeCredentials(p.(*cce.Credentials))
+		if err != nil {
+			log.Errf("Error reading credentials of node %s: %v", p.GetID(), err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if !creds.Revoked && creds.NotAfter.Before(deadline) {
+			list.Credentials = append(list.Credentials, creds)
+		}
+	}
+	sort.SliceStable(list.Credentials, func(i, j int) bool {
+		return list.Credentials[i].NotAfter.Before(list.Credentials[j].NotAfter)
+	})
+
+	listJSON, err := json.Marshal(list)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(listJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for POST /nodes/{node_id}/enrollment-tokens endpoint
+func (g *Gorilla) swagPOSTNodeEnrollmentTokens(w http.ResponseWriter, r *http.Request) {
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	nodeID := mux.Vars(r)["node_id"]
+
+	// Issue the token for ?ttl=<duration>
+	ttl := cce.DefaultEnrollmentTokenTTL
+	if v := r.URL.Query().Get("ttl"); v != "" {
+		var err error
+		if ttl, err = time.ParseDuration(v); err != nil || ttl <= 0 || ttl > cce.MaxEnrollmentTokenTTL {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Fetch the node from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), nodeID, &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	token, enrollmentToken, err := cce.NewEnrollmentToken(nodeID, ttl)
+	if err != nil {
+		log.Errf("Error generating enrollment token: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err = ctrl.PersistenceService.Create(r.Context(), enrollmentToken); err != nil {
+		log.Errf("Error storing enrollment token: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	log.Infof("Issued enrollment token %s for node %s", enrollmentToken.ID, nodeID)
+
+	tokenJSON, err := json.Marshal(swagger.EnrollmentToken{
+		NodeID:    nodeID,
+		Token:     token,
+		ExpiresAt: enrollmentToken.ExpiresAt,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if _, err = w.Write(tokenJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /crl endpoint
+func (g *Gorilla) swagGETCRL(w http.ResponseWriter, r *http.Request) {
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	revoked, err := cce.RevocationList(r.Context(), ctrl.PersistenceService)
+	if err != nil {
+		log.Errf("Error reading revoked credentials: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	crl, err := ctrl.AuthorityService.CRL(revoked)
+	if err != nil {
+		log.Errf("Error creating CRL: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-pem-file")
+	if err = pem.Encode(w, &pem.Block{Type: "X509 CRL", Bytes: crl}); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
 }
 
 // Used for GET /apps endpoint
@@ -236,6 +586,55 @@ func (g *Gorilla) swagGETApps(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Used for GET /apps/{app_id}/versions endpoint
+func (g *Gorilla) swagGETAppVersions(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["app_id"], &cce.App{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Fetch the versions of the app
+	versions, err := cce.AppVersions(r.Context(), ctrl.PersistenceService, persisted.(*cce.App))
+	if err != nil {
+		log.Errf("Error reading app versions: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	apps := swagger.AppList{Apps: []swagger.AppSummary{}}
+	for _, v := range versions {
+		apps.Apps = append(apps.Apps, swagger.AppSummary{
+			ID:          v.ID,
+			Type:        v.Type,
+			Name:        v.Name,
+			Version:     v.Version,
+			Vendor:      v.Vendor,
+			Description: v.Description,
+		})
+	}
+
+	// Marshal the response object to JSON
+	appsJSON, err := json.Marshal(apps)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(appsJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
 // Used for POST /apps endpoint
 func (g *Gorilla) swagPOSTApps(w http.ResponseWriter, r *http.Request) {
 	g.appsHandler.create(w, r)
@@ -267,11 +666,18 @@ func (g *Gorilla) swagGETAppByID(w http.ResponseWriter, r *http.Request) {
 			Vendor:      persisted.(*cce.App).Vendor,
 			Description: persisted.(*cce.App).Description,
 		},
-		Cores:       persisted.(*cce.App).Cores,
-		Memory:      persisted.(*cce.App).Memory,
-		Source:      persisted.(*cce.App).Source,
-		Ports:       persisted.(*cce.App).Ports,
-		EPAFeatures: persisted.(*cce.App).EPAFeatures,
+		Cores:         persisted.(*cce.App).Cores,
+		Memory:        persisted.(*cce.App).Memory,
+		Source:        persisted.(*cce.App).Source,
+		Ports:         persisted.(*cce.App).Ports,
+		EPAFeatures:   persisted.(*cce.App).EPAFeatures,
+		Config:        persisted.(*cce.App).Config,
+		Probes:        persisted.(*cce.App).Probes,
+		RestartPolicy: persisted.(*cce.App).RestartPolicy,
+		DiskSize:      persisted.(*cce.App).DiskSize,
+		Digest:        persisted.(*cce.App).Digest,
+		Signature:     persisted.(*cce.App).Signature,
+		PublisherID:   persisted.(*cce.App).PublisherID,
 	}
 
 	// Marshal the response object to JSON
@@ -302,17 +708,24 @@ func (g *Gorilla) swagPATCHAppByID(w http.ResponseWriter, r *http.Request) {
 
 	// Convert it to a persistable object
 	persisted := cce.App{
-		ID:          app.ID,
-		Type:        app.Type,
-		Name:        app.Name,
-		Version:     app.Version,
-		Vendor:      app.Vendor,
-		Description: app.Description,
-		Cores:       app.Cores,
-		Memory:      app.Memory,
-		Source:      app.Source,
-		Ports:       app.Ports,
-		EPAFeatures: app.EPAFeatures,
+		ID:            app.ID,
+		Type:          app.Type,
+		Name:          app.Name,
+		Version:       app.Version,
+		Vendor:        app.Vendor,
+		Description:   app.Description,
+		Cores:         app.Cores,
+		Memory:        app.Memory,
+		Source:        app.Source,
+		Ports:         app.Ports,
+		EPAFeatures:   app.EPAFeatures,
+		Config:        app.Config,
+		Probes:        app.Probes,
+		RestartPolicy: app.RestartPolicy,
+		DiskSize:      app.DiskSize,
+		Digest:        app.Digest,
+		Signature:     app.Signature,
+		PublisherID:   app.PublisherID,
 	}
 
 	// Validate the object
@@ -326,6 +739,17 @@ func (g *Gorilla) swagPATCHAppByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Verify the signature with the key of the publisher
+	if statusCode, err := checkDBCreateApps(r.Context(), ctrl.PersistenceService, &persisted); err != nil {
+		log.Errf("Error checking DB create: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
 	// Persist the object
 	if err := ctrl.PersistenceService.BulkUpdate(r.Context(), []cce.Persistable{&persisted}); err != nil {
 		log.Errf("Error updating entities: %v", err)
@@ -350,23 +774,697 @@ func (g *Gorilla) swagDELETEAppByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Fetch the entity from persistence and check if it's there
-	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["app_id"], &cce.App{})
-	if err != nil {
-		log.Errf("Error reading entity: %v", err)
-		w.WriteHeader(http.StatusInternalServerError)
-		_, err = w.Write([]byte(err.Error()))
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["app_id"], &cce.App{})
+	if err != nil {
+		log.Errf("Error reading entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	ok, err := ctrl.PersistenceService.Delete(r.Context(), mux.Vars(r)["app_id"], &cce.App{})
+	if err != nil {
+		log.Errf("Error deleting entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// we just fetched the entity, so if !ok then something went wrong
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Used for POST /apps/{app_id}/verify endpoint
+func (g *Gorilla) swagPOSTAppVerify(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["app_id"], &cce.App{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	app := persisted.(*cce.App)
+
+	// Fetch the image and check it against the digest and signature
+	statusCode, err := verifyAppImage(r.Context(), app)
+	if err != nil {
+		log.Errf("Error verifying app %s: %v", app.ID, err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Marshal the response object to JSON
+	resultJSON, err := json.Marshal(swagger.AppVerification{
+		ID:     app.ID,
+		Digest: app.Digest,
+		Signed: len(app.Signature) > 0,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(resultJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for POST /apps/{app_id}/schedule endpoint
+func (g *Gorilla) swagPOSTAppSchedule(w http.ResponseWriter, r *http.Request) { //nolint:gocyclo
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload, which may be omitted to use the defaults
+	req := swagger.ScheduleRequest{}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			log.Errf("Error unmarshaling json: %v", err)
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+	schedReq := scheduler.Request{
+		Replicas:     req.Replicas,
+		Strategy:     req.Strategy,
+		NodeSelector: req.NodeSelector,
+		Affinity:     req.Affinity,
+		AntiAffinity: req.AntiAffinity,
+	}
+
+	// Validate the request
+	if err := schedReq.Validate(); err != nil {
+		log.Debugf("Validation failed for %#v: %v", schedReq, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Fetch the entity from persistence and check if it's there
+	app, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["app_id"], &cce.App{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if app == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Choose the nodes
+	candidates, err := scheduler.Candidates(r.Context(), ctrl.PersistenceService)
+	if err != nil {
+		log.Errf("Error loading scheduling candidates: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	decision, err := scheduler.Schedule(app.(*cce.App), candidates, schedReq)
+	if err != nil {
+		log.Errf("Error scheduling app %s: %v", app.GetID(), err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	result := swagger.ScheduleResult{
+		AppID:     app.GetID(),
+		Scheduled: []swagger.ScheduledNode{},
+		Excluded:  []swagger.ExcludedNode{},
+	}
+	for _, p := range decision.Selected {
+		result.Scheduled = append(result.Scheduled, swagger.ScheduledNode{NodeID: p.NodeID, Score: p.Score})
+	}
+	for _, e := range decision.Excluded {
+		result.Excluded = append(result.Excluded, swagger.ExcludedNode{NodeID: e.NodeID, Reasons: e.Reasons})
+	}
+
+	statusCode := http.StatusCreated
+	switch {
+	case len(decision.Selected) < schedReq.Replicas || len(decision.Selected) == 0:
+		// Deploy nothing unless every replica can be placed
+		log.Infof("Unable to schedule app %s: %d node(s) fit", app.GetID(), len(decision.Selected))
+		statusCode = http.StatusUnprocessableEntity
+	case req.DryRun:
+		statusCode = http.StatusOK
+	default:
+		// Deploy the app to the chosen nodes, or to none of them
+		var deployed []*cce.NodeApp
+		for i, p := range decision.Selected {
+			nodeApp, code, err := deployNodeApp(r.Context(), ctrl.PersistenceService, p.NodeID, app.(*cce.App))
+			if err != nil {
+				log.Errf("Error deploying app %s to node %s: %v", app.GetID(), p.NodeID, err)
+				result.Scheduled[i].Error = err.Error()
+				statusCode = code
+				break
+			}
+			log.Infof("App %s scheduled to node %s (score %d)", app.GetID(), p.NodeID, p.Score)
+			deployed = append(deployed, nodeApp)
+		}
+		if len(deployed) == len(decision.Selected) {
+			break
+		}
+		for i, nodeApp := range deployed {
+			if err = undeployNodeApp(r.Context(), ctrl.PersistenceService, nodeApp); err != nil {
+				log.Errf("Error rolling back app %s on node %s: %v", app.GetID(), nodeApp.NodeID, err)
+				result.Scheduled[i].Error = fmt.Sprintf("rollback failed: %v", err)
+			}
+		}
+	}
+
+	// Marshal the response object to JSON
+	resultJSON, err := json.Marshal(result)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if _, err = w.Write(resultJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /zones endpoint
+func (g *Gorilla) swagGETZones(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the zones from persistence
+	persisted, err := ctrl.PersistenceService.ReadAll(r.Context(), &cce.Zone{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	zones := swagger.ZoneList{Zones: []swagger.ZoneSummary{}}
+	for _, z := range persisted {
+		zones.Zones = append(zones.Zones, toZoneSummary(z.(*cce.Zone)))
+	}
+
+	// Marshal the response object to JSON
+	zonesJSON, err := json.Marshal(zones)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(zonesJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for POST /zones endpoint
+func (g *Gorilla) swagPOSTZones(w http.ResponseWriter, r *http.Request) {
+	g.zonesHandler.create(w, r)
+}
+
+// Used for GET /zones/{zone_id} endpoint
+func (g *Gorilla) swagGETZoneByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["zone_id"], &cce.Zone{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Fetch the nodes the zone is assigned to
+	nodeZones, err := ctrl.PersistenceService.Filter(
+		r.Context(),
+		&cce.NodeZone{},
+		[]cce.Filter{
+			{
+				Field: "zone_id",
+				Value: persisted.GetID(),
+			},
+		})
+	if err != nil {
+		log.Errf("Error filtering nodes_zones: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	zone := swagger.ZoneDetail{
+		ZoneSummary: toZoneSummary(persisted.(*cce.Zone)),
+		Nodes:       []string{},
+	}
+	for _, nz := range nodeZones {
+		zone.Nodes = append(zone.Nodes, nz.(*cce.NodeZone).NodeID)
+	}
+	sort.Strings(zone.Nodes)
+
+	// Marshal the response object to JSON
+	zoneJSON, err := json.Marshal(zone)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(zoneJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for PATCH /zones/{zone_id} endpoint
+func (g *Gorilla) swagPATCHZoneByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	zone := swagger.ZoneSummary{}
+	if err := json.Unmarshal(body, &zone); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["zone_id"], &cce.Zone{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Convert it to a persistable object
+	requested := cce.Zone{
+		ID:          persisted.GetID(),
+		Name:        zone.Name,
+		Description: zone.Description,
+	}
+
+	// Validate the object
+	if err = requested.Validate(); err != nil {
+		log.Debugf("Validation failed for %#v: %v", requested, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Check that the name is still unique
+	if statusCode, err := checkDBCreateZones(r.Context(), ctrl.PersistenceService, &requested); err != nil {
+		log.Errf("Error checking DB create: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Update the zone on the nodes it is assigned to
+	if statusCode, err := handleUpdateZones(r.Context(), ctrl.PersistenceService, &requested); err != nil {
+		log.Errf("Error updating remote entities: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Persist the object
+	if err := ctrl.PersistenceService.BulkUpdate(r.Context(), []cce.Persistable{&requested}); err != nil {
+		log.Errf("Error updating entities: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Used for DELETE /zones/{zone_id} endpoint
+func (g *Gorilla) swagDELETEZoneByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["zone_id"], &cce.Zone{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Check that we can delete the entity
+	if statusCode, err := checkDBDeleteZones(r.Context(), ctrl.PersistenceService, persisted.GetID()); err != nil {
+		log.Errf("Error running DB logic: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	ok, err := ctrl.PersistenceService.Delete(r.Context(), persisted.GetID(), &cce.Zone{})
+	if err != nil {
+		log.Errf("Error deleting entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// we just fetched the entity, so if !ok then something went wrong
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Used for GET /publishers endpoint
+func (g *Gorilla) swagGETPublishers(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the publishers from persistence
+	persisted, err := ctrl.PersistenceService.ReadAll(r.Context(), &cce.Publisher{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	publishers := swagger.PublisherList{Publishers: []swagger.PublisherSummary{}}
+	for _, p := range persisted {
+		publishers.Publishers = append(publishers.Publishers, swagger.PublisherSummary{
+			ID:   p.(*cce.Publisher).ID,
+			Name: p.(*cce.Publisher).Name,
+		})
+	}
+
+	// Marshal the response object to JSON
+	publishersJSON, err := json.Marshal(publishers)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(publishersJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for POST /publishers endpoint
+func (g *Gorilla) swagPOSTPublishers(w http.ResponseWriter, r *http.Request) {
+	g.publishersHandler.create(w, r)
+}
+
+// Used for GET /publishers/{publisher_id} endpoint
+func (g *Gorilla) swagGETPublisherByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["publisher_id"], &cce.Publisher{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Construct the response object
+	publisher := swagger.PublisherDetail{
+		PublisherSummary: swagger.PublisherSummary{
+			ID:   persisted.(*cce.Publisher).ID,
+			Name: persisted.(*cce.Publisher).Name,
+		},
+		PublicKey: persisted.(*cce.Publisher).PublicKey,
+	}
+
+	// Marshal the response object to JSON
+	publisherJSON, err := json.Marshal(publisher)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(publisherJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for DELETE /publishers/{publisher_id} endpoint
+func (g *Gorilla) swagDELETEPublisherByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["publisher_id"], &cce.Publisher{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Check that we can delete the entity
+	if statusCode, err := checkDBDeletePublishers(r.Context(), ctrl.PersistenceService, persisted.GetID()); err != nil {
+		log.Errf("Error running DB logic: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	ok, err := ctrl.PersistenceService.Delete(r.Context(), persisted.GetID(), &cce.Publisher{})
+	if err != nil {
+		log.Errf("Error deleting entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// we just fetched the entity, so if !ok then something went wrong
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
+
+// Used for GET /rollouts endpoint
+func (g *Gorilla) swagGETRollouts(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the rollouts from persistence
+	persisted, err := ctrl.PersistenceService.ReadAll(r.Context(), &cce.Rollout{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	rollouts := swagger.RolloutList{Rollouts: []swagger.RolloutSummary{}}
+	for _, ro := range persisted {
+		rollouts.Rollouts = append(rollouts.Rollouts, toRolloutSummary(ro.(*cce.Rollout)))
+	}
+
+	// Marshal the response object to JSON
+	rolloutsJSON, err := json.Marshal(rollouts)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(rolloutsJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for POST /rollouts endpoint
+func (g *Gorilla) swagPOSTRollouts(w http.ResponseWriter, r *http.Request) { //nolint:gocyclo
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	req := swagger.RolloutRequest{}
+	if err := json.Unmarshal(body, &req); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Error unmarshaling json: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Convert it to a persistable object
+	now := time.Now().UTC()
+	requested := cce.Rollout{
+		ID:               uuid.New(),
+		AppID:            req.AppID,
+		NodeIDs:          req.NodeIDs,
+		Canary:           req.Canary,
+		BatchSize:        req.BatchSize,
+		FailureThreshold: req.FailureThreshold,
+		OnFailure:        req.OnFailure,
+		VerifyTimeout:    req.VerifyTimeout,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	requested.SetDefaults()
+
+	// Validate the object
+	if err := requested.Validate(); err != nil {
+		log.Debugf("Validation failed for %#v: %v", requested, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Check that the rollout can be made
+	statusCode, err := checkDBCreateRollouts(r.Context(), ctrl.PersistenceService, &requested)
+	if err != nil {
+		log.Errf("Error checking DB create: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Plan the waves
+	app, err := ctrl.PersistenceService.Read(r.Context(), requested.AppID, &cce.App{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err = rollout.Plan(r.Context(), ctrl.PersistenceService, &requested, app.(*cce.App)); err != nil {
+		log.Errf("Error planning rollout: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if requested.Waves() == 0 {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, err = w.Write([]byte(fmt.Sprintf("no node to roll out app_id %s to", requested.AppID)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Persist the object
+	if err = ctrl.PersistenceService.Create(r.Context(), &requested); err != nil {
+		log.Errf("Error creating entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	log.Infof("Rollout %s of app %s to %d node(s) in %d wave(s)",
+		requested.ID, requested.AppID, len(requested.Targets), requested.Waves())
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	if _, err = w.Write([]byte(fmt.Sprintf(`{"id":"%s"}`, requested.ID))); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /rollouts/{rollout_id} endpoint
+func (g *Gorilla) swagGETRolloutByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["rollout_id"], &cce.Rollout{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Marshal the response object to JSON
+	rolloutJSON, err := json.Marshal(toRolloutDetail(persisted.(*cce.Rollout)))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(rolloutJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for DELETE /rollouts/{rollout_id} endpoint
+func (g *Gorilla) swagDELETERolloutByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["rollout_id"], &cce.Rollout{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// A running rollout must be paused first, the nodes are left as they are
+	if persisted.(*cce.Rollout).State == cce.RolloutRunning {
+		w.WriteHeader(http.StatusConflict)
+		_, err = w.Write([]byte(fmt.Sprintf(
+			"cannot delete rollout_id %s: rollout is running", persisted.GetID())))
 		if err != nil {
 			log.Errf("Error writing response: %v", err)
 		}
 		return
 	}
-	if persisted == nil {
-		w.WriteHeader(http.StatusNotFound)
-		return
-	}
 
-	ok, err := ctrl.PersistenceService.Delete(r.Context(), mux.Vars(r)["app_id"], &cce.App{})
+	ok, err := ctrl.PersistenceService.Delete(r.Context(), persisted.GetID(), &cce.Rollout{})
 	if err != nil {
 		log.Errf("Error deleting entity: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -380,6 +1478,58 @@ func (g *Gorilla) swagDELETEAppByID(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Used for POST /rollouts/{rollout_id}/pause endpoint
+func (g *Gorilla) swagPOSTRolloutPause(w http.ResponseWriter, r *http.Request) {
+	g.setRolloutState(w, r, cce.RolloutRunning, cce.RolloutPaused)
+}
+
+// Used for POST /rollouts/{rollout_id}/resume endpoint
+func (g *Gorilla) swagPOSTRolloutResume(w http.ResponseWriter, r *http.Request) {
+	g.setRolloutState(w, r, cce.RolloutPaused, cce.RolloutRunning)
+}
+
+// errRolloutState is returned when a rollout is not in the state to move it
+// from.
+var errRolloutState = errors.New("rollout in another state")
+
+// setRolloutState moves a rollout from state from to state to. Only the state
+// and the reason are updated, keeping the progress of the rollout.
+func (g *Gorilla) setRolloutState(w http.ResponseWriter, r *http.Request, from, to string) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	var state string
+	ro, err := cce.UpdateRollout(r.Context(), ctrl.PersistenceService, mux.Vars(r)["rollout_id"],
+		func(ro *cce.Rollout) error {
+			if state = ro.State; state != from {
+				return errRolloutState
+			}
+			ro.State = to
+			ro.UpdatedAt = time.Now().UTC()
+			if to == cce.RolloutRunning {
+				ro.Reason = ""
+			}
+			return nil
+		})
+	switch {
+	case err == errRolloutState:
+		w.WriteHeader(http.StatusConflict)
+		_, err = w.Write([]byte(fmt.Sprintf("rollout_id %s is %s, not %s", mux.Vars(r)["rollout_id"], state, from)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	case err != nil:
+		log.Errf("Error updating entities: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	case ro == nil:
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	log.Infof("Rollout %s %s", ro.ID, ro.State)
+}
+
 // Used for GET /policies endpoint
 func (g *Gorilla) swagGETPolicies(w http.ResponseWriter, r *http.Request) {
 	// Load the controller to access the persistence
@@ -1088,19 +2238,8 @@ func (g *Gorilla) swagGETInterfaces(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Construct the response object
-	ifaces := swagger.InterfaceList{Interfaces: []swagger.InterfaceSummary{}}
-	for _, res := range response.(*cce.NodeResp).NetworkInterfaces {
-		iface := swagger.InterfaceSummary{
-			ID:                res.ID,
-			Description:       res.Description,
-			Driver:            res.Driver,
-			Type:              res.Type,
-			MACAddress:        res.MACAddress,
-			VLAN:              res.VLAN,
-			Zones:             res.Zones,
-			FallbackInterface: res.FallbackInterface,
-		}
-		ifaces.Interfaces = append(ifaces.Interfaces, iface)
+	ifaces := swagger.InterfaceList{
+		Interfaces: toInterfaceSummaries(response.(*cce.NodeResp).NetworkInterfaces),
 	}
 
 	// Marshal the response object to JSON
@@ -1115,6 +2254,67 @@ func (g *Gorilla) swagGETInterfaces(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Used for GET /nodes/{node_id}/inventory endpoint
+func (g *Gorilla) swagGETNodeInventory(w http.ResponseWriter, r *http.Request) {
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	nodeID := mux.Vars(r)["node_id"]
+
+	// Collect the inventory from the node if ?refresh=true
+	var refresh bool
+	if v := r.URL.Query().Get("refresh"); v != "" {
+		var err error
+		if refresh, err = strconv.ParseBool(v); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+	}
+
+	// Fetch the node from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), nodeID, &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	var inv *cce.NodeInventory
+	if !refresh {
+		if inv, err = inventory.Get(r.Context(), ctrl.PersistenceService, nodeID); err != nil {
+			log.Errf("Error reading node inventory: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// Collect an inventory that was not collected yet
+	if inv == nil {
+		collector := &inventory.Collector{Controller: ctrl}
+		if inv, err = collector.CollectNode(r.Context(), nodeID); err != nil {
+			log.Errf("Error collecting inventory of node %s: %v", nodeID, err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+	if inv == nil {
+		// The node never enrolled
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	invJSON, err := json.Marshal(toNodeInventory(inv))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(invJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
 // Used for PATCH /nodes/{node_id}/interfaces endpoint
 func (g *Gorilla) swagPATCHInterfaces(w http.ResponseWriter, r *http.Request) {
 	// Load the controller to access the persistence and the payload
@@ -1171,6 +2371,23 @@ func (g *Gorilla) swagPATCHInterfaces(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Check that the interfaces only reference zones of the node
+	zoneIDs, err := getNodeZoneIDs(r.Context(), ctrl.PersistenceService, persisted.GetID())
+	if err != nil {
+		log.Errf("Error filtering nodes_zones: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if err = requested.ValidateZones(zoneIDs); err != nil {
+		log.Debugf("Validation failed for %#v: %v", requested, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
 	code, err := handleUpdateNodes(r.Context(), ctrl.PersistenceService, &requested)
 	switch {
 	case code != 0:
@@ -1233,22 +2450,248 @@ func (g *Gorilla) swagGETInterfaceByID(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	// If its not set, then it's not found
-	if iface.ID == "" {
-		w.WriteHeader(http.StatusNotFound)
+	// If its not set, then it's not found
+	if iface.ID == "" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Marshal the response object to JSON
+	ifaceJSON, err := json.Marshal(iface)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(ifaceJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for GET /nodes/{node_id}/zones endpoint
+func (g *Gorilla) swagGETNodeZones(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the node from persistence and check if it's there
+	node, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Fetch the zones assigned to the node
+	nodeZones, err := ctrl.PersistenceService.Filter(
+		r.Context(),
+		&cce.NodeZone{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: node.GetID(),
+			},
+		})
+	if err != nil {
+		log.Errf("Error filtering nodes_zones: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	zones := swagger.ZoneList{Zones: []swagger.ZoneSummary{}}
+	for _, nz := range nodeZones {
+		zone, err := ctrl.PersistenceService.Read(r.Context(), nz.(*cce.NodeZone).ZoneID, &cce.Zone{})
+		if err != nil {
+			log.Errf("Error reading zone: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if zone == nil {
+			log.Errf("Zone %s of node %s not found", nz.(*cce.NodeZone).ZoneID, node.GetID())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		zones.Zones = append(zones.Zones, toZoneSummary(zone.(*cce.Zone)))
+	}
+
+	// Marshal the response object to JSON
+	zonesJSON, err := json.Marshal(zones)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(zonesJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for POST /nodes/{node_id}/zones endpoint
+func (g *Gorilla) swagPOSTNodeZone(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence and the payload
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	body := r.Context().Value(contextKey("body")).([]byte)
+
+	// Unmarshal the payload
+	var baseResource swagger.BaseResource
+	if err := json.Unmarshal(body, &baseResource); err != nil {
+		log.Errf("Error unmarshaling json: %v", err)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	// Fetch the node from persistence and check if it's there
+	persisted, err := ctrl.PersistenceService.Read(r.Context(), mux.Vars(r)["node_id"], &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if persisted == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Construct the create object
+	nodeZone := cce.NodeZone{
+		ID:     uuid.New(),
+		NodeID: persisted.GetID(),
+		ZoneID: baseResource.ID,
+	}
+
+	// Validate the object
+	if err = nodeZone.Validate(); err != nil {
+		log.Debugf("Validation failed for %#v: %v", nodeZone, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(fmt.Sprintf("Validation failed: %v", err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Fetch the zone from persistence and check if it's there
+	zone, err := ctrl.PersistenceService.Read(r.Context(), nodeZone.ZoneID, &cce.Zone{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if zone == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Check that the zone is not assigned to the node yet
+	if statusCode, err := checkDBCreateNodesZones(r.Context(), ctrl.PersistenceService, &nodeZone); err != nil {
+		log.Errf("Error checking DB create: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
+	// Create the zone on the node
+	if err = handleCreateNodesZones(r.Context(), ctrl.PersistenceService, &nodeZone); err != nil {
+		log.Errf("Error creating node zone: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Persist the object
+	if err = ctrl.PersistenceService.Create(r.Context(), &nodeZone); err != nil {
+		log.Errf("Error creating entity: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+// Used for GET /nodes/{node_id}/zones/{zone_id} endpoint
+func (g *Gorilla) swagGETNodeZoneByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	nodeZone, err := getNodeZone(r.Context(), ctrl.PersistenceService, mux.Vars(r)["node_id"], mux.Vars(r)["zone_id"])
+	if err != nil {
+		log.Errf("Error filtering nodes_zones: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if nodeZone == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	zone, err := ctrl.PersistenceService.Read(r.Context(), nodeZone.ZoneID, &cce.Zone{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if zone == nil {
+		log.Errf("Zone %s of node %s not found", nodeZone.ZoneID, nodeZone.NodeID)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Marshal the response object to JSON
+	zoneJSON, err := json.Marshal(toZoneSummary(zone.(*cce.Zone)))
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(zoneJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
+// Used for DELETE /nodes/{node_id}/zones/{zone_id} endpoint
+func (g *Gorilla) swagDELETENodeZoneByID(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	nodeZone, err := getNodeZone(r.Context(), ctrl.PersistenceService, mux.Vars(r)["node_id"], mux.Vars(r)["zone_id"])
+	if err != nil {
+		log.Errf("Error filtering nodes_zones: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if nodeZone == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Delete the zone from the node
+	if statusCode, err := handleDeleteNodesZones(r.Context(), ctrl.PersistenceService, nodeZone); err != nil {
+		log.Errf("Error making remote call: %v", err)
+		w.WriteHeader(statusCode)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
 		return
 	}
 
-	// Marshal the response object to JSON
-	ifaceJSON, err := json.Marshal(iface)
+	// Delete the resource
+	ok, err := ctrl.PersistenceService.Delete(r.Context(), nodeZone.ID, &cce.NodeZone{})
 	if err != nil {
+		log.Errf("Error deleting entity: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
-	w.Header().Set("Content-Type", "application/json")
-	if _, err = w.Write(ifaceJSON); err != nil {
-		log.Errf("Error writing response: %v", err)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
+
+	w.WriteHeader(http.StatusNoContent)
 }
 
 // Used for GET /nodes/{node_id}/interfaces/{interface_id}/policy endpoint
@@ -1527,6 +2970,229 @@ func getNfdFeatures(ctx context.Context, nodeID string) (map[string]string, erro
 	return features, nil
 }
 
+// changeNodeAppVersion upgrades the app deployed to a node to the requested
+// version or rolls it back to the previous one, and records it in the node
+// app's history.
+func changeNodeAppVersion( //nolint:gocyclo
+	ctx context.Context,
+	ps cce.PersistenceService,
+	req *cce.NodeAppReq,
+) (statusCode int, err error) {
+	nodeApp := &req.NodeApp
+
+	current, err := ps.Read(ctx, nodeApp.AppID, &cce.App{})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if current == nil {
+		return http.StatusInternalServerError, fmt.Errorf("app_id %s not found", nodeApp.AppID)
+	}
+
+	// Find the version to deploy
+	var target *cce.App
+	switch req.Cmd {
+	case "upgrade":
+		versions, err := cce.AppVersions(ctx, ps, current.(*cce.App))
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		for _, v := range versions {
+			if v.Version != req.Version {
+				continue
+			}
+			if target != nil {
+				return http.StatusUnprocessableEntity, fmt.Errorf(
+					"version %s of app_id %s is ambiguous", req.Version, nodeApp.AppID)
+			}
+			target = v
+		}
+		if target == nil {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"version %s of app_id %s not found", req.Version, nodeApp.AppID)
+		}
+		if target.ID == nodeApp.AppID {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"app_id %s is already at version %s", nodeApp.AppID, req.Version)
+		}
+	case "rollback":
+		prev := nodeApp.Previous()
+		if prev == nil {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"app_id %s has no previous version to roll back to", nodeApp.AppID)
+		}
+		persisted, err := ps.Read(ctx, prev.AppID, &cce.App{})
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+		if persisted == nil {
+			return http.StatusUnprocessableEntity, fmt.Errorf(
+				"previous version %s (app_id %s) no longer exists", prev.Version, prev.AppID)
+		}
+		target = persisted.(*cce.App)
+	}
+
+	return setNodeAppVersion(ctx, ps, nodeApp, current.(*cce.App), target, req.Cmd)
+}
+
+// setNodeAppVersion replaces the version current of the app deployed to a node
+// with target and records it in the node app's history. Cmd is upgrade or
+// rollback.
+func setNodeAppVersion(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeApp *cce.NodeApp,
+	current, target *cce.App,
+	cmd string,
+) (statusCode int, err error) {
+	// Check that the version is not deployed to the node already
+	dups, err := ps.Filter(ctx, &cce.NodeApp{}, []cce.Filter{
+		{Field: "node_id", Value: nodeApp.NodeID},
+		{Field: "app_id", Value: target.ID},
+	})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if len(dups) > 0 {
+		return http.StatusUnprocessableEntity, fmt.Errorf(
+			"app_id %s is already deployed to node_id %s", target.ID, nodeApp.NodeID)
+	}
+
+	// Check that the node has the features and the resources of the version
+	node, err := ps.Read(ctx, nodeApp.NodeID, &cce.Node{})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if node == nil {
+		return http.StatusNotFound, fmt.Errorf("node_id %s not found", nodeApp.NodeID)
+	}
+	features, err := getNfdFeatures(ctx, nodeApp.NodeID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if err = target.EPAValidate(features); err != nil {
+		return http.StatusUnprocessableEntity, err
+	}
+	committed, err := cce.CommittedResources(ctx, ps)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	others := committed[nodeApp.NodeID]
+	others.Cores -= current.Cores * nodeApp.ReplicaCount()
+	others.Memory -= current.Memory * nodeApp.ReplicaCount()
+	err = cce.CheckCapacity(target, nodeApp.ReplicaCount(), cce.Allocatable(node.(*cce.Node), features), others)
+	if err != nil {
+		return http.StatusConflict, fmt.Errorf("cannot deploy app_id %s to node_id %s: %v",
+			target.ID, nodeApp.NodeID, err)
+	}
+
+	if err = handleUpgradeNodesApps(ctx, ps, nodeApp, target); err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	nodeApp.Record(target, cmd, time.Now().UTC())
+	if err = ps.BulkUpdate(ctx, []cce.Persistable{nodeApp}); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	log.Infof("App %s on node %s: %s to version %s (app %s)",
+		nodeApp.RemoteAppID(), nodeApp.NodeID, cmd, target.Version, target.ID)
+
+	return 0, nil
+}
+
+// deployNodeApp deploys an app to a node once checked that the node has the
+// features and the resources of the app, and persists the node app.
+func deployNodeApp(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+	app *cce.App,
+) (nodeApp *cce.NodeApp, statusCode int, err error) {
+	// Check that the app is not deployed to the node already
+	dups, err := ps.Filter(ctx, &cce.NodeApp{}, []cce.Filter{
+		{Field: "node_id", Value: nodeID},
+		{Field: "app_id", Value: app.ID},
+	})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if len(dups) > 0 {
+		return nil, http.StatusUnprocessableEntity, fmt.Errorf(
+			"duplicate record in nodes_apps detected for node_id %s and app_id %s", nodeID, app.ID)
+	}
+
+	// Check that the node has the features and the resources of the app
+	node, err := ps.Read(ctx, nodeID, &cce.Node{})
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if node == nil {
+		return nil, http.StatusNotFound, fmt.Errorf("node_id %s not found", nodeID)
+	}
+	features, err := getNfdFeatures(ctx, nodeID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if err = app.EPAValidate(features); err != nil {
+		return nil, http.StatusUnprocessableEntity, err
+	}
+	committed, err := cce.CommittedResources(ctx, ps)
+	if err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if err = cce.CheckCapacity(app, 1, cce.Allocatable(node.(*cce.Node), features), committed[nodeID]); err != nil {
+		return nil, http.StatusConflict, fmt.Errorf("cannot deploy app_id %s to node_id %s: %v", app.ID, nodeID, err)
+	}
+
+	nodeApp = &cce.NodeApp{
+		ID:     uuid.New(),
+		NodeID: nodeID,
+		AppID:  app.ID,
+	}
+	nodeApp.Record(app, "deploy", time.Now().UTC())
+	if err = handleCreateNodesApps(ctx, ps, nodeApp); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+	if err = ps.Create(ctx, nodeApp); err != nil {
+		return nil, http.StatusInternalServerError, err
+	}
+
+	return nodeApp, 0, nil
+}
+
+// undeployNodeApp removes an app from a node and deletes the node app.
+func undeployNodeApp(ctx context.Context, ps cce.PersistenceService, nodeApp *cce.NodeApp) error {
+	if err := handleDeleteNodesApps(ctx, ps, nodeApp); err != nil {
+		return err
+	}
+	_, err := ps.Delete(ctx, nodeApp.ID, &cce.NodeApp{})
+	return err
+}
+
+// setNodeStatus fills the liveness fields of a node summary. A node without a
+// recorded status has not been probed yet.
+func setNodeStatus(node *swagger.NodeSummary, status *cce.NodeStatus) {
+	if status == nil {
+		node.Status = string(cce.NodeStateUnknown)
+		return
+	}
+	node.Status = string(status.Status)
+	if !status.LastSeen.IsZero() {
+		lastSeen := status.LastSeen
+		node.LastSeen = &lastSeen
+	}
+}
+
+// setNodeAddresses fills the address fields of a node detail.
+func setNodeAddresses(node *swagger.NodeDetail, target *cce.NodeGRPCTarget) {
+	node.Address = target.GRPCTarget
+	for _, prev := range target.Previous {
+		node.PreviousAddresses = append(node.PreviousAddresses, swagger.NodeAddress{
+			Address: prev.GRPCTarget,
+			Until:   prev.Until,
+		})
+	}
+}
+
 // Used for GET /nodes/{node_id}/apps endpoint
 func (g *Gorilla) swagGETNodeApps(w http.ResponseWriter, r *http.Request) {
 	// Load the controller to access the persistence
@@ -1590,8 +3256,8 @@ func (g *Gorilla) swagPOSTNodeApp(w http.ResponseWriter, r *http.Request) { //no
 	body := r.Context().Value(contextKey("body")).([]byte)
 
 	// Unmarshal the payload
-	var baseResource swagger.BaseResource
-	if err := json.Unmarshal(body, &baseResource); err != nil {
+	var req swagger.NodeAppRequest
+	if err := json.Unmarshal(body, &req); err != nil {
 		log.Errf("Error unmarshaling json: %v", err)
 		w.WriteHeader(http.StatusBadRequest)
 		_, err = w.Write([]byte(fmt.Sprintf("Error unmarshaling json: %v", err)))
@@ -1612,7 +3278,7 @@ func (g *Gorilla) swagPOSTNodeApp(w http.ResponseWriter, r *http.Request) { //no
 			},
 			{
 				Field: "app_id",
-				Value: baseResource.ID,
+				Value: req.ID,
 			},
 		})
 	if err != nil {
@@ -1625,7 +3291,7 @@ func (g *Gorilla) swagPOSTNodeApp(w http.ResponseWriter, r *http.Request) { //no
 		w.WriteHeader(http.StatusUnprocessableEntity)
 		_, err = w.Write([]byte(fmt.Sprintf(
 			"duplicate record in nodes_apps detected for node_id %s and app_id %s",
-			mux.Vars(r)["node_id"], baseResource.ID,
+			mux.Vars(r)["node_id"], req.ID,
 		)))
 		if err != nil {
 			log.Errf("Error writing response: %v", err)
@@ -1643,12 +3309,18 @@ func (g *Gorilla) swagPOSTNodeApp(w http.ResponseWriter, r *http.Request) { //no
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
+	node := persisted.(*cce.Node)
 
 	// Construct the create object to dial to the node app
 	nodeApp := cce.NodeApp{
-		ID:     uuid.New(),
-		NodeID: mux.Vars(r)["node_id"],
-		AppID:  baseResource.ID,
+		ID:        uuid.New(),
+		NodeID:    mux.Vars(r)["node_id"],
+		AppID:     req.ID,
+		Config:    req.Config,
+		Namespace: req.Namespace,
+		Replicas:  req.Replicas,
+		Networks:  req.Networks,
+		Service:   req.Service,
 	}
 
 	// Validate the object
@@ -1661,9 +3333,18 @@ func (g *Gorilla) swagPOSTNodeApp(w http.ResponseWriter, r *http.Request) { //no
 		}
 		return
 	}
+	if err = nodeApp.ValidateOrchestration(ctrl.OrchestrationMode); err != nil {
+		log.Debugf("Validation failed for %#v: %v", nodeApp, err)
+		w.WriteHeader(http.StatusBadRequest)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
 
 	// Fetch the entity from persistence and check if it's there
-	persisted, err = ctrl.PersistenceService.Read(r.Context(), baseResource.ID, &cce.App{})
+	persisted, err = ctrl.PersistenceService.Read(r.Context(), req.ID, &cce.App{})
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		return
@@ -1673,6 +3354,16 @@ func (g *Gorilla) swagPOSTNodeApp(w http.ResponseWriter, r *http.Request) { //no
 		return
 	}
 
+	if err = nodeApp.ValidateApp(persisted.(*cce.App)); err != nil {
+		log.Debugf("Validation failed for %#v: %v", nodeApp, err)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
 	// EPA validation
 	features, err := getNfdFeatures(r.Context(), mux.Vars(r)["node_id"])
 	if err != nil {
@@ -1688,7 +3379,27 @@ func (g *Gorilla) swagPOSTNodeApp(w http.ResponseWriter, r *http.Request) { //no
 		return
 	}
 
+	// Check that the app does not over-commit the node
+	committed, err := cce.CommittedResources(r.Context(), ctrl.PersistenceService)
+	if err != nil {
+		log.Errf("Error reading committed resources: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	err = cce.CheckCapacity(persisted.(*cce.App), nodeApp.ReplicaCount(), cce.Allocatable(node, features),
+		committed[node.ID])
+	if err != nil {
+		log.Errf("Unable to deploy app [%s] on node [%s]: %v", nodeApp.AppID, node.ID, err)
+		w.WriteHeader(http.StatusConflict)
+		_, err = w.Write([]byte(fmt.Sprintf("cannot deploy app_id %s to node_id %s: %v", nodeApp.AppID, node.ID, err)))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
+	}
+
 	// Create the remote node app
+	nodeApp.Record(persisted.(*cce.App), "deploy", time.Now().UTC())
 	err = handleCreateNodesApps(r.Context(), ctrl.PersistenceService, &nodeApp)
 	if err != nil {
 		log.Errf("Error creating node app: %v", err)
@@ -1752,7 +3463,15 @@ func (g *Gorilla) swagGETNodeAppsByID(w http.ResponseWriter, r *http.Request) {
 		NodeAppSummary: swagger.NodeAppSummary{
 			ID: nodeApps[0].(*cce.NodeApp).AppID,
 		},
-		Status: response.(*cce.NodeAppResp).Status,
+		Status:        response.(*cce.NodeAppResp).Status,
+		Readiness:     response.(*cce.NodeAppResp).Readiness,
+		Config:        nodeApps[0].(*cce.NodeApp).Config,
+		Namespace:     nodeApps[0].(*cce.NodeApp).Namespace,
+		Replicas:      nodeApps[0].(*cce.NodeApp).Replicas,
+		Networks:      nodeApps[0].(*cce.NodeApp).Networks,
+		NetworkStatus: response.(*cce.NodeAppResp).NetworkStatus,
+		Service:       nodeApps[0].(*cce.NodeApp).Service,
+		Endpoints:     response.(*cce.NodeAppResp).Endpoints,
 	}
 
 	// Marshal the response object to JSON
@@ -1767,6 +3486,75 @@ func (g *Gorilla) swagGETNodeAppsByID(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// Used for GET /nodes/{node_id}/apps/{app_id}/history endpoint
+func (g *Gorilla) swagGETNodeAppHistory(w http.ResponseWriter, r *http.Request) {
+	// Load the controller to access the persistence
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+
+	// Fetch the entity from persistence and check if it's there
+	nodeApps, err := ctrl.PersistenceService.Filter(
+		r.Context(),
+		&cce.NodeApp{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: mux.Vars(r)["node_id"],
+			},
+			{
+				Field: "app_id",
+				Value: mux.Vars(r)["app_id"],
+			},
+		})
+	if err != nil {
+		log.Errf("Error filtering node_apps: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if len(nodeApps) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	nodeApp := nodeApps[0].(*cce.NodeApp)
+
+	// Fetch the deployed version of the app
+	app, err := ctrl.PersistenceService.Read(r.Context(), nodeApp.AppID, &cce.App{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if app == nil {
+		log.Errf("App %s of node app %s not found", nodeApp.AppID, nodeApp.ID)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object
+	history := swagger.NodeAppHistory{
+		ID:          nodeApp.AppID,
+		Version:     app.(*cce.App).Version,
+		Deployments: []swagger.NodeAppDeployment{},
+	}
+	for _, d := range nodeApp.History {
+		history.Deployments = append(history.Deployments, swagger.NodeAppDeployment{
+			ID:      d.AppID,
+			Version: d.Version,
+			Command: d.Cmd,
+			At:      d.At,
+		})
+	}
+
+	// Marshal the response object to JSON
+	historyJSON, err := json.Marshal(history)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(historyJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
+
 // Used for PATCH /nodes/{node_id}/apps/{app_id} endpoint
 func (g *Gorilla) swagPATCHNodeAppsByID(w http.ResponseWriter, r *http.Request) {
 	// Load the controller to access the persistence and the payload
@@ -1818,6 +3606,10 @@ func (g *Gorilla) swagPATCHNodeAppsByID(w http.ResponseWriter, r *http.Request)
 	requested := cce.NodeAppReq{
 		NodeApp: *nodeApps[0].(*cce.NodeApp),
 		Cmd:     nodeAppDetail.Command,
+		Version: nodeAppDetail.Version,
+	}
+	if requested.Cmd == "scale" {
+		requested.Replicas = nodeAppDetail.Replicas
 	}
 
 	// Validate the object
@@ -1831,8 +3623,45 @@ func (g *Gorilla) swagPATCHNodeAppsByID(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// Upgrade and rollback change the version of the app on the node
+	if requested.Cmd == "upgrade" || requested.Cmd == "rollback" {
+		code, err := changeNodeAppVersion(r.Context(), ctrl.PersistenceService, &requested)
+		if err != nil {
+			log.Errf("Error changing the version of node app: %v", err)
+			w.WriteHeader(code)
+			_, err = w.Write([]byte(err.Error()))
+			if err != nil {
+				log.Errf("Error writing response: %v", err)
+			}
+		}
+		return
+	}
+
+	// Check that the node has the resources of the replicas scaled to
+	if requested.Cmd == "scale" && ctrl.OrchestrationMode != cce.OrchestrationModeNative {
+		code, err := checkScaleCapacity(r.Context(), ctrl.PersistenceService, nodeApps[0].(*cce.NodeApp),
+			requested.ReplicaCount())
+		if err != nil {
+			log.Errf("Error scaling node app: %v", err)
+			w.WriteHeader(code)
+			_, err = w.Write([]byte(err.Error()))
+			if err != nil {
+				log.Errf("Error writing response: %v", err)
+			}
+			return
+		}
+	}
+
 	code, err := handleUpdateNodesApps(r.Context(), ctrl.PersistenceService, &requested)
 	switch {
+	case code == http.StatusBadRequest:
+		log.Debugf("Error updating remote entities: %v", err)
+		w.WriteHeader(code)
+		_, err = w.Write([]byte(err.Error()))
+		if err != nil {
+			log.Errf("Error writing response: %v", err)
+		}
+		return
 	case code != 0:
 		log.Errf("Error updating remote entities: %v", err)
 		w.WriteHeader(code)
@@ -1842,6 +3671,59 @@ func (g *Gorilla) swagPATCHNodeAppsByID(w http.ResponseWriter, r *http.Request)
 		}
 		return
 	}
+
+	// Keep the number of replicas the app was scaled to for its next start
+	if requested.Cmd == "scale" {
+		if err = ctrl.PersistenceService.BulkUpdate(
+			r.Context(), []cce.Persistable{&requested.NodeApp},
+		); err != nil {
+			log.Errf("Error updating node app: %v", err)
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// checkScaleCapacity checks that the node an app is deployed to has the
+// resources of the replicas it is scaled to, on top of the ones committed to
+// the other apps.
+func checkScaleCapacity(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeApp *cce.NodeApp,
+	replicas int,
+) (statusCode int, err error) {
+	app, err := ps.Read(ctx, nodeApp.AppID, &cce.App{})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if app == nil {
+		return http.StatusNotFound, fmt.Errorf("app_id %s not found", nodeApp.AppID)
+	}
+	node, err := ps.Read(ctx, nodeApp.NodeID, &cce.Node{})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	if node == nil {
+		return http.StatusNotFound, fmt.Errorf("node_id %s not found", nodeApp.NodeID)
+	}
+	features, err := getNfdFeatures(ctx, nodeApp.NodeID)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	committed, err := cce.CommittedResources(ctx, ps)
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+	others := committed[nodeApp.NodeID]
+	others.Cores -= app.(*cce.App).Cores * nodeApp.ReplicaCount()
+	others.Memory -= app.(*cce.App).Memory * nodeApp.ReplicaCount()
+	err = cce.CheckCapacity(app.(*cce.App), replicas, cce.Allocatable(node.(*cce.Node), features), others)
+	if err != nil {
+		return http.StatusConflict, fmt.Errorf("cannot scale app_id %s on node_id %s to %d replicas: %v",
+			nodeApp.AppID, nodeApp.NodeID, replicas, err)
+	}
+	return 0, nil
 }
 
 // Used for DELETE /nodes/{node_id}/apps/{app_id} endpoint
@@ -2064,11 +3946,12 @@ func (g *Gorilla) swagPATCHNodeAppPolicy(w http.ResponseWriter, r *http.Request)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	defer disconnectNode(nodeCC)
 
 	// Make gRPC call to node to set the policy
 	if err = nodeCC.AppPolicySvcCli.Set(
 		r.Context(),
-		nodeApps[0].(*cce.NodeApp).AppID,
+		nodeApps[0].(*cce.NodeApp).RemoteAppID(),
 		policy.(*cce.TrafficPolicy),
 	); err != nil {
 		log.Errf("Error setting policy: %v", err)
@@ -2188,11 +4071,12 @@ func (g *Gorilla) swagDELETENodeAppPolicy(w http.ResponseWriter, r *http.Request
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
+	defer disconnectNode(nodeCC)
 
 	// Make gRPC call to node to delete the policy
 	if err = nodeCC.AppPolicySvcCli.Delete(
 		r.Context(),
-		nodeApps[0].(*cce.NodeApp).AppID,
+		nodeApps[0].(*cce.NodeApp).RemoteAppID(),
 	); err != nil {
 		log.Errf("Error deleting policy: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -2348,11 +4232,11 @@ func (g *Gorilla) swagPATCHNodeAppKubeOVNPolicy(w http.ResponseWriter, r *http.R
 
 	// Try delete network policy for app
 	_ = ctrl.KubernetesClient.DeleteNetworkPolicy(r.Context(), nodeApps[0].(*cce.NodeApp).NodeID,
-		nodeApps[0].(*cce.NodeApp).AppID)
+		nodeApps[0].(*cce.NodeApp).RemoteAppID())
 
 	// Apply new network policy for app
 	if err = ctrl.KubernetesClient.ApplyNetworkPolicy(r.Context(), nodeApps[0].(*cce.NodeApp).NodeID,
-		nodeApps[0].(*cce.NodeApp).AppID, policy.(*cce.TrafficPolicyKubeOVN).ToK8s(),
+		nodeApps[0].(*cce.NodeApp).RemoteAppID(), policy.(*cce.TrafficPolicyKubeOVN).ToK8s(),
 	); err != nil {
 		log.Errf("Error setting policy: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -2457,7 +4341,7 @@ func (g *Gorilla) swagDELETENodeAppKubeOVNPolicy(w http.ResponseWriter, r *http.
 
 	// Make gRPC call to node to delete the policy
 	if err = ctrl.KubernetesClient.DeleteNetworkPolicy(
-		r.Context(), nodeApps[0].(*cce.NodeApp).NodeID, nodeApps[0].(*cce.NodeApp).AppID,
+		r.Context(), nodeApps[0].(*cce.NodeApp).NodeID, nodeApps[0].(*cce.NodeApp).RemoteAppID(),
 	); err != nil {
 		log.Errf("Error deleting policy: %v", err)
 		w.WriteHeader(http.StatusInternalServerError)
@@ -2510,3 +4394,63 @@ func (g *Gorilla) swagGETNodeNFDTags(w http.ResponseWriter, r *http.Request) {
 	}
 	fmt.Fprintf(w, "\n")
 }
+
+// Used for GET /nodes/{node_id}/events endpoint
+func (g *Gorilla) swagGETNodeEvents(w http.ResponseWriter, r *http.Request) {
+	ctrl := r.Context().Value(contextKey("controller")).(*cce.Controller)
+	nodeID := mux.Vars(r)["node_id"]
+
+	// Check that the node exists
+	node, err := ctrl.PersistenceService.Read(r.Context(), nodeID, &cce.Node{})
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if node == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	// Fetch the events of the node from persistence
+	persisted, err := ctrl.PersistenceService.Filter(
+		r.Context(),
+		&cce.Event{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+		},
+	)
+	if err != nil {
+		log.Errf("Error reading events: %v", err)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	// Construct the response object, oldest event first
+	events := swagger.EventList{Events: []swagger.Event{}}
+	for _, e := range persisted {
+		events.Events = append(events.Events, swagger.Event{
+			ID:      e.(*cce.Event).ID,
+			NodeID:  e.(*cce.Event).NodeID,
+			Type:    e.(*cce.Event).Type,
+			Message: e.(*cce.Event).Message,
+			Time:    e.(*cce.Event).Time,
+		})
+	}
+	sort.SliceStable(events.Events, func(i, j int) bool {
+		return events.Events[i].Time.Before(events.Events[j].Time)
+	})
+
+	// Marshal the response object to JSON
+	eventsJSON, err := json.Marshal(events)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if _, err = w.Write(eventsJSON); err != nil {
+		log.Errf("Error writing response: %v", err)
+	}
+}
@@ -27,12 +27,16 @@ func handleUpdateNodes(
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
+	defer disconnectNode(nodeCC)
 
 	if e.(*cce.NodeReq).NetworkInterfaces != nil {
 		if err := nodeCC.IfaceSvcCli.BulkUpdate(ctx, e.(*cce.NodeReq).NetworkInterfaces); err != nil {
 			if s, ok := status.FromError(errors.Cause(err)); ok {
-				if s.Code() == codes.NotFound {
+				switch s.Code() {
+				case codes.NotFound:
 					return http.StatusNotFound, errors.New(s.Message())
+				case codes.InvalidArgument:
+					return http.StatusBadRequest, errors.New(s.Message())
 				}
 			}
 			return http.StatusInternalServerError, err
@@ -70,16 +74,19 @@ func handleUpdateNodesApps( //nolint: gocyclo
 	if err != nil {
 		return http.StatusInternalServerError, err
 	}
+	defer disconnectNode(nodeCC)
 
 	switch ctrl.OrchestrationMode {
 	case cce.OrchestrationModeNative:
 		switch e.(*cce.NodeAppReq).Cmd {
 		case "start":
-			err = nodeCC.AppLifeSvcCli.Start(ctx, e.(*cce.NodeAppReq).AppID)
+			err = nodeCC.AppLifeSvcCli.Start(ctx, e.(*cce.NodeAppReq).RemoteAppID())
 		case "stop":
-			err = nodeCC.AppLifeSvcCli.Stop(ctx, e.(*cce.NodeAppReq).AppID)
+			err = nodeCC.AppLifeSvcCli.Stop(ctx, e.(*cce.NodeAppReq).RemoteAppID())
 		case "restart":
-			err = nodeCC.AppLifeSvcCli.Restart(ctx, e.(*cce.NodeAppReq).AppID)
+			err = nodeCC.AppLifeSvcCli.Restart(ctx, e.(*cce.NodeAppReq).RemoteAppID())
+		case "scale":
+			return http.StatusBadRequest, errors.New("scale is only supported in kubernetes mode")
 		}
 		if err != nil {
 			return http.StatusInternalServerError, err
@@ -88,13 +95,19 @@ func handleUpdateNodesApps( //nolint: gocyclo
 		switch e.(*cce.NodeAppReq).Cmd {
 		case "start":
 			err = ctrl.KubernetesClient.Start(ctx,
-				e.(*cce.NodeAppReq).NodeApp.NodeID, e.(*cce.NodeAppReq).NodeApp.AppID)
+				e.(*cce.NodeAppReq).NodeApp.NodeID, e.(*cce.NodeAppReq).NodeApp.RemoteAppID(),
+				e.(*cce.NodeAppReq).NodeApp.ReplicaCount())
 		case "stop":
 			err = ctrl.KubernetesClient.Stop(ctx,
-				e.(*cce.NodeAppReq).NodeApp.NodeID, e.(*cce.NodeAppReq).NodeApp.AppID)
+				e.(*cce.NodeAppReq).NodeApp.NodeID, e.(*cce.NodeAppReq).NodeApp.RemoteAppID())
 		case "restart":
 			err = ctrl.KubernetesClient.Restart(ctx,
-				e.(*cce.NodeAppReq).NodeApp.NodeID, e.(*cce.NodeAppReq).NodeApp.AppID)
+				e.(*cce.NodeAppReq).NodeApp.NodeID, e.(*cce.NodeAppReq).NodeApp.RemoteAppID(),
+				e.(*cce.NodeAppReq).NodeApp.ReplicaCount())
+		case "scale":
+			err = ctrl.KubernetesClient.Scale(ctx,
+				e.(*cce.NodeAppReq).NodeApp.NodeID, e.(*cce.NodeAppReq).NodeApp.RemoteAppID(),
+				e.(*cce.NodeAppReq).NodeApp.ReplicaCount())
 		}
 		if err != nil {
 			return http.StatusInternalServerError, err
@@ -103,3 +116,86 @@ func handleUpdateNodesApps( //nolint: gocyclo
 
 	return 0, nil
 }
+
+// handleUpgradeNodesApps replaces the version of an app deployed to a node
+// with target. Natively the node redeploys the app under the ID it knows it
+// by. In Kubernetes mode the image of target is deployed to the node and the
+// app's Deployment is updated to it.
+func handleUpgradeNodesApps(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeApp *cce.NodeApp,
+	target *cce.App,
+) error {
+	ctrl := getController(ctx)
+	nodePort := ctrl.EVAPort
+	if nodePort == "" {
+		nodePort = defaultEVAPort
+	}
+	nodeCC, err := connectNode(ctx, ps, nodeApp, nodePort, ctrl.EdgeNodeCreds)
+	if err != nil {
+		return err
+	}
+	defer disconnectNode(nodeCC)
+
+	target = target.Configured(nodeApp.Config)
+
+	switch ctrl.OrchestrationMode {
+	case cce.OrchestrationModeKubernetes, cce.OrchestrationModeKubernetesOVN:
+		deployed := false
+		for _, id := range nodeApp.AppIDs() {
+			deployed = deployed || id == target.ID
+		}
+		if !deployed {
+			if err = nodeCC.AppDeploySvcCli.Deploy(ctx, target); err != nil {
+				return err
+			}
+		}
+		return ctrl.KubernetesClient.Upgrade(ctx, nodeApp.NodeID, nodeApp.RemoteAppID(), toK8SNodeApp(target, nodeApp))
+	default:
+		redeployed := *target
+		redeployed.ID = nodeApp.RemoteAppID()
+		return nodeCC.AppDeploySvcCli.Redeploy(ctx, &redeployed)
+	}
+}
+
+// handleUpdateZones pushes an updated zone to every node it is assigned to.
+func handleUpdateZones(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	e cce.Validatable,
+) (statusCode int, err error) {
+	nodeZones, err := ps.Filter(
+		ctx,
+		&cce.NodeZone{},
+		[]cce.Filter{
+			{
+				Field: "zone_id",
+				Value: e.(*cce.Zone).ID,
+			},
+		})
+	if err != nil {
+		return http.StatusInternalServerError, err
+	}
+
+	ctrl := getController(ctx)
+	nodePort := ctrl.ELAPort
+	if nodePort == "" {
+		nodePort = defaultELAPort
+	}
+	for _, nodeZone := range nodeZones {
+		nodeCC, err := connectNode(ctx, ps, nodeZone.(*cce.NodeZone), nodePort, ctrl.EdgeNodeCreds)
+		if err != nil {
+			return http.StatusInternalServerError, err
+		}
+
+		err = nodeCC.ZoneSvcCli.Update(ctx, toNetworkZone(e.(*cce.Zone)))
+		disconnectNode(nodeCC)
+		if err != nil {
+			return http.StatusInternalServerError, errors.Wrapf(err,
+				"could not update zone on node %s", nodeZone.(*cce.NodeZone).NodeID)
+		}
+	}
+
+	return 0, nil
+}
@@ -22,6 +22,10 @@ const (
 // ApplicationDeploymentServiceClient wraps the PB client.
 type ApplicationDeploymentServiceClient struct {
 	PBCli evapb.ApplicationDeploymentServiceClient
+
+	// Policy bounds and retries the RPCs. If it is nil, a single attempt
+	// is made.
+	Policy *CallPolicy
 }
 
 // NewApplicationDeploymentServiceClient creates a new client.
@@ -29,7 +33,7 @@ func NewApplicationDeploymentServiceClient(
 	conn *grpc.ClientConn,
 ) *ApplicationDeploymentServiceClient {
 	return &ApplicationDeploymentServiceClient{
-		conn.NewApplicationDeploymentServiceClient(),
+		PBCli: conn.NewApplicationDeploymentServiceClient(),
 	}
 }
 
@@ -39,14 +43,15 @@ func (c *ApplicationDeploymentServiceClient) Deploy(
 	ctx context.Context,
 	app *cce.App,
 ) error {
-	var err error
-
-	switch app.Type {
-	case "container":
-		_, err = c.PBCli.DeployContainer(ctx, toPBApp(app))
-	case "vm":
-		_, err = c.PBCli.DeployVM(ctx, toPBApp(app))
-	}
+	err := c.Policy.call(ctx, false, func(ctx context.Context) (err error) {
+		switch app.Type {
+		case "container":
+			_, err = c.PBCli.DeployContainer(ctx, toPBApp(app))
+		case "vm":
+			_, err = c.PBCli.DeployVM(ctx, toPBApp(app))
+		}
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error deploying application")
@@ -121,7 +126,29 @@ func toPBApp(app *cce.App) *evapb.Application {
 				HttpUri: app.Source,
 			},
 		},
-		EACJsonBlob: string(tmp),
+		EACJsonBlob:  string(tmp),
+		Digest:       app.Digest,
+		Signature:    app.Signature,
+		PublisherKey: []byte(app.PublisherKey),
+	}
+
+	if app.Config != nil {
+		config, err := json.Marshal(app.Config)
+		if err != nil {
+			return nil
+		}
+		pb.ConfigJsonBlob = string(config)
+	}
+
+	if app.Probes != nil || app.RestartPolicy != "" {
+		health, err := json.Marshal(struct {
+			Probes        *cce.AppProbes `json:"probes,omitempty"`
+			RestartPolicy string         `json:"restart_policy,omitempty"`
+		}{app.Probes, app.RestartPolicy})
+		if err != nil {
+			return nil
+		}
+		pb.HealthJsonBlob = string(health)
 	}
 
 	cniConf, err := getCNIConf()
@@ -138,7 +165,10 @@ func (c *ApplicationDeploymentServiceClient) Redeploy(
 	ctx context.Context,
 	app *cce.App,
 ) error {
-	_, err := c.PBCli.Redeploy(ctx, toPBApp(app))
+	err := c.Policy.call(ctx, false, func(ctx context.Context) error {
+		_, err := c.PBCli.Redeploy(ctx, toPBApp(app))
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error redeploying application")
@@ -152,11 +182,14 @@ func (c *ApplicationDeploymentServiceClient) Undeploy(
 	ctx context.Context,
 	id string,
 ) error {
-	_, err := c.PBCli.Undeploy(
-		ctx,
-		&evapb.ApplicationID{
-			Id: id,
-		})
+	err := c.Policy.call(ctx, false, func(ctx context.Context) error {
+		_, err := c.PBCli.Undeploy(
+			ctx,
+			&evapb.ApplicationID{
+				Id: id,
+			})
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error removing application")
@@ -15,6 +15,10 @@ import (
 // ApplicationLifecycleServiceClient wraps the PB client.
 type ApplicationLifecycleServiceClient struct {
 	PBCli evapb.ApplicationLifecycleServiceClient
+
+	// Policy bounds and retries the RPCs. If it is nil, a single attempt
+	// is made.
+	Policy *CallPolicy
 }
 
 // NewApplicationLifecycleServiceClient creates a new client.
@@ -22,7 +26,7 @@ func NewApplicationLifecycleServiceClient(
 	conn *grpc.ClientConn,
 ) *ApplicationLifecycleServiceClient {
 	return &ApplicationLifecycleServiceClient{
-		conn.NewApplicationLifecycleServiceClient(),
+		PBCli: conn.NewApplicationLifecycleServiceClient(),
 	}
 }
 
@@ -31,12 +35,15 @@ func (c *ApplicationLifecycleServiceClient) Start(
 	ctx context.Context,
 	id string,
 ) error {
-	_, err := c.PBCli.Start(
-		ctx,
-		&evapb.LifecycleCommand{
-			Id:  id,
-			Cmd: evapb.LifecycleCommand_START,
-		})
+	err := c.Policy.call(ctx, false, func(ctx context.Context) error {
+		_, err := c.PBCli.Start(
+			ctx,
+			&evapb.LifecycleCommand{
+				Id:  id,
+				Cmd: evapb.LifecycleCommand_START,
+			})
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error starting application")
@@ -50,12 +57,15 @@ func (c *ApplicationLifecycleServiceClient) Stop(
 	ctx context.Context,
 	id string,
 ) error {
-	_, err := c.PBCli.Stop(
-		ctx,
-		&evapb.LifecycleCommand{
-			Id:  id,
-			Cmd: evapb.LifecycleCommand_STOP,
-		})
+	err := c.Policy.call(ctx, false, func(ctx context.Context) error {
+		_, err := c.PBCli.Stop(
+			ctx,
+			&evapb.LifecycleCommand{
+				Id:  id,
+				Cmd: evapb.LifecycleCommand_STOP,
+			})
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error stopping application")
@@ -69,12 +79,15 @@ func (c *ApplicationLifecycleServiceClient) Restart(
 	ctx context.Context,
 	id string,
 ) error {
-	_, err := c.PBCli.Restart(
-		ctx,
-		&evapb.LifecycleCommand{
-			Id:  id,
-			Cmd: evapb.LifecycleCommand_RESTART,
-		})
+	err := c.Policy.call(ctx, false, func(ctx context.Context) error {
+		_, err := c.PBCli.Restart(
+			ctx,
+			&evapb.LifecycleCommand{
+				Id:  id,
+				Cmd: evapb.LifecycleCommand_RESTART,
+			})
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error restarting application")
@@ -88,9 +101,13 @@ func (c *ApplicationLifecycleServiceClient) GetStatus(
 	ctx context.Context,
 	id string,
 ) (cce.LifecycleStatus, error) {
-	pbStatus, err := c.PBCli.GetStatus(
-		ctx,
-		&evapb.ApplicationID{Id: id})
+	var pbStatus *evapb.LifecycleStatus
+	err := c.Policy.call(ctx, true, func(ctx context.Context) (err error) {
+		pbStatus, err = c.PBCli.GetStatus(
+			ctx,
+			&evapb.ApplicationID{Id: id})
+		return err
+	})
 
 	if err != nil {
 		return cce.Unknown, errors.Wrap(err, "error retrieving application")
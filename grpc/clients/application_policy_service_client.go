@@ -15,6 +15,10 @@ import (
 // ApplicationPolicyServiceClient wraps the PB client.
 type ApplicationPolicyServiceClient struct {
 	PBCli elapb.ApplicationPolicyServiceClient
+
+	// Policy bounds and retries the RPCs. If it is nil, a single attempt
+	// is made.
+	Policy *CallPolicy
 }
 
 // NewApplicationPolicyServiceClient creates a new client.
@@ -22,7 +26,7 @@ func NewApplicationPolicyServiceClient(
 	conn *grpc.ClientConn,
 ) *ApplicationPolicyServiceClient {
 	return &ApplicationPolicyServiceClient{
-		conn.NewApplicationPolicyServiceClient(),
+		PBCli: conn.NewApplicationPolicyServiceClient(),
 	}
 }
 
@@ -32,9 +36,12 @@ func (c *ApplicationPolicyServiceClient) Set(
 	appID string,
 	policy *cce.TrafficPolicy,
 ) error {
-	_, err := c.PBCli.Set(
-		ctx,
-		toPBTrafficPolicy(appID, policy))
+	err := c.Policy.call(ctx, true, func(ctx context.Context) error {
+		_, err := c.PBCli.Set(
+			ctx,
+			toPBTrafficPolicy(appID, policy))
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error setting application policy")
@@ -0,0 +1,240 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package clients
+
+import (
+	"context"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Defaults for CallConfig.
+const (
+	DefaultCallTimeout      = 10 * time.Second
+	DefaultMaxAttempts      = 3
+	DefaultBackoff          = 100 * time.Millisecond
+	DefaultMaxBackoff       = 2 * time.Second
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+)
+
+// ErrCircuitOpen is returned without calling the node while its circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+// CallConfig configures the deadlines, retries and circuit breaking of the
+// RPCs made to a node. Zero values disable the corresponding feature.
+type CallConfig struct {
+	// Timeout bounds each attempt of an RPC.
+	Timeout time.Duration
+
+	// MaxAttempts is the number of attempts made for an idempotent RPC.
+	// Non-idempotent RPCs are attempted once.
+	MaxAttempts int
+
+	// Backoff is the delay before the first retry. It doubles with every
+	// retry up to MaxBackoff and is fully jittered.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	// BreakerThreshold is the number of consecutive failed RPCs after which
+	// the circuit breaker opens and RPCs fail fast with ErrCircuitOpen.
+	BreakerThreshold int
+
+	// BreakerCooldown is the time an open circuit breaker waits before it
+	// lets a single trial RPC through.
+	BreakerCooldown time.Duration
+}
+
+// DefaultCallConfig returns the default CallConfig.
+func DefaultCallConfig() CallConfig {
+	return CallConfig{
+		Timeout:          DefaultCallTimeout,
+		MaxAttempts:      DefaultMaxAttempts,
+		Backoff:          DefaultBackoff,
+		MaxBackoff:       DefaultMaxBackoff,
+		BreakerThreshold: DefaultBreakerThreshold,
+		BreakerCooldown:  DefaultBreakerCooldown,
+	}
+}
+
+// NewCallPolicy creates a CallPolicy for the node named name, with a circuit
+// breaker of its own.
+func (c CallConfig) NewCallPolicy(name string) *CallPolicy {
+	p := &CallPolicy{CallConfig: c}
+	if c.BreakerThreshold > 0 {
+		p.Breaker = &CircuitBreaker{
+			Name:      name,
+			Threshold: c.BreakerThreshold,
+			Cooldown:  c.BreakerCooldown,
+		}
+	}
+	return p
+}
+
+// CallPolicy applies a CallConfig to the RPCs made by the clients of a node.
+// A nil CallPolicy makes a single attempt bounded only by the caller's
+// context.
+type CallPolicy struct {
+	CallConfig
+	Breaker *CircuitBreaker
+}
+
+// call runs fn, retrying it if idempotent is true and the node could not be
+// reached.
+func (p *CallPolicy) call(ctx context.Context, idempotent bool, fn func(context.Context) error) error {
+	if p == nil {
+		return fn(ctx)
+	}
+
+	attempts := 1
+	if idempotent && p.MaxAttempts > 1 {
+		attempts = p.MaxAttempts
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			if werr := sleep(ctx, p.backoff(attempt)); werr != nil {
+				return err
+			}
+		}
+
+		if err = p.Breaker.allow(); err != nil {
+			return err
+		}
+
+		err = p.attempt(ctx, fn)
+		if !isUnreachable(err) {
+			p.Breaker.success()
+			return err
+		}
+		p.Breaker.failure()
+
+		// Stop if the caller gave up rather than the attempt timing out
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (p *CallPolicy) attempt(ctx context.Context, fn func(context.Context) error) error {
+	if p.Timeout <= 0 {
+		return fn(ctx)
+	}
+	ctx, cancel := context.WithTimeout(ctx, p.Timeout)
+	defer cancel()
+	return fn(ctx)
+}
+
+// backoff returns the jittered delay before the given retry.
+func (p *CallPolicy) backoff(retry int) time.Duration {
+	if p.Backoff <= 0 {
+		return 0
+	}
+	d := p.Backoff << uint(retry-1)
+	if p.MaxBackoff > 0 && (d > p.MaxBackoff || d <= 0) {
+		d = p.MaxBackoff
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// isUnreachable reports whether err means the node could not be reached or
+// did not answer in time. Errors returned by the node itself do not count.
+func isUnreachable(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch status.Code(errors.Cause(err)) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// CircuitBreaker fails calls to a node fast after too many consecutive
+// failures. Once Cooldown has elapsed a single trial call is let through; it
+// closes the breaker if it succeeds and reopens it otherwise.
+type CircuitBreaker struct {
+	Name      string
+	Threshold int
+	Cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	openedAt time.Time
+	trial    bool
+}
+
+// Open reports whether the breaker currently fails calls fast.
+func (b *CircuitBreaker) Open() bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.failures >= b.Threshold && (b.trial || time.Since(b.openedAt) < b.Cooldown)
+}
+
+func (b *CircuitBreaker) allow() error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.Threshold {
+		return nil
+	}
+	if b.trial || time.Since(b.openedAt) < b.Cooldown {
+		return errors.Wrapf(ErrCircuitOpen, "node %s unreachable after %d failed calls", b.Name, b.failures)
+	}
+	b.trial = true
+	return nil
+}
+
+func (b *CircuitBreaker) success() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.trial = false
+}
+
+func (b *CircuitBreaker) failure() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.trial = false
+	if b.failures >= b.Threshold {
+		b.openedAt = time.Now()
+	}
+}
@@ -0,0 +1,146 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package clients_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+	gclients "github.com/open-ness/edgecontroller/grpc/clients"
+	ctrlgmock "github.com/open-ness/edgecontroller/mock/controller/grpc"
+	nodegmock "github.com/open-ness/edgecontroller/mock/node/grpc"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var _ = Describe("Call Policy", func() {
+	var (
+		node      *nodegmock.MockNode
+		policy    *gclients.CallPolicy
+		lifeCli   *gclients.ApplicationLifecycleServiceClient
+		ifaceCli  *gclients.InterfaceServiceClient
+		unreached = status.Error(codes.Unavailable, "connection refused")
+	)
+
+	BeforeEach(func() {
+		node = nodegmock.NewMockNode()
+		policy = gclients.CallConfig{
+			Timeout:          50 * time.Millisecond,
+			MaxAttempts:      3,
+			Backoff:          time.Millisecond,
+			MaxBackoff:       5 * time.Millisecond,
+			BreakerThreshold: 4,
+			BreakerCooldown:  time.Hour,
+		}.NewCallPolicy("test-node")
+		lifeCli = &gclients.ApplicationLifecycleServiceClient{
+			PBCli:  &ctrlgmock.MockPBApplicationLifecycleServiceClient{MockNode: node},
+			Policy: policy,
+		}
+		ifaceCli = &gclients.InterfaceServiceClient{
+			PBCli:  &ctrlgmock.MockPBInterfaceServiceClient{MockNode: node},
+			Policy: policy,
+		}
+	})
+
+	Describe("Retries", func() {
+		It("Should retry idempotent calls until the node answers", func() {
+			node.FailNext(2, unreached)
+
+			_, err := ifaceCli.GetAll(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(node.Calls()).To(Equal(3))
+		})
+
+		It("Should give up after the maximum number of attempts", func() {
+			node.FailNext(3, unreached)
+
+			_, err := ifaceCli.GetAll(ctx)
+			Expect(status.Code(errors.Cause(err))).To(Equal(codes.Unavailable))
+			Expect(node.Calls()).To(Equal(3))
+		})
+
+		It("Should not retry errors returned by the node", func() {
+			_, err := lifeCli.GetStatus(ctx, "unknown-app")
+			Expect(status.Code(errors.Cause(err))).To(Equal(codes.NotFound))
+			Expect(node.Calls()).To(Equal(1))
+		})
+
+		It("Should not retry calls that are not idempotent", func() {
+			node.FailNext(1, unreached)
+
+			err := lifeCli.Start(ctx, "unknown-app")
+			Expect(status.Code(errors.Cause(err))).To(Equal(codes.Unavailable))
+			Expect(node.Calls()).To(Equal(1))
+		})
+	})
+
+	Describe("Deadlines", func() {
+		It("Should bound each attempt by the timeout", func() {
+			node.HangNext(3)
+
+			start := time.Now()
+			_, err := ifaceCli.GetAll(ctx)
+			Expect(status.Code(errors.Cause(err))).To(Equal(codes.DeadlineExceeded))
+			Expect(node.Calls()).To(Equal(3))
+			Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+		})
+	})
+
+	Describe("Circuit Breaker", func() {
+		It("Should fail fast once the threshold is reached", func() {
+			node.FailNext(4, unreached)
+
+			By("Failing 4 attempts over two calls")
+			_, err := ifaceCli.GetAll(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(policy.Breaker.Open()).To(BeFalse())
+			err = lifeCli.Start(ctx, "unknown-app")
+			Expect(err).To(HaveOccurred())
+			Expect(policy.Breaker.Open()).To(BeTrue())
+
+			By("Failing without calling the node")
+			_, err = ifaceCli.GetAll(ctx)
+			Expect(errors.Cause(err)).To(Equal(gclients.ErrCircuitOpen))
+			Expect(err.Error()).To(ContainSubstring("node test-node unreachable"))
+			Expect(node.Calls()).To(Equal(4))
+		})
+
+		It("Should close after a successful trial call", func() {
+			policy.Breaker.Cooldown = 0
+			node.FailNext(4, unreached)
+
+			_, err := ifaceCli.GetAll(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(lifeCli.Start(ctx, "unknown-app")).ToNot(Succeed())
+
+			By("Letting the trial call through after the cooldown")
+			_, err = ifaceCli.GetAll(ctx)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(policy.Breaker.Open()).To(BeFalse())
+		})
+
+		It("Should not count errors returned by the node", func() {
+			for i := 0; i < 5; i++ {
+				status, err := lifeCli.GetStatus(ctx, "unknown-app")
+				Expect(err).To(HaveOccurred())
+				Expect(status).To(Equal(cce.Unknown))
+			}
+			Expect(policy.Breaker.Open()).To(BeFalse())
+		})
+	})
+
+	Describe("Nil Policy", func() {
+		It("Should make a single attempt", func() {
+			ifaceCli.Policy = nil
+			node.FailNext(1, unreached)
+
+			_, err := ifaceCli.GetAll(ctx)
+			Expect(err).To(HaveOccurred())
+			Expect(node.Calls()).To(Equal(1))
+		})
+	})
+})
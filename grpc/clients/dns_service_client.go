@@ -15,12 +15,16 @@ import (
 // DNSServiceClient wraps the PB client.
 type DNSServiceClient struct {
 	PBCli elapb.DNSServiceClient
+
+	// Policy bounds and retries the RPCs. If it is nil, a single attempt
+	// is made.
+	Policy *CallPolicy
 }
 
 // NewDNSServiceClient creates a new client.
 func NewDNSServiceClient(conn *grpc.ClientConn) *DNSServiceClient {
 	return &DNSServiceClient{
-		conn.NewDNSServiceClient(),
+		PBCli: conn.NewDNSServiceClient(),
 	}
 }
 
@@ -29,12 +33,15 @@ func (c *DNSServiceClient) SetA(
 	ctx context.Context,
 	record *cce.DNSARecord,
 ) error {
-	_, err := c.PBCli.SetA(
-		ctx,
-		&elapb.DNSARecordSet{
-			Name:   record.Name,
-			Values: record.IPs,
-		})
+	err := c.Policy.call(ctx, true, func(ctx context.Context) error {
+		_, err := c.PBCli.SetA(
+			ctx,
+			&elapb.DNSARecordSet{
+				Name:   record.Name,
+				Values: record.IPs,
+			})
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error setting A records")
@@ -48,12 +55,15 @@ func (c *DNSServiceClient) DeleteA(
 	ctx context.Context,
 	record *cce.DNSARecord,
 ) error {
-	_, err := c.PBCli.DeleteA(
-		ctx,
-		&elapb.DNSARecordSet{
-			Name:   record.Name,
-			Values: record.IPs,
-		})
+	err := c.Policy.call(ctx, false, func(ctx context.Context) error {
+		_, err := c.PBCli.DeleteA(
+			ctx,
+			&elapb.DNSARecordSet{
+				Name:   record.Name,
+				Values: record.IPs,
+			})
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error deleting A records")
@@ -72,8 +82,11 @@ func (c *DNSServiceClient) SetForwarders(
 		ips = append(ips, forwarder.IP)
 	}
 
-	_, err := c.PBCli.SetForwarders(ctx, &elapb.DNSForwarders{
-		IpAddresses: ips,
+	err := c.Policy.call(ctx, true, func(ctx context.Context) error {
+		_, err := c.PBCli.SetForwarders(ctx, &elapb.DNSForwarders{
+			IpAddresses: ips,
+		})
+		return err
 	})
 
 	if err != nil {
@@ -93,8 +106,11 @@ func (c *DNSServiceClient) DeleteForwarders(
 		ips = append(ips, forwarder.IP)
 	}
 
-	_, err := c.PBCli.DeleteForwarders(ctx, &elapb.DNSForwarders{
-		IpAddresses: ips,
+	err := c.Policy.call(ctx, false, func(ctx context.Context) error {
+		_, err := c.PBCli.DeleteForwarders(ctx, &elapb.DNSForwarders{
+			IpAddresses: ips,
+		})
+		return err
 	})
 
 	if err != nil {
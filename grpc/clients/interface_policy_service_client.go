@@ -15,6 +15,10 @@ import (
 // InterfacePolicyServiceClient wraps the PB client.
 type InterfacePolicyServiceClient struct {
 	PBCli elapb.InterfacePolicyServiceClient
+
+	// Policy bounds and retries the RPCs. If it is nil, a single attempt
+	// is made.
+	Policy *CallPolicy
 }
 
 // NewInterfacePolicyServiceClient creates a new client.
@@ -22,7 +26,7 @@ func NewInterfacePolicyServiceClient(
 	conn *grpc.ClientConn,
 ) *InterfacePolicyServiceClient {
 	return &InterfacePolicyServiceClient{
-		conn.NewInterfacePolicyServiceClient(),
+		PBCli: conn.NewInterfacePolicyServiceClient(),
 	}
 }
 
@@ -32,9 +36,12 @@ func (c *InterfacePolicyServiceClient) Set(
 	interfaceID string,
 	interfacePolicy *cce.TrafficPolicy,
 ) error {
-	_, err := c.PBCli.Set(
-		ctx,
-		toPBTrafficPolicy(interfaceID, interfacePolicy))
+	err := c.Policy.call(ctx, true, func(ctx context.Context) error {
+		_, err := c.PBCli.Set(
+			ctx,
+			toPBTrafficPolicy(interfaceID, interfacePolicy))
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error setting interface policy")
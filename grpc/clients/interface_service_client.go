@@ -16,12 +16,16 @@ import (
 // InterfaceServiceClient wraps the PB client.
 type InterfaceServiceClient struct {
 	PBCli elapb.InterfaceServiceClient
+
+	// Policy bounds and retries the RPCs. If it is nil, a single attempt
+	// is made.
+	Policy *CallPolicy
 }
 
 // NewInterfaceServiceClient creates a new client.
 func NewInterfaceServiceClient(conn *grpc.ClientConn) *InterfaceServiceClient {
 	return &InterfaceServiceClient{
-		conn.NewInterfaceServiceClient(),
+		PBCli: conn.NewInterfaceServiceClient(),
 	}
 }
 
@@ -30,9 +34,12 @@ func (c *InterfaceServiceClient) Update(
 	ctx context.Context,
 	ni *cce.NetworkInterface,
 ) error {
-	_, err := c.PBCli.Update(
-		ctx,
-		toPBNetworkInterface(ni))
+	err := c.Policy.call(ctx, false, func(ctx context.Context) error {
+		_, err := c.PBCli.Update(
+			ctx,
+			toPBNetworkInterface(ni))
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error updating network interface")
@@ -51,11 +58,14 @@ func (c *InterfaceServiceClient) BulkUpdate(
 		pbNIs = append(pbNIs, toPBNetworkInterface(ni))
 	}
 
-	_, err := c.PBCli.BulkUpdate(
-		ctx,
-		&elapb.NetworkInterfaces{
-			NetworkInterfaces: pbNIs,
-		})
+	err := c.Policy.call(ctx, false, func(ctx context.Context) error {
+		_, err := c.PBCli.BulkUpdate(
+			ctx,
+			&elapb.NetworkInterfaces{
+				NetworkInterfaces: pbNIs,
+			})
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error bulk updating network interfaces")
@@ -68,7 +78,11 @@ func (c *InterfaceServiceClient) BulkUpdate(
 func (c *InterfaceServiceClient) GetAll(
 	ctx context.Context,
 ) ([]*cce.NetworkInterface, error) {
-	pbNIs, err := c.PBCli.GetAll(ctx, &empty.Empty{})
+	var pbNIs *elapb.NetworkInterfaces
+	err := c.Policy.call(ctx, true, func(ctx context.Context) (err error) {
+		pbNIs, err = c.PBCli.GetAll(ctx, &empty.Empty{})
+		return err
+	})
 
 	if err != nil {
 		return nil, errors.Wrap(err, "error retrieving all network interfaces")
@@ -87,11 +101,15 @@ func (c *InterfaceServiceClient) Get(
 	ctx context.Context,
 	id string,
 ) (*cce.NetworkInterface, error) {
-	pbNI, err := c.PBCli.Get(
-		ctx,
-		&elapb.InterfaceID{
-			Id: id,
-		})
+	var pbNI *elapb.NetworkInterface
+	err := c.Policy.call(ctx, true, func(ctx context.Context) (err error) {
+		pbNI, err = c.PBCli.Get(
+			ctx,
+			&elapb.InterfaceID{
+				Id: id,
+			})
+		return err
+	})
 
 	if err != nil {
 		return nil, errors.Wrap(err, "error retrieving network interface")
@@ -209,6 +209,27 @@ var _ = Describe("Network Interface Service Client", func() {
 					status.Errorf(codes.NotFound,
 						"Network Interface %s not found", badID)))
 			})
+
+			It("Should return an error if a zone does not exist", func() {
+				By("Passing a nonexistent zone ID")
+				badID := uuid.New()
+				err := interfaceSvcCli.Update(ctx,
+					&cce.NetworkInterface{
+						ID:          "if2",
+						Description: "interface2",
+						Driver:      "kernel",
+						Type:        "none",
+						MACAddress:  "mac2",
+						VLAN:        2,
+						Zones:       []string{badID},
+					})
+
+				By("Verifying an InvalidArgument response")
+				Expect(err).To(HaveOccurred())
+				Expect(errors.Cause(err)).To(Equal(
+					status.Errorf(codes.InvalidArgument,
+						"Network Zone %s of Network Interface if2 not found", badID)))
+			})
 		})
 	})
 
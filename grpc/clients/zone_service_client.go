@@ -15,12 +15,16 @@ import (
 // ZoneServiceClient wraps the PB client.
 type ZoneServiceClient struct {
 	PBCli elapb.ZoneServiceClient
+
+	// Policy bounds and retries the RPCs. If it is nil, a single attempt
+	// is made.
+	Policy *CallPolicy
 }
 
 // NewZoneServiceClient creates a new client.
 func NewZoneServiceClient(conn *grpc.ClientConn) *ZoneServiceClient {
 	return &ZoneServiceClient{
-		conn.NewZoneServiceClient(),
+		PBCli: conn.NewZoneServiceClient(),
 	}
 }
 
@@ -29,9 +33,12 @@ func (c *ZoneServiceClient) Create(
 	ctx context.Context,
 	zone *elapb.NetworkZone,
 ) error {
-	_, err := c.PBCli.Create(
-		ctx,
-		zone)
+	err := c.Policy.call(ctx, false, func(ctx context.Context) error {
+		_, err := c.PBCli.Create(
+			ctx,
+			zone)
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error creating network zone")
@@ -45,9 +52,12 @@ func (c *ZoneServiceClient) Update(
 	ctx context.Context,
 	ni *elapb.NetworkZone,
 ) error {
-	_, err := c.PBCli.Update(
-		ctx,
-		ni)
+	err := c.Policy.call(ctx, false, func(ctx context.Context) error {
+		_, err := c.PBCli.Update(
+			ctx,
+			ni)
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error updating network zone")
@@ -61,9 +71,12 @@ func (c *ZoneServiceClient) BulkUpdate(
 	ctx context.Context,
 	nis *elapb.NetworkZones,
 ) error {
-	_, err := c.PBCli.BulkUpdate(
-		ctx,
-		nis)
+	err := c.Policy.call(ctx, false, func(ctx context.Context) error {
+		_, err := c.PBCli.BulkUpdate(
+			ctx,
+			nis)
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error bulk updating network zones")
@@ -76,7 +89,11 @@ func (c *ZoneServiceClient) BulkUpdate(
 func (c *ZoneServiceClient) GetAll(
 	ctx context.Context,
 ) (*elapb.NetworkZones, error) {
-	nis, err := c.PBCli.GetAll(ctx, &empty.Empty{})
+	var nis *elapb.NetworkZones
+	err := c.Policy.call(ctx, true, func(ctx context.Context) (err error) {
+		nis, err = c.PBCli.GetAll(ctx, &empty.Empty{})
+		return err
+	})
 
 	if err != nil {
 		return nil, errors.Wrap(err, "error retrieving all network zones")
@@ -90,11 +107,15 @@ func (c *ZoneServiceClient) Get(
 	ctx context.Context,
 	id string,
 ) (*elapb.NetworkZone, error) {
-	ni, err := c.PBCli.Get(
-		ctx,
-		&elapb.ZoneID{
-			Id: id,
-		})
+	var ni *elapb.NetworkZone
+	err := c.Policy.call(ctx, true, func(ctx context.Context) (err error) {
+		ni, err = c.PBCli.Get(
+			ctx,
+			&elapb.ZoneID{
+				Id: id,
+			})
+		return err
+	})
 
 	if err != nil {
 		return nil, errors.Wrap(err, "error retrieving network zone")
@@ -108,11 +129,14 @@ func (c *ZoneServiceClient) Delete(
 	ctx context.Context,
 	id string,
 ) error {
-	_, err := c.PBCli.Delete(
-		ctx,
-		&elapb.ZoneID{
-			Id: id,
-		})
+	err := c.Policy.call(ctx, false, func(ctx context.Context) error {
+		_, err := c.PBCli.Delete(
+			ctx,
+			&elapb.ZoneID{
+				Id: id,
+			})
+		return err
+	})
 
 	if err != nil {
 		return errors.Wrap(err, "error deleting network zone")
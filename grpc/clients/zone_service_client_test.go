@@ -6,6 +6,7 @@ package clients_test
 import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
 	elapb "github.com/open-ness/edgecontroller/pb/ela"
 	"github.com/open-ness/edgecontroller/uuid"
 	"github.com/pkg/errors"
@@ -52,7 +53,24 @@ var _ = Describe("Network Zone Service Client", func() {
 			})
 		})
 
-		Describe("Errors", func() {})
+		Describe("Errors", func() {
+			It("Should return an error if the ID already exists", func() {
+				By("Creating the first zone again")
+				err := zoneSvcCli.Create(
+					ctx,
+					&elapb.NetworkZone{
+						Id:          zoneID,
+						Description: "test_duplicate_network_zone",
+					},
+				)
+
+				By("Verifying an AlreadyExists response")
+				Expect(err).To(HaveOccurred())
+				Expect(errors.Cause(err)).To(Equal(
+					status.Errorf(codes.AlreadyExists,
+						"Network Zone %s already exists", zoneID)))
+			})
+		})
 	})
 
 	Describe("Update", func() {
@@ -273,6 +291,35 @@ var _ = Describe("Network Zone Service Client", func() {
 					status.Errorf(codes.NotFound,
 						"Network Zone %s not found", badID)))
 			})
+
+			It("Should return an error if the zone is in use", func() {
+				By("Adding the first interface to the zone")
+				iface := &cce.NetworkInterface{
+					ID:          "if0",
+					Description: "interface0",
+					Driver:      "kernel",
+					Type:        "none",
+					MACAddress:  "mac0",
+					VLAN:        0,
+					Zones:       []string{zoneID},
+				}
+				Expect(interfaceSvcCli.Update(ctx, iface)).To(Succeed())
+
+				defer func() {
+					By("Removing the first interface from the zone")
+					iface.Zones = nil
+					Expect(interfaceSvcCli.Update(ctx, iface)).To(Succeed())
+				}()
+
+				By("Deleting the zone")
+				err := zoneSvcCli.Delete(ctx, zoneID)
+
+				By("Verifying a FailedPrecondition response")
+				Expect(err).To(HaveOccurred())
+				Expect(errors.Cause(err)).To(Equal(
+					status.Errorf(codes.FailedPrecondition,
+						"Network Zone %s in use by Network Interface if0", zoneID)))
+			})
 		})
 	})
 })
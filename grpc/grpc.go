@@ -10,6 +10,7 @@ import (
 
 	"github.com/pkg/errors"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials"
 
 	logger "github.com/open-ness/common/log"
@@ -48,6 +49,11 @@ func (c *ClientConn) Close() error {
 	return c.conn.Close()
 }
 
+// GetState wraps grpc.GetState()
+func (c *ClientConn) GetState() connectivity.State {
+	return c.conn.GetState()
+}
+
 // NewApplicationDeploymentServiceClient wraps the pb function.
 func (c *ClientConn) NewApplicationDeploymentServiceClient() evapb.ApplicationDeploymentServiceClient {
 	return evapb.NewApplicationDeploymentServiceClient(c.conn)
@@ -6,11 +6,13 @@ package node
 import (
 	"context"
 	"crypto/tls"
+	"fmt"
 
 	cce "github.com/open-ness/edgecontroller"
 	"github.com/open-ness/edgecontroller/grpc"
 	gclients "github.com/open-ness/edgecontroller/grpc/clients"
 	ggrpc "google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 )
 
 // ClientConn wraps a Node and provides a Connect() method to create wrapped gRPC clients.
@@ -19,8 +21,14 @@ type ClientConn struct {
 	Port string
 	TLS  *tls.Config
 
+	// Policy is applied to all RPCs made through the clients. It may be nil.
+	Policy *gclients.CallPolicy
+
 	conn *grpc.ClientConn
 
+	// release is set on connections owned by a ConnManager
+	release func()
+
 	AppDeploySvcCli   *gclients.ApplicationDeploymentServiceClient
 	AppLifeSvcCli     *gclients.ApplicationLifecycleServiceClient
 	AppPolicySvcCli   *gclients.ApplicationPolicyServiceClient
@@ -42,7 +50,9 @@ func (cc *ClientConn) Connect(ctx context.Context) error {
 
 		// EVA
 		cc.AppDeploySvcCli = gclients.NewApplicationDeploymentServiceClient(cc.conn)
+		cc.AppDeploySvcCli.Policy = cc.Policy
 		cc.AppLifeSvcCli = gclients.NewApplicationLifecycleServiceClient(cc.conn)
+		cc.AppLifeSvcCli.Policy = cc.Policy
 	} else {
 		// OP-1742: ContextDialler not supported by Gateway
 		//nolint:staticcheck
@@ -51,16 +61,83 @@ func (cc *ClientConn) Connect(ctx context.Context) error {
 
 		// ELA
 		cc.AppPolicySvcCli = gclients.NewApplicationPolicyServiceClient(cc.conn)
+		cc.AppPolicySvcCli.Policy = cc.Policy
 		cc.IfacePolicySvcCli = gclients.NewInterfacePolicyServiceClient(cc.conn)
+		cc.IfacePolicySvcCli.Policy = cc.Policy
 		cc.DNSSvcCli = gclients.NewDNSServiceClient(cc.conn)
+		cc.DNSSvcCli.Policy = cc.Policy
 		cc.IfaceSvcCli = gclients.NewInterfaceServiceClient(cc.conn)
+		cc.IfaceSvcCli.Policy = cc.Policy
 
-		cc.ZoneSvcCli = gclients.NewZoneServiceClient(cc.conn) // XXX unimplemented?
+		cc.ZoneSvcCli = gclients.NewZoneServiceClient(cc.conn)
+		cc.ZoneSvcCli.Policy = cc.Policy
 	}
 
 	return err
 }
 
+// Disconnect closes the connection. Connections obtained from a ConnManager
+// are released and left open for reuse instead.
 func (cc *ClientConn) Disconnect() {
-	cc.conn.Close()
+	if cc.release != nil {
+		cc.release()
+		return
+	}
+	cc.close()
+}
+
+// Release implements cce.NodeConn. It is the same as Disconnect.
+func (cc *ClientConn) Release() {
+	cc.Disconnect()
+}
+
+// Dial returns a connection to the node agent listening on addr and port. It
+// is taken from conns if that is not nil, and dialed otherwise. Either way the
+// caller must Disconnect it when done.
+func Dial(
+	ctx context.Context,
+	conns cce.NodeConnectionService,
+	nodeID string,
+	addr string,
+	port string,
+	conf *tls.Config,
+) (*ClientConn, error) {
+	if conns != nil {
+		conn, err := conns.Get(ctx, nodeID, addr, port, conf)
+		if err != nil {
+			return nil, err
+		}
+		cc, ok := conn.(*ClientConn)
+		if !ok {
+			conn.Release()
+			return nil, fmt.Errorf("unsupported node connection %T", conn)
+		}
+		return cc, nil
+	}
+
+	cc := &ClientConn{Addr: addr, Port: port, TLS: conf}
+	if err := cc.Connect(ctx); err != nil {
+		return nil, err
+	}
+	return cc, nil
+}
+
+func (cc *ClientConn) close() {
+	if cc.conn != nil {
+		cc.conn.Close()
+	}
+}
+
+// usable reports whether the connection can serve new requests. A connection
+// that is shut down or failing to reconnect is replaced rather than waited on.
+func (cc *ClientConn) usable() bool {
+	if cc.conn == nil {
+		return true
+	}
+	switch cc.conn.GetState() {
+	case connectivity.Shutdown, connectivity.TransientFailure:
+		return false
+	default:
+		return true
+	}
 }
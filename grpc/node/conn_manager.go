@@ -0,0 +1,242 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package node
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"sync"
+	"time"
+
+	logger "github.com/open-ness/common/log"
+	cce "github.com/open-ness/edgecontroller"
+	gclients "github.com/open-ness/edgecontroller/grpc/clients"
+)
+
+var log = logger.DefaultLogger.WithField("pkg", "node")
+
+// DefaultIdleTimeout is the default time an unused connection is kept open.
+const DefaultIdleTimeout = 5 * time.Minute
+
+// ErrConnManagerClosed is returned by Get after the ConnManager was closed.
+var ErrConnManagerClosed = errors.New("node connection manager closed")
+
+type connKey struct {
+	nodeID string
+	port   string
+}
+
+type managedConn struct {
+	cc       *ClientConn
+	lastUsed time.Time
+
+	// refs counts the callers that got the connection and did not release it
+	refs int
+	// stale connections are no longer handed out and are closed once released
+	stale bool
+}
+
+// ConnManager caches one ClientConn per node and port so that requests to a
+// node reuse an established connection instead of dialing and handshaking
+// every time. All connections to a node share a CallPolicy, and so a circuit
+// breaker. It implements cce.NodeConnectionService.
+//
+// Connections handed out by Get are shared and must not be closed by the
+// caller; Disconnect releases them instead. A connection that is invalidated,
+// replaced or evicted while in use is closed once its last user released it.
+type ConnManager struct {
+	idleTimeout time.Duration
+	calls       gclients.CallConfig
+
+	// connect dials a ClientConn and disconnect closes it. They are replaced
+	// in tests.
+	connect    func(ctx context.Context, cc *ClientConn) error
+	disconnect func(cc *ClientConn)
+
+	mu       sync.Mutex
+	conns    map[connKey]*managedConn
+	policies map[string]*gclients.CallPolicy
+	closed   bool
+	done     chan struct{}
+}
+
+// NewConnManager creates a ConnManager that closes connections unused for
+// longer than idleTimeout and applies calls to their RPCs. If idleTimeout is
+// zero, DefaultIdleTimeout is used.
+func NewConnManager(idleTimeout time.Duration, calls gclients.CallConfig) *ConnManager {
+	if idleTimeout == 0 {
+		idleTimeout = DefaultIdleTimeout
+	}
+	m := &ConnManager{
+		idleTimeout: idleTimeout,
+		calls:       calls,
+		connect: func(ctx context.Context, cc *ClientConn) error {
+			return cc.Connect(ctx)
+		},
+		disconnect: func(cc *ClientConn) {
+			cc.close()
+		},
+		conns:    make(map[connKey]*managedConn),
+		policies: make(map[string]*gclients.CallPolicy),
+		done:     make(chan struct{}),
+	}
+	go m.evictLoop()
+	return m
+}
+
+// Get returns a connection to the node agent listening on addr and port. A
+// cached connection is reused unless the node's address changed or the
+// connection is failing, in which case it is replaced. The connection is a
+// *ClientConn and must be released with Release or Disconnect.
+func (m *ConnManager) Get(
+	ctx context.Context,
+	nodeID string,
+	addr string,
+	port string,
+	conf *tls.Config,
+) (cce.NodeConn, error) {
+	key := connKey{nodeID: nodeID, port: port}
+
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil, ErrConnManagerClosed
+	}
+	if mc, ok := m.conns[key]; ok {
+		if mc.cc.Addr == addr && mc.cc.usable() {
+			cc := mc.acquire()
+			m.mu.Unlock()
+			return cc, nil
+		}
+		log.Debugf("Replacing connection to node %s on port %s", nodeID, port)
+		m.retire(key, mc)
+	}
+	policy, ok := m.policies[nodeID]
+	if !ok {
+		policy = m.calls.NewCallPolicy(nodeID)
+		m.policies[nodeID] = policy
+	}
+	m.mu.Unlock()
+
+	// Dial without holding the lock so that other nodes are not blocked
+	cc := &ClientConn{Addr: addr, Port: port, TLS: conf, Policy: policy}
+	if err := m.connect(ctx, cc); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.closed {
+		m.disconnect(cc)
+		return nil, ErrConnManagerClosed
+	}
+	// Another request may have dialed the same node in the meantime, at the
+	// same address or at one replaced since
+	if mc, ok := m.conns[key]; ok {
+		if mc.cc.Addr == addr {
+			m.disconnect(cc)
+			return mc.acquire(), nil
+		}
+		m.retire(key, mc)
+	}
+	mc := &managedConn{cc: cc}
+	cc.release = func() { m.release(mc) }
+	m.conns[key] = mc
+	return mc.acquire(), nil
+}
+
+// acquire hands out the connection to one more caller. m.mu must be held.
+func (mc *managedConn) acquire() *ClientConn {
+	mc.refs++
+	mc.lastUsed = time.Now()
+	return mc.cc
+}
+
+// release is called when a caller is done with a connection.
+func (m *ConnManager) release(mc *managedConn) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if mc.refs == 0 {
+		return
+	}
+	mc.refs--
+	mc.lastUsed = time.Now()
+	if mc.stale && mc.refs == 0 {
+		m.disconnect(mc.cc)
+	}
+}
+
+// retire stops handing out a connection and closes it as soon as no caller
+// uses it anymore. m.mu must be held.
+func (m *ConnManager) retire(key connKey, mc *managedConn) {
+	delete(m.conns, key)
+	mc.stale = true
+	if mc.refs == 0 {
+		m.disconnect(mc.cc)
+	}
+}
+
+// Invalidate closes all cached connections to a node and resets its circuit
+// breaker. It must be called when the node's address or credentials change.
+// Connections in use are closed once released.
+func (m *ConnManager) Invalidate(nodeID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.policies, nodeID)
+
+	for key, mc := range m.conns {
+		if key.nodeID == nodeID {
+			m.retire(key, mc)
+		}
+	}
+}
+
+// Close closes all cached connections and stops idle eviction. Connections in
+// use are closed once released. Subsequent calls to Get fail with
+// ErrConnManagerClosed.
+func (m *ConnManager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil
+	}
+	m.closed = true
+	close(m.done)
+	for key, mc := range m.conns {
+		m.retire(key, mc)
+	}
+	return nil
+}
+
+func (m *ConnManager) evictLoop() {
+	ticker := time.NewTicker(m.idleTimeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.done:
+			return
+		case now := <-ticker.C:
+			m.evictIdle(now)
+		}
+	}
+}
+
+// evictIdle closes the connections unused since before now - idleTimeout.
+// Connections in use are never idle.
+func (m *ConnManager) evictIdle(now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, mc := range m.conns {
+		if mc.refs == 0 && now.Sub(mc.lastUsed) > m.idleTimeout {
+			log.Debugf("Closing idle connection to node %s on port %s", key.nodeID, key.port)
+			m.retire(key, mc)
+		}
+	}
+}
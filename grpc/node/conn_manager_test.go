@@ -0,0 +1,199 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package node_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	gclients "github.com/open-ness/edgecontroller/grpc/clients"
+	"github.com/open-ness/edgecontroller/grpc/node"
+)
+
+var _ = Describe("ConnManager", func() {
+	var (
+		conns  *node.ConnManager
+		dials  int
+		closed []*node.ClientConn
+	)
+
+	BeforeEach(func() {
+		dials = 0
+		closed = nil
+		conns = node.NewConnManager(time.Minute, gclients.DefaultCallConfig())
+		conns.SetConnect(func(ctx context.Context, cc *node.ClientConn) error {
+			dials++
+			return nil
+		})
+		conns.SetDisconnect(func(cc *node.ClientConn) {
+			closed = append(closed, cc)
+		})
+	})
+
+	AfterEach(func() {
+		Expect(conns.Close()).To(Succeed())
+	})
+
+	Describe("Get", func() {
+		It("Should reuse the connection to the same node and port", func() {
+			cc1, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+			cc2, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(cc2).To(BeIdenticalTo(cc1))
+			Expect(dials).To(Equal(1))
+		})
+
+		It("Should dial each port separately", func() {
+			cc1, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+			cc2, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42102", nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(cc2).ToNot(BeIdenticalTo(cc1))
+			Expect(dials).To(Equal(2))
+		})
+
+		It("Should redial when the node address changed", func() {
+			_, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+			cc, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.2", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(cc.Addr).To(Equal("127.0.0.2"))
+			Expect(dials).To(Equal(2))
+		})
+
+		It("Should not cache a failed dial", func() {
+			conns.SetConnect(func(ctx context.Context, cc *node.ClientConn) error {
+				dials++
+				return errors.New("dial failed")
+			})
+
+			_, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).To(MatchError("dial failed"))
+			_, err = node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).To(HaveOccurred())
+			Expect(dials).To(Equal(2))
+		})
+
+		It("Should close the connection replaced by a concurrent dial", func() {
+			dialing := make(chan struct{})
+			dialed := make(chan struct{})
+			conns.SetConnect(func(ctx context.Context, cc *node.ClientConn) error {
+				if cc.Addr == "127.0.0.1" {
+					close(dialing)
+					<-dialed
+				}
+				return nil
+			})
+
+			done := make(chan *node.ClientConn)
+			go func() {
+				defer GinkgoRecover()
+				cc, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+				Expect(err).ToNot(HaveOccurred())
+				done <- cc
+			}()
+			<-dialing
+
+			By("Dialing the node at another address in the meantime")
+			cc2, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.2", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+			cc2.Disconnect()
+			close(dialed)
+			cc1 := <-done
+
+			Expect(closed).To(ConsistOf(cc2))
+			cc, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cc).To(BeIdenticalTo(cc1))
+		})
+
+		It("Should fail after Close", func() {
+			Expect(conns.Close()).To(Succeed())
+
+			_, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).To(Equal(node.ErrConnManagerClosed))
+		})
+	})
+
+	Describe("Disconnect", func() {
+		It("Should keep a pooled connection", func() {
+			cc1, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+			cc1.Disconnect()
+
+			cc2, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cc2).To(BeIdenticalTo(cc1))
+		})
+	})
+
+	Describe("Invalidate", func() {
+		It("Should drop all connections to the node only", func() {
+			_, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42102", nil)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = node.Dial(context.TODO(), conns, "node-2", "127.0.0.2", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			conns.Invalidate("node-1")
+
+			for _, port := range []string{"42101", "42102"} {
+				_, err = node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", port, nil)
+				Expect(err).ToNot(HaveOccurred())
+			}
+			_, err = node.Dial(context.TODO(), conns, "node-2", "127.0.0.2", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dials).To(Equal(5))
+		})
+
+		It("Should close a connection in use once it is released", func() {
+			cc, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			conns.Invalidate("node-1")
+			Expect(closed).To(BeEmpty())
+
+			cc.Disconnect()
+			Expect(closed).To(ConsistOf(cc))
+		})
+	})
+
+	Describe("EvictIdle", func() {
+		It("Should drop connections unused for longer than the idle timeout", func() {
+			cc, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+			cc.Disconnect()
+
+			conns.EvictIdle(time.Now().Add(30 * time.Second))
+			cc, err = node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+			cc.Disconnect()
+			Expect(dials).To(Equal(1))
+
+			conns.EvictIdle(time.Now().Add(2 * time.Minute))
+			_, err = node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dials).To(Equal(2))
+		})
+
+		It("Should keep connections in use", func() {
+			_, err := node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+
+			conns.EvictIdle(time.Now().Add(2 * time.Minute))
+			Expect(closed).To(BeEmpty())
+			_, err = node.Dial(context.TODO(), conns, "node-1", "127.0.0.1", "42101", nil)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(dials).To(Equal(1))
+		})
+	})
+})
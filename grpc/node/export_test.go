@@ -0,0 +1,26 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package node
+
+import (
+	"context"
+	"time"
+)
+
+// SetConnect replaces the function used by a ConnManager to dial nodes.
+func (m *ConnManager) SetConnect(connect func(ctx context.Context, cc *ClientConn) error) {
+	m.connect = connect
+}
+
+// SetDisconnect replaces the function used by a ConnManager to close
+// connections.
+func (m *ConnManager) SetDisconnect(disconnect func(cc *ClientConn)) {
+	m.disconnect = disconnect
+}
+
+// EvictIdle closes the connections of a ConnManager unused since before now
+// minus the idle timeout.
+func (m *ConnManager) EvictIdle(now time.Time) {
+	m.evictIdle(now)
+}
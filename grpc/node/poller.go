@@ -0,0 +1,118 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package node
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/pkg/errors"
+)
+
+const (
+	defaultELAPort = "42101"
+	defaultEVAPort = "42102"
+)
+
+// Poller periodically polls the agents of every enrolled node. It is shared by
+// the jobs that keep track of nodes, such as the liveness monitor and the
+// inventory collector.
+type Poller struct {
+	Controller *cce.Controller
+
+	// Interval is the time between two rounds of polls.
+	Interval time.Duration
+
+	// Timeout bounds a single request to a node agent.
+	Timeout time.Duration
+
+	// Poll polls a single node. Errors are logged.
+	Poll func(ctx context.Context, target *cce.NodeGRPCTarget) error
+}
+
+// Run polls all nodes every Interval until the context is canceled.
+func (p *Poller) Run(ctx context.Context) error {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.PollAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// PollAll polls all enrolled nodes concurrently.
+func (p *Poller) PollAll(ctx context.Context) {
+	targets, err := p.Controller.PersistenceService.ReadAll(ctx, &cce.NodeGRPCTarget{})
+	if err != nil {
+		log.Errf("Error reading node targets: %v", err)
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, t := range targets {
+		wg.Add(1)
+		go func(target *cce.NodeGRPCTarget) {
+			defer wg.Done()
+			if err := p.Poll(ctx, target); err != nil {
+				log.Errf("Error polling node %s: %v", target.NodeID, err)
+			}
+		}(t.(*cce.NodeGRPCTarget))
+	}
+	wg.Wait()
+}
+
+// Target returns the address of a node, or nil if the node has not enrolled
+// yet.
+func (p *Poller) Target(ctx context.Context, nodeID string) (*cce.NodeGRPCTarget, error) {
+	targets, err := p.Controller.PersistenceService.Filter(ctx, &cce.NodeGRPCTarget{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch gRPC target from DB")
+	}
+	if len(targets) == 0 {
+		return nil, nil
+	}
+	return targets[0].(*cce.NodeGRPCTarget), nil
+}
+
+// Connect returns a connection to the node agent listening on addr and port,
+// authenticated with the edge node credentials of the controller. The caller
+// must Disconnect it when done.
+func (p *Poller) Connect(ctx context.Context, nodeID, addr, port string) (*ClientConn, error) {
+	conf := p.Controller.EdgeNodeCreds
+	if conf != nil {
+		conf = conf.Clone()
+		conf.ServerName = nodeID
+	}
+	return Dial(ctx, p.Controller.NodeConns, nodeID, addr, port, conf)
+}
+
+// ELAPort returns the port the ELA of nodes listens on.
+func (p *Poller) ELAPort() string {
+	if p.Controller.ELAPort == "" {
+		return defaultELAPort
+	}
+	return p.Controller.ELAPort
+}
+
+// EVAPort returns the port the EVA of nodes listens on.
+func (p *Poller) EVAPort() string {
+	if p.Controller.EVAPort == "" {
+		return defaultEVAPort
+	}
+	return p.Controller.EVAPort
+}
@@ -0,0 +1,77 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package node_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/grpc/node"
+	"github.com/open-ness/edgecontroller/internal/stubs"
+)
+
+var _ = Describe("Poller", func() {
+	var (
+		ps     *stubs.MemoryPersistenceService
+		poller *node.Poller
+
+		mu     sync.Mutex
+		polled []string
+	)
+
+	BeforeEach(func() {
+		ps = stubs.NewMemoryPersistenceService()
+		polled = nil
+		poller = &node.Poller{
+			Controller: &cce.Controller{PersistenceService: ps},
+			Interval:   time.Minute,
+			Timeout:    time.Second,
+			Poll: func(ctx context.Context, target *cce.NodeGRPCTarget) error {
+				mu.Lock()
+				defer mu.Unlock()
+				polled = append(polled, target.NodeID)
+				return errors.New("node unreachable")
+			},
+		}
+
+		Expect(ps.Create(context.TODO(), &cce.NodeGRPCTarget{
+			ID: "target-1", NodeID: "node-1", GRPCTarget: "127.0.0.1"})).To(Succeed())
+		Expect(ps.Create(context.TODO(), &cce.NodeGRPCTarget{
+			ID: "target-2", NodeID: "node-2", GRPCTarget: "127.0.0.2"})).To(Succeed())
+	})
+
+	Describe("PollAll", func() {
+		It("Should poll every enrolled node despite errors", func() {
+			poller.PollAll(context.TODO())
+			Expect(polled).To(ConsistOf("node-1", "node-2"))
+		})
+	})
+
+	Describe("Target", func() {
+		It("Should return the address of a node", func() {
+			target, err := poller.Target(context.TODO(), "node-2")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(target.GRPCTarget).To(Equal("127.0.0.2"))
+		})
+
+		It("Should return nil for a node that has not enrolled", func() {
+			Expect(poller.Target(context.TODO(), "node-3")).To(BeNil())
+		})
+	})
+
+	Describe("ELAPort and EVAPort", func() {
+		It("Should default to the standard ports", func() {
+			Expect(poller.ELAPort()).To(Equal("42101"))
+			Expect(poller.EVAPort()).To(Equal("42102"))
+
+			poller.Controller.ELAPort = "8101"
+			Expect(poller.ELAPort()).To(Equal("8101"))
+		})
+	})
+})
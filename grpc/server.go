@@ -5,19 +5,19 @@ package grpc
 
 import (
 	"context"
-	"crypto/md5" //nolint:gosec
 	"crypto/tls"
 	"crypto/x509"
 	"crypto/x509/pkix"
-	"encoding/base64"
 	"encoding/pem"
 	"fmt"
 	"net"
+	"time"
 
 	"google.golang.org/grpc"
 
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
 
@@ -42,6 +42,10 @@ const (
 	// receive a certificate. It is similar to how a REST app may require a
 	// session token for API paths other than /login.
 	enrollmentMethod = "/openness.auth.AuthService/RequestCredentials"
+
+	// EnrollmentTokenKey is the gRPC metadata key of the enrollment token a
+	// node may enroll with instead of having its public key registered.
+	EnrollmentTokenKey = "enrollment-token"
 )
 
 // Server wraps grpc.Server
@@ -64,7 +68,7 @@ func NewServer(controller *cce.Controller, conf *tls.Config) *Server {
 					handler grpc.UnaryHandler,
 				) (resp interface{}, err error) {
 					// apply checkAuth middleware
-					if err := checkAuth(ctx,
+					if err := checkAuth(ctx, controller.PersistenceService,
 						info.FullMethod); err != nil {
 						return nil, err
 					}
@@ -79,7 +83,7 @@ func NewServer(controller *cce.Controller, conf *tls.Config) *Server {
 					handler grpc.StreamHandler,
 				) error {
 					// apply checkAuth middleware
-					if err := checkAuth(ss.Context(),
+					if err := checkAuth(ss.Context(), controller.PersistenceService,
 						info.FullMethod); err != nil {
 						return err
 					}
@@ -97,8 +101,9 @@ func NewServer(controller *cce.Controller, conf *tls.Config) *Server {
 
 // checkAuth is a middleware, applied inside the unary and stream interceptors,
 // to ensure that if the enrollment server config was used (i.e. no client cert
-// was provided) that only the enrollment endpoint is authorized.
-func checkAuth(ctx context.Context, method string) error {
+// was provided) that only the enrollment endpoint is authorized. Otherwise the
+// client certificate must not have been revoked.
+func checkAuth(ctx context.Context, ps cce.PersistenceService, method string) error {
 	p, ok := peer.FromContext(ctx)
 	if !ok {
 		return fmt.Errorf("expected peer info in gRPC context")
@@ -115,7 +120,20 @@ func checkAuth(ctx context.Context, method string) error {
 		}
 		return nil
 	case SNI:
-		return nil
+		chains := tlsInfo.State.VerifiedChains
+		if len(chains) < 1 {
+			return status.Error(codes.Unauthenticated, "no verified client certificate")
+		}
+		switch err := cce.CheckRevocation(ctx, ps, chains[0][0]); err {
+		case nil:
+			return nil
+		case cce.ErrCertificateRevoked:
+			return status.Errorf(codes.Unauthenticated, "client certificate of %s revoked",
+				chains[0][0].Subject.CommonName)
+		default:
+			log.Errf("Failed to check client certificate revocation: %v", err)
+			return status.Error(codes.Internal, "unable to check client certificate")
+		}
 	default:
 		return fmt.Errorf("unexpected server name: %s", tlsInfo.State.ServerName)
 	}
@@ -137,12 +155,234 @@ func (s *Server) Stop() {
 }
 
 // RequestCredentials requests authentication endpoint credentials.
-func (s *Server) RequestCredentials(ctx context.Context, id *authpb.Identity) ( // nolint: gocyclo
+func (s *Server) RequestCredentials(ctx context.Context, id *authpb.Identity) (
 	*authpb.Credentials,
 	error,
 ) {
-	// Parse and validate CSR
-	csr := id.GetCsr()
+	certReq, err := parseCSR(id.GetCsr())
+	if err != nil {
+		return nil, err
+	}
+
+	// Node's identity is the SHA-256 fingerprint of the public key data
+	serial := cce.KeyFingerprint(certReq.RawSubjectPublicKeyInfo)
+
+	// Approve the Node by enrollment token or by its pre-registered public key
+	var node *cce.Node
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(EnrollmentTokenKey)) > 0 {
+		node, err = s.redeemEnrollmentToken(ctx, md.Get(EnrollmentTokenKey)[0], serial)
+	} else {
+		node, err = s.approvedNode(ctx, certReq.RawSubjectPublicKeyInfo)
+		if err == nil {
+			err = s.checkNotRevoked(ctx, node.ID)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	creds, err := s.issueCredentials(ctx, node.ID, certReq)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the Node's IP address
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Internal, "missing peer data from context")
+	}
+	nodeIP, _, err := net.SplitHostPort(p.Addr.String())
+	if nodeIP == "" || err != nil {
+		return nil, status.Errorf(codes.Internal, "bad remote address in peer data: %s: %v",
+			p.Addr.String(), err)
+	}
+
+	// Store the Node's address, replacing the one of an earlier enrollment
+	if err := s.storeGRPCTarget(ctx, node.ID, nodeIP); err != nil {
+		log.Errf("Failed to store Node address: %v", err)
+		return nil, status.Error(codes.Internal, "unable to store node address")
+	}
+	// Also let the proxy node we have a new client
+	cce.RegisterToProxy(ctx, s.controller.PersistenceService, node.ID)
+	// Connections made before enrollment are no longer valid
+	if s.controller.NodeConns != nil {
+		s.controller.NodeConns.Invalidate(node.ID)
+	}
+
+	return creds, nil
+}
+
+// approvedNode returns the node pre-approved by the fingerprint of its public
+// key. A node registered with the legacy MD5 fingerprint is migrated to the
+// SHA-256 fingerprint.
+func (s *Server) approvedNode(ctx context.Context, publicKeyInfo []byte) (*cce.Node, error) {
+	ps := s.controller.PersistenceService
+	serial := cce.KeyFingerprint(publicKeyInfo)
+
+	for _, fingerprint := range []string{serial, cce.LegacyKeyFingerprint(publicKeyInfo)} {
+		entities, err := ps.Filter(ctx, &cce.Node{}, []cce.Filter{{
+			Field: "serial",
+			Value: fingerprint,
+		}})
+		if err != nil {
+			log.Errf("error getting node approval: %v", err)
+			return nil, status.Errorf(codes.Unauthenticated, "node %s not approved", serial)
+		}
+		if len(entities) == 0 {
+			continue
+		}
+
+		node := entities[0].(*cce.Node)
+		if node.Serial != serial {
+			node.Serial = serial
+			if err = ps.BulkUpdate(ctx, []cce.Persistable{node}); err != nil {
+				log.Errf("error migrating node %s to serial %s: %v", node.ID, serial, err)
+				return nil, status.Error(codes.Internal, "unable to update node")
+			}
+			log.Noticef("Node %s migrated from MD5 to SHA-256 serial %s", node.ID, serial)
+		}
+		return node, nil
+	}
+
+	return nil, status.Errorf(codes.Unauthenticated, "node %s not approved", serial)
+}
+
+// checkNotRevoked refuses to enroll a node by its public key while its current
+// certificate is revoked. An operator approves the node again by issuing it an
+// enrollment token.
+func (s *Server) checkNotRevoked(ctx context.Context, nodeID string) error {
+	persisted, err := s.controller.PersistenceService.Read(ctx, nodeID, &cce.Credentials{})
+	if err != nil {
+		log.Errf("error getting credentials of node %s: %v", nodeID, err)
+		return status.Error(codes.Internal, "unable to get credentials")
+	}
+	if persisted == nil {
+		return nil
+	}
+
+	creds := persisted.(*cce.Credentials)
+	cert, err := creds.ParseCertificate()
+	if err != nil {
+		log.Errf("error parsing certificate of node %s: %v", nodeID, err)
+		return status.Error(codes.Internal, "unable to get credentials")
+	}
+	if creds.IsRevoked(cert.SerialNumber) {
+		return status.Errorf(codes.Unauthenticated, "credentials of node %s revoked", nodeID)
+	}
+	return nil
+}
+
+// redeemEnrollmentToken consumes an enrollment token and binds the node it was
+// issued for to the public key serial.
+func (s *Server) redeemEnrollmentToken(ctx context.Context, token, serial string) (*cce.Node, error) {
+	ps := s.controller.PersistenceService
+
+	entities, err := ps.Filter(ctx, &cce.EnrollmentToken{}, []cce.Filter{{
+		Field: "token_hash",
+		Value: cce.HashEnrollmentToken(token),
+	}})
+	if err != nil {
+		log.Errf("error getting enrollment token: %v", err)
+		return nil, status.Error(codes.Internal, "unable to get enrollment token")
+	}
+	if len(entities) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "invalid enrollment token")
+	}
+	enrollmentToken := entities[0].(*cce.EnrollmentToken)
+
+	// A key can only identify a single node
+	nodes, err := ps.Filter(ctx, &cce.Node{}, []cce.Filter{{
+		Field: "serial",
+		Value: serial,
+	}})
+	if err != nil {
+		log.Errf("error getting nodes with serial %s: %v", serial, err)
+		return nil, status.Error(codes.Internal, "unable to get nodes")
+	}
+	for _, n := range nodes {
+		if n.GetID() != enrollmentToken.NodeID {
+			return nil, status.Errorf(codes.AlreadyExists, "serial %s already bound to another node", serial)
+		}
+	}
+
+	// Consume the token first, so only one request can redeem it
+	ok, err := ps.Delete(ctx, enrollmentToken.ID, enrollmentToken)
+	if err != nil {
+		log.Errf("error consuming enrollment token: %v", err)
+		return nil, status.Error(codes.Internal, "unable to consume enrollment token")
+	}
+	if !ok || enrollmentToken.Expired() {
+		return nil, status.Error(codes.Unauthenticated, "invalid enrollment token")
+	}
+
+	persisted, err := ps.Read(ctx, enrollmentToken.NodeID, &cce.Node{})
+	if err != nil {
+		log.Errf("error getting node %s: %v", enrollmentToken.NodeID, err)
+		return nil, status.Error(codes.Internal, "unable to get node")
+	}
+	if persisted == nil {
+		return nil, status.Error(codes.Unauthenticated, "invalid enrollment token")
+	}
+	node := persisted.(*cce.Node)
+
+	// Bind the node to its key
+	if node.Serial != serial {
+		if node.Serial != "" {
+			log.Noticef("Node %s enrolled with a new key: serial %s replaces %s", node.ID, serial, node.Serial)
+		}
+		node.Serial = serial
+		if err = ps.BulkUpdate(ctx, []cce.Persistable{node}); err != nil {
+			log.Errf("error binding node %s to serial %s: %v", node.ID, serial, err)
+			return nil, status.Error(codes.Internal, "unable to update node")
+		}
+	}
+
+	return node, nil
+}
+
+// RenewCredentials issues a new certificate to an enrolled node before its
+// current one expires. The node authenticates with its current certificate,
+// so the new one is issued for the same node ID.
+func (s *Server) RenewCredentials(ctx context.Context, id *authpb.Identity) (
+	*authpb.Credentials,
+	error,
+) {
+	nodeID, err := getNodeID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	certReq, err := parseCSR(id.GetCsr())
+	if err != nil {
+		return nil, err
+	}
+
+	// Only nodes that enrolled before can renew
+	node, err := s.controller.PersistenceService.Read(ctx, nodeID, &cce.Node{})
+	if err != nil {
+		log.Errf("error getting node %s: %v", nodeID, err)
+		return nil, status.Error(codes.Internal, "unable to get node")
+	}
+	if node == nil {
+		return nil, status.Errorf(codes.Unauthenticated, "node %s not approved", nodeID)
+	}
+
+	creds, err := s.issueCredentials(ctx, nodeID, certReq)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = cce.RaiseEvent(ctx, s.controller.PersistenceService, nodeID,
+		cce.EventNodeCredentialsRenewed, "certificate renewed"); err != nil {
+		log.Errf("Failed to raise event for node %s: %v", nodeID, err)
+	}
+	log.Infof("Renewed certificate of node %s", nodeID)
+
+	return creds, nil
+}
+
+// parseCSR decodes and validates a PEM-encoded certificate signing request.
+func parseCSR(csr string) (*x509.CertificateRequest, error) {
 	if csr == "" {
 		return nil, status.Error(codes.InvalidArgument, "CSR cannot be empty")
 	}
@@ -162,30 +402,21 @@ func (s *Server) RequestCredentials(ctx context.Context, id *authpb.Identity) (
 	if err = certReq.CheckSignature(); err != nil {
 		return nil, status.Errorf(codes.InvalidArgument, "error validating CSR: %v", err)
 	}
+	return certReq, nil
+}
 
-	// Node's identity is base64-encoded (w/o padding) MD5 hash of the public key data
-	// gosec: not hashing user input/passwords
-	hash := md5.Sum(certReq.RawSubjectPublicKeyInfo) //nolint:gosec
-	serial := base64.RawURLEncoding.EncodeToString(hash[:])
-
-	// Verify the Node's pre-approval by public key data
-	entities, err := s.controller.PersistenceService.Filter(ctx, &cce.Node{}, []cce.Filter{{
-		Field: "serial",
-		Value: serial,
-	}})
-	if err != nil || len(entities) == 0 {
-		if err != nil {
-			log.Errf("error getting node approval: %v", err)
-		}
-		return nil, status.Errorf(codes.Unauthenticated, "node %s not approved", serial)
-	}
-	node := entities[0].(*cce.Node)
-
+// issueCredentials signs the CSR for a node, stores the certificate and
+// returns it with the CA chain.
+func (s *Server) issueCredentials(
+	ctx context.Context,
+	nodeID string,
+	certReq *x509.CertificateRequest,
+) (*authpb.Credentials, error) {
 	// Sign cert request
 	cert, err := s.controller.AuthorityService.SignCSR(
 		certReq.Raw,
 		&x509.Certificate{
-			Subject: pkix.Name{CommonName: node.ID},
+			Subject: pkix.Name{CommonName: nodeID},
 		})
 	if err != nil {
 		log.Errf("Failed to sign CSR: %v", err)
@@ -222,45 +453,95 @@ func (s *Server) RequestCredentials(ctx context.Context, id *authpb.Identity) (
 	// Add the root CA to the Node's CA pool
 	caPoolPEM := chainPEM[len(chainPEM)-1:]
 
-	// Store Node credentials
-	creds := &cce.Credentials{
-		ID:          node.ID,
-		Certificate: string(certPEM),
-	}
-	if err = s.controller.PersistenceService.Create(ctx, creds); err != nil {
+	// Store Node credentials, keeping the certificates revoked before
+	creds, err := s.storeCredentials(ctx, nodeID, string(certPEM))
+	if err != nil {
 		log.Errf("Failed to store Node credentials: %v", err)
 		return nil, status.Error(codes.Internal, "unable to store credentials")
 	}
 
-	// Get the Node's IP address
-	p, ok := peer.FromContext(ctx)
-	if !ok {
-		return nil, status.Error(codes.Internal, "missing peer data from context")
+	return &authpb.Credentials{
+		Certificate: creds.Certificate,
+		CaChain:     chainPEM,
+		CaPool:      caPoolPEM,
+	}, nil
+}
+
+// storeCredentials stores the certificate issued to a node, replacing the
+// certificate issued before.
+func (s *Server) storeCredentials(ctx context.Context, nodeID, certPEM string) (*cce.Credentials, error) {
+	persisted, err := s.controller.PersistenceService.Read(ctx, nodeID, &cce.Credentials{})
+	if err != nil {
+		return nil, err
 	}
-	nodeIP, _, err := net.SplitHostPort(p.Addr.String())
-	if nodeIP == "" || err != nil {
-		return nil, status.Errorf(codes.Internal, "bad remote address in peer data: %s: %v",
-			p.Addr.String(), err)
+	if persisted == nil {
+		creds := &cce.Credentials{
+			ID:          nodeID,
+			Certificate: certPEM,
+		}
+		return creds, s.controller.PersistenceService.Create(ctx, creds)
 	}
 
-	// Store the Node's address
-	nodeWithTarget := &cce.NodeGRPCTarget{
-		ID:         uuid.New(),
-		NodeID:     node.ID,
-		GRPCTarget: nodeIP,
+	creds := persisted.(*cce.Credentials)
+	creds.Certificate = certPEM
+	return creds, s.controller.PersistenceService.BulkUpdate(ctx, []cce.Persistable{creds})
+}
+
+// storeGRPCTarget stores the address of a node. A node has a single address,
+// so the address stored when the node enrolled before is updated and kept in
+// its history. An address stored for another node is stale, as it was
+// reassigned to this node, and is freed.
+func (s *Server) storeGRPCTarget(ctx context.Context, nodeID, target string) error {
+	ps := s.controller.PersistenceService
+
+	stale, err := ps.Filter(ctx, &cce.NodeGRPCTarget{}, []cce.Filter{{
+		Field: "grpc_target",
+		Value: target,
+	}})
+	if err != nil {
+		return err
 	}
-	if err := s.controller.PersistenceService.Create(ctx, nodeWithTarget); err != nil {
-		log.Errf("Failed to store Node address: %v", err)
-		return nil, status.Error(codes.Internal, "unable to store node address")
+	for _, p := range stale {
+		staleTarget := p.(*cce.NodeGRPCTarget)
+		if staleTarget.NodeID == nodeID {
+			continue
+		}
+		if _, err = ps.Delete(ctx, staleTarget.ID, staleTarget); err != nil {
+			return err
+		}
+		log.Noticef("Node %s took over address %s of node %s", nodeID, target, staleTarget.NodeID)
+		if s.controller.NodeConns != nil {
+			s.controller.NodeConns.Invalidate(staleTarget.NodeID)
+		}
 	}
-	// Also let the proxy node we have a new client
-	cce.RegisterToProxy(ctx, s.controller.PersistenceService, node.ID)
 
-	return &authpb.Credentials{
-		Certificate: creds.Certificate,
-		CaChain:     chainPEM,
-		CaPool:      caPoolPEM,
-	}, nil
+	persisted, err := ps.Filter(ctx, &cce.NodeGRPCTarget{}, []cce.Filter{{
+		Field: "node_id",
+		Value: nodeID,
+	}})
+	if err != nil {
+		return err
+	}
+	if len(persisted) == 0 {
+		return ps.Create(ctx, &cce.NodeGRPCTarget{
+			ID:         uuid.New(),
+			NodeID:     nodeID,
+			GRPCTarget: target,
+		})
+	}
+
+	nodeWithTarget := persisted[0].(*cce.NodeGRPCTarget)
+	previous := nodeWithTarget.GRPCTarget
+	if !nodeWithTarget.Move(target, time.Now().UTC()) {
+		return nil
+	}
+	if err = ps.BulkUpdate(ctx, []cce.Persistable{nodeWithTarget}); err != nil {
+		return err
+	}
+	log.Infof("Node %s moved from address %s to %s", nodeID, previous, target)
+
+	return cce.RaiseEvent(ctx, ps, nodeID, cce.EventNodeAddressChanged,
+		fmt.Sprintf("address changed from %s to %s", previous, target))
 }
 
 // GetContainerByIP retrieves info of deployed application with IP provided
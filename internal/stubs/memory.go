@@ -0,0 +1,162 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package stubs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+
+	cce "github.com/open-ness/edgecontroller"
+)
+
+// MemoryPersistenceService is an in-memory cce.PersistenceService. Entities
+// are stored as JSON per table and filters are matched against the top-level
+// JSON fields, mirroring the generated columns of the MySQL schema.
+type MemoryPersistenceService struct {
+	mu     sync.Mutex
+	tables map[string]map[string][]byte
+}
+
+// NewMemoryPersistenceService creates an empty MemoryPersistenceService.
+func NewMemoryPersistenceService() *MemoryPersistenceService {
+	return &MemoryPersistenceService{tables: make(map[string]map[string][]byte)}
+}
+
+// Create persists a resource.
+func (ps *MemoryPersistenceService) Create(ctx context.Context, e cce.Persistable) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	t := ps.table(e.GetTableName())
+	if _, ok := t[e.GetID()]; ok {
+		return fmt.Errorf("duplicate entry %s in %s", e.GetID(), e.GetTableName())
+	}
+	bytes, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	t[e.GetID()] = bytes
+	return nil
+}
+
+// Read retrieves a single resource of the given type by ID.
+func (ps *MemoryPersistenceService) Read(
+	ctx context.Context,
+	id string,
+	zv cce.Persistable,
+) (cce.Persistable, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	bytes, ok := ps.table(zv.GetTableName())[id]
+	if !ok {
+		return nil, nil
+	}
+	return decode(bytes, zv)
+}
+
+// ReadAll retrieves all resources of the given type ordered by ID.
+func (ps *MemoryPersistenceService) ReadAll(ctx context.Context, zv cce.Persistable) ([]cce.Persistable, error) {
+	return ps.filter(zv, nil)
+}
+
+// Filter retrieves the resources of the given type matching all filters.
+func (ps *MemoryPersistenceService) Filter(
+	ctx context.Context,
+	zv cce.Filterable,
+	fs []cce.Filter,
+) ([]cce.Persistable, error) {
+	return ps.filter(zv, fs)
+}
+
+func (ps *MemoryPersistenceService) filter(zv cce.Persistable, fs []cce.Filter) ([]cce.Persistable, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	t := ps.table(zv.GetTableName())
+	ids := make([]string, 0, len(t))
+	for id := range t {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var es []cce.Persistable
+	for _, id := range ids {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(t[id], &fields); err != nil {
+			return nil, err
+		}
+		matched := true
+		for _, f := range fs {
+			v, ok := fields[f.Field]
+			if !ok || v == nil || fmt.Sprint(v) != f.Value {
+				matched = false
+				break
+			}
+		}
+		if !matched {
+			continue
+		}
+		e, err := decode(t[id], zv)
+		if err != nil {
+			return nil, err
+		}
+		es = append(es, e)
+	}
+	return es, nil
+}
+
+// BulkUpdate updates multiple resources. Resources that do not exist are
+// ignored, as with an UPDATE statement.
+func (ps *MemoryPersistenceService) BulkUpdate(ctx context.Context, es []cce.Persistable) error {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	for _, e := range es {
+		t := ps.table(e.GetTableName())
+		if _, ok := t[e.GetID()]; !ok {
+			continue
+		}
+		bytes, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		t[e.GetID()] = bytes
+	}
+	return nil
+}
+
+// Delete deletes a resource of the given type.
+func (ps *MemoryPersistenceService) Delete(ctx context.Context, id string, zv cce.Persistable) (bool, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	t := ps.table(zv.GetTableName())
+	if _, ok := t[id]; !ok {
+		return false, nil
+	}
+	delete(t, id)
+	return true, nil
+}
+
+func (ps *MemoryPersistenceService) table(name string) map[string][]byte {
+	t, ok := ps.tables[name]
+	if !ok {
+		t = make(map[string][]byte)
+		ps.tables[name] = t
+	}
+	return t
+}
+
+func decode(bytes []byte, zv cce.Persistable) (cce.Persistable, error) {
+	e := reflect.New(reflect.ValueOf(zv).Elem().Type()).Interface().(cce.Persistable)
+	if err := json.Unmarshal(bytes, e); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
@@ -0,0 +1,231 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	logger "github.com/open-ness/common/log"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/grpc/node"
+	nfd "github.com/open-ness/edgecontroller/nfd-master"
+	"github.com/open-ness/edgecontroller/uuid"
+	"github.com/pkg/errors"
+)
+
+var log = logger.DefaultLogger.WithField("pkg", "inventory")
+
+const (
+	// DefaultInterval is the default time between two collections.
+	DefaultInterval = 5 * time.Minute
+	// DefaultTimeout is the default time a node has to report its
+	// interfaces.
+	DefaultTimeout = 10 * time.Second
+)
+
+// Collector periodically collects the inventory of every enrolled node: the
+// network interfaces reported by the node, its NFD labels and the apps
+// deployed to it. A change of the interfaces is kept in the inventory history
+// and raises an event.
+type Collector struct {
+	Controller *cce.Controller
+
+	// Interval is the time between two collections. If it is zero,
+	// DefaultInterval is used.
+	Interval time.Duration
+
+	// Timeout bounds fetching the interfaces of a node. If it is zero,
+	// DefaultTimeout is used.
+	Timeout time.Duration
+
+	// FetchInterfaces gets the network interfaces of the node from its ELA
+	// listening on port. If it is nil, they are fetched over gRPC. This field
+	// is intended for use mocking the node.
+	FetchInterfaces func(ctx context.Context, nodeID, addr, port string) ([]*cce.NetworkInterface, error)
+}
+
+// Run collects the inventory of all nodes every Interval until the context is
+// canceled.
+func (c *Collector) Run(ctx context.Context) error {
+	return c.poller().Run(ctx)
+}
+
+// CollectAll collects the inventory of all enrolled nodes concurrently.
+func (c *Collector) CollectAll(ctx context.Context) {
+	c.poller().PollAll(ctx)
+}
+
+// CollectNode collects the inventory of a single node and returns it. A nil
+// inventory is returned if the node has not enrolled yet.
+func (c *Collector) CollectNode(ctx context.Context, nodeID string) (*cce.NodeInventory, error) {
+	target, err := c.poller().Target(ctx, nodeID)
+	if err != nil || target == nil {
+		return nil, err
+	}
+	return c.collectTarget(ctx, target)
+}
+
+func (c *Collector) poller() *node.Poller {
+	p := &node.Poller{
+		Controller: c.Controller,
+		Interval:   c.Interval,
+		Timeout:    c.Timeout,
+		Poll: func(ctx context.Context, target *cce.NodeGRPCTarget) error {
+			_, err := c.collectTarget(ctx, target)
+			return err
+		},
+	}
+	if p.Interval == 0 {
+		p.Interval = DefaultInterval
+	}
+	if p.Timeout == 0 {
+		p.Timeout = DefaultTimeout
+	}
+	return p
+}
+
+func (c *Collector) collectTarget(ctx context.Context, target *cce.NodeGRPCTarget) (*cce.NodeInventory, error) {
+	ps := c.Controller.PersistenceService
+	poller := c.poller()
+
+	fetchCtx, cancel := context.WithTimeout(ctx, poller.Timeout)
+	ifaces, err := c.fetchInterfaces(fetchCtx, poller, target.NodeID, target.GRPCTarget, poller.ELAPort())
+	cancel()
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch interfaces")
+	}
+
+	features, err := getFeatures(ctx, ps, target.NodeID)
+	if err != nil {
+		return nil, err
+	}
+	apps, err := getApps(ctx, ps, target.NodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	prev, err := Get(ctx, ps, target.NodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	inv := &cce.NodeInventory{
+		ID:     uuid.New(),
+		NodeID: target.NodeID,
+	}
+	if prev != nil {
+		inv = prev
+	}
+	inv.CollectedAt = time.Now().UTC()
+	inv.Features = features
+	inv.Apps = apps
+
+	// The interfaces of the first collection are not a change
+	var change *cce.InterfaceChange
+	if prev == nil {
+		inv.Interfaces = ifaces
+	} else {
+		change = inv.SetInterfaces(ifaces, inv.CollectedAt)
+	}
+
+	if prev == nil {
+		err = ps.Create(ctx, inv)
+	} else {
+		err = ps.BulkUpdate(ctx, []cce.Persistable{inv})
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not store node inventory")
+	}
+
+	if change != nil {
+		msg := fmt.Sprintf("interfaces changed: %d added, %d removed, %d changed",
+			len(change.Added), len(change.Removed), len(change.Changed))
+		log.Infof("Node %s: %s", inv.NodeID, msg)
+		if err := cce.RaiseEvent(ctx, ps, inv.NodeID, cce.EventNodeInterfacesChanged, msg); err != nil {
+			log.Errf("Error raising event for node %s: %v", inv.NodeID, err)
+		}
+	}
+
+	return inv, nil
+}
+
+// Get returns the last collected inventory of a node or nil if it has never
+// been collected.
+func Get(ctx context.Context, ps cce.PersistenceService, nodeID string) (*cce.NodeInventory, error) {
+	inventories, err := ps.Filter(ctx, &cce.NodeInventory{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch node inventory from DB")
+	}
+	if len(inventories) == 0 {
+		return nil, nil
+	}
+	return inventories[0].(*cce.NodeInventory), nil
+}
+
+func getFeatures(ctx context.Context, ps cce.PersistenceService, nodeID string) (map[string]string, error) {
+	persisted, err := ps.Filter(ctx, &nfd.NodeFeatureNFD{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch NFD features from DB")
+	}
+
+	features := make(map[string]string)
+	for _, f := range persisted {
+		feature := f.(*nfd.NodeFeatureNFD)
+		features[feature.NfdID] = feature.NfdValue
+	}
+	return features, nil
+}
+
+func getApps(ctx context.Context, ps cce.PersistenceService, nodeID string) ([]string, error) {
+	persisted, err := ps.Filter(ctx, &cce.NodeApp{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch node apps from DB")
+	}
+
+	apps := []string{}
+	for _, a := range persisted {
+		apps = append(apps, a.(*cce.NodeApp).AppID)
+	}
+	sort.Strings(apps)
+	return apps, nil
+}
+
+func (c *Collector) fetchInterfaces(
+	ctx context.Context,
+	poller *node.Poller,
+	nodeID, addr, port string,
+) ([]*cce.NetworkInterface, error) {
+	if c.FetchInterfaces != nil {
+		return c.FetchInterfaces(ctx, nodeID, addr, port)
+	}
+
+	nodeCC, err := poller.Connect(ctx, nodeID, addr, port)
+	if err != nil {
+		return nil, err
+	}
+	defer nodeCC.Disconnect()
+
+	return nodeCC.IfaceSvcCli.GetAll(ctx)
+}
@@ -0,0 +1,180 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package inventory_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/internal/stubs"
+	"github.com/open-ness/edgecontroller/inventory"
+	nfd "github.com/open-ness/edgecontroller/nfd-master"
+)
+
+const nodeID = "48606c73-3905-47e0-864f-14bc7466f5bb"
+
+var _ = Describe("Collector", func() {
+	var (
+		ps        *stubs.MemoryPersistenceService
+		collector *inventory.Collector
+
+		mu       sync.Mutex
+		ifaces   []*cce.NetworkInterface
+		fetchErr error
+		fetches  int
+	)
+
+	setInterfaces := func(is []*cce.NetworkInterface, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		ifaces, fetchErr = is, err
+	}
+
+	events := func() []*cce.Event {
+		persisted, err := ps.ReadAll(context.TODO(), &cce.Event{})
+		Expect(err).ToNot(HaveOccurred())
+		var es []*cce.Event
+		for _, e := range persisted {
+			es = append(es, e.(*cce.Event))
+		}
+		return es
+	}
+
+	BeforeEach(func() {
+		ps = stubs.NewMemoryPersistenceService()
+		fetches = 0
+		setInterfaces([]*cce.NetworkInterface{
+			{ID: "if0", Driver: "kernel", MACAddress: "mac0"},
+			{ID: "if1", Driver: "kernel", MACAddress: "mac1"},
+		}, nil)
+		collector = &inventory.Collector{
+			Controller: &cce.Controller{
+				PersistenceService: ps,
+				ELAPort:            "42101",
+			},
+			FetchInterfaces: func(ctx context.Context, id, addr, port string) ([]*cce.NetworkInterface, error) {
+				Expect(id).To(Equal(nodeID))
+				Expect(addr).To(Equal("127.0.0.1"))
+				Expect(port).To(Equal("42101"))
+				mu.Lock()
+				defer mu.Unlock()
+				fetches++
+				return ifaces, fetchErr
+			},
+		}
+
+		Expect(ps.Create(context.TODO(), &cce.NodeGRPCTarget{
+			ID:         "ca0fa495-1020-405b-a78c-9a1884349078",
+			NodeID:     nodeID,
+			GRPCTarget: "127.0.0.1",
+		})).To(Succeed())
+	})
+
+	Describe("CollectNode", func() {
+		It("Should return nil for a node that has not enrolled", func() {
+			inv, err := collector.CollectNode(context.TODO(), "a0e0ec5f-6d2a-4bb0-a0b5-3a2b0dc5b0e4")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inv).To(BeNil())
+		})
+
+		It("Should collect interfaces, NFD labels and apps", func() {
+			Expect(ps.Create(context.TODO(), &nfd.NodeFeatureNFD{
+				ID:       "5ae1ba08-a1a2-4ab8-9d47-bd1f5e9e0e5c",
+				NodeID:   nodeID,
+				NfdID:    "cpu-cpuid.AVX512F",
+				NfdValue: "true",
+			})).To(Succeed())
+			Expect(ps.Create(context.TODO(), &cce.NodeApp{
+				ID:     "0ee4d1a4-4f56-4ac4-8ba4-7e2bb73b3b9b",
+				NodeID: nodeID,
+				AppID:  "b4d2a7fd-b41c-4e25-9b8c-8ca8d2e0d8c6",
+			})).To(Succeed())
+
+			inv, err := collector.CollectNode(context.TODO(), nodeID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inv.NodeID).To(Equal(nodeID))
+			Expect(inv.CollectedAt).ToNot(BeZero())
+			Expect(inv.Interfaces).To(HaveLen(2))
+			Expect(inv.Features).To(Equal(map[string]string{"cpu-cpuid.AVX512F": "true"}))
+			Expect(inv.Apps).To(Equal([]string{"b4d2a7fd-b41c-4e25-9b8c-8ca8d2e0d8c6"}))
+			Expect(inv.History).To(BeEmpty())
+
+			By("Verifying the inventory was stored")
+			stored, err := inventory.Get(context.TODO(), ps, nodeID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.ID).To(Equal(inv.ID))
+			Expect(stored.Interfaces).To(HaveLen(2))
+			Expect(events()).To(BeEmpty())
+		})
+
+		It("Should record interface changes in the history", func() {
+			first, err := collector.CollectNode(context.TODO(), nodeID)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Collecting the same interfaces")
+			inv, err := collector.CollectNode(context.TODO(), nodeID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inv.ID).To(Equal(first.ID))
+			Expect(inv.History).To(BeEmpty())
+
+			By("Collecting changed interfaces")
+			setInterfaces([]*cce.NetworkInterface{
+				{ID: "if0", Driver: "userspace", MACAddress: "mac0"},
+				{ID: "if2", Driver: "kernel", MACAddress: "mac2"},
+			}, nil)
+			inv, err = collector.CollectNode(context.TODO(), nodeID)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(inv.History).To(HaveLen(1))
+			change := inv.History[0]
+			Expect(change.At).To(Equal(inv.CollectedAt))
+			Expect(change.Added).To(ConsistOf(&cce.NetworkInterface{ID: "if2", Driver: "kernel", MACAddress: "mac2"}))
+			Expect(change.Removed).To(ConsistOf(&cce.NetworkInterface{ID: "if1", Driver: "kernel", MACAddress: "mac1"}))
+			Expect(change.Changed).To(ConsistOf(&cce.NetworkInterface{ID: "if0", Driver: "userspace", MACAddress: "mac0"}))
+
+			By("Verifying an event was raised")
+			es := events()
+			Expect(es).To(HaveLen(1))
+			Expect(es[0].Type).To(Equal(cce.EventNodeInterfacesChanged))
+			Expect(es[0].NodeID).To(Equal(nodeID))
+		})
+
+		It("Should keep the cached inventory if the node cannot be reached", func() {
+			first, err := collector.CollectNode(context.TODO(), nodeID)
+			Expect(err).ToNot(HaveOccurred())
+
+			setInterfaces(nil, errors.New("connection refused"))
+			_, err = collector.CollectNode(context.TODO(), nodeID)
+			Expect(err).To(HaveOccurred())
+
+			stored, err := inventory.Get(context.TODO(), ps, nodeID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.CollectedAt).To(Equal(first.CollectedAt))
+			Expect(stored.Interfaces).To(HaveLen(2))
+		})
+	})
+
+	Describe("CollectAll", func() {
+		It("Should collect the inventory of every enrolled node", func() {
+			collector.CollectAll(context.TODO())
+			Expect(fetches).To(Equal(1))
+
+			inv, err := inventory.Get(context.TODO(), ps, nodeID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inv).ToNot(BeNil())
+		})
+	})
+
+	Describe("Get", func() {
+		It("Should return nil if the inventory was never collected", func() {
+			inv, err := inventory.Get(context.TODO(), ps, nodeID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(inv).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,221 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	appsV1 "k8s.io/api/apps/v1"
+	apiV1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/tools/cache"
+)
+
+// DefaultCacheSyncTimeout is the default time to wait for the caches of the
+// client to sync when connecting.
+const DefaultCacheSyncTimeout = 30 * time.Second
+
+// Size of the buffer of the event stream
+const eventBufferSize = 100
+
+// AppEvent is a change of the status of the pods of an app deployed to a
+// node. The status is unknown once the app has no pods nor deployment left.
+type AppEvent struct {
+	NodeID string
+	AppID  string
+	Status LifecycleStatus
+	Ready  bool
+}
+
+// appSelector selects the objects of an app deployed to a node.
+func appSelector(nodeID, appID string) labels.Selector {
+	return labels.SelectorFromSet(labels.Set{
+		appIDLabelKey:  appID,
+		nodeIDLabelKey: nodeID,
+	})
+}
+
+// Events returns the stream of changes of the status of apps, sent once the
+// client is connected. Events are dropped when the stream is not consumed fast
+// enough.
+func (ks *Client) Events() <-chan AppEvent {
+	// A failure to connect is returned by the next request and retried then
+	_ = ks.connect()
+
+	ks.eventsMu.Lock()
+	defer ks.eventsMu.Unlock()
+	if ks.events == nil {
+		ks.events = make(chan AppEvent, eventBufferSize)
+	}
+	return ks.events
+}
+
+// Close stops the informers of the client and drops their caches. The next
+// request connects again.
+func (ks *Client) Close() {
+	ks.connectMu.Lock()
+	defer ks.connectMu.Unlock()
+	if ks.stop != nil {
+		close(ks.stop)
+		ks.stop = nil
+	}
+	ks.connected = false
+	ks.deployments = nil
+	ks.pods = nil
+	ks.networkPolicies = nil
+	ks.services = nil
+	ks.nodes = nil
+}
+
+// connect connects to the Kubernetes server unless already done. A failure to
+// connect is retried on the next call.
+func (ks *Client) connect() error {
+	ks.connectMu.Lock()
+	defer ks.connectMu.Unlock()
+	if ks.connected {
+		return nil
+	}
+	if err := ks.init(); err != nil {
+		return err
+	}
+	ks.connected = true
+	return nil
+}
+
+// startInformers starts the shared informers caching the deployments, pods,
+// network policies and services of apps, i.e. labeled with a node and app ID,
+// and the nodes labeled with a node ID, and waits for them to sync.
+func (ks *Client) startInformers() error {
+	factory := informers.NewSharedInformerFactoryWithOptions(ks.clientSet, 0,
+		informers.WithTweakListOptions(func(opts *metaV1.ListOptions) {
+			opts.LabelSelector = nodeIDLabelKey + "," + appIDLabelKey
+		}))
+	ks.deployments = factory.Apps().V1().Deployments().Lister()
+	ks.pods = factory.Core().V1().Pods().Lister()
+	ks.networkPolicies = factory.Networking().V1().NetworkPolicies().Lister()
+	ks.services = factory.Core().V1().Services().Lister()
+
+	nodeFactory := informers.NewSharedInformerFactoryWithOptions(ks.clientSet, 0,
+		informers.WithTweakListOptions(func(opts *metaV1.ListOptions) {
+			opts.LabelSelector = nodeIDLabelKey
+		}))
+	ks.nodes = nodeFactory.Core().V1().Nodes().Lister()
+
+	ks.eventsMu.Lock()
+	if ks.events == nil {
+		ks.events = make(chan AppEvent, eventBufferSize)
+	}
+	ks.lastEvents = make(map[string]AppEvent)
+	ks.eventsMu.Unlock()
+	factory.Core().V1().Pods().Informer().AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    ks.onPodChange,
+		UpdateFunc: func(_, obj interface{}) { ks.onPodChange(obj) },
+		DeleteFunc: ks.onPodChange,
+	})
+
+	ks.stop = make(chan struct{})
+	factory.Start(ks.stop)
+	nodeFactory.Start(ks.stop)
+
+	timeout := ks.CacheSyncTimeout
+	if timeout == 0 {
+		timeout = DefaultCacheSyncTimeout
+	}
+	expired := make(chan struct{})
+	timer := time.AfterFunc(timeout, func() { close(expired) })
+	defer timer.Stop()
+	for _, f := range []informers.SharedInformerFactory{factory, nodeFactory} {
+		for typ, synced := range f.WaitForCacheSync(expired) {
+			if !synced {
+				close(ks.stop)
+				ks.stop = nil
+				return errors.Errorf("timed out syncing the cache of %v", typ)
+			}
+		}
+	}
+	return nil
+}
+
+// onPodChange sends an event if the status of the app of a pod changed.
+func (ks *Client) onPodChange(obj interface{}) {
+	pod, ok := obj.(*apiV1.Pod)
+	if !ok {
+		tombstone, ok := obj.(cache.DeletedFinalStateUnknown)
+		if !ok {
+			return
+		}
+		if pod, ok = tombstone.Obj.(*apiV1.Pod); !ok {
+			return
+		}
+	}
+
+	event := AppEvent{
+		NodeID: pod.Labels[nodeIDLabelKey],
+		AppID:  pod.Labels[appIDLabelKey],
+		Status: Unknown,
+	}
+	pods, err := ks.pods.List(appSelector(event.NodeID, event.AppID))
+	if err != nil {
+		return
+	}
+	deployments, err := ks.deployments.List(appSelector(event.NodeID, event.AppID))
+	if err != nil {
+		return
+	}
+	switch {
+	case len(pods) > 0:
+		event.Status = getPodsStatus(pods)
+		event.Ready = arePodsReady(pods)
+	case len(deployments) > 0:
+		event.Status = Deployed
+	}
+
+	ks.eventsMu.Lock()
+	defer ks.eventsMu.Unlock()
+	key := event.NodeID + "/" + event.AppID
+	if last, ok := ks.lastEvents[key]; ok && last == event {
+		return
+	}
+	if event.Status == Unknown {
+		delete(ks.lastEvents, key)
+	} else {
+		ks.lastEvents[key] = event
+	}
+	select {
+	case ks.events <- event:
+	default:
+	}
+}
+
+// getCachedDeployment gets the deployment of an app from the cache, or nil if
+// it is not cached.
+func (ks *Client) getCachedDeployment(nodeID, appID string) (*appsV1.Deployment, error) {
+	deps, err := ks.deployments.List(appSelector(nodeID, appID))
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting list of deployments")
+	}
+	if len(deps) == 0 {
+		return nil, nil
+	}
+	if len(deps) > 1 {
+		return nil, errors.New("more than one deployment found")
+	}
+
+	// Objects of the cache must not be modified
+	return deps[0].DeepCopy(), nil
+}
+
+// getCachedNetworkPolicy gets the network policy of an app from the cache, or
+// nil if it is not cached.
+func (ks *Client) getCachedNetworkPolicy(nodeID, appID string) (*networkingV1.NetworkPolicy, error) {
+	netpols, err := ks.networkPolicies.List(appSelector(nodeID, appID))
+	if err != nil || len(netpols) == 0 {
+		return nil, err
+	}
+	return netpols[0].DeepCopy(), nil
+}
@@ -0,0 +1,167 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/open-ness/edgecontroller/k8s"
+	appsV1 "k8s.io/api/apps/v1"
+	apiV1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8sTesting "k8s.io/client-go/testing"
+)
+
+var _ = Describe("K8S cache", func() {
+	var (
+		clientSet *fake.Clientset
+		client    *k8s.Client
+		pod       *apiV1.Pod
+
+		labels = map[string]string{
+			"app-id":  appID,
+			"node-id": nodeID,
+		}
+	)
+
+	BeforeEach(func() {
+		pod = &apiV1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{Name: "app-1", Namespace: "tenant-a", Labels: labels},
+			Status: apiV1.PodStatus{
+				Phase: apiV1.PodRunning,
+				PodIP: "10.16.0.10",
+				Conditions: []apiV1.PodCondition{
+					{Type: apiV1.PodReady, Status: apiV1.ConditionTrue},
+				},
+			},
+		}
+		clientSet = fake.NewSimpleClientset(
+			&appsV1.Deployment{
+				ObjectMeta: metaV1.ObjectMeta{Name: "app", Namespace: "tenant-a", Labels: labels},
+			},
+			pod,
+		)
+		client = &k8s.Client{
+			NewClientSet: func() (kubernetes.Interface, error) { return clientSet, nil },
+		}
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	It("Should answer status queries and IP lookups from the cache", func() {
+		ctx := context.Background()
+
+		Expect(client.Status(ctx, nodeID, appID)).To(Equal(k8s.Running))
+		Expect(client.Ready(ctx, nodeID, appID)).To(BeTrue())
+		Expect(client.GetAppIDByIP(ctx, nodeID, "10.16.0.10")).To(Equal(appID))
+
+		By("Failing to find an unknown pod")
+		_, err := client.GetAppIDByIP(ctx, nodeID, "10.16.0.11")
+		Expect(err).To(MatchError("no pod found with IP '10.16.0.11'"))
+	})
+
+	It("Should look up objects yet to be cached", func() {
+		ctx := context.Background()
+		Expect(client.Status(ctx, nodeID, appID)).To(Equal(k8s.Running))
+
+		otherAppID := "5c6e2d1a-7f4b-4e3a-9b8c-1d2e3f4a5b6c"
+		_, err := clientSet.AppsV1().Deployments("tenant-b").Create(&appsV1.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      "other-app",
+				Namespace: "tenant-b",
+				Labels:    map[string]string{"app-id": otherAppID, "node-id": nodeID},
+			},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.Status(ctx, nodeID, otherAppID)).To(Equal(k8s.Deployed))
+
+		_, err = client.GetNetworkPolicy(ctx, nodeID, appID)
+		Expect(apiErrors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("Should stream the changes of the status of apps", func() {
+		events := client.Events()
+		Eventually(events).Should(Receive(Equal(k8s.AppEvent{
+			NodeID: nodeID,
+			AppID:  appID,
+			Status: k8s.Running,
+			Ready:  true,
+		})))
+
+		By("Updating the pod to not ready")
+		pod.Status.Conditions[0].Status = apiV1.ConditionFalse
+		_, err := clientSet.CoreV1().Pods("tenant-a").Update(pod)
+		Expect(err).ToNot(HaveOccurred())
+		Eventually(events).Should(Receive(Equal(k8s.AppEvent{
+			NodeID: nodeID,
+			AppID:  appID,
+			Status: k8s.Running,
+		})))
+
+		By("Deleting the pod")
+		Expect(clientSet.CoreV1().Pods("tenant-a").Delete("app-1", &metaV1.DeleteOptions{})).To(Succeed())
+		Eventually(events).Should(Receive(Equal(k8s.AppEvent{
+			NodeID: nodeID,
+			AppID:  appID,
+			Status: k8s.Deployed,
+		})))
+	})
+
+	It("Should retry connecting after a failure", func() {
+		attempts := 0
+		client.NewClientSet = func() (kubernetes.Interface, error) {
+			attempts++
+			if attempts == 1 {
+				return nil, errors.New("connection refused")
+			}
+			return clientSet, nil
+		}
+
+		ctx := context.Background()
+		_, err := client.Status(ctx, nodeID, appID)
+		Expect(err).To(MatchError("connection refused"))
+		Expect(client.Status(ctx, nodeID, appID)).To(Equal(k8s.Running))
+	})
+
+	It("Should reconnect after being closed", func() {
+		ctx := context.Background()
+		connects := 0
+		client.NewClientSet = func() (kubernetes.Interface, error) {
+			connects++
+			return clientSet, nil
+		}
+		Expect(client.Status(ctx, nodeID, appID)).To(Equal(k8s.Running))
+		client.Close()
+
+		By("Deleting the pod once the cache is stopped")
+		Expect(clientSet.CoreV1().Pods("tenant-a").Delete("app-1", &metaV1.DeleteOptions{})).To(Succeed())
+		Expect(client.Status(ctx, nodeID, appID)).To(Equal(k8s.Deployed))
+		Expect(connects).To(Equal(2))
+	})
+
+	It("Should be closed once its cache failed to sync", func() {
+		listed := make(chan struct{})
+		defer close(listed)
+		clientSet.PrependReactor("list", "pods", func(k8sTesting.Action) (bool, runtime.Object, error) {
+			<-listed
+			return false, nil, nil
+		})
+		client.CacheSyncTimeout = 100 * time.Millisecond
+
+		_, err := client.Status(context.Background(), nodeID, appID)
+		Expect(err).To(HaveOccurred())
+		Expect(client.Close).ToNot(Panic())
+	})
+})
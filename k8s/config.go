@@ -0,0 +1,192 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	apiV1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Name of the volume of the ConfigMap holding the config files of an app
+const configVolumeName = "config"
+
+// EnvVar is an environment variable set in the container.
+type EnvVar struct {
+	Name  string
+	Value string
+}
+
+// ConfigFile is a file mounted read-only in the container at Path. The config
+// files of an app are stored in a ConfigMap.
+type ConfigFile struct {
+	Path    string
+	Content string
+}
+
+// SecretRef exposes the Key of the Kubernetes secret Name to the container
+// either as the environment variable Env or as a file mounted at Path.
+type SecretRef struct {
+	Name string
+	Key  string
+	Env  string
+	Path string
+}
+
+// configMapName is the name of the ConfigMap of an app deployed to a node.
+func configMapName(nodeID, appID string) string {
+	return fmt.Sprintf("cfg-%s.%s", nodeID, appID)
+}
+
+// configFileKey is the key of the i-th config file in the ConfigMap.
+func configFileKey(i int) string {
+	return fmt.Sprintf("file-%d", i)
+}
+
+// configure sets the runtime configuration of app on the container and the
+// volumes it mounts on the pod. The pod has no other volumes.
+func configure(container *apiV1.Container, pod *apiV1.PodSpec, nodeID, appID string, app App) {
+	container.Command = app.Command
+	container.Args = app.Args
+	container.Env = nil
+	container.VolumeMounts = nil
+	pod.Volumes = nil
+
+	for _, env := range app.Env {
+		container.Env = append(container.Env, apiV1.EnvVar{Name: env.Name, Value: env.Value})
+	}
+
+	if len(app.ConfigFiles) > 0 {
+		pod.Volumes = append(pod.Volumes, apiV1.Volume{
+			Name: configVolumeName,
+			VolumeSource: apiV1.VolumeSource{
+				ConfigMap: &apiV1.ConfigMapVolumeSource{
+					LocalObjectReference: apiV1.LocalObjectReference{Name: configMapName(nodeID, appID)},
+				},
+			},
+		})
+	}
+	for i, file := range app.ConfigFiles {
+		container.VolumeMounts = append(container.VolumeMounts, apiV1.VolumeMount{
+			Name:      configVolumeName,
+			MountPath: file.Path,
+			SubPath:   configFileKey(i),
+			ReadOnly:  true,
+		})
+	}
+
+	// Each secret mounted as a file gets a volume, shared by its keys
+	secretVolumes := make(map[string]string)
+	for _, secret := range app.Secrets {
+		if secret.Env != "" {
+			container.Env = append(container.Env, apiV1.EnvVar{
+				Name: secret.Env,
+				ValueFrom: &apiV1.EnvVarSource{
+					SecretKeyRef: &apiV1.SecretKeySelector{
+						LocalObjectReference: apiV1.LocalObjectReference{Name: secret.Name},
+						Key:                  secret.Key,
+					},
+				},
+			})
+			continue
+		}
+
+		volume, ok := secretVolumes[secret.Name]
+		if !ok {
+			volume = fmt.Sprintf("secret-%d", len(secretVolumes))
+			secretVolumes[secret.Name] = volume
+			pod.Volumes = append(pod.Volumes, apiV1.Volume{
+				Name: volume,
+				VolumeSource: apiV1.VolumeSource{
+					Secret: &apiV1.SecretVolumeSource{SecretName: secret.Name},
+				},
+			})
+		}
+		container.VolumeMounts = append(container.VolumeMounts, apiV1.VolumeMount{
+			Name:      volume,
+			MountPath: secret.Path,
+			SubPath:   secret.Key,
+			ReadOnly:  true,
+		})
+	}
+}
+
+// applyConfigMap creates or updates the ConfigMap of the config files of app in
+// namespace, or deletes it if app has none.
+func (ks *Client) applyConfigMap(namespace, nodeID, appID string, app App) error {
+	configMapsClient := ks.clientSet.CoreV1().ConfigMaps(namespace)
+	name := configMapName(nodeID, appID)
+
+	if len(app.ConfigFiles) == 0 {
+		return ks.deleteConfigMap(namespace, nodeID, appID)
+	}
+
+	configMap := &apiV1.ConfigMap{
+		ObjectMeta: metaV1.ObjectMeta{
+			Name: name,
+			Labels: map[string]string{
+				appIDLabelKey:  appID,
+				nodeIDLabelKey: nodeID,
+			},
+		},
+		Data: make(map[string]string),
+	}
+	for i, file := range app.ConfigFiles {
+		configMap.Data[configFileKey(i)] = file.Content
+	}
+
+	_, err := configMapsClient.Update(configMap)
+	if apiErrors.IsNotFound(err) {
+		_, err = configMapsClient.Create(configMap)
+	}
+	return errors.Wrap(err, "error applying config map")
+}
+
+// deleteConfigMap deletes the ConfigMap of an app if there is one.
+func (ks *Client) deleteConfigMap(namespace, nodeID, appID string) error {
+	err := ks.clientSet.CoreV1().ConfigMaps(namespace).
+		Delete(configMapName(nodeID, appID), &metaV1.DeleteOptions{})
+	if apiErrors.IsNotFound(err) {
+		return nil
+	}
+	return errors.Wrap(err, "error deleting config map")
+}
+
+// Defaults of the period and failure threshold of a probe
+const (
+	defaultProbePeriodSeconds    = 10
+	defaultProbeFailureThreshold = 3
+)
+
+// setProbes sets the health probes of app on the container. The Kubernetes
+// API in use predates startup probes, so the liveness probe is rather delayed
+// for as long as the startup probe would let the app start.
+func setProbes(container *apiV1.Container, app App) error {
+	if app.RestartPolicy != "" && app.RestartPolicy != apiV1.RestartPolicyAlways {
+		return errors.Errorf("restart policy %s is not supported by deployments", app.RestartPolicy)
+	}
+
+	container.ReadinessProbe = app.ReadinessProbe
+	container.LivenessProbe = app.LivenessProbe
+	if app.LivenessProbe == nil || app.StartupProbe == nil {
+		return nil
+	}
+
+	period, threshold := app.StartupProbe.PeriodSeconds, app.StartupProbe.FailureThreshold
+	if period == 0 {
+		period = defaultProbePeriodSeconds
+	}
+	if threshold == 0 {
+		threshold = defaultProbeFailureThreshold
+	}
+	if startup := app.StartupProbe.InitialDelaySeconds + period*threshold; startup > app.LivenessProbe.InitialDelaySeconds {
+		liveness := *app.LivenessProbe
+		liveness.InitialDelaySeconds = startup
+		container.LivenessProbe = &liveness
+	}
+	return nil
+}
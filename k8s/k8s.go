@@ -6,8 +6,10 @@ package k8s
 import (
 	"context"
 	"fmt"
+	"net"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/open-ness/edgecontroller/uuid"
 	"github.com/pkg/errors"
@@ -15,9 +17,16 @@ import (
 	autoscalingV1 "k8s.io/api/autoscaling/v1"
 	apiV1 "k8s.io/api/core/v1"
 	networkingV1 "k8s.io/api/networking/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	appsListers "k8s.io/client-go/listers/apps/v1"
+	coreListers "k8s.io/client-go/listers/core/v1"
+	networkingListers "k8s.io/client-go/listers/networking/v1"
 	restClient "k8s.io/client-go/rest"
 )
 
@@ -29,6 +38,39 @@ type App struct {
 	Memory int // in MB
 	Image  string
 	Ports  []*PortProto
+
+	// Namespace of the tenant or project the app is deployed for, created on
+	// demand. If it is empty, the app is deployed to the default namespace.
+	Namespace string
+
+	// VM is set for apps run as KubeVirt virtual machines rather than
+	// deployments. The runtime configuration and health of the container do
+	// not apply to them.
+	VM *VM
+
+	// Runtime configuration of the container
+	Env         []EnvVar
+	Command     []string
+	Args        []string
+	ConfigFiles []ConfigFile
+	Secrets     []SecretRef
+
+	// Enhanced platform awareness of the container: the device-plugin
+	// resources and hugepages it requests besides its cores and memory,
+	// whether its cores are pinned and the Multus networks it is attached to.
+	Resources  apiV1.ResourceList
+	PinnedCPUs bool
+	Networks   []NetworkAttachment
+
+	// Service exposes the ports of the app at a stable address if it is set
+	Service *Service
+
+	// Health of the container. Deployments only support the Always restart
+	// policy.
+	LivenessProbe  *apiV1.Probe
+	ReadinessProbe *apiV1.Probe
+	StartupProbe   *apiV1.Probe
+	RestartPolicy  apiV1.RestartPolicy
 }
 
 // PortProto is a port and protocol tuple
@@ -71,58 +113,109 @@ type Client struct {
 	// mocking an external connection.
 	NewClientSet func() (kubernetes.Interface, error)
 
-	connectOnce sync.Once
-	clientSet   kubernetes.Interface
-	err         error
+	// NewDynamicClient creates a new dynamic client managing the KubeVirt
+	// virtual machines. If it is nil, a REST client with TLS will be used.
+	// This field is intended for use mocking an external connection.
+	NewDynamicClient func() (dynamic.Interface, error)
+
+	// ExternalIPRanges are the ranges the external IPs of the Services of
+	// apps must be in. If it is empty, Services cannot have external IPs.
+	ExternalIPRanges []*net.IPNet
+
+	// NamespaceQuota is the hard limits of the ResourceQuota of each tenant
+	// namespace. If it is empty, the namespaces have no quota.
+	NamespaceQuota apiV1.ResourceList
+
+	// NetworkNamespaces are the namespaces of the NetworkAttachmentDefinitions
+	// apps of any namespace can attach to. Apps can otherwise only attach to
+	// the ones of their own namespace.
+	NetworkNamespaces []string
+
+	// CacheSyncTimeout bounds the time to wait for the caches of the
+	// deployments, pods and network policies of apps to sync when connecting.
+	// If it is zero, DefaultCacheSyncTimeout is used.
+	CacheSyncTimeout time.Duration
+
+	connectMu     sync.Mutex
+	connected     bool
+	clientSet     kubernetes.Interface
+	dynamicClient dynamic.Interface
+
+	// Caches of the objects of apps and of the nodes, kept up to date by
+	// informers
+	deployments     appsListers.DeploymentLister
+	pods            coreListers.PodLister
+	networkPolicies networkingListers.NetworkPolicyLister
+	services        coreListers.ServiceLister
+	nodes           coreListers.NodeLister
+	stop            chan struct{}
+
+	events     chan AppEvent
+	eventsMu   sync.Mutex
+	lastEvents map[string]AppEvent
 }
 
 // Ping checks the connection to the Kubernetes server.
 func (ks *Client) Ping() error {
-	ks.connectOnce.Do(ks.init)
-	if ks.err != nil {
-		return ks.err
+	if err := ks.connect(); err != nil {
+		return err
 	}
 	return ks.clientSet.CoreV1().RESTClient().Get().AbsPath("/").Do().Error()
 }
 
-func (ks *Client) init() {
+// init creates the clients of the Kubernetes server and starts the informers.
+func (ks *Client) init() error {
 	// default image pull policy is never pull the image, only use the local image provided
 	if ks.ImagePullPolicy == "" {
 		ks.ImagePullPolicy = apiV1.PullNever
 	}
 
+	config := &restClient.Config{
+		Host:     ks.Host,
+		APIPath:  ks.APIPath,
+		Username: ks.Username,
+		TLSClientConfig: restClient.TLSClientConfig{
+			Insecure: false,
+			CertFile: ks.CertFile,
+			KeyFile:  ks.KeyFile,
+			CAFile:   ks.CAFile,
+		},
+	}
 	csCreate := ks.NewClientSet
 	if csCreate == nil {
 		csCreate = func() (kubernetes.Interface, error) {
-			return kubernetes.NewForConfig(
-				&restClient.Config{
-					Host:     ks.Host,
-					APIPath:  ks.APIPath,
-					Username: ks.Username,
-					TLSClientConfig: restClient.TLSClientConfig{
-						Insecure: false,
-						CertFile: ks.CertFile,
-						KeyFile:  ks.KeyFile,
-						CAFile:   ks.CAFile,
-					},
-				},
-			)
+			return kubernetes.NewForConfig(config)
+		}
+	}
+	dynCreate := ks.NewDynamicClient
+	if dynCreate == nil {
+		dynCreate = func() (dynamic.Interface, error) {
+			return dynamic.NewForConfig(config)
 		}
 	}
-	ks.clientSet, ks.err = csCreate()
+	var err error
+	if ks.clientSet, err = csCreate(); err != nil {
+		return err
+	}
+	if ks.dynamicClient, err = dynCreate(); err != nil {
+		return err
+	}
+	return ks.startInformers()
 }
 
-// Deploy creates a kubernetes deployment
+// Deploy creates a kubernetes deployment, or a virtual machine for a VM app
 func (ks *Client) Deploy(ctx context.Context, nodeID string, app App) error {
-	ks.connectOnce.Do(ks.init)
-	if ks.err != nil {
-		return ks.err
+	if err := ks.connect(); err != nil {
+		return err
 	}
 	// initial checks
 	if err := ks.checkNode(nodeID); err != nil {
 		return errors.Wrap(err, "deploy: node available error")
 	}
 	// make the deployment to the correct node
+	if app.VM != nil {
+		return errors.Wrap(ks.deployVM(nodeID, app), "deploy: virtual machine error")
+	}
 	if err := ks.deploy(nodeID, app); err != nil {
 		return errors.Wrap(err, "deploy: deployment error")
 	}
@@ -131,9 +224,8 @@ func (ks *Client) Deploy(ctx context.Context, nodeID string, app App) error {
 
 // Undeploy cascade deletes a kubernetes deployment
 func (ks *Client) Undeploy(ctx context.Context, nodeID, appID string) error {
-	ks.connectOnce.Do(ks.init)
-	if ks.err != nil {
-		return ks.err
+	if err := ks.connect(); err != nil {
+		return err
 	}
 	// make the deployment to the correct node
 	if err := ks.undeploy(nodeID, appID); err != nil {
@@ -158,8 +250,56 @@ func (ks *Client) checkNode(nodeID string) error {
 	return nil
 }
 
-// create a kubernetes deployment
-func (ks *Client) deploy(nodeID string, app App) error {
+// Upgrade replaces the image, resources and runtime configuration of the
+// kubernetes deployment of appID with the ones of app. The deployment keeps
+// the labels of appID.
+func (ks *Client) Upgrade(ctx context.Context, nodeID, appID string, app App) error {
+	if err := ks.connect(); err != nil {
+		return err
+	}
+
+	ports, err := toContainerPorts(app.Ports)
+	if err != nil {
+		return errors.Wrap(err, "upgrade: deployment error")
+	}
+
+	deployment, err := ks.getDeployment(nodeID, appID)
+	if err == errDeploymentNotFound {
+		if _, err = ks.getVM(nodeID, appID); err == nil {
+			return errors.New("upgrade: virtual machine apps cannot be upgraded")
+		}
+	}
+	if err != nil {
+		return errors.Wrap(err, "upgrade: error getting deployment by ID")
+	}
+	if err = ks.checkNetworks(deployment.Namespace, app.Networks); err != nil {
+		return errors.Wrap(err, "upgrade: deployment error")
+	}
+	if err = ks.applyConfigMap(deployment.Namespace, nodeID, appID, app); err != nil {
+		return errors.Wrap(err, "upgrade: deployment error")
+	}
+	podSpec := &deployment.Spec.Template.Spec
+	for i := range podSpec.Containers {
+		container := &podSpec.Containers[i]
+		container.Image = app.Image
+		container.Ports = ports
+		configure(container, podSpec, nodeID, appID, app)
+		if err = setPlatform(container, &deployment.Spec.Template, app); err != nil {
+			return errors.Wrap(err, "upgrade: deployment error")
+		}
+		if err = setProbes(container, app); err != nil {
+			return errors.Wrap(err, "upgrade: deployment error")
+		}
+	}
+
+	_, err = ks.clientSet.AppsV1().Deployments(deployment.Namespace).Update(deployment)
+	if err != nil {
+		return errors.Wrap(err, "upgrade: error updating deployment")
+	}
+	return errors.Wrap(ks.applyService(deployment.Namespace, nodeID, appID, app), "upgrade: deployment error")
+}
+
+func toContainerPorts(portProts []*PortProto) ([]apiV1.ContainerPort, error) {
 	protoConverter := map[string]apiV1.Protocol{
 		"tcp":  apiV1.ProtocolTCP,
 		"udp":  apiV1.ProtocolUDP,
@@ -167,20 +307,65 @@ func (ks *Client) deploy(nodeID string, app App) error {
 	}
 
 	var ports []apiV1.ContainerPort
-	for _, portProt := range app.Ports {
+	for _, portProt := range portProts {
 		proto, ok := protoConverter[portProt.Protocol]
 		if !ok {
-			return errors.New("unsupported protocol for kubernetes error")
+			return nil, errors.New("unsupported protocol for kubernetes error")
 		}
 		ports = append(ports, apiV1.ContainerPort{
 			ContainerPort: portProt.Port,
 			Protocol:      proto,
 		})
 	}
+	return ports, nil
+}
 
-	// deployment client
-	deploymentsClient := ks.clientSet.AppsV1().Deployments(apiV1.NamespaceDefault)
-	_, err := deploymentsClient.Create(&appsV1.Deployment{
+func toResourceLimits(app App) apiV1.ResourceList {
+	return apiV1.ResourceList{
+		// CPU, in cores. (500m = .5 cores)
+		apiV1.ResourceCPU: *resource.NewQuantity(
+			int64(app.Cores),
+			resource.DecimalSI,
+		),
+
+		// Memory, in bytes. (500Gi = 500GiB = 500 * 1024 * 1024 * 1024)
+		apiV1.ResourceMemory: *resource.NewQuantity(
+			int64(1024*1024*app.Memory),
+			resource.BinarySI,
+		),
+
+		// Volume size, in bytes (e,g. 5Gi = 5GiB = 5 * 1024 * 1024 * 1024)
+		// apiV1.ResourceStorage: resource.MustParse(d.Storage),
+
+		// Local ephemeral storage, in bytes. (500Gi = 500GiB = 500 * 1024 * 1024 * 1024)
+		// The resource name for ResourceEphemeralStorage is alpha and it can change
+		// across releases.
+		// apiV1.ResourceEphemeralStorage: resource.MustParse(d.EphemeralStorage),
+	}
+}
+
+// create a kubernetes deployment
+func (ks *Client) deploy(nodeID string, app App) error {
+	ports, err := toContainerPorts(app.Ports)
+	if err != nil {
+		return err
+	}
+	if app.Service != nil && len(ports) == 0 {
+		return errNoServicePorts
+	}
+
+	namespace := namespaceOf(app)
+	if err = ks.ensureNamespace(namespace); err != nil {
+		return err
+	}
+	if err = ks.checkNetworks(namespace, app.Networks); err != nil {
+		return err
+	}
+	if err = ks.applyConfigMap(namespace, nodeID, app.ID, app); err != nil {
+		return err
+	}
+
+	deployment := &appsV1.Deployment{
 		ObjectMeta: metaV1.ObjectMeta{
 			GenerateName: "app",
 			Labels: map[string]string{
@@ -213,29 +398,6 @@ func (ks *Client) deploy(nodeID string, app App) error {
 				Spec: apiV1.PodSpec{
 					Containers: []apiV1.Container{
 						{
-							Resources: apiV1.ResourceRequirements{
-								Limits: apiV1.ResourceList{
-									// CPU, in cores. (500m = .5 cores)
-									apiV1.ResourceCPU: *resource.NewQuantity(
-										int64(app.Cores),
-										resource.DecimalSI,
-									),
-
-									// Memory, in bytes. (500Gi = 500GiB = 500 * 1024 * 1024 * 1024)
-									apiV1.ResourceMemory: *resource.NewQuantity(
-										int64(1024*1024*app.Memory),
-										resource.BinarySI,
-									),
-
-									// Volume size, in bytes (e,g. 5Gi = 5GiB = 5 * 1024 * 1024 * 1024)
-									// apiV1.ResourceStorage: resource.MustParse(d.Storage),
-
-									// Local ephemeral storage, in bytes. (500Gi = 500GiB = 500 * 1024 * 1024 * 1024)
-									// The resource name for ResourceEphemeralStorage is alpha and it can change
-									// across releases.
-									// apiV1.ResourceEphemeralStorage: resource.MustParse(d.EphemeralStorage),
-								},
-							},
 							Name:            uuid.New(),
 							Image:           app.ID,
 							Ports:           ports,
@@ -253,122 +415,152 @@ func (ks *Client) deploy(nodeID string, app App) error {
 				},
 			},
 		},
-	})
+	}
+	podSpec := &deployment.Spec.Template.Spec
+	configure(&podSpec.Containers[0], podSpec, nodeID, app.ID, app)
+	if err = setPlatform(&podSpec.Containers[0], &deployment.Spec.Template, app); err != nil {
+		return err
+	}
+	if err = setProbes(&podSpec.Containers[0], app); err != nil {
+		return err
+	}
+
+	// deployment client
+	deploymentsClient := ks.clientSet.AppsV1().Deployments(namespace)
+	_, err = deploymentsClient.Create(deployment)
 	if err != nil {
 		return errors.Wrap(err, "create kubernetes deployment error")
 	}
-	return nil
+	return ks.applyService(namespace, nodeID, app.ID, app)
 }
 
 // delete a kubernetes deployment
 func (ks *Client) undeploy(nodeID, appID string) error {
-	deploymentName, err := ks.getDeploymentName(nodeID, appID)
+	deployment, err := ks.getDeployment(nodeID, appID)
+	if err == errDeploymentNotFound {
+		return ks.undeployVM(nodeID, appID)
+	}
 	if err != nil {
 		return errors.Wrap(err, "start: error getting deployment name by ID")
 	}
 
-	deploymentsClient := ks.clientSet.AppsV1().Deployments(apiV1.NamespaceDefault)
+	deploymentsClient := ks.clientSet.AppsV1().Deployments(deployment.Namespace)
 	foreground := metaV1.DeletePropagationForeground
-	err = deploymentsClient.Delete(deploymentName, &metaV1.DeleteOptions{
+	err = deploymentsClient.Delete(deployment.Name, &metaV1.DeleteOptions{
 		PropagationPolicy: &foreground,
 	})
-	return errors.Wrap(err, "create kubernetes deployment error")
+	if err != nil {
+		return errors.Wrap(err, "create kubernetes deployment error")
+	}
+	if err = ks.deleteConfigMap(deployment.Namespace, nodeID, appID); err != nil {
+		return err
+	}
+
+	service, err := ks.getService(nodeID, appID)
+	if err != nil {
+		return err
+	}
+	return ks.deleteService(service)
 }
 
 func int32Ptr(i int32) *int32 { return &i }
 
-// Start scales up the number of replicas of kubernetes deployment to 1.
-func (ks *Client) Start(ctx context.Context, nodeID, appID string) error {
-	deploymentName, err := ks.getDeploymentName(nodeID, appID)
+// Start scales up the number of replicas of kubernetes deployment to replicas,
+// or runs the virtual machine of a VM app.
+func (ks *Client) Start(ctx context.Context, nodeID, appID string, replicas int) error {
+	deployment, err := ks.getDeployment(nodeID, appID)
+	if err == errDeploymentNotFound {
+		return errors.Wrap(ks.setRunStrategy(nodeID, appID, runStrategyAlways), "start: virtual machine error")
+	}
 	if err != nil {
-		return errors.Wrap(err, "start: error getting deployment name by ID")
+		return errors.Wrap(err, "start: error getting deployment by ID")
 	}
 
-	deploymentsClient := ks.clientSet.AppsV1().Deployments(apiV1.NamespaceDefault)
-	_, err = deploymentsClient.UpdateScale(
-		deploymentName,
-		&autoscalingV1.Scale{
-			ObjectMeta: metaV1.ObjectMeta{
-				Name:      deploymentName,
-				Namespace: apiV1.NamespaceDefault,
-			},
-			Spec: autoscalingV1.ScaleSpec{Replicas: 1},
-		})
-	return errors.Wrap(err, "start: error scaling deployment to 1 replica")
+	return errors.Wrapf(ks.scale(deployment, replicas), "start: error scaling deployment to %d replicas", replicas)
 }
 
-// Stop scales down the number of replicas of kubernetes deployment to 0.
+// Stop scales down the number of replicas of kubernetes deployment to 0, or
+// halts the virtual machine of a VM app.
 func (ks *Client) Stop(ctx context.Context, nodeID, appID string) error {
-	deploymentName, err := ks.getDeploymentName(nodeID, appID)
+	deployment, err := ks.getDeployment(nodeID, appID)
+	if err == errDeploymentNotFound {
+		return errors.Wrap(ks.setRunStrategy(nodeID, appID, runStrategyHalted), "stop: virtual machine error")
+	}
 	if err != nil {
-		return errors.Wrap(err, "stop: error getting deployment name by ID")
+		return errors.Wrap(err, "stop: error getting deployment by ID")
 	}
 
-	deploymentsClient := ks.clientSet.AppsV1().Deployments(apiV1.NamespaceDefault)
-	_, err = deploymentsClient.UpdateScale(
-		deploymentName,
-		&autoscalingV1.Scale{
-			ObjectMeta: metaV1.ObjectMeta{
-				Name:      deploymentName,
-				Namespace: apiV1.NamespaceDefault,
-			},
-			Spec: autoscalingV1.ScaleSpec{Replicas: 0},
-		})
-	return errors.Wrap(err, "stop: error scaling deployment to 0 replicas")
+	return errors.Wrap(ks.scale(deployment, 0), "stop: error scaling deployment to 0 replicas")
 }
 
-// Restart scales down the number of replicas of kubernetes deployment to 0 and then scale up to 1.
-func (ks *Client) Restart(ctx context.Context, nodeID, appID string) error {
-	deploymentName, err := ks.getDeploymentName(nodeID, appID)
+// Restart scales down the number of replicas of kubernetes deployment to 0 and then scale up to replicas,
+// or restarts the virtual machine of a VM app.
+func (ks *Client) Restart(ctx context.Context, nodeID, appID string, replicas int) error {
+	deployment, err := ks.getDeployment(nodeID, appID)
+	if err == errDeploymentNotFound {
+		return errors.Wrap(ks.restartVM(ctx, nodeID, appID), "restart: virtual machine error")
+	}
 	if err != nil {
-		return errors.Wrap(err, "restart: error getting deployment name by ID")
+		return errors.Wrap(err, "restart: error getting deployment by ID")
 	}
 
-	deploymentsClient := ks.clientSet.AppsV1().Deployments(apiV1.NamespaceDefault)
+	if err = ks.scale(deployment, 0); err != nil {
+		return errors.Wrap(err, "restart: error scaling deployment to 0 replicas")
+	}
+	return errors.Wrapf(ks.scale(deployment, replicas), "restart: error scaling deployment to %d replicas", replicas)
+}
 
-	// Scale down to 0
-	_, err = deploymentsClient.UpdateScale(
-		deploymentName,
-		&autoscalingV1.Scale{
-			ObjectMeta: metaV1.ObjectMeta{
-				Name:      deploymentName,
-				Namespace: apiV1.NamespaceDefault,
-			},
-			Spec: autoscalingV1.ScaleSpec{Replicas: 0},
-		},
-	)
+// Scale changes the number of replicas of kubernetes deployment to replicas
+// if it is started. A stopped deployment is left stopped.
+func (ks *Client) Scale(ctx context.Context, nodeID, appID string, replicas int) error {
+	deployment, err := ks.getDeployment(nodeID, appID)
+	if err == errDeploymentNotFound {
+		if _, err = ks.getVM(nodeID, appID); err == nil {
+			return errors.New("scale: virtual machine apps cannot be scaled")
+		}
+	}
 	if err != nil {
-		return errors.Wrap(err, "restart: error scaling deployment to 0 replicas")
+		return errors.Wrap(err, "scale: error getting deployment by ID")
 	}
 
-	// Scale up to 1
-	_, err = deploymentsClient.UpdateScale(
-		deploymentName,
+	if deployment.Spec.Replicas != nil && *deployment.Spec.Replicas == 0 {
+		return nil
+	}
+	return errors.Wrapf(ks.scale(deployment, replicas), "scale: error scaling deployment to %d replicas", replicas)
+}
+
+// scale updates the number of replicas of a deployment.
+func (ks *Client) scale(deployment *appsV1.Deployment, replicas int) error {
+	_, err := ks.clientSet.AppsV1().Deployments(deployment.Namespace).UpdateScale(
+		deployment.Name,
 		&autoscalingV1.Scale{
 			ObjectMeta: metaV1.ObjectMeta{
-				Name:      deploymentName,
-				Namespace: apiV1.NamespaceDefault,
+				Name:      deployment.Name,
+				Namespace: deployment.Namespace,
 			},
-			Spec: autoscalingV1.ScaleSpec{Replicas: 1},
-		},
-	)
-	return errors.Wrap(err, "restart: error scaling deployment to 1 replica")
+			Spec: autoscalingV1.ScaleSpec{Replicas: int32(replicas)},
+		})
+	return err
 }
 
-// get unique generated deployment name by controller deployment ID
-func (ks *Client) getDeploymentName(nodeID, appID string) (string, error) {
-	deployment, err := ks.getDeployment(nodeID, appID)
-	if err != nil {
-		return "", err
-	}
-	return deployment.ObjectMeta.Name, nil
-}
+// errDeploymentNotFound is returned for an app that has no deployment, e.g.
+// a VM app.
+var errDeploymentNotFound = errors.New("deployment not found")
 
-// get deployment info by controller deployment ID
+// get deployment info by controller deployment ID, from the cache unless it
+// is yet to be cached
 func (ks *Client) getDeployment(nodeID, appID string) (*appsV1.Deployment, error) {
-	deployments, err := ks.clientSet.AppsV1().Deployments(apiV1.NamespaceDefault).
+	if err := ks.connect(); err != nil {
+		return nil, err
+	}
+	deployment, err := ks.getCachedDeployment(nodeID, appID)
+	if deployment != nil || err != nil {
+		return deployment, err
+	}
+
+	deployments, err := ks.clientSet.AppsV1().Deployments(metaV1.NamespaceAll).
 		List(metaV1.ListOptions{
-			LabelSelector: fmt.Sprintf("%s=%s,%s=%s", appIDLabelKey, appID, nodeIDLabelKey, nodeID),
+			LabelSelector: appSelector(nodeID, appID).String(),
 		})
 	if err != nil {
 		return nil, errors.Wrap(err, "error getting list of deployments")
@@ -376,7 +568,7 @@ func (ks *Client) getDeployment(nodeID, appID string) (*appsV1.Deployment, error
 
 	deps := deployments.Items
 	if len(deps) == 0 {
-		return nil, errors.New("deployment not found")
+		return nil, errDeploymentNotFound
 	}
 	if len(deps) > 1 {
 		return nil, errors.New("more than one deployment found")
@@ -413,59 +605,108 @@ func getPodStatus(pod apiV1.Pod) LifecycleStatus {
 	return Unknown
 }
 
-// Status gets the status of kubernetes app
+// getPodsStatus gets the status of the pods of an app
+func getPodsStatus(pods []*apiV1.Pod) LifecycleStatus {
+	if len(pods) == 1 {
+		// Just one pod for deployment
+		return getPodStatus(*pods[0])
+	}
+
+	// Many pods exist
+	state := Unknown
+	isAnyTerminating := false
+	for _, pod := range pods {
+		if getPodStatus(*pod) == Terminating {
+			isAnyTerminating = true
+			continue
+		}
+
+		state = getPodStatus(*pod)
+	}
+
+	if state == Unknown && isAnyTerminating {
+		return Terminating
+	}
+
+	return state
+}
+
+// arePodsReady tells whether any pod of an app is ready
+func arePodsReady(pods []*apiV1.Pod) bool {
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+		for _, cond := range pod.Status.Conditions {
+			if cond.Type == apiV1.PodReady && cond.Status == apiV1.ConditionTrue {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Status gets the status of kubernetes app from the cache, or of the virtual
+// machine of a VM app
 func (ks *Client) Status(ctx context.Context, nodeID, appID string) (LifecycleStatus, error) {
 	// Check if deployment actually exists
 	_, err := ks.getDeployment(nodeID, appID)
+	if err == errDeploymentNotFound {
+		return ks.vmStatus(nodeID, appID)
+	}
 	if err != nil {
 		return Error, err
 	}
 
-	podsClient := ks.clientSet.CoreV1().Pods(apiV1.NamespaceDefault)
-
-	listOptions := metaV1.ListOptions{
-		LabelSelector: fmt.Sprintf("node-id=%s,app-id=%s", nodeID, appID),
-	}
-
-	pods, err := podsClient.List(listOptions)
+	pods, err := ks.pods.List(appSelector(nodeID, appID))
 	if err != nil {
 		return Unknown, err
 	}
 
-	if len(pods.Items) == 0 {
+	if len(pods) == 0 {
 		// Deployment exists, but no pod is running
 		return Deployed, nil
 	}
 
-	if len(pods.Items) == 1 {
-		// Just one pod for deployment
-		return getPodStatus(pods.Items[0]), nil
-	}
-
-	// Many pods exist
-	state := Unknown
-	isAnyTerminating := false
-	for _, pod := range pods.Items {
-		if getPodStatus(pod) == Terminating {
-			isAnyTerminating = true
-			continue
-		}
+	return getPodsStatus(pods), nil
+}
 
-		state = getPodStatus(pod)
+// Ready tells whether a pod of kubernetes app is ready, i.e. passes its
+// readiness probe if it has one, from the cache
+func (ks *Client) Ready(ctx context.Context, nodeID, appID string) (bool, error) {
+	if err := ks.connect(); err != nil {
+		return false, err
 	}
 
-	if state == Unknown && isAnyTerminating {
-		return Terminating, nil
+	pods, err := ks.pods.List(appSelector(nodeID, appID))
+	if err != nil {
+		return false, errors.Wrapf(err, "error getting pods of app %s on node %s", appID, nodeID)
 	}
 
-	return state, nil
+	return arePodsReady(pods), nil
 }
 
 // GetAppIDByIP gets the ID of an application running on a node by its pod IP address
+// from the cache unless the pod is yet to be cached
 func (ks *Client) GetAppIDByIP(ctx context.Context, nodeID, ipAddr string) (string, error) {
-	pods, err := ks.clientSet.CoreV1().Pods(apiV1.NamespaceDefault).List(
+	if err := ks.connect(); err != nil {
+		return "", err
+	}
+
+	onNode := labels.SelectorFromSet(labels.Set{nodeIDLabelKey: nodeID})
+	cached, err := ks.pods.List(onNode)
+	if err != nil {
+		return "", errors.Wrapf(err, "error getting pods on node %s", nodeID)
+	}
+	for _, pod := range cached {
+		if pod.Status.PodIP == ipAddr {
+			return pod.GetLabels()[appIDLabelKey], nil
+		}
+	}
+
+	pods, err := ks.clientSet.CoreV1().Pods(metaV1.NamespaceAll).List(
 		metaV1.ListOptions{
-			LabelSelector: fmt.Sprintf("%s=%s", nodeIDLabelKey, nodeID),
+			LabelSelector: onNode.String(),
 		},
 	)
 	if err != nil {
@@ -489,10 +730,19 @@ func (ks *Client) GetAppIDByIP(ctx context.Context, nodeID, ipAddr string) (stri
 func (ks *Client) ApplyNetworkPolicy(ctx context.Context,
 	nodeID, appID string, policy *networkingV1.NetworkPolicy) error {
 
+	deployment, err := ks.getDeployment(nodeID, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to create network policy")
+	}
+
 	networkingClient := ks.clientSet.NetworkingV1().RESTClient()
 
 	// Currently only 1 NetworkPolicy per app so we can just concatenate node and app
-	policy.ObjectMeta.Name = fmt.Sprintf("np-%s.%s", nodeID, appID)
+	policy.ObjectMeta.Name = networkPolicyName(nodeID, appID)
+	policy.ObjectMeta.Labels = map[string]string{
+		appIDLabelKey:  appID,
+		nodeIDLabelKey: nodeID,
+	}
 
 	policy.Spec.PodSelector = metaV1.LabelSelector{
 		MatchLabels: map[string]string{
@@ -501,9 +751,9 @@ func (ks *Client) ApplyNetworkPolicy(ctx context.Context,
 		},
 	}
 
-	err := networkingClient.Post().
+	err = networkingClient.Post().
 		Context(ctx).
-		Namespace(apiV1.NamespaceDefault).
+		Namespace(deployment.Namespace).
 		Resource("networkpolicies").
 		Body(policy).
 		Do().Error()
@@ -517,6 +767,11 @@ func (ks *Client) ApplyNetworkPolicy(ctx context.Context,
 
 // DeleteNetworkPolicy deletes network policy for app on specified node
 func (ks *Client) DeleteNetworkPolicy(ctx context.Context, nodeID, appID string) error {
+	netpol, err := ks.GetNetworkPolicy(ctx, nodeID, appID)
+	if err != nil {
+		return errors.Wrap(err, "failed to delete network policy")
+	}
+
 	networkingClient := ks.clientSet.NetworkingV1().RESTClient()
 
 	propagation := metaV1.DeletePropagationBackground
@@ -525,13 +780,12 @@ func (ks *Client) DeleteNetworkPolicy(ctx context.Context, nodeID, appID string)
 		PropagationPolicy:  &propagation,
 		GracePeriodSeconds: &gracePeriodSeconds,
 	}
-	name := fmt.Sprintf("np-%s.%s", nodeID, appID)
 
-	err := networkingClient.Delete().
+	err = networkingClient.Delete().
 		Context(ctx).
-		Namespace(apiV1.NamespaceDefault).
+		Namespace(netpol.Namespace).
 		Resource("networkpolicies").
-		Name(name).
+		Name(netpol.Name).
 		Body(deleteOptions).
 		Do().Error()
 
@@ -542,13 +796,37 @@ func (ks *Client) DeleteNetworkPolicy(ctx context.Context, nodeID, appID string)
 	return nil
 }
 
-// GetNetworkPolicy returns network policy for app on specified node
+// GetNetworkPolicy returns network policy for app on specified node, in
+// whichever namespace the app is deployed to, from the cache unless it is yet
+// to be cached
 func (ks *Client) GetNetworkPolicy(ctx context.Context, nodeID, appID string) (*networkingV1.NetworkPolicy, error) {
-	networkingClient := ks.clientSet.NetworkingV1().NetworkPolicies(apiV1.NamespaceDefault)
+	if err := ks.connect(); err != nil {
+		return nil, err
+	}
+	netpol, err := ks.getCachedNetworkPolicy(nodeID, appID)
+	if netpol != nil || err != nil {
+		return netpol, err
+	}
+
+	networkingClient := ks.clientSet.NetworkingV1().NetworkPolicies(metaV1.NamespaceAll)
 
-	name := fmt.Sprintf("np-%s.%s", nodeID, appID)
+	name := networkPolicyName(nodeID, appID)
 
-	netpol, err := networkingClient.Get(name, metaV1.GetOptions{})
+	netpols, err := networkingClient.List(metaV1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("metadata.name", name).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(netpols.Items) == 0 {
+		return nil, apiErrors.NewNotFound(networkingV1.Resource("networkpolicies"), name)
+	}
+
+	return &netpols.Items[0], nil
+}
 
-	return netpol, err
+// networkPolicyName is the name of the NetworkPolicy of an app deployed to a
+// node.
+func networkPolicyName(nodeID, appID string) string {
+	return fmt.Sprintf("np-%s.%s", nodeID, appID)
 }
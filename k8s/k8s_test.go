@@ -28,7 +28,7 @@ var (
 
 // In order to run these tests, mini-kube and virtualization
 // tools need to be installed to setup mini-kube on travis CI.
-var _ = BeforeSuite(func() {
+func setupMinikube() {
 	u, err := user.Current()
 	Expect(err).NotTo(HaveOccurred())
 	homeDir = u.HomeDir
@@ -48,9 +48,9 @@ var _ = BeforeSuite(func() {
 	// docker pull public image for testing
 	cmd = exec.Command("docker", "pull", "nginx:1.12")
 	Expect(cmd.Run()).To(Succeed())
-})
+}
 
-var _ = AfterSuite(func() {
+func cleanupMinikube() {
 	// clean up k8s deployments
 	cmd := exec.Command("kubectl", "delete", "--all", "deployments", "--namespace=default")
 	Expect(cmd.Run()).To(Succeed())
@@ -60,11 +60,14 @@ var _ = AfterSuite(func() {
 	// label node with correct app id
 	cmd = exec.Command("kubectl", "label", "nodes", "minikube", "node-id-")
 	Expect(cmd.Run()).To(Succeed())
-})
+}
 
 var _ = Describe("K8S", func() {
 	Context("API calls to K8S master", func() {
-		It("Should deploy, start, stop, restart and undeploy an app from a public docker image", func() {
+		BeforeEach(setupMinikube)
+		AfterEach(cleanupMinikube)
+
+		It("Should deploy, start, scale, stop, restart, upgrade and undeploy an app from a public docker image", func() {
 			kubeConfig := path.Join(homeDir, ".kube", "config")
 			config, err := clientcmd.BuildConfigFromFlags("", kubeConfig)
 			Expect(err).NotTo(HaveOccurred())
@@ -126,6 +129,18 @@ var _ = Describe("K8S", func() {
 						Protocol: "tcp",
 					},
 				},
+				Env: []k8s.EnvVar{
+					{
+						Name:  "MODE",
+						Value: "test",
+					},
+				},
+				ConfigFiles: []k8s.ConfigFile{
+					{
+						Path:    "/etc/nginx/conf.d/test.conf",
+						Content: "# test",
+					},
+				},
 			}
 			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
@@ -146,7 +161,11 @@ var _ = Describe("K8S", func() {
 
 			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
-			Expect(client.Start(ctx, nodeID, appID)).To(Succeed())
+			Expect(client.Start(ctx, nodeID, appID, 1)).To(Succeed())
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			Expect(client.Scale(ctx, nodeID, appID, 2)).To(Succeed())
 
 			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
@@ -162,7 +181,14 @@ var _ = Describe("K8S", func() {
 
 			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
-			Expect(client.Restart(ctx, nodeID, appID)).To(Succeed())
+			Expect(client.Restart(ctx, nodeID, appID, 2)).To(Succeed())
+
+			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			upgraded := app
+			upgraded.ID = "5c6e2d1a-7f4b-4e3a-9b8c-1d2e3f4a5b6c"
+			upgraded.Memory = 200
+			Expect(client.Upgrade(ctx, nodeID, appID, upgraded)).To(Succeed())
 
 			ctx, cancel = context.WithTimeout(context.Background(), 5*time.Second)
 			defer cancel()
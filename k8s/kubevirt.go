@@ -0,0 +1,297 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/open-ness/edgecontroller/uuid"
+	"github.com/pkg/errors"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// VM describes the disk of an app run as a KubeVirt virtual machine. The VM
+// boots from the image of the app as an ephemeral containerDisk, unless
+// DiskSize is set: the disk is then a persistent DataVolume imported from
+// Source.
+type VM struct {
+	Source   string
+	DiskSize int // in GB
+}
+
+// Run strategies of KubeVirt virtual machines
+const (
+	runStrategyAlways = "Always"
+	runStrategyHalted = "Halted"
+)
+
+var (
+	virtualMachines = schema.GroupVersionResource{
+		Group:    "kubevirt.io",
+		Version:  "v1alpha3",
+		Resource: "virtualmachines",
+	}
+	virtualMachineInstances = schema.GroupVersionResource{
+		Group:    "kubevirt.io",
+		Version:  "v1alpha3",
+		Resource: "virtualmachineinstances",
+	}
+)
+
+// errVMNotFound is returned for an app that has neither a deployment nor a
+// virtual machine.
+var errVMNotFound = errors.New("deployment or virtual machine not found")
+
+// toVirtualMachine builds the halted virtual machine of an app deployed to a
+// node.
+func toVirtualMachine(nodeID string, app App) *unstructured.Unstructured {
+	name := "vm-" + uuid.New()
+	labels := map[string]interface{}{
+		appIDLabelKey:  app.ID,
+		nodeIDLabelKey: nodeID,
+	}
+
+	rootfs := map[string]interface{}{"name": "rootfs"}
+	spec := map[string]interface{}{
+		"runStrategy": runStrategyHalted,
+	}
+	if app.VM.DiskSize > 0 {
+		rootfs["dataVolume"] = map[string]interface{}{"name": name + "-disk"}
+		spec["dataVolumeTemplates"] = []interface{}{
+			map[string]interface{}{
+				"metadata": map[string]interface{}{"name": name + "-disk"},
+				"spec": map[string]interface{}{
+					"source": map[string]interface{}{
+						"http": map[string]interface{}{"url": app.VM.Source},
+					},
+					"pvc": map[string]interface{}{
+						"accessModes": []interface{}{"ReadWriteOnce"},
+						"resources": map[string]interface{}{
+							"requests": map[string]interface{}{
+								"storage": fmt.Sprintf("%dGi", app.VM.DiskSize),
+							},
+						},
+					},
+				},
+			},
+		}
+	} else {
+		rootfs["containerDisk"] = map[string]interface{}{"image": app.Image}
+	}
+
+	cpu := map[string]interface{}{"cores": int64(app.Cores)}
+	if app.PinnedCPUs {
+		cpu["dedicatedCpuPlacement"] = true
+	}
+
+	spec["template"] = map[string]interface{}{
+		"metadata": map[string]interface{}{"labels": labels},
+		"spec": map[string]interface{}{
+			"nodeSelector": map[string]interface{}{nodeIDLabelKey: nodeID},
+			"domain": map[string]interface{}{
+				"cpu": cpu,
+				"resources": map[string]interface{}{
+					"requests": map[string]interface{}{
+						"memory": fmt.Sprintf("%dMi", app.Memory),
+					},
+					// Limits let the quota of a tenant namespace admit the
+					// pod the VM runs in
+					"limits": map[string]interface{}{
+						"cpu":    fmt.Sprintf("%d", app.Cores),
+						"memory": fmt.Sprintf("%dMi", app.Memory),
+					},
+				},
+				"devices": map[string]interface{}{
+					"disks": []interface{}{
+						map[string]interface{}{
+							"name": "rootfs",
+							"disk": map[string]interface{}{"bus": "virtio"},
+						},
+					},
+					"interfaces": []interface{}{
+						map[string]interface{}{
+							"name":   "default",
+							"bridge": map[string]interface{}{},
+						},
+					},
+				},
+			},
+			"networks": []interface{}{
+				map[string]interface{}{
+					"name": "default",
+					"pod":  map[string]interface{}{},
+				},
+			},
+			"volumes": []interface{}{rootfs},
+		},
+	}
+
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "kubevirt.io/v1alpha3",
+			"kind":       "VirtualMachine",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": namespaceOf(app),
+				"labels":    labels,
+			},
+			"spec": spec,
+		},
+	}
+}
+
+// checkVM checks that a VM app does not set what only applies to containers:
+// a service, networks or a runtime configuration.
+func checkVM(app App) error {
+	switch {
+	case app.Service != nil:
+		return errors.New("virtual machine apps cannot be exposed by a service")
+	case len(app.Networks) > 0:
+		return errors.New("virtual machine apps cannot be attached to networks")
+	case len(app.Env) > 0 || len(app.Command) > 0 || len(app.Args) > 0 ||
+		len(app.ConfigFiles) > 0 || len(app.Secrets) > 0:
+		return errors.New("virtual machine apps cannot have a runtime configuration")
+	case len(app.Resources) > 0:
+		return errors.New("virtual machine apps cannot request extended resources")
+	case app.LivenessProbe != nil || app.ReadinessProbe != nil || app.StartupProbe != nil:
+		return errors.New("virtual machine apps cannot have probes")
+	}
+	return nil
+}
+
+// deployVM creates the virtual machine of an app. Like deployments, it is
+// created halted.
+func (ks *Client) deployVM(nodeID string, app App) error {
+	if err := checkVM(app); err != nil {
+		return err
+	}
+
+	namespace := namespaceOf(app)
+	if err := ks.ensureNamespace(namespace); err != nil {
+		return err
+	}
+
+	_, err := ks.dynamicClient.Resource(virtualMachines).Namespace(namespace).
+		Create(toVirtualMachine(nodeID, app), metaV1.CreateOptions{})
+	return errors.Wrap(err, "create kubevirt virtual machine error")
+}
+
+// getVM gets the virtual machine of an app.
+func (ks *Client) getVM(nodeID, appID string) (*unstructured.Unstructured, error) {
+	vms, err := ks.dynamicClient.Resource(virtualMachines).Namespace(metaV1.NamespaceAll).
+		List(metaV1.ListOptions{
+			LabelSelector: appSelector(nodeID, appID).String(),
+		})
+	if apiErrors.IsNotFound(err) {
+		// KubeVirt is not installed
+		return nil, errVMNotFound
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting list of virtual machines")
+	}
+
+	if len(vms.Items) == 0 {
+		return nil, errVMNotFound
+	}
+	if len(vms.Items) > 1 {
+		return nil, errors.New("more than one virtual machine found")
+	}
+	return &vms.Items[0], nil
+}
+
+// undeployVM cascade deletes the virtual machine of an app, with its instance
+// and disk.
+func (ks *Client) undeployVM(nodeID, appID string) error {
+	vm, err := ks.getVM(nodeID, appID)
+	if err != nil {
+		return err
+	}
+
+	foreground := metaV1.DeletePropagationForeground
+	err = ks.dynamicClient.Resource(virtualMachines).Namespace(vm.GetNamespace()).
+		Delete(vm.GetName(), &metaV1.DeleteOptions{PropagationPolicy: &foreground})
+	return errors.Wrap(err, "delete kubevirt virtual machine error")
+}
+
+// setRunStrategy sets the run strategy of the virtual machine of an app.
+func (ks *Client) setRunStrategy(nodeID, appID, strategy string) error {
+	vm, err := ks.getVM(nodeID, appID)
+	if err != nil {
+		return err
+	}
+
+	if err = unstructured.SetNestedField(vm.Object, strategy, "spec", "runStrategy"); err != nil {
+		return err
+	}
+	// The run strategy replaces the legacy running field
+	unstructured.RemoveNestedField(vm.Object, "spec", "running")
+
+	_, err = ks.dynamicClient.Resource(virtualMachines).Namespace(vm.GetNamespace()).
+		Update(vm, metaV1.UpdateOptions{})
+	return err
+}
+
+// restartVM restarts the virtual machine of an app by deleting its instance,
+// which KubeVirt recreates as the VM is set to always run.
+func (ks *Client) restartVM(ctx context.Context, nodeID, appID string) error {
+	if err := ks.setRunStrategy(nodeID, appID, runStrategyAlways); err != nil {
+		return err
+	}
+
+	vm, err := ks.getVM(nodeID, appID)
+	if err != nil {
+		return err
+	}
+	err = ks.dynamicClient.Resource(virtualMachineInstances).Namespace(vm.GetNamespace()).
+		Delete(vm.GetName(), &metaV1.DeleteOptions{})
+	if apiErrors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
+
+// vmStatus gets the status of the virtual machine of an app from the phase of
+// its instance.
+func (ks *Client) vmStatus(nodeID, appID string) (LifecycleStatus, error) {
+	vm, err := ks.getVM(nodeID, appID)
+	if err != nil {
+		return Error, err
+	}
+
+	vmi, err := ks.dynamicClient.Resource(virtualMachineInstances).Namespace(vm.GetNamespace()).
+		Get(vm.GetName(), metaV1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		if strategy, _, _ := unstructured.NestedString(vm.Object, "spec", "runStrategy"); strategy == runStrategyAlways {
+			// Instance yet to be created
+			return Pending, nil
+		}
+		// VM exists, but is not running
+		return Deployed, nil
+	}
+	if err != nil {
+		return Unknown, err
+	}
+
+	if vmi.GetDeletionTimestamp() != nil {
+		return Terminating, nil
+	}
+	phase, _, _ := unstructured.NestedString(vmi.Object, "status", "phase")
+	switch phase {
+	case "Pending":
+		return Pending, nil
+	case "Scheduling", "Scheduled":
+		return Starting, nil
+	case "Running":
+		return Running, nil
+	case "Succeeded":
+		return Deployed, nil
+	case "Failed":
+		return Error, nil
+	}
+	return Unknown, nil
+}
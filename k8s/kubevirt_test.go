@@ -0,0 +1,188 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+
+	"github.com/open-ness/edgecontroller/k8s"
+	apiV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("K8S KubeVirt", func() {
+	var (
+		dynamicClient *dynamicFake.FakeDynamicClient
+		client        *k8s.Client
+
+		vms  = schema.GroupVersionResource{Group: "kubevirt.io", Version: "v1alpha3", Resource: "virtualmachines"}
+		vmis = schema.GroupVersionResource{Group: "kubevirt.io", Version: "v1alpha3", Resource: "virtualmachineinstances"}
+	)
+
+	nested := func(obj map[string]interface{}, fields ...string) interface{} {
+		value, found, err := unstructured.NestedFieldNoCopy(obj, fields...)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(found).To(BeTrue())
+		return value
+	}
+
+	getVM := func() *unstructured.Unstructured {
+		list, err := dynamicClient.Resource(vms).Namespace(metaV1.NamespaceAll).List(metaV1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list.Items).To(HaveLen(1))
+		return &list.Items[0]
+	}
+
+	BeforeEach(func() {
+		dynamicClient = dynamicFake.NewSimpleDynamicClient(runtime.NewScheme())
+		clientSet := fake.NewSimpleClientset(&apiV1.Node{
+			ObjectMeta: metaV1.ObjectMeta{Name: "node", Labels: map[string]string{"node-id": nodeID}},
+		})
+		client = &k8s.Client{
+			NewClientSet:     func() (kubernetes.Interface, error) { return clientSet, nil },
+			NewDynamicClient: func() (dynamic.Interface, error) { return dynamicClient, nil },
+		}
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	It("Should deploy a VM app as a halted virtual machine", func() {
+		Expect(client.Deploy(context.Background(), nodeID, k8s.App{
+			ID:     appID,
+			Image:  appID + ":latest",
+			Cores:  2,
+			Memory: 1024,
+			VM:     &k8s.VM{Source: "http://www.test.com/vm.qcow2", DiskSize: 10},
+		})).To(Succeed())
+
+		vm := getVM()
+		Expect(vm.GetNamespace()).To(Equal("default"))
+		Expect(vm.GetLabels()).To(Equal(map[string]string{"app-id": appID, "node-id": nodeID}))
+		Expect(nested(vm.Object, "spec", "runStrategy")).To(Equal("Halted"))
+		Expect(nested(vm.Object,
+			"spec", "template", "spec", "domain", "cpu", "cores")).To(BeEquivalentTo(2))
+		Expect(nested(vm.Object,
+			"spec", "template", "spec", "domain", "resources", "requests", "memory")).To(Equal("1024Mi"))
+		Expect(nested(vm.Object,
+			"spec", "template", "spec", "domain", "resources", "limits")).To(Equal(map[string]interface{}{
+			"cpu":    "2",
+			"memory": "1024Mi",
+		}))
+
+		templates := nested(vm.Object, "spec", "dataVolumeTemplates").([]interface{})
+		Expect(templates).To(HaveLen(1))
+		Expect(nested(templates[0].(map[string]interface{}),
+			"spec", "source", "http", "url")).To(Equal("http://www.test.com/vm.qcow2"))
+		Expect(nested(templates[0].(map[string]interface{}),
+			"spec", "pvc", "resources", "requests", "storage")).To(Equal("10Gi"))
+	})
+
+	It("Should boot a VM app without a disk size from a container disk", func() {
+		Expect(client.Deploy(context.Background(), nodeID, k8s.App{
+			ID:     appID,
+			Image:  appID + ":latest",
+			Cores:  1,
+			Memory: 512,
+			VM:     &k8s.VM{Source: "http://www.test.com/vm.qcow2"},
+		})).To(Succeed())
+
+		volumes := nested(getVM().Object, "spec", "template", "spec", "volumes").([]interface{})
+		Expect(volumes).To(HaveLen(1))
+		Expect(nested(volumes[0].(map[string]interface{}),
+			"containerDisk", "image")).To(Equal(appID + ":latest"))
+	})
+
+	DescribeTable("Should reject what only applies to containers",
+		func(app k8s.App, msg string) {
+			app.ID = appID
+			app.Image = appID + ":latest"
+			app.Cores = 1
+			app.Memory = 512
+			app.VM = &k8s.VM{Source: "http://www.test.com/vm.qcow2"}
+			Expect(client.Deploy(context.Background(), nodeID, app)).To(MatchError(
+				"deploy: virtual machine error: " + msg))
+
+			list, err := dynamicClient.Resource(vms).Namespace(metaV1.NamespaceAll).List(metaV1.ListOptions{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(list.Items).To(BeEmpty())
+		},
+		Entry("A service",
+			k8s.App{Service: &k8s.Service{Type: apiV1.ServiceTypeClusterIP}},
+			"virtual machine apps cannot be exposed by a service"),
+		Entry("Networks",
+			k8s.App{Networks: []k8s.NetworkAttachment{{Name: "sriov-net"}}},
+			"virtual machine apps cannot be attached to networks"),
+		Entry("A runtime configuration",
+			k8s.App{Env: []k8s.EnvVar{{Name: "MODE", Value: "edge"}}},
+			"virtual machine apps cannot have a runtime configuration"),
+		Entry("Extended resources",
+			k8s.App{Resources: apiV1.ResourceList{"intel.com/sriov": resource.MustParse("1")}},
+			"virtual machine apps cannot request extended resources"),
+		Entry("A readiness probe",
+			k8s.App{ReadinessProbe: &apiV1.Probe{PeriodSeconds: 5}},
+			"virtual machine apps cannot have probes"),
+	)
+
+	It("Should map the lifecycle of a VM app to run strategies", func() {
+		ctx := context.Background()
+		Expect(client.Deploy(ctx, nodeID, k8s.App{
+			ID:     appID,
+			Image:  appID + ":latest",
+			Cores:  1,
+			Memory: 512,
+			VM:     &k8s.VM{Source: "http://www.test.com/vm.qcow2"},
+		})).To(Succeed())
+		Expect(client.Status(ctx, nodeID, appID)).To(Equal(k8s.Deployed))
+
+		By("Starting the virtual machine")
+		Expect(client.Start(ctx, nodeID, appID, 1)).To(Succeed())
+		vm := getVM()
+		Expect(nested(vm.Object, "spec", "runStrategy")).To(Equal("Always"))
+		Expect(client.Status(ctx, nodeID, appID)).To(Equal(k8s.Pending))
+
+		_, err := dynamicClient.Resource(vmis).Namespace(vm.GetNamespace()).Create(&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "kubevirt.io/v1alpha3",
+				"kind":       "VirtualMachineInstance",
+				"metadata":   map[string]interface{}{"name": vm.GetName(), "namespace": vm.GetNamespace()},
+				"status":     map[string]interface{}{"phase": "Running"},
+			},
+		}, metaV1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(client.Status(ctx, nodeID, appID)).To(Equal(k8s.Running))
+
+		By("Refusing to scale the virtual machine")
+		Expect(client.Scale(ctx, nodeID, appID, 2)).To(
+			MatchError("scale: virtual machine apps cannot be scaled"))
+
+		By("Restarting the virtual machine")
+		Expect(client.Restart(ctx, nodeID, appID, 1)).To(Succeed())
+		Expect(client.Status(ctx, nodeID, appID)).To(Equal(k8s.Pending))
+
+		By("Stopping the virtual machine")
+		Expect(client.Stop(ctx, nodeID, appID)).To(Succeed())
+		Expect(nested(getVM().Object, "spec", "runStrategy")).To(Equal("Halted"))
+		Expect(client.Status(ctx, nodeID, appID)).To(Equal(k8s.Deployed))
+
+		By("Undeploying the virtual machine")
+		Expect(client.Undeploy(ctx, nodeID, appID)).To(Succeed())
+		list, err := dynamicClient.Resource(vms).Namespace(metaV1.NamespaceAll).List(metaV1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list.Items).To(BeEmpty())
+	})
+})
@@ -0,0 +1,152 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s
+
+import (
+	"reflect"
+
+	"github.com/pkg/errors"
+	apiV1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// Key for the label attached to the namespaces created for tenants
+	managedByLabelKey = "managed-by"
+	managedByLabel    = "edgecontroller"
+
+	// Names of the ResourceQuota and NetworkPolicy of a tenant namespace
+	namespaceQuotaName      = "tenant-quota"
+	namespaceDenyPolicyName = "default-deny"
+)
+
+// NamespaceQuota returns the hard limits of the ResourceQuota of a tenant
+// namespace on the cores and memory (in MB) of its apps. Zero values leave a
+// resource unlimited.
+func NamespaceQuota(cores, memory int) apiV1.ResourceList {
+	quota := apiV1.ResourceList{}
+	if cores > 0 {
+		quota[apiV1.ResourceLimitsCPU] = *resource.NewQuantity(int64(cores), resource.DecimalSI)
+	}
+	if memory > 0 {
+		quota[apiV1.ResourceLimitsMemory] = *resource.NewQuantity(int64(1024*1024*memory), resource.BinarySI)
+	}
+	return quota
+}
+
+// namespaceOf returns the namespace an app is deployed to.
+func namespaceOf(app App) string {
+	if app.Namespace == "" {
+		return apiV1.NamespaceDefault
+	}
+	return app.Namespace
+}
+
+// ensureNamespace creates the namespace of a tenant unless it exists. The
+// namespace is isolated by a NetworkPolicy denying all ingress traffic to its
+// pods but the one allowed by the policies of its apps, and limited by a
+// ResourceQuota of NamespaceQuota if it is set. Both are ensured on every call,
+// so that they also apply to the namespaces created before or by others. The
+// default namespace is left as is.
+func (ks *Client) ensureNamespace(name string) error {
+	if name == apiV1.NamespaceDefault {
+		return nil
+	}
+
+	_, err := ks.clientSet.CoreV1().Namespaces().Get(name, metaV1.GetOptions{})
+	switch {
+	case apiErrors.IsNotFound(err):
+		_, err = ks.clientSet.CoreV1().Namespaces().Create(&apiV1.Namespace{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{managedByLabelKey: managedByLabel},
+			},
+		})
+		if err != nil && !apiErrors.IsAlreadyExists(err) {
+			return errors.Wrapf(err, "error creating namespace %s", name)
+		}
+	case err != nil:
+		return errors.Wrapf(err, "error getting namespace %s", name)
+	}
+
+	if err = ks.ensureDenyPolicy(name); err != nil {
+		return errors.Wrapf(err, "error isolating namespace %s", name)
+	}
+	if err = ks.ensureQuota(name); err != nil {
+		return errors.Wrapf(err, "error setting quota of namespace %s", name)
+	}
+	return nil
+}
+
+// ensureDenyPolicy creates the NetworkPolicy denying all ingress traffic to
+// the pods of a namespace, or resets it if it was changed.
+func (ks *Client) ensureDenyPolicy(namespace string) error {
+	spec := networkingV1.NetworkPolicySpec{
+		PodSelector: metaV1.LabelSelector{},
+		PolicyTypes: []networkingV1.PolicyType{networkingV1.PolicyTypeIngress},
+	}
+
+	policies := ks.clientSet.NetworkingV1().NetworkPolicies(namespace)
+	policy, err := policies.Get(namespaceDenyPolicyName, metaV1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		_, err = policies.Create(&networkingV1.NetworkPolicy{
+			ObjectMeta: metaV1.ObjectMeta{Name: namespaceDenyPolicyName},
+			Spec:       spec,
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if reflect.DeepEqual(policy.Spec, spec) {
+		return nil
+	}
+	policy.Spec = spec
+	_, err = policies.Update(policy)
+	return err
+}
+
+// ensureQuota creates the ResourceQuota of a namespace, or updates it to
+// NamespaceQuota if it differs. It does nothing if NamespaceQuota is not set.
+func (ks *Client) ensureQuota(namespace string) error {
+	if len(ks.NamespaceQuota) == 0 {
+		return nil
+	}
+
+	quotas := ks.clientSet.CoreV1().ResourceQuotas(namespace)
+	quota, err := quotas.Get(namespaceQuotaName, metaV1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		_, err = quotas.Create(&apiV1.ResourceQuota{
+			ObjectMeta: metaV1.ObjectMeta{Name: namespaceQuotaName},
+			Spec:       apiV1.ResourceQuotaSpec{Hard: ks.NamespaceQuota},
+		})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	if equalResources(quota.Spec.Hard, ks.NamespaceQuota) {
+		return nil
+	}
+	quota.Spec.Hard = ks.NamespaceQuota
+	_, err = quotas.Update(quota)
+	return err
+}
+
+// equalResources returns whether two resource lists hold the same quantities.
+func equalResources(a, b apiV1.ResourceList) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, qa := range a {
+		qb, ok := b[name]
+		if !ok || qa.Cmp(qb) != 0 {
+			return false
+		}
+	}
+	return true
+}
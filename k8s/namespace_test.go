@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/open-ness/edgecontroller/k8s"
+	apiV1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("K8S namespaces", func() {
+	var (
+		clientSet *fake.Clientset
+		client    *k8s.Client
+		app       k8s.App
+	)
+
+	newClient := func(objects ...runtime.Object) {
+		objects = append(objects, &apiV1.Node{
+			ObjectMeta: metaV1.ObjectMeta{Name: "node", Labels: map[string]string{"node-id": nodeID}},
+		})
+		clientSet = fake.NewSimpleClientset(objects...)
+		client = &k8s.Client{
+			NewClientSet:   func() (kubernetes.Interface, error) { return clientSet, nil },
+			NamespaceQuota: k8s.NamespaceQuota(4, 2048),
+		}
+	}
+
+	getPolicy := func(namespace string) *networkingV1.NetworkPolicy {
+		policy, err := clientSet.NetworkingV1().NetworkPolicies(namespace).Get("default-deny", metaV1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return policy
+	}
+
+	getQuota := func(namespace string) *apiV1.ResourceQuota {
+		quota, err := clientSet.CoreV1().ResourceQuotas(namespace).Get("tenant-quota", metaV1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return quota
+	}
+
+	expectIsolated := func(namespace string) {
+		policy := getPolicy(namespace)
+		Expect(policy.Spec.PodSelector).To(Equal(metaV1.LabelSelector{}))
+		Expect(policy.Spec.Ingress).To(BeEmpty())
+		Expect(policy.Spec.PolicyTypes).To(Equal([]networkingV1.PolicyType{networkingV1.PolicyTypeIngress}))
+
+		hard := getQuota(namespace).Spec.Hard
+		Expect(hard).To(HaveLen(2))
+		cores, memory := hard[apiV1.ResourceLimitsCPU], hard[apiV1.ResourceLimitsMemory]
+		Expect(cores.Cmp(resource.MustParse("4"))).To(BeZero())
+		Expect(memory.Cmp(resource.MustParse("2Gi"))).To(BeZero())
+	}
+
+	BeforeEach(func() {
+		app = k8s.App{
+			ID:        appID,
+			Image:     appID + ":latest",
+			Cores:     1,
+			Memory:    512,
+			Namespace: "tenant-a",
+		}
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	It("Should create an isolated namespace for a tenant", func() {
+		newClient()
+		Expect(client.Deploy(context.Background(), nodeID, app)).To(Succeed())
+
+		namespace, err := clientSet.CoreV1().Namespaces().Get("tenant-a", metaV1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(namespace.Labels).To(Equal(map[string]string{"managed-by": "edgecontroller"}))
+		expectIsolated("tenant-a")
+	})
+
+	It("Should isolate a namespace that already exists", func() {
+		newClient(&apiV1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "tenant-a"}})
+		Expect(client.Deploy(context.Background(), nodeID, app)).To(Succeed())
+
+		namespace, err := clientSet.CoreV1().Namespaces().Get("tenant-a", metaV1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(namespace.Labels).To(BeEmpty())
+		expectIsolated("tenant-a")
+	})
+
+	It("Should reset a changed policy and quota", func() {
+		newClient(
+			&apiV1.Namespace{ObjectMeta: metaV1.ObjectMeta{Name: "tenant-a"}},
+			&networkingV1.NetworkPolicy{
+				ObjectMeta: metaV1.ObjectMeta{Name: "default-deny", Namespace: "tenant-a"},
+				Spec: networkingV1.NetworkPolicySpec{
+					PodSelector: metaV1.LabelSelector{},
+					Ingress:     []networkingV1.NetworkPolicyIngressRule{{}},
+					PolicyTypes: []networkingV1.PolicyType{networkingV1.PolicyTypeIngress},
+				},
+			},
+			&apiV1.ResourceQuota{
+				ObjectMeta: metaV1.ObjectMeta{Name: "tenant-quota", Namespace: "tenant-a"},
+				Spec: apiV1.ResourceQuotaSpec{Hard: apiV1.ResourceList{
+					apiV1.ResourceLimitsCPU: resource.MustParse("64"),
+				}},
+			},
+		)
+		Expect(client.Deploy(context.Background(), nodeID, app)).To(Succeed())
+
+		expectIsolated("tenant-a")
+	})
+
+	It("Should not set a quota unless one is configured", func() {
+		newClient()
+		client.NamespaceQuota = nil
+		Expect(client.Deploy(context.Background(), nodeID, app)).To(Succeed())
+
+		getPolicy("tenant-a")
+		_, err := clientSet.CoreV1().ResourceQuotas("tenant-a").Get("tenant-quota", metaV1.GetOptions{})
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("Should leave the default namespace as is", func() {
+		newClient()
+		app.Namespace = ""
+		Expect(client.Deploy(context.Background(), nodeID, app)).To(Succeed())
+
+		policies, err := clientSet.NetworkingV1().NetworkPolicies(apiV1.NamespaceDefault).List(metaV1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(policies.Items).To(BeEmpty())
+	})
+})
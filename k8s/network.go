@@ -0,0 +1,150 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	apiV1 "k8s.io/api/core/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// Annotation of a pod listing the Multus networks it is attached to
+	networksAnnotation = "k8s.v1.cni.cncf.io/networks"
+	// Annotation of a pod in which Multus reports its interfaces
+	networkStatusAnnotation = "k8s.v1.cni.cncf.io/networks-status"
+)
+
+var networkAttachmentDefinitions = schema.GroupVersionResource{
+	Group:    "k8s.cni.cncf.io",
+	Version:  "v1",
+	Resource: "network-attachment-definitions",
+}
+
+// NetworkAttachment attaches a pod to a network besides the default pod
+// network, through a Multus NetworkAttachmentDefinition. Name is
+// [<namespace>/]<name>, in the namespace of the pod by default. Multus
+// names the interface if Interface is empty.
+type NetworkAttachment struct {
+	Name      string
+	Interface string
+	IPs       []string // in CIDR notation
+	MAC       string
+}
+
+// NetworkStatus is an interface of a pod, as reported by Multus.
+type NetworkStatus struct {
+	Name      string   `json:"name"`
+	Interface string   `json:"interface,omitempty"`
+	IPs       []string `json:"ips,omitempty"`
+	MAC       string   `json:"mac,omitempty"`
+	Default   bool     `json:"default,omitempty"`
+}
+
+// networkSelection is an element of the Multus networks annotation.
+type networkSelection struct {
+	Name      string   `json:"name"`
+	Namespace string   `json:"namespace,omitempty"`
+	Interface string   `json:"interface,omitempty"`
+	IPs       []string `json:"ips,omitempty"`
+	MAC       string   `json:"mac,omitempty"`
+}
+
+// namespacedName splits the name of the NetworkAttachmentDefinition of a
+// network attached to a pod in namespace.
+func (na NetworkAttachment) namespacedName(namespace string) (string, string) {
+	if i := strings.Index(na.Name, "/"); i >= 0 {
+		return na.Name[:i], na.Name[i+1:]
+	}
+	return namespace, na.Name
+}
+
+// toNetworksAnnotation renders the Multus networks annotation of a pod.
+func toNetworksAnnotation(networks []NetworkAttachment) (string, error) {
+	var selections []networkSelection
+	for _, network := range networks {
+		namespace, name := network.namespacedName("")
+		selections = append(selections, networkSelection{
+			Name:      name,
+			Namespace: namespace,
+			Interface: network.Interface,
+			IPs:       network.IPs,
+			MAC:       network.MAC,
+		})
+	}
+	annotation, err := json.Marshal(selections)
+	return string(annotation), errors.Wrap(err, "error rendering networks annotation")
+}
+
+// checkNetworks checks that the NetworkAttachmentDefinitions of the networks
+// attached to a pod in namespace exist, in namespace or in one of
+// NetworkNamespaces.
+func (ks *Client) checkNetworks(namespace string, networks []NetworkAttachment) error {
+	for _, network := range networks {
+		nadNamespace, name := network.namespacedName(namespace)
+		if !ks.allowedNetworkNamespace(namespace, nadNamespace) {
+			return errors.Errorf("network attachment definition %s/%s not allowed in namespace %s",
+				nadNamespace, name, namespace)
+		}
+		_, err := ks.dynamicClient.Resource(networkAttachmentDefinitions).Namespace(nadNamespace).
+			Get(name, metaV1.GetOptions{})
+		if apiErrors.IsNotFound(err) {
+			return errors.Errorf("network attachment definition %s/%s not found", nadNamespace, name)
+		}
+		if err != nil {
+			return errors.Wrapf(err, "error getting network attachment definition %s/%s", nadNamespace, name)
+		}
+	}
+	return nil
+}
+
+// allowedNetworkNamespace returns whether a pod in namespace can attach to the
+// NetworkAttachmentDefinitions of nadNamespace.
+func (ks *Client) allowedNetworkNamespace(namespace, nadNamespace string) bool {
+	if nadNamespace == namespace {
+		return true
+	}
+	for _, ns := range ks.NetworkNamespaces {
+		if nadNamespace == ns {
+			return true
+		}
+	}
+	return false
+}
+
+// Networks gets the interfaces of the pods of an app from the cache, as
+// reported by Multus.
+func (ks *Client) Networks(ctx context.Context, nodeID, appID string) ([]NetworkStatus, error) {
+	if err := ks.connect(); err != nil {
+		return nil, err
+	}
+
+	pods, err := ks.pods.List(appSelector(nodeID, appID))
+	if err != nil {
+		return nil, errors.Wrapf(err, "error getting pods of app %s on node %s", appID, nodeID)
+	}
+
+	var statuses []NetworkStatus
+	for _, pod := range pods {
+		if pod.Status.Phase != apiV1.PodRunning {
+			continue
+		}
+		annotation, ok := pod.Annotations[networkStatusAnnotation]
+		if !ok {
+			continue
+		}
+		var podStatuses []NetworkStatus
+		if err = json.Unmarshal([]byte(annotation), &podStatuses); err != nil {
+			return nil, errors.Wrapf(err, "error parsing network status of pod %s", pod.Name)
+		}
+		statuses = append(statuses, podStatuses...)
+	}
+	return statuses, nil
+}
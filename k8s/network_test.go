@@ -0,0 +1,155 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/open-ness/edgecontroller/k8s"
+	appsV1 "k8s.io/api/apps/v1"
+	apiV1 "k8s.io/api/core/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("K8S networks", func() {
+	var (
+		clientSet     *fake.Clientset
+		dynamicClient *dynamicFake.FakeDynamicClient
+		client        *k8s.Client
+
+		nads = schema.GroupVersionResource{
+			Group:    "k8s.cni.cncf.io",
+			Version:  "v1",
+			Resource: "network-attachment-definitions",
+		}
+	)
+
+	createNAD := func(namespace, name string) {
+		_, err := dynamicClient.Resource(nads).Namespace(namespace).Create(&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "k8s.cni.cncf.io/v1",
+				"kind":       "NetworkAttachmentDefinition",
+				"metadata":   map[string]interface{}{"name": name, "namespace": namespace},
+			},
+		}, metaV1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	BeforeEach(func() {
+		clientSet = fake.NewSimpleClientset(&apiV1.Node{
+			ObjectMeta: metaV1.ObjectMeta{Name: "node", Labels: map[string]string{"node-id": nodeID}},
+		})
+		dynamicClient = dynamicFake.NewSimpleDynamicClient(runtime.NewScheme())
+		client = &k8s.Client{
+			NewClientSet:     func() (kubernetes.Interface, error) { return clientSet, nil },
+			NewDynamicClient: func() (dynamic.Interface, error) { return dynamicClient, nil },
+		}
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	It("Should attach an app to networks with static addresses", func() {
+		createNAD("tenant-a", "sriov-net")
+		createNAD("kube-ovn", "ovn-subnet")
+		client.NetworkNamespaces = []string{"kube-ovn"}
+
+		Expect(client.Deploy(context.Background(), nodeID, k8s.App{
+			ID:        appID,
+			Image:     appID + ":latest",
+			Cores:     1,
+			Memory:    512,
+			Namespace: "tenant-a",
+			Networks: []k8s.NetworkAttachment{
+				{Name: "sriov-net", Interface: "net1", IPs: []string{"10.10.10.2/24"}, MAC: "02:00:00:00:00:01"},
+				{Name: "kube-ovn/ovn-subnet"},
+			},
+		})).To(Succeed())
+
+		list, err := clientSet.AppsV1().Deployments("tenant-a").List(metaV1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list.Items).To(HaveLen(1))
+		Expect(list.Items[0].Spec.Template.Annotations).To(HaveKeyWithValue("k8s.v1.cni.cncf.io/networks",
+			`[{"name":"sriov-net","interface":"net1","ips":["10.10.10.2/24"],"mac":"02:00:00:00:00:01"},`+
+				`{"name":"ovn-subnet","namespace":"kube-ovn"}]`))
+	})
+
+	It("Should not deploy an app attached to an unknown network", func() {
+		createNAD("default", "sriov-net")
+
+		err := client.Deploy(context.Background(), nodeID, k8s.App{
+			ID:        appID,
+			Image:     appID + ":latest",
+			Cores:     1,
+			Memory:    512,
+			Namespace: "tenant-a",
+			Networks:  []k8s.NetworkAttachment{{Name: "sriov-net"}},
+		})
+		Expect(err).To(MatchError(
+			"deploy: deployment error: network attachment definition tenant-a/sriov-net not found"))
+
+		list, err := clientSet.AppsV1().Deployments(metaV1.NamespaceAll).List(metaV1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list.Items).To(BeEmpty())
+	})
+
+	It("Should not deploy an app attached to a network of another namespace", func() {
+		createNAD("tenant-b", "sriov-net")
+
+		err := client.Deploy(context.Background(), nodeID, k8s.App{
+			ID:        appID,
+			Image:     appID + ":latest",
+			Cores:     1,
+			Memory:    512,
+			Namespace: "tenant-a",
+			Networks:  []k8s.NetworkAttachment{{Name: "tenant-b/sriov-net"}},
+		})
+		Expect(err).To(MatchError("deploy: deployment error: " +
+			"network attachment definition tenant-b/sriov-net not allowed in namespace tenant-a"))
+
+		list, err := clientSet.AppsV1().Deployments(metaV1.NamespaceAll).List(metaV1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list.Items).To(BeEmpty())
+	})
+
+	It("Should report the interfaces of the pods of an app", func() {
+		labels := map[string]string{"app-id": appID, "node-id": nodeID}
+		_, err := clientSet.AppsV1().Deployments("default").Create(&appsV1.Deployment{
+			ObjectMeta: metaV1.ObjectMeta{Name: "app", Namespace: "default", Labels: labels},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		_, err = clientSet.CoreV1().Pods("default").Create(&apiV1.Pod{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:      "app-1",
+				Namespace: "default",
+				Labels:    labels,
+				Annotations: map[string]string{
+					"k8s.v1.cni.cncf.io/networks-status": `[` +
+						`{"name":"kube-ovn","interface":"eth0","ips":["10.16.0.10"],"default":true},` +
+						`{"name":"sriov-net","interface":"net1","ips":["10.10.10.2"],"mac":"02:00:00:00:00:01"}]`,
+				},
+			},
+			Status: apiV1.PodStatus{Phase: apiV1.PodRunning},
+		})
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() ([]k8s.NetworkStatus, error) {
+			return client.Networks(context.Background(), nodeID, appID)
+		}).Should(Equal([]k8s.NetworkStatus{
+			{Name: "kube-ovn", Interface: "eth0", IPs: []string{"10.16.0.10"}, Default: true},
+			{Name: "sriov-net", Interface: "net1", IPs: []string{"10.10.10.2"}, MAC: "02:00:00:00:00:01"},
+		}))
+	})
+})
@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s
+
+import (
+	"strings"
+
+	apiV1 "k8s.io/api/core/v1"
+)
+
+const (
+	hugepagesVolume    = "hugepages"
+	hugepagesMountPath = "/hugepages"
+)
+
+// isHugepages reports whether name is a hugepages resource, e.g. hugepages-1Gi.
+func isHugepages(name apiV1.ResourceName) bool {
+	return strings.HasPrefix(string(name), apiV1.ResourceHugePagesPrefix)
+}
+
+// setPlatform sets the resources of a container to the cores and memory of
+// the app and the device-plugin resources and hugepages it requests. Pinned
+// apps request exactly what they are limited to, which gives their pod the
+// Guaranteed QoS class and exclusive cores with the static CPU manager
+// policy. It attaches the pod to the Multus networks of the app, and mounts
+// hugepages at /hugepages. It must be called after configure, which resets
+// the volumes of the pod.
+func setPlatform(container *apiV1.Container, template *apiV1.PodTemplateSpec, app App) error {
+	limits := toResourceLimits(app)
+	hugepages := false
+	for name, quantity := range app.Resources {
+		limits[name] = quantity
+		hugepages = hugepages || isHugepages(name)
+	}
+	container.Resources = apiV1.ResourceRequirements{Limits: limits}
+	if app.PinnedCPUs {
+		container.Resources.Requests = limits.DeepCopy()
+	}
+
+	if len(app.Networks) > 0 {
+		annotation, err := toNetworksAnnotation(app.Networks)
+		if err != nil {
+			return err
+		}
+		if template.Annotations == nil {
+			template.Annotations = make(map[string]string)
+		}
+		template.Annotations[networksAnnotation] = annotation
+	} else {
+		delete(template.Annotations, networksAnnotation)
+	}
+
+	if !hugepages {
+		return nil
+	}
+	pod := &template.Spec
+	for _, volume := range pod.Volumes {
+		if volume.Name == hugepagesVolume {
+			return nil
+		}
+	}
+	pod.Volumes = append(pod.Volumes, apiV1.Volume{
+		Name: hugepagesVolume,
+		VolumeSource: apiV1.VolumeSource{
+			EmptyDir: &apiV1.EmptyDirVolumeSource{Medium: apiV1.StorageMediumHugePages},
+		},
+	})
+	container.VolumeMounts = append(container.VolumeMounts, apiV1.VolumeMount{
+		Name:      hugepagesVolume,
+		MountPath: hugepagesMountPath,
+	})
+	// Hugepages are locked in memory
+	if container.SecurityContext == nil {
+		container.SecurityContext = &apiV1.SecurityContext{}
+	}
+	if container.SecurityContext.Capabilities == nil {
+		container.SecurityContext.Capabilities = &apiV1.Capabilities{}
+	}
+	for _, capability := range container.SecurityContext.Capabilities.Add {
+		if capability == "IPC_LOCK" {
+			return nil
+		}
+	}
+	container.SecurityContext.Capabilities.Add = append(container.SecurityContext.Capabilities.Add, "IPC_LOCK")
+	return nil
+}
@@ -0,0 +1,123 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/open-ness/edgecontroller/k8s"
+	appsV1 "k8s.io/api/apps/v1"
+	apiV1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	dynamicFake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("K8S platform", func() {
+	var (
+		clientSet *fake.Clientset
+		client    *k8s.Client
+		app       k8s.App
+	)
+
+	getDeployment := func() *appsV1.Deployment {
+		list, err := clientSet.AppsV1().Deployments(metaV1.NamespaceAll).List(metaV1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(list.Items).To(HaveLen(1))
+		return &list.Items[0]
+	}
+
+	BeforeEach(func() {
+		clientSet = fake.NewSimpleClientset(&apiV1.Node{
+			ObjectMeta: metaV1.ObjectMeta{Name: "node", Labels: map[string]string{"node-id": nodeID}},
+		})
+		dynamicClient := dynamicFake.NewSimpleDynamicClient(runtime.NewScheme())
+		_, err := dynamicClient.Resource(schema.GroupVersionResource{
+			Group:    "k8s.cni.cncf.io",
+			Version:  "v1",
+			Resource: "network-attachment-definitions",
+		}).Namespace("default").Create(&unstructured.Unstructured{
+			Object: map[string]interface{}{
+				"apiVersion": "k8s.cni.cncf.io/v1",
+				"kind":       "NetworkAttachmentDefinition",
+				"metadata":   map[string]interface{}{"name": "sriov-net", "namespace": "default"},
+			},
+		}, metaV1.CreateOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		client = &k8s.Client{
+			NewClientSet:     func() (kubernetes.Interface, error) { return clientSet, nil },
+			NewDynamicClient: func() (dynamic.Interface, error) { return dynamicClient, nil },
+		}
+		app = k8s.App{
+			ID:     appID,
+			Image:  appID + ":latest",
+			Cores:  2,
+			Memory: 512,
+			Resources: apiV1.ResourceList{
+				"intel.com/intel_fec_5g": resource.MustParse("1"),
+				"hugepages-1Gi":          resource.MustParse("2Gi"),
+			},
+			PinnedCPUs: true,
+			Networks:   []k8s.NetworkAttachment{{Name: "sriov-net", Interface: "net1"}},
+		}
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	It("Should request the platform resources of an app", func() {
+		Expect(client.Deploy(context.Background(), nodeID, app)).To(Succeed())
+
+		template := getDeployment().Spec.Template
+		Expect(template.Annotations).To(HaveKeyWithValue(
+			"k8s.v1.cni.cncf.io/networks", `[{"name":"sriov-net","interface":"net1"}]`))
+
+		container := template.Spec.Containers[0]
+		Expect(container.Resources.Limits).To(Equal(apiV1.ResourceList{
+			apiV1.ResourceCPU:        *resource.NewQuantity(2, resource.DecimalSI),
+			apiV1.ResourceMemory:     *resource.NewQuantity(512*1024*1024, resource.BinarySI),
+			"intel.com/intel_fec_5g": resource.MustParse("1"),
+			"hugepages-1Gi":          resource.MustParse("2Gi"),
+		}))
+		Expect(container.Resources.Requests).To(Equal(container.Resources.Limits))
+		Expect(container.SecurityContext.Capabilities.Add).To(ConsistOf(
+			apiV1.Capability("NET_ADMIN"), apiV1.Capability("IPC_LOCK")))
+		Expect(container.VolumeMounts).To(ContainElement(apiV1.VolumeMount{Name: "hugepages", MountPath: "/hugepages"}))
+		Expect(template.Spec.Volumes).To(ContainElement(apiV1.Volume{
+			Name: "hugepages",
+			VolumeSource: apiV1.VolumeSource{
+				EmptyDir: &apiV1.EmptyDirVolumeSource{Medium: apiV1.StorageMediumHugePages},
+			},
+		}))
+	})
+
+	It("Should drop the platform resources of an app on upgrade", func() {
+		ctx := context.Background()
+		Expect(client.Deploy(ctx, nodeID, app)).To(Succeed())
+
+		Expect(client.Upgrade(ctx, nodeID, appID, k8s.App{
+			ID:     appID,
+			Image:  appID + ":2",
+			Cores:  2,
+			Memory: 512,
+		})).To(Succeed())
+
+		template := getDeployment().Spec.Template
+		Expect(template.Annotations).ToNot(HaveKey("k8s.v1.cni.cncf.io/networks"))
+		container := template.Spec.Containers[0]
+		Expect(container.Resources.Limits).To(HaveLen(2))
+		Expect(container.Resources.Requests).To(BeNil())
+		Expect(template.Spec.Volumes).To(BeEmpty())
+	})
+})
@@ -0,0 +1,280 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/pkg/errors"
+	apiV1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	apiErrors "k8s.io/apimachinery/pkg/api/errors"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// Service exposes the ports of an app at a stable address. Type is ClusterIP
+// or NodePort. The ports are also exposed at ExternalIPs, if any, which must
+// be in the ExternalIPRanges of the client.
+type Service struct {
+	Type        apiV1.ServiceType
+	ExternalIPs []string
+}
+
+// Endpoint is an address at which a port of an app is reachable.
+type Endpoint struct {
+	IP       string
+	Port     int32
+	Protocol string
+}
+
+// errNoServicePorts is returned for an app exposed by a Service without
+// ports to expose.
+var errNoServicePorts = errors.New("service: app exposes no ports")
+
+// getService gets the Service of an app, or nil if it has none.
+func (ks *Client) getService(nodeID, appID string) (*apiV1.Service, error) {
+	services, err := ks.services.List(appSelector(nodeID, appID))
+	if err != nil {
+		return nil, errors.Wrap(err, "error getting list of services")
+	}
+	if len(services) == 0 {
+		// The service may be yet to be cached
+		list, err := ks.clientSet.CoreV1().Services(metaV1.NamespaceAll).List(metaV1.ListOptions{
+			LabelSelector: appSelector(nodeID, appID).String(),
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, "error getting list of services")
+		}
+		for i := range list.Items {
+			services = append(services, &list.Items[i])
+		}
+	}
+
+	switch len(services) {
+	case 0:
+		return nil, nil
+	case 1:
+		// Objects of the cache must not be modified
+		return services[0].DeepCopy(), nil
+	default:
+		return nil, errors.New("more than one service found")
+	}
+}
+
+// checkExternalIPs checks that the external IPs of a service are in the
+// ExternalIPRanges, as they let the service intercept the traffic of the
+// cluster to these addresses.
+func (ks *Client) checkExternalIPs(externalIPs []string) error {
+	for _, s := range externalIPs {
+		ip := net.ParseIP(s)
+		allowed := false
+		for _, ipNet := range ks.ExternalIPRanges {
+			if ip != nil && ipNet.Contains(ip) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return errors.Errorf("service: external ip %s not allowed", s)
+		}
+	}
+	return nil
+}
+
+// applyService creates or updates the Service exposing the ports of the app
+// deployed as appID, or deletes it if the app has none.
+func (ks *Client) applyService(namespace, nodeID, appID string, app App) error {
+	existing, err := ks.getService(nodeID, appID)
+	if err != nil {
+		return err
+	}
+	if app.Service == nil {
+		return ks.deleteService(existing)
+	}
+
+	if err = ks.checkExternalIPs(app.Service.ExternalIPs); err != nil {
+		return err
+	}
+	ports, err := toContainerPorts(app.Ports)
+	if err != nil {
+		return err
+	}
+	if len(ports) == 0 {
+		return errNoServicePorts
+	}
+	var servicePorts []apiV1.ServicePort
+	for _, port := range ports {
+		servicePorts = append(servicePorts, apiV1.ServicePort{
+			// Ports of a multi-port service must be named
+			Name:     fmt.Sprintf("%s-%d", strings.ToLower(string(port.Protocol)), port.ContainerPort),
+			Protocol: port.Protocol,
+			Port:     port.ContainerPort,
+		})
+	}
+
+	labels := map[string]string{
+		appIDLabelKey:  appID,
+		nodeIDLabelKey: nodeID,
+	}
+	spec := apiV1.ServiceSpec{
+		Type:        app.Service.Type,
+		Selector:    labels,
+		Ports:       servicePorts,
+		ExternalIPs: app.Service.ExternalIPs,
+	}
+
+	if existing == nil {
+		_, err = ks.clientSet.CoreV1().Services(namespace).Create(&apiV1.Service{
+			ObjectMeta: metaV1.ObjectMeta{
+				GenerateName: "app-",
+				Labels:       labels,
+			},
+			Spec: spec,
+		})
+		if err != nil {
+			return errors.Wrap(err, "error creating service")
+		}
+		return ks.applyServicePolicy(namespace, nodeID, appID, servicePorts)
+	}
+
+	// The cluster IP of a service is immutable
+	spec.ClusterIP = existing.Spec.ClusterIP
+	// Keep the node ports allocated to the ports still exposed
+	for i := range spec.Ports {
+		for _, port := range existing.Spec.Ports {
+			if port.Name == spec.Ports[i].Name && spec.Type == apiV1.ServiceTypeNodePort {
+				spec.Ports[i].NodePort = port.NodePort
+			}
+		}
+	}
+	existing.Spec = spec
+	if _, err = ks.clientSet.CoreV1().Services(existing.Namespace).Update(existing); err != nil {
+		return errors.Wrap(err, "error updating service")
+	}
+	return ks.applyServicePolicy(existing.Namespace, nodeID, appID, servicePorts)
+}
+
+// deleteService deletes the Service of an app if there is one, with the
+// NetworkPolicy letting traffic reach its ports.
+func (ks *Client) deleteService(service *apiV1.Service) error {
+	if service == nil {
+		return nil
+	}
+	err := ks.clientSet.CoreV1().Services(service.Namespace).Delete(service.Name, &metaV1.DeleteOptions{})
+	if err != nil {
+		return errors.Wrap(err, "error deleting service")
+	}
+
+	err = ks.clientSet.NetworkingV1().NetworkPolicies(service.Namespace).Delete(
+		servicePolicyName(service.Labels[nodeIDLabelKey], service.Labels[appIDLabelKey]),
+		&metaV1.DeleteOptions{})
+	if err != nil && !apiErrors.IsNotFound(err) {
+		return errors.Wrap(err, "error deleting service network policy")
+	}
+	return nil
+}
+
+// servicePolicyName is the name of the NetworkPolicy letting traffic reach the
+// ports of the Service of an app deployed to a node.
+func servicePolicyName(nodeID, appID string) string {
+	return fmt.Sprintf("svc-%s.%s", nodeID, appID)
+}
+
+// applyServicePolicy creates or updates the NetworkPolicy letting any traffic
+// reach the ports of the Service of an app through the policy denying all
+// ingress traffic to a tenant namespace. The policy is not labeled with the
+// app, which is the label of the policy set by the traffic policy of the app.
+// Pods of the default namespace are not isolated, so they have no such policy.
+func (ks *Client) applyServicePolicy(namespace, nodeID, appID string, ports []apiV1.ServicePort) error {
+	if namespace == apiV1.NamespaceDefault {
+		return nil
+	}
+
+	var policyPorts []networkingV1.NetworkPolicyPort
+	for i := range ports {
+		port := intstr.FromInt(int(ports[i].Port))
+		policyPorts = append(policyPorts, networkingV1.NetworkPolicyPort{
+			Protocol: &ports[i].Protocol,
+			Port:     &port,
+		})
+	}
+	spec := networkingV1.NetworkPolicySpec{
+		PodSelector: metaV1.LabelSelector{
+			MatchLabels: map[string]string{appIDLabelKey: appID, nodeIDLabelKey: nodeID},
+		},
+		Ingress:     []networkingV1.NetworkPolicyIngressRule{{Ports: policyPorts}},
+		PolicyTypes: []networkingV1.PolicyType{networkingV1.PolicyTypeIngress},
+	}
+
+	policies := ks.clientSet.NetworkingV1().NetworkPolicies(namespace)
+	policy, err := policies.Get(servicePolicyName(nodeID, appID), metaV1.GetOptions{})
+	if apiErrors.IsNotFound(err) {
+		_, err = policies.Create(&networkingV1.NetworkPolicy{
+			ObjectMeta: metaV1.ObjectMeta{
+				Name:   servicePolicyName(nodeID, appID),
+				Labels: map[string]string{managedByLabelKey: managedByLabel},
+			},
+			Spec: spec,
+		})
+		return errors.Wrap(err, "error creating service network policy")
+	}
+	if err != nil {
+		return errors.Wrap(err, "error getting service network policy")
+	}
+	policy.Spec = spec
+	_, err = policies.Update(policy)
+	return errors.Wrap(err, "error updating service network policy")
+}
+
+// Endpoints gets the addresses at which the Service of an app exposes its
+// ports from the cache: its cluster IP, its external IPs and, for a NodePort
+// service, the internal IP of the node.
+func (ks *Client) Endpoints(ctx context.Context, nodeID, appID string) ([]Endpoint, error) {
+	if err := ks.connect(); err != nil {
+		return nil, err
+	}
+
+	service, err := ks.getService(nodeID, appID)
+	if err != nil || service == nil {
+		return nil, err
+	}
+
+	var endpoints []Endpoint
+	add := func(ip string, port int32, protocol apiV1.Protocol) {
+		if ip == "" || ip == apiV1.ClusterIPNone || port == 0 {
+			return
+		}
+		endpoints = append(endpoints, Endpoint{IP: ip, Port: port, Protocol: strings.ToLower(string(protocol))})
+	}
+	for _, port := range service.Spec.Ports {
+		add(service.Spec.ClusterIP, port.Port, port.Protocol)
+		for _, ip := range service.Spec.ExternalIPs {
+			add(ip, port.Port, port.Protocol)
+		}
+	}
+	if service.Spec.Type != apiV1.ServiceTypeNodePort {
+		return endpoints, nil
+	}
+
+	nodes, err := ks.nodes.List(labels.SelectorFromSet(labels.Set{nodeIDLabelKey: nodeID}))
+	if err != nil {
+		return nil, errors.Wrap(err, "get kubernetes node list error")
+	}
+	for _, node := range nodes {
+		for _, address := range node.Status.Addresses {
+			if address.Type != apiV1.NodeInternalIP {
+				continue
+			}
+			for _, port := range service.Spec.Ports {
+				add(address.Address, port.NodePort, port.Protocol)
+			}
+		}
+	}
+	return endpoints, nil
+}
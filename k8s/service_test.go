@@ -0,0 +1,174 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package k8s_test
+
+import (
+	"context"
+	"net"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"github.com/open-ness/edgecontroller/k8s"
+	apiV1 "k8s.io/api/core/v1"
+	networkingV1 "k8s.io/api/networking/v1"
+	metaV1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var _ = Describe("K8S services", func() {
+	var (
+		clientSet *fake.Clientset
+		client    *k8s.Client
+		app       k8s.App
+	)
+
+	getServices := func() []apiV1.Service {
+		list, err := clientSet.CoreV1().Services(metaV1.NamespaceAll).List(metaV1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return list.Items
+	}
+
+	getPolicies := func(namespace string) []networkingV1.NetworkPolicy {
+		list, err := clientSet.NetworkingV1().NetworkPolicies(namespace).List(metaV1.ListOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return list.Items
+	}
+
+	getPolicy := func(namespace, name string) *networkingV1.NetworkPolicy {
+		policy, err := clientSet.NetworkingV1().NetworkPolicies(namespace).Get(name, metaV1.GetOptions{})
+		Expect(err).ToNot(HaveOccurred())
+		return policy
+	}
+
+	BeforeEach(func() {
+		clientSet = fake.NewSimpleClientset(&apiV1.Node{
+			ObjectMeta: metaV1.ObjectMeta{Name: "node", Labels: map[string]string{"node-id": nodeID}},
+			Status: apiV1.NodeStatus{
+				Addresses: []apiV1.NodeAddress{
+					{Type: apiV1.NodeHostName, Address: "node"},
+					{Type: apiV1.NodeInternalIP, Address: "192.168.1.10"},
+				},
+			},
+		})
+		_, externalIPs, err := net.ParseCIDR("10.0.0.0/24")
+		Expect(err).ToNot(HaveOccurred())
+		client = &k8s.Client{
+			NewClientSet:     func() (kubernetes.Interface, error) { return clientSet, nil },
+			ExternalIPRanges: []*net.IPNet{externalIPs},
+		}
+		app = k8s.App{
+			ID:        appID,
+			Image:     appID + ":latest",
+			Cores:     1,
+			Memory:    512,
+			Namespace: "tenant-a",
+			Ports:     []*k8s.PortProto{{Port: 80, Protocol: "tcp"}, {Port: 5000, Protocol: "udp"}},
+			Service: &k8s.Service{
+				Type:        apiV1.ServiceTypeNodePort,
+				ExternalIPs: []string{"10.0.0.1"},
+			},
+		}
+	})
+
+	AfterEach(func() {
+		client.Close()
+	})
+
+	It("Should expose the ports of an app and report its endpoints", func() {
+		ctx := context.Background()
+		Expect(client.Deploy(ctx, nodeID, app)).To(Succeed())
+
+		services := getServices()
+		Expect(services).To(HaveLen(1))
+		service := services[0]
+		Expect(service.Namespace).To(Equal("tenant-a"))
+		Expect(service.Spec.Type).To(Equal(apiV1.ServiceTypeNodePort))
+		Expect(service.Spec.Selector).To(Equal(map[string]string{"app-id": appID, "node-id": nodeID}))
+		Expect(service.Spec.ExternalIPs).To(Equal([]string{"10.0.0.1"}))
+		Expect(service.Spec.Ports).To(Equal([]apiV1.ServicePort{
+			{Name: "tcp-80", Protocol: apiV1.ProtocolTCP, Port: 80},
+			{Name: "udp-5000", Protocol: apiV1.ProtocolUDP, Port: 5000},
+		}))
+
+		By("Reporting the addresses allocated to the service")
+		service.Spec.ClusterIP = "10.96.0.20"
+		service.Spec.Ports[0].NodePort = 30080
+		service.Spec.Ports[1].NodePort = 30500
+		_, err := clientSet.CoreV1().Services("tenant-a").Update(&service)
+		Expect(err).ToNot(HaveOccurred())
+
+		Eventually(func() ([]k8s.Endpoint, error) {
+			return client.Endpoints(ctx, nodeID, appID)
+		}).Should(Equal([]k8s.Endpoint{
+			{IP: "10.96.0.20", Port: 80, Protocol: "tcp"},
+			{IP: "10.0.0.1", Port: 80, Protocol: "tcp"},
+			{IP: "10.96.0.20", Port: 5000, Protocol: "udp"},
+			{IP: "10.0.0.1", Port: 5000, Protocol: "udp"},
+			{IP: "192.168.1.10", Port: 30080, Protocol: "tcp"},
+			{IP: "192.168.1.10", Port: 30500, Protocol: "udp"},
+		}))
+
+		By("Keeping the addresses of the service on upgrade")
+		upgraded := app
+		upgraded.Ports = app.Ports[:1]
+		Expect(client.Upgrade(ctx, nodeID, appID, upgraded)).To(Succeed())
+		services = getServices()
+		Expect(services).To(HaveLen(1))
+		Expect(services[0].Spec.ClusterIP).To(Equal("10.96.0.20"))
+		Expect(services[0].Spec.Ports).To(Equal([]apiV1.ServicePort{
+			{Name: "tcp-80", Protocol: apiV1.ProtocolTCP, Port: 80, NodePort: 30080},
+		}))
+
+		By("Deleting the service on undeploy")
+		Expect(client.Undeploy(ctx, nodeID, appID)).To(Succeed())
+		Expect(getServices()).To(BeEmpty())
+		policies := getPolicies("tenant-a")
+		Expect(policies).To(HaveLen(1))
+		Expect(policies[0].Name).To(Equal("default-deny"))
+	})
+
+	It("Should let traffic reach the ports of a service in a tenant namespace", func() {
+		Expect(client.Deploy(context.Background(), nodeID, app)).To(Succeed())
+
+		policy := getPolicy("tenant-a", "svc-"+nodeID+"."+appID)
+		Expect(policy.Spec.PodSelector.MatchLabels).To(Equal(map[string]string{"app-id": appID, "node-id": nodeID}))
+		Expect(policy.Spec.Ingress).To(HaveLen(1))
+		Expect(policy.Spec.Ingress[0].From).To(BeEmpty())
+		ports := policy.Spec.Ingress[0].Ports
+		Expect(ports).To(HaveLen(2))
+		Expect(*ports[0].Protocol).To(Equal(apiV1.ProtocolTCP))
+		Expect(ports[0].Port.IntValue()).To(Equal(80))
+		Expect(*ports[1].Protocol).To(Equal(apiV1.ProtocolUDP))
+		Expect(ports[1].Port.IntValue()).To(Equal(5000))
+
+		By("Not isolating the pods of the default namespace")
+		Expect(client.Undeploy(context.Background(), nodeID, appID)).To(Succeed())
+		app.Namespace = ""
+		Expect(client.Deploy(context.Background(), nodeID, app)).To(Succeed())
+		Expect(getPolicies("default")).To(BeEmpty())
+	})
+
+	It("Should not expose an app at an external IP out of the allowed ranges", func() {
+		app.Service.ExternalIPs = []string{"10.0.1.1"}
+		Expect(client.Deploy(context.Background(), nodeID, app)).To(
+			MatchError("deploy: deployment error: service: external ip 10.0.1.1 not allowed"))
+		Expect(getServices()).To(BeEmpty())
+	})
+
+	It("Should not expose an app without ports", func() {
+		app.Ports = nil
+		Expect(client.Deploy(context.Background(), nodeID, app)).To(
+			MatchError("deploy: deployment error: service: app exposes no ports"))
+		Expect(getServices()).To(BeEmpty())
+	})
+
+	It("Should report no endpoints for an app without a service", func() {
+		app.Service = nil
+		Expect(client.Deploy(context.Background(), nodeID, app)).To(Succeed())
+		Expect(getServices()).To(BeEmpty())
+		Expect(client.Endpoints(context.Background(), nodeID, appID)).To(BeEmpty())
+	})
+})
@@ -0,0 +1,223 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package liveness
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	logger "github.com/open-ness/common/log"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/grpc/node"
+	"github.com/open-ness/edgecontroller/uuid"
+	"github.com/pkg/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var log = logger.DefaultLogger.WithField("pkg", "liveness")
+
+const (
+	// DefaultInterval is the default time between two rounds of probes.
+	DefaultInterval = 30 * time.Second
+	// DefaultTimeout is the default time a node agent has to answer a probe.
+	DefaultTimeout = 5 * time.Second
+)
+
+// Monitor periodically probes the ELA and EVA of every enrolled node and
+// records the outcome as the node's status. A change of status raises an
+// event.
+type Monitor struct {
+	Controller *cce.Controller
+
+	// Interval is the time between two rounds of probes. If it is zero,
+	// DefaultInterval is used.
+	Interval time.Duration
+
+	// Timeout bounds a single probe. If it is zero, DefaultTimeout is used.
+	Timeout time.Duration
+
+	// Probe checks whether the node agent listening on port answers. If it is
+	// nil, a cheap RPC is made to the agent over gRPC. This field is intended
+	// for use mocking the node.
+	Probe func(ctx context.Context, nodeID, addr, port string) error
+}
+
+// Run probes all nodes every Interval until the context is canceled.
+func (m *Monitor) Run(ctx context.Context) error {
+	return m.poller().Run(ctx)
+}
+
+// ProbeAll probes all enrolled nodes concurrently.
+func (m *Monitor) ProbeAll(ctx context.Context) {
+	m.poller().PollAll(ctx)
+}
+
+// ProbeNode probes a single node and returns its updated status. A nil status
+// is returned if the node has not enrolled yet.
+func (m *Monitor) ProbeNode(ctx context.Context, nodeID string) (*cce.NodeStatus, error) {
+	target, err := m.poller().Target(ctx, nodeID)
+	if err != nil || target == nil {
+		return nil, err
+	}
+	return m.probeTarget(ctx, target)
+}
+
+func (m *Monitor) poller() *node.Poller {
+	p := &node.Poller{
+		Controller: m.Controller,
+		Interval:   m.Interval,
+		Timeout:    m.Timeout,
+		Poll: func(ctx context.Context, target *cce.NodeGRPCTarget) error {
+			_, err := m.probeTarget(ctx, target)
+			return err
+		},
+	}
+	if p.Interval == 0 {
+		p.Interval = DefaultInterval
+	}
+	if p.Timeout == 0 {
+		p.Timeout = DefaultTimeout
+	}
+	return p
+}
+
+func (m *Monitor) probeTarget(ctx context.Context, target *cce.NodeGRPCTarget) (*cce.NodeStatus, error) {
+	ps := m.Controller.PersistenceService
+	poller := m.poller()
+
+	prev, err := GetStatus(ctx, ps, target.NodeID)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &cce.NodeStatus{
+		ID:         uuid.New(),
+		NodeID:     target.NodeID,
+		LastProbed: time.Now().UTC(),
+	}
+	if prev != nil {
+		s.ID = prev.ID
+		s.LastSeen = prev.LastSeen
+	}
+
+	var (
+		answered int
+		failures []string
+	)
+	for _, port := range []string{poller.ELAPort(), poller.EVAPort()} {
+		probeCtx, cancel := context.WithTimeout(ctx, poller.Timeout)
+		start := time.Now()
+		err := m.probe(probeCtx, poller, target.NodeID, target.GRPCTarget, port)
+		rtt := time.Since(start)
+		cancel()
+
+		if !isAnswered(err) {
+			failures = append(failures, fmt.Sprintf("port %s: %v", port, err))
+			continue
+		}
+		answered++
+		if ms := rtt.Nanoseconds() / int64(time.Millisecond); ms > s.Latency {
+			s.Latency = ms
+		}
+	}
+
+	switch answered {
+	case 2:
+		s.Status = cce.NodeStateOnline
+	case 1:
+		s.Status = cce.NodeStateDegraded
+	default:
+		s.Status = cce.NodeStateOffline
+	}
+	if answered > 0 {
+		s.LastSeen = s.LastProbed
+	}
+	s.Error = strings.Join(failures, "; ")
+
+	if prev == nil {
+		err = ps.Create(ctx, s)
+	} else {
+		err = ps.BulkUpdate(ctx, []cce.Persistable{s})
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "could not store node status")
+	}
+
+	prevState := cce.NodeStateUnknown
+	if prev != nil {
+		prevState = prev.Status
+	}
+	if prevState != s.Status {
+		msg := fmt.Sprintf("node status changed from %s to %s", prevState, s.Status)
+		if s.Error != "" {
+			msg += ": " + s.Error
+		}
+		log.Infof("Node %s: %s", s.NodeID, msg)
+		if err := cce.RaiseEvent(ctx, ps, s.NodeID, cce.EventNodeStatusChanged, msg); err != nil {
+			log.Errf("Error raising event for node %s: %v", s.NodeID, err)
+		}
+	}
+
+	return s, nil
+}
+
+// GetStatus returns the last recorded status of a node or nil if the node has
+// never been probed.
+func GetStatus(ctx context.Context, ps cce.PersistenceService, nodeID string) (*cce.NodeStatus, error) {
+	statuses, err := ps.Filter(ctx, &cce.NodeStatus{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch node status from DB")
+	}
+	if len(statuses) == 0 {
+		return nil, nil
+	}
+	return statuses[0].(*cce.NodeStatus), nil
+}
+
+func (m *Monitor) probe(ctx context.Context, poller *node.Poller, nodeID, addr, port string) error {
+	if m.Probe != nil {
+		return m.Probe(ctx, nodeID, addr, port)
+	}
+
+	nodeCC, err := poller.Connect(ctx, nodeID, addr, port)
+	if err != nil {
+		return err
+	}
+	defer nodeCC.Disconnect()
+
+	if nodeCC.IfaceSvcCli != nil {
+		_, err = nodeCC.IfaceSvcCli.GetAll(ctx)
+		return err
+	}
+	// Any answer proves EVA is serving, including NotFound for the empty ID
+	_, err = nodeCC.AppLifeSvcCli.GetStatus(ctx, "")
+	return err
+}
+
+// isAnswered reports whether a probe reached the node agent. An error status
+// returned by the agent itself still means the agent is alive.
+func isAnswered(err error) bool {
+	if err == nil {
+		return true
+	}
+	s, ok := status.FromError(errors.Cause(err))
+	if !ok {
+		return false
+	}
+	switch s.Code() {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.Canceled:
+		return false
+	default:
+		return true
+	}
+}
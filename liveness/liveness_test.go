@@ -0,0 +1,158 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package liveness_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/internal/stubs"
+	"github.com/open-ness/edgecontroller/liveness"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const nodeID = "48606c73-3905-47e0-864f-14bc7466f5bb"
+
+var _ = Describe("Monitor", func() {
+	var (
+		ps      *stubs.MemoryPersistenceService
+		monitor *liveness.Monitor
+
+		mu        sync.Mutex
+		probeErrs map[string]error
+	)
+
+	setProbeErr := func(port string, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		probeErrs[port] = err
+	}
+
+	events := func() []*cce.Event {
+		persisted, err := ps.ReadAll(context.TODO(), &cce.Event{})
+		Expect(err).ToNot(HaveOccurred())
+		var es []*cce.Event
+		for _, e := range persisted {
+			es = append(es, e.(*cce.Event))
+		}
+		return es
+	}
+
+	BeforeEach(func() {
+		ps = stubs.NewMemoryPersistenceService()
+		probeErrs = make(map[string]error)
+		monitor = &liveness.Monitor{
+			Controller: &cce.Controller{
+				PersistenceService: ps,
+				ELAPort:            "42101",
+				EVAPort:            "42102",
+			},
+			Probe: func(ctx context.Context, id, addr, port string) error {
+				Expect(id).To(Equal(nodeID))
+				Expect(addr).To(Equal("127.0.0.1"))
+				mu.Lock()
+				defer mu.Unlock()
+				return probeErrs[port]
+			},
+		}
+
+		Expect(ps.Create(context.TODO(), &cce.NodeGRPCTarget{
+			ID:         "ca0fa495-1020-405b-a78c-9a1884349078",
+			NodeID:     nodeID,
+			GRPCTarget: "127.0.0.1",
+		})).To(Succeed())
+	})
+
+	Describe("ProbeNode", func() {
+		It("Should return nil for a node that has not enrolled", func() {
+			s, err := monitor.ProbeNode(context.TODO(), "a0e0ec5f-6d2a-4bb0-a0b5-3a2b0dc5b0e4")
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s).To(BeNil())
+		})
+
+		It("Should mark a node answering on both ports online", func() {
+			s, err := monitor.ProbeNode(context.TODO(), nodeID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.Status).To(Equal(cce.NodeStateOnline))
+			Expect(s.LastSeen).To(Equal(s.LastProbed))
+			Expect(s.Error).To(BeEmpty())
+
+			By("Raising an event for the transition from unknown")
+			Expect(events()).To(HaveLen(1))
+			Expect(events()[0].Type).To(Equal(cce.EventNodeStatusChanged))
+			Expect(events()[0].Message).To(Equal("node status changed from unknown to online"))
+		})
+
+		It("Should treat an error returned by the agent as an answer", func() {
+			setProbeErr("42102", status.Error(codes.NotFound, "application not found"))
+
+			s, err := monitor.ProbeNode(context.TODO(), nodeID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.Status).To(Equal(cce.NodeStateOnline))
+		})
+
+		It("Should mark a node answering on one port degraded", func() {
+			setProbeErr("42102", status.Error(codes.Unavailable, "connection refused"))
+
+			s, err := monitor.ProbeNode(context.TODO(), nodeID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.Status).To(Equal(cce.NodeStateDegraded))
+			Expect(s.Error).To(ContainSubstring("port 42102"))
+		})
+
+		It("Should mark a node that does not answer offline and keep last seen", func() {
+			first, err := monitor.ProbeNode(context.TODO(), nodeID)
+			Expect(err).ToNot(HaveOccurred())
+
+			setProbeErr("42101", errors.New("dial failed"))
+			setProbeErr("42102", status.Error(codes.DeadlineExceeded, "timeout"))
+
+			s, err := monitor.ProbeNode(context.TODO(), nodeID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.ID).To(Equal(first.ID))
+			Expect(s.Status).To(Equal(cce.NodeStateOffline))
+			Expect(s.LastSeen).To(Equal(first.LastSeen))
+
+			By("Storing the status")
+			stored, err := liveness.GetStatus(context.TODO(), ps, nodeID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(stored.Status).To(Equal(cce.NodeStateOffline))
+
+			By("Raising an event for each transition")
+			Expect(events()).To(HaveLen(2))
+		})
+
+		It("Should not raise an event when the status is unchanged", func() {
+			_, err := monitor.ProbeNode(context.TODO(), nodeID)
+			Expect(err).ToNot(HaveOccurred())
+			_, err = monitor.ProbeNode(context.TODO(), nodeID)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(events()).To(HaveLen(1))
+		})
+	})
+
+	Describe("ProbeAll", func() {
+		It("Should probe every enrolled node", func() {
+			monitor.ProbeAll(context.TODO())
+
+			s, err := liveness.GetStatus(context.TODO(), ps, nodeID)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(s.Status).To(Equal(cce.NodeStateOnline))
+		})
+	})
+
+	Describe("Run", func() {
+		It("Should return once the context is canceled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			Expect(monitor.Run(ctx)).To(Succeed())
+		})
+	})
+})
@@ -23,6 +23,9 @@ func (c *MockPBApplicationDeploymentServiceClient) DeployContainer(
 	in *evapb.Application,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.AppDeploySvc.DeployContainer(ctx, in)
 }
 
@@ -32,6 +35,9 @@ func (c *MockPBApplicationDeploymentServiceClient) DeployVM(
 	in *evapb.Application,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.AppDeploySvc.DeployVM(ctx, in)
 }
 
@@ -41,6 +47,9 @@ func (c *MockPBApplicationDeploymentServiceClient) Redeploy(
 	in *evapb.Application,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.AppDeploySvc.Redeploy(ctx, in)
 }
 
@@ -50,5 +59,8 @@ func (c *MockPBApplicationDeploymentServiceClient) Undeploy(
 	in *evapb.ApplicationID,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.AppDeploySvc.Undeploy(ctx, in)
 }
@@ -23,6 +23,9 @@ func (c *MockPBApplicationLifecycleServiceClient) Start(
 	in *evapb.LifecycleCommand,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.AppLifeSvc.Start(ctx, in)
 }
 
@@ -32,6 +35,9 @@ func (c *MockPBApplicationLifecycleServiceClient) Stop(
 	in *evapb.LifecycleCommand,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.AppLifeSvc.Stop(ctx, in)
 }
 
@@ -41,6 +47,9 @@ func (c *MockPBApplicationLifecycleServiceClient) Restart(
 	in *evapb.LifecycleCommand,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.AppLifeSvc.Restart(ctx, in)
 }
 
@@ -50,5 +59,8 @@ func (c *MockPBApplicationLifecycleServiceClient) GetStatus(
 	in *evapb.ApplicationID,
 	opts ...grpc.CallOption,
 ) (*evapb.LifecycleStatus, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.AppLifeSvc.GetStatus(ctx, in)
 }
@@ -23,6 +23,9 @@ func (c *MockPBDNSServiceClient) SetA(
 	in *elapb.DNSARecordSet,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.DNSSvc.SetA(ctx, in)
 }
 
@@ -32,6 +35,9 @@ func (c *MockPBDNSServiceClient) DeleteA(
 	in *elapb.DNSARecordSet,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.DNSSvc.DeleteA(ctx, in)
 }
 
@@ -41,6 +47,9 @@ func (c *MockPBDNSServiceClient) SetForwarders(
 	in *elapb.DNSForwarders,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.DNSSvc.SetForwarders(ctx, in)
 }
 
@@ -50,5 +59,8 @@ func (c *MockPBDNSServiceClient) DeleteForwarders(
 	in *elapb.DNSForwarders,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.DNSSvc.DeleteForwarders(ctx, in)
 }
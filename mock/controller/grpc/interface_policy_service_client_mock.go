@@ -23,5 +23,8 @@ func (c *MockPBInterfacePolicyServiceClient) Set(
 	in *elapb.TrafficPolicy,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.IfPolicySvc.Set(ctx, in)
 }
@@ -23,6 +23,9 @@ func (c *MockPBInterfaceServiceClient) Update(
 	in *elapb.NetworkInterface,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.InterfaceSvc.Update(ctx, in)
 }
 
@@ -32,6 +35,9 @@ func (c *MockPBInterfaceServiceClient) BulkUpdate(
 	in *elapb.NetworkInterfaces,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.InterfaceSvc.BulkUpdate(ctx, in)
 }
 
@@ -41,6 +47,9 @@ func (c *MockPBInterfaceServiceClient) GetAll(
 	in *empty.Empty,
 	opts ...grpc.CallOption,
 ) (*elapb.NetworkInterfaces, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.InterfaceSvc.GetAll(ctx, in)
 }
 
@@ -50,5 +59,8 @@ func (c *MockPBInterfaceServiceClient) Get(
 	in *elapb.InterfaceID,
 	opts ...grpc.CallOption,
 ) (*elapb.NetworkInterface, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.InterfaceSvc.Get(ctx, in)
 }
@@ -23,6 +23,9 @@ func (c *MockPBZoneServiceClient) Create(
 	in *elapb.NetworkZone,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.ZoneSvc.Create(ctx, in)
 }
 
@@ -32,6 +35,9 @@ func (c *MockPBZoneServiceClient) Update(
 	in *elapb.NetworkZone,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.ZoneSvc.Update(ctx, in)
 }
 
@@ -41,6 +47,9 @@ func (c *MockPBZoneServiceClient) BulkUpdate(
 	in *elapb.NetworkZones,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.ZoneSvc.BulkUpdate(ctx, in)
 }
 
@@ -50,6 +59,9 @@ func (c *MockPBZoneServiceClient) GetAll(
 	in *empty.Empty,
 	opts ...grpc.CallOption,
 ) (*elapb.NetworkZones, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.ZoneSvc.GetAll(ctx, in)
 }
 
@@ -59,6 +71,9 @@ func (c *MockPBZoneServiceClient) Get(
 	in *elapb.ZoneID,
 	opts ...grpc.CallOption,
 ) (*elapb.NetworkZone, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.ZoneSvc.Get(ctx, in)
 }
 
@@ -68,5 +83,8 @@ func (c *MockPBZoneServiceClient) Delete(
 	in *elapb.ZoneID,
 	opts ...grpc.CallOption,
 ) (*empty.Empty, error) {
+	if err := c.MockNode.Fault(ctx); err != nil {
+		return nil, err
+	}
 	return c.MockNode.ZoneSvc.Delete(ctx, in)
 }
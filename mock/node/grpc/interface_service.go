@@ -14,6 +14,8 @@ import (
 
 type interfaceService struct {
 	nis []*elapb.NetworkInterface
+
+	zoneService *zoneService
 }
 
 func ifs() []*elapb.NetworkInterface {
@@ -71,6 +73,10 @@ func (s *interfaceService) Update(
 	ctx context.Context,
 	ni *elapb.NetworkInterface,
 ) (*empty.Empty, error) {
+	if err := s.checkZones(ni); err != nil {
+		return nil, err
+	}
+
 	i := s.findIndex(ni.Id)
 
 	if i < len(s.nis) {
@@ -95,6 +101,13 @@ func (s *interfaceService) BulkUpdate(
 		}
 	}
 
+	// make sure all zones referenced exist
+	for _, ni := range nis.NetworkInterfaces {
+		if err := s.checkZones(ni); err != nil {
+			return nil, err
+		}
+	}
+
 	// make sure all interfaces are passed in
 	for _, ni := range s.nis {
 		if findInPB(nis.NetworkInterfaces, ni.Id) == len(nis.NetworkInterfaces) {
@@ -136,6 +149,18 @@ func (s *interfaceService) Get(
 		codes.NotFound, "Network Interface %s not found", id.Id)
 }
 
+func (s *interfaceService) checkZones(ni *elapb.NetworkInterface) error {
+	for _, zoneID := range ni.Zones {
+		if s.zoneService == nil || s.zoneService.find(zoneID) == nil {
+			return status.Errorf(
+				codes.InvalidArgument,
+				"Network Zone %s of Network Interface %s not found", zoneID, ni.Id)
+		}
+	}
+
+	return nil
+}
+
 func (s *interfaceService) find(id string) *elapb.NetworkInterface {
 	for _, ni := range s.nis {
 		if ni.Id == id {
@@ -4,8 +4,13 @@
 package grpc
 
 import (
+	"context"
+	"sync"
+
 	elapb "github.com/open-ness/edgecontroller/pb/ela"
 	evapb "github.com/open-ness/edgecontroller/pb/eva"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // MockNode provides a mock node gRPC server.
@@ -17,8 +22,15 @@ type MockNode struct {
 	InterfaceSvc elapb.InterfaceServiceServer
 	IfPolicySvc  elapb.InterfacePolicyServiceServer
 	ZoneSvc      elapb.ZoneServiceServer
+
+	faultMu sync.Mutex
+	faults  []error
+	calls   int
 }
 
+// errHang is queued by HangNext to block a call until its context is done.
+var errHang = status.Error(codes.Internal, "hang")
+
 // NewMockNode creates a new MockNode with node services initialized.
 // AppDeploySvc and AppLifeSvc are combined into appDeployLifeService.
 func NewMockNode() *MockNode {
@@ -28,10 +40,11 @@ func NewMockNode() *MockNode {
 		dnsSvc           = newDNSService()
 		interfaceSvc     = newInterfaceService()
 		ifPolicySvc      = newInterfacePolicyService(interfaceSvc)
-		zoneSvc          = &zoneService{}
+		zoneSvc          = newZoneService(interfaceSvc)
 	)
 
 	appDeployLifeSvc.appPolicyService = appPolicySvc
+	interfaceSvc.zoneService = zoneSvc
 
 	return &MockNode{
 		AppDeploySvc: appDeployLifeSvc,
@@ -52,4 +65,58 @@ func (mn *MockNode) Reset() {
 	mn.IfPolicySvc.(*interfacePolicyService).reset()
 	mn.ZoneSvc.(*zoneService).reset()
 	mn.DNSSvc.(*dnsService).reset()
+
+	mn.faultMu.Lock()
+	mn.faults = nil
+	mn.calls = 0
+	mn.faultMu.Unlock()
+}
+
+// FailNext makes the next n calls through the mock PB clients fail with err
+// without reaching the node services.
+func (mn *MockNode) FailNext(n int, err error) {
+	mn.faultMu.Lock()
+	defer mn.faultMu.Unlock()
+
+	for i := 0; i < n; i++ {
+		mn.faults = append(mn.faults, err)
+	}
+}
+
+// HangNext makes the next n calls through the mock PB clients block until
+// their context is done, as an unresponsive node would.
+func (mn *MockNode) HangNext(n int) {
+	mn.FailNext(n, errHang)
+}
+
+// Calls returns the number of calls made through the mock PB clients since
+// the last reset.
+func (mn *MockNode) Calls() int {
+	mn.faultMu.Lock()
+	defer mn.faultMu.Unlock()
+
+	return mn.calls
+}
+
+// Fault counts a call and returns the error queued for it, if any. It is
+// called by the mock PB clients before delegating to the node services.
+func (mn *MockNode) Fault(ctx context.Context) error {
+	mn.faultMu.Lock()
+	mn.calls++
+	if len(mn.faults) == 0 {
+		mn.faultMu.Unlock()
+		return nil
+	}
+	err := mn.faults[0]
+	mn.faults = mn.faults[1:]
+	mn.faultMu.Unlock()
+
+	if err != errHang {
+		return err
+	}
+	<-ctx.Done()
+	if ctx.Err() == context.DeadlineExceeded {
+		return status.Error(codes.DeadlineExceeded, ctx.Err().Error())
+	}
+	return status.Error(codes.Canceled, ctx.Err().Error())
 }
@@ -14,6 +14,14 @@ import (
 
 type zoneService struct {
 	zones []*elapb.NetworkZone
+
+	interfaceService *interfaceService
+}
+
+func newZoneService(interfaceService *interfaceService) *zoneService {
+	return &zoneService{
+		interfaceService: interfaceService,
+	}
 }
 
 func (s *zoneService) reset() {
@@ -24,6 +32,11 @@ func (s *zoneService) Create(
 	ctx context.Context,
 	zone *elapb.NetworkZone,
 ) (*empty.Empty, error) {
+	if s.find(zone.Id) != nil {
+		return nil, status.Errorf(
+			codes.AlreadyExists, "Network Zone %s already exists", zone.Id)
+	}
+
 	s.zones = append(s.zones, zone)
 
 	return &empty.Empty{}, nil
@@ -92,6 +105,17 @@ func (s *zoneService) Delete(
 	ctx context.Context,
 	id *elapb.ZoneID,
 ) (*empty.Empty, error) {
+	// make sure no interface is still in the zone
+	for _, ni := range s.interfaceService.nis {
+		for _, zoneID := range ni.Zones {
+			if zoneID == id.Id {
+				return nil, status.Errorf(
+					codes.FailedPrecondition,
+					"Network Zone %s in use by Network Interface %s", id.Id, ni.Id)
+			}
+		}
+	}
+
 	i := s.findIndex(id.Id)
 
 	if i < len(s.zones) {
@@ -14,7 +14,7 @@ USE controller_ce
 CREATE TABLE nodes (
     id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
     -- TODO add UNIQUE KEY on serial - will require refactoring the tests
-    serial VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.serial') STORED,
+    serial VARCHAR(64) GENERATED ALWAYS AS (entity->>'$.serial') STORED,
     entity JSON
 );
 
@@ -30,6 +30,39 @@ CREATE TABLE node_grpc_targets (
     UNIQUE KEY (grpc_target)
 );
 
+-- liveness of a node as observed by the controller's periodic probes
+CREATE TABLE node_statuses (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    node_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.node_id') STORED UNIQUE KEY,
+    status VARCHAR(16) GENERATED ALWAYS AS (entity->>'$.status') STORED,
+    entity JSON,
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE
+);
+
+-- hardware, network and apps of a node as last collected by the controller
+CREATE TABLE node_inventories (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    node_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.node_id') STORED UNIQUE KEY,
+    entity JSON,
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE events (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    node_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.node_id') STORED,
+    type VARCHAR(64) GENERATED ALWAYS AS (entity->>'$.type') STORED,
+    entity JSON,
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE
+);
+
+CREATE TABLE enrollment_tokens (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    node_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.node_id') STORED,
+    token_hash VARCHAR(64) GENERATED ALWAYS AS (entity->>'$.token_hash') STORED UNIQUE KEY,
+    entity JSON,
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE
+);
+
 CREATE TABLE nodes_nfd_features (
     id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
     node_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.node_id') STORED,
@@ -39,10 +72,19 @@ CREATE TABLE nodes_nfd_features (
     UNIQUE KEY (node_id, nfd_id)
 );
 
+-- trusted publishers of app images
+CREATE TABLE publishers (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    name VARCHAR(64) GENERATED ALWAYS AS (entity->>'$.name') STORED UNIQUE KEY,
+    entity JSON
+);
+
 CREATE TABLE apps (
     id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
     type VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.type') STORED,
-    entity JSON
+    publisher_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.publisher_id') STORED,
+    entity JSON,
+    FOREIGN KEY (publisher_id) REFERENCES publishers(id)
 );
 
 CREATE TABLE traffic_policies (
@@ -60,6 +102,21 @@ CREATE TABLE credentials (
     entity JSON
 );
 
+CREATE TABLE zones (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    name VARCHAR(64) GENERATED ALWAYS AS (entity->>'$.name') STORED UNIQUE KEY,
+    entity JSON
+);
+
+-- staged deployments of a version of an app across nodes; an app can only be deleted once its rollouts finished, so
+-- we specify ON DELETE CASCADE to drop their history along with it
+CREATE TABLE rollouts (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    app_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.app_id') STORED,
+    entity JSON,
+    FOREIGN KEY (app_id) REFERENCES apps(id) ON DELETE CASCADE
+);
+
 -- -------------------
 -- Primary join tables
 -- -------------------
@@ -100,6 +157,17 @@ CREATE TABLE nodes_dns_configs (
     FOREIGN KEY (dns_config_id) REFERENCES dns_configs(id)
 );
 
+-- nodes x zones
+CREATE TABLE nodes_zones (
+    id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
+    node_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.node_id') STORED,
+    zone_id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.zone_id') STORED,
+    entity JSON,
+    FOREIGN KEY (node_id) REFERENCES nodes(id) ON DELETE CASCADE,
+    FOREIGN KEY (zone_id) REFERENCES zones(id),
+    UNIQUE KEY (node_id, zone_id)
+);
+
 -- nodes (network_interfaces) x traffic_policies
 CREATE TABLE nodes_network_interfaces_traffic_policies (
     id VARCHAR(36) GENERATED ALWAYS AS (entity->>'$.id') STORED UNIQUE KEY,
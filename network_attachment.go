@@ -0,0 +1,67 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+)
+
+var (
+	networkNameRegexp   = regexp.MustCompile(`^([a-z0-9]([-a-z0-9]*[a-z0-9])?/)?[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+	interfaceNameRegexp = regexp.MustCompile(`^[a-zA-Z0-9_.-]{1,15}$`)
+)
+
+// NetworkAttachment attaches an app to a network besides the default pod
+// network in Kubernetes mode, through a Multus NetworkAttachmentDefinition.
+type NetworkAttachment struct {
+	// Name is the name of the NetworkAttachmentDefinition as
+	// [<namespace>/]<name>, in the namespace of the app by default. Other
+	// namespaces must be allowed by the operator.
+	Name string `json:"name"`
+	// Interface is the name of the interface in the pod, chosen by Multus if
+	// it is empty.
+	Interface string `json:"interface,omitempty"`
+	// IPs are the static addresses of the interface in CIDR notation.
+	IPs []string `json:"ips,omitempty"`
+	// MAC is the static MAC address of the interface.
+	MAC string `json:"mac,omitempty"`
+}
+
+// NetworkStatus is an interface of an app attached to a network, as
+// reported by Multus.
+type NetworkStatus struct {
+	Name      string   `json:"name"`
+	Interface string   `json:"interface,omitempty"`
+	IPs       []string `json:"ips,omitempty"`
+	MAC       string   `json:"mac,omitempty"`
+	// Default is set for the interface on the default pod network.
+	Default bool `json:"default,omitempty"`
+}
+
+// Validate validates the network attachment.
+func (na *NetworkAttachment) Validate() error {
+	if na.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+	if !networkNameRegexp.MatchString(na.Name) {
+		return fmt.Errorf("name %q must be [<namespace>/]<name>", na.Name)
+	}
+	if na.Interface != "" && !interfaceNameRegexp.MatchString(na.Interface) {
+		return fmt.Errorf("interface %q is invalid", na.Interface)
+	}
+	for _, ip := range na.IPs {
+		if _, _, err := net.ParseCIDR(ip); err != nil {
+			return fmt.Errorf("ip %q must be in CIDR notation", ip)
+		}
+	}
+	if na.MAC != "" {
+		if _, err := net.ParseMAC(na.MAC); err != nil {
+			return fmt.Errorf("mac %q is invalid", na.MAC)
+		}
+	}
+	return nil
+}
@@ -44,8 +44,11 @@ type ServerNFD struct {
 	Endpoint   int
 	CaCertPath string
 	CaKeyPath  string
-	Sni        string
-	Dsn        string
+	// CaChainPath holds the issuers of the CA certificate if it is an
+	// intermediate CA, ending with the root CA. It is optional.
+	CaChainPath string
+	Sni         string
+	Dsn         string
 }
 
 type labeler struct {
@@ -103,13 +106,25 @@ func (s ServerNFD) createCredentialsTLS() (credentials.TransportCredentials, err
 		return nil, errors.Wrap(err, "Failed to generate server certificate")
 	}
 
+	// Send the chain up to the root CA trusted by the nodes
+	nfdChain := [][]byte{nfdCert.Raw, caCert.Raw}
+	if s.CaChainPath != "" {
+		issuers, err := pki.LoadCertificateChain(filepath.Clean(s.CaChainPath))
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to load CA chain")
+		}
+		for _, issuer := range issuers {
+			nfdChain = append(nfdChain, issuer.Raw)
+		}
+	}
+
 	certPool := x509.NewCertPool()
 	certPool.AddCert(caCert)
 
 	return credentials.NewTLS(&tls.Config{
 		ClientAuth: tls.RequireAndVerifyClientCert,
 		Certificates: []tls.Certificate{{
-			Certificate: [][]byte{nfdCert.Raw},
+			Certificate: nfdChain,
 			PrivateKey:  nfdKey,
 		}},
 		ClientCAs: certPool,
@@ -157,8 +172,9 @@ func (s ServerNFD) ServeGRPC(ctx context.Context) error {
 	return nil
 }
 
-// getPeerName returns Subject.CommonName from peer TLS certificate
-func getPeerName(ctx context.Context) (string, error) {
+// getPeerName returns Subject.CommonName from peer TLS certificate, unless the
+// certificate was revoked
+func getPeerName(ctx context.Context, ps cce.PersistenceService) (string, error) {
 	p, ok := peer.FromContext(ctx)
 	if !ok {
 		return "", errors.New("Missing peer data in gRPC context")
@@ -178,6 +194,10 @@ func getPeerName(ctx context.Context) (string, error) {
 		return "", errors.New("gRPC peer connected with a client TLS cert with no Common Name")
 	}
 
+	if err := cce.CheckRevocation(ctx, ps, chains[0][0]); err != nil {
+		return "", errors.Wrapf(err, "gRPC peer %s rejected", nodeID)
+	}
+
 	return nodeID, nil
 }
 
@@ -185,7 +205,7 @@ func getPeerName(ctx context.Context) (string, error) {
 func (l labeler) SetLabels(c context.Context, r *pb.SetLabelsRequest) (*pb.SetLabelsReply, error) {
 	log.Infof("REQUEST Node: %s NFD-version: %s Labels: %s", r.NodeName, r.NfdVersion, r.Labels)
 
-	nodeID, err := getPeerName(c)
+	nodeID, err := getPeerName(c, l.persistenceService)
 	if err != nil {
 		log.Errf("Peer error %v", err)
 		return &pb.SetLabelsReply{}, err
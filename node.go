@@ -16,7 +16,14 @@ type Node struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
 	Location string `json:"location"`
-	Serial   string `json:"serial"`
+	// Serial identifies the node's public key. It may be left empty for a
+	// node that enrolls with an enrollment token.
+	Serial string `json:"serial"`
+	// Labels are set by the operator to select nodes when scheduling apps.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Allocatable are the resources of the node apps can use, as set by the
+	// operator. If it is nil, the ones reported by NFD are used.
+	Allocatable *Resources `json:"allocatable,omitempty"`
 }
 
 // NodeReq is a Node request.
@@ -83,8 +90,15 @@ func (n *Node) Validate() error {
 	if n.Location == "" {
 		return errors.New("location cannot be empty")
 	}
-	if n.Serial == "" {
-		return errors.New("serial cannot be empty")
+	for key := range n.Labels {
+		if key == "" {
+			return errors.New("labels cannot have an empty key")
+		}
+	}
+	if n.Allocatable != nil {
+		if err := n.Allocatable.Validate(); err != nil {
+			return fmt.Errorf("allocatable.%v", err)
+		}
 	}
 
 	return nil
@@ -145,6 +159,24 @@ func (nr *NodeReq) Validate() error {
 	return nil
 }
 
+// ValidateZones checks that the network interfaces only reference zones
+// assigned to the node.
+func (nr *NodeReq) ValidateZones(zoneIDs []string) error {
+	assigned := make(map[string]bool, len(zoneIDs))
+	for _, id := range zoneIDs {
+		assigned[id] = true
+	}
+	for i, ni := range nr.NetworkInterfaces {
+		for j, zone := range ni.Zones {
+			if !assigned[zone] {
+				return fmt.Errorf("network_interfaces[%d].zones[%d] %s is not a zone of the node", i, j, zone)
+			}
+		}
+	}
+
+	return nil
+}
+
 // GetTableName returns the name of the persistence table.
 func (nr *NodeReq) GetTableName() string {
 	return nr.Node.GetTableName()
@@ -6,16 +6,75 @@ package cce
 import (
 	"errors"
 	"fmt"
+	"net"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/open-ness/edgecontroller/uuid"
 )
 
+// MaxNodeAppHistory is the number of deployments kept in a node app's
+// history.
+const MaxNodeAppHistory = 10
+
 // NodeApp represents an association between a Node and an App.
 type NodeApp struct {
 	ID     string `json:"id"`
 	NodeID string `json:"node_id"`
-	AppID  string `json:"app_id"`
+	// AppID is the version of the app currently deployed to the node.
+	AppID string `json:"app_id"`
+	// DeployedAppID is the version of the app first deployed to the node,
+	// which the node keeps knowing the app by once it is upgraded. It is
+	// empty until then.
+	DeployedAppID string `json:"deployed_app_id,omitempty"`
+	// History lists the versions of the app deployed to the node, most
+	// recent first.
+	History []NodeAppDeployment `json:"history,omitempty"`
+	// Config overrides the runtime configuration of the app on the node.
+	Config *AppConfig `json:"config,omitempty"`
+	// Namespace is the Kubernetes namespace of the tenant or project the app
+	// is deployed for, the default namespace if it is empty.
+	Namespace string `json:"namespace,omitempty"`
+	// Replicas is the number of pods the app runs in Kubernetes mode once
+	// started, 1 if it is 0.
+	Replicas int `json:"replicas,omitempty"`
+	// Networks attaches the app to networks besides the default pod network
+	// in Kubernetes mode.
+	Networks []NetworkAttachment `json:"networks,omitempty"`
+	// Service exposes the ports of the app at a stable address in Kubernetes
+	// mode if it is set.
+	Service *NodeAppService `json:"service,omitempty"`
+}
+
+// Types of the Kubernetes service of a node app
+const (
+	ServiceClusterIP = "cluster_ip"
+	ServiceNodePort  = "node_port"
+)
+
+// NodeAppService is the Kubernetes service exposing the ports of a node app.
+// Type is cluster_ip or node_port. The ports are also exposed at
+// ExternalIPs, if any, which must be in the ranges allowed by the operator.
+type NodeAppService struct {
+	Type        string   `json:"type"`
+	ExternalIPs []string `json:"external_ips,omitempty"`
+}
+
+// AppEndpoint is an address at which a port of a node app is reachable.
+type AppEndpoint struct {
+	IP       string `json:"ip"`
+	Port     int    `json:"port"`
+	Protocol string `json:"protocol"`
+}
+
+// NodeAppDeployment is a version of an app deployed to a node. Cmd is one of
+// deploy, upgrade or rollback.
+type NodeAppDeployment struct {
+	AppID   string    `json:"app_id"`
+	Version string    `json:"version"`
+	Cmd     string    `json:"cmd"`
+	At      time.Time `json:"at"`
 }
 
 // NodeAppReq is a NodeApp request.
@@ -23,13 +82,26 @@ type NodeApp struct {
 type NodeAppReq struct {
 	NodeApp
 	Cmd string `json:"cmd,omitempty"`
+	// Version is the version to upgrade the app to.
+	Version string `json:"version,omitempty"`
 }
 
+var namespaceRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]{0,61}[a-z0-9])?$`)
+
 // NodeAppResp is a NodeApp response.
 // TODO add a String() method and test for this struct.
 type NodeAppResp struct {
 	NodeApp
 	Status string `json:"status"`
+	// Readiness is one of ready, not_ready or unknown, and unset for native
+	// apps whose readiness the node does not report.
+	Readiness string `json:"readiness,omitempty"`
+	// NetworkStatus lists the interfaces of the app in Kubernetes mode, with
+	// their assigned addresses.
+	NetworkStatus []NetworkStatus `json:"network_status,omitempty"`
+	// Endpoints lists the addresses at which the service of the app exposes
+	// its ports.
+	Endpoints []AppEndpoint `json:"endpoints,omitempty"`
 }
 
 // GetTableName returns the name of the persistence table.
@@ -63,7 +135,82 @@ func (n_a *NodeApp) Validate() error {
 	if !uuid.IsValid(n_a.AppID) {
 		return errors.New("app_id not a valid uuid")
 	}
+	if n_a.Config != nil {
+		if err := n_a.Config.Validate(); err != nil {
+			return fmt.Errorf("config: %v", err)
+		}
+	}
+	if n_a.Namespace != "" && !namespaceRegexp.MatchString(n_a.Namespace) {
+		return fmt.Errorf("namespace %q is invalid", n_a.Namespace)
+	}
+	if strings.HasPrefix(n_a.Namespace, "kube-") {
+		return fmt.Errorf("namespace %s is reserved", n_a.Namespace)
+	}
+	if n_a.Replicas < 0 {
+		return errors.New("replicas cannot be negative")
+	}
+	for i := range n_a.Networks {
+		if err := n_a.Networks[i].Validate(); err != nil {
+			return fmt.Errorf("networks[%d]: %v", i, err)
+		}
+		// Replicas would share the static addresses of an interface
+		if n_a.ReplicaCount() > 1 && (len(n_a.Networks[i].IPs) > 0 || n_a.Networks[i].MAC != "") {
+			return fmt.Errorf("networks[%d]: static ips and mac require a single replica", i)
+		}
+	}
+	if n_a.Service != nil {
+		if err := n_a.Service.Validate(); err != nil {
+			return fmt.Errorf("service: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// ValidateOrchestration validates the node app for an orchestration mode.
+// Natively an app runs once on the node, outside of any namespace.
+func (n_a *NodeApp) ValidateOrchestration(mode OrchestrationMode) error {
+	if mode != OrchestrationModeNative {
+		return nil
+	}
+	if n_a.Namespace != "" {
+		return errors.New("namespace is only supported in kubernetes mode")
+	}
+	if n_a.Replicas != 0 {
+		return errors.New("replicas are only supported in kubernetes mode")
+	}
+	return nil
+}
+
+// ValidateApp validates the node app for the app it deploys. The service,
+// networks and runtime configuration of a node app only apply to containers.
+func (n_a *NodeApp) ValidateApp(app *App) error {
+	if app.Type != "vm" {
+		return nil
+	}
+	switch {
+	case n_a.Service != nil:
+		return errors.New("service is not supported for vm apps")
+	case len(n_a.Networks) > 0:
+		return errors.New("networks are not supported for vm apps")
+	case n_a.Config != nil:
+		return errors.New("config is not supported for vm apps")
+	}
+	return nil
+}
 
+// Validate validates the service.
+func (s *NodeAppService) Validate() error {
+	switch s.Type {
+	case ServiceClusterIP, ServiceNodePort:
+	default:
+		return fmt.Errorf("type must be one of [%s, %s]", ServiceClusterIP, ServiceNodePort)
+	}
+	for _, ip := range s.ExternalIPs {
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("external ip %q is invalid", ip)
+		}
+	}
 	return nil
 }
 
@@ -75,6 +222,80 @@ func (*NodeApp) FilterFields() []string {
 	}
 }
 
+// RemoteAppID returns the ID the node knows the app by.
+func (n_a *NodeApp) RemoteAppID() string {
+	if n_a.DeployedAppID != "" {
+		return n_a.DeployedAppID
+	}
+	return n_a.AppID
+}
+
+// ReplicaCount returns the number of pods the app runs once started.
+func (n_a *NodeApp) ReplicaCount() int {
+	if n_a.Replicas == 0 {
+		return 1
+	}
+	return n_a.Replicas
+}
+
+// AppIDs returns the IDs of all the versions of the app deployed to the node.
+func (n_a *NodeApp) AppIDs() []string {
+	var ids []string
+	seen := make(map[string]bool)
+	add := func(id string) {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	add(n_a.RemoteAppID())
+	add(n_a.AppID)
+	for _, d := range n_a.History {
+		add(d.AppID)
+	}
+	return ids
+}
+
+// References returns whether the node app runs appID, is known to the node by
+// it or may be rolled back to it.
+func (n_a *NodeApp) References(appID string) bool {
+	if n_a.AppID == appID || n_a.DeployedAppID == appID {
+		return true
+	}
+	prev := n_a.Previous()
+	return prev != nil && prev.AppID == appID
+}
+
+// Previous returns the version of the app deployed before the current one or
+// nil if there is none.
+func (n_a *NodeApp) Previous() *NodeAppDeployment {
+	if len(n_a.History) < 2 {
+		return nil
+	}
+	prev := n_a.History[1]
+	return &prev
+}
+
+// Record makes app the version deployed to the node by cmd and adds it to the
+// history.
+func (n_a *NodeApp) Record(app *App, cmd string, at time.Time) {
+	if n_a.AppID != app.ID && n_a.DeployedAppID == "" {
+		n_a.DeployedAppID = n_a.AppID
+	}
+	n_a.AppID = app.ID
+
+	n_a.History = append([]NodeAppDeployment{{
+		AppID:   app.ID,
+		Version: app.Version,
+		Cmd:     cmd,
+		At:      at,
+	}}, n_a.History...)
+	if len(n_a.History) > MaxNodeAppHistory {
+		n_a.History = n_a.History[:MaxNodeAppHistory]
+	}
+}
+
 // Validate validates the request model.
 // TODO add a test for this method.
 func (n_ar *NodeAppReq) Validate() error {
@@ -82,7 +303,17 @@ func (n_ar *NodeAppReq) Validate() error {
 		return err
 	}
 	switch n_ar.Cmd {
-	case "start", "stop", "restart":
+	case "start", "stop", "restart", "rollback":
+		return nil
+	case "scale":
+		if n_ar.Replicas == 0 {
+			return errors.New("replicas cannot be empty for scale")
+		}
+		return nil
+	case "upgrade":
+		if n_ar.Version == "" {
+			return errors.New("version cannot be empty for upgrade")
+		}
 		return nil
 	case "":
 		return errors.New("cmd missing")
@@ -5,6 +5,7 @@ package cce_test
 
 import (
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -70,6 +71,189 @@ var _ = Describe("Join Entities: NodeApp", func() {
 			Expect(na.Validate()).To(MatchError(
 				"app_id not a valid uuid"))
 		})
+
+		It("Should return an error if Config is invalid", func() {
+			na.Config = &cce.AppConfig{Args: []string{"-v"}, ConfigFiles: []cce.ConfigFile{{Path: "app.conf"}}}
+			Expect(na.Validate()).To(MatchError(
+				`config: path "app.conf" must be an absolute file path`))
+		})
+
+		It("Should return an error if Namespace is invalid", func() {
+			na.Namespace = "Tenant_A"
+			Expect(na.Validate()).To(MatchError(`namespace "Tenant_A" is invalid`))
+		})
+
+		It("Should return an error if Namespace is reserved", func() {
+			na.Namespace = "kube-system"
+			Expect(na.Validate()).To(MatchError("namespace kube-system is reserved"))
+		})
+
+		It("Should return an error if Replicas is negative", func() {
+			na.Replicas = -1
+			Expect(na.Validate()).To(MatchError("replicas cannot be negative"))
+		})
+
+		It("Should not return an error for valid Networks", func() {
+			na.Networks = []cce.NetworkAttachment{
+				{Name: "sriov-net", Interface: "net1", IPs: []string{"10.10.10.2/24"}, MAC: "02:00:00:00:00:01"},
+				{Name: "kube-ovn/ovn-subnet"},
+			}
+			Expect(na.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if a network IP is not in CIDR notation", func() {
+			na.Networks = []cce.NetworkAttachment{{Name: "sriov-net", IPs: []string{"10.10.10.2"}}}
+			Expect(na.Validate()).To(MatchError(`networks[0]: ip "10.10.10.2" must be in CIDR notation`))
+		})
+
+		It("Should return an error if a network MAC is invalid", func() {
+			na.Networks = []cce.NetworkAttachment{{Name: "sriov-net", MAC: "02:00"}}
+			Expect(na.Validate()).To(MatchError(`networks[0]: mac "02:00" is invalid`))
+		})
+
+		It("Should return an error if replicas share static network addresses", func() {
+			na.Replicas = 2
+			na.Networks = []cce.NetworkAttachment{{Name: "sriov-net", IPs: []string{"10.10.10.2/24"}}}
+			Expect(na.Validate()).To(MatchError("networks[0]: static ips and mac require a single replica"))
+			na.Networks = []cce.NetworkAttachment{{Name: "sriov-net", MAC: "02:00:00:00:00:01"}}
+			Expect(na.Validate()).To(MatchError("networks[0]: static ips and mac require a single replica"))
+			na.Networks = []cce.NetworkAttachment{{Name: "sriov-net"}}
+			Expect(na.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if an app with static network addresses is scaled", func() {
+			na.Networks = []cce.NetworkAttachment{{Name: "sriov-net", IPs: []string{"10.10.10.2/24"}}}
+			req := cce.NodeAppReq{NodeApp: *na, Cmd: "scale"}
+			req.Replicas = 2
+			Expect(req.Validate()).To(MatchError("networks[0]: static ips and mac require a single replica"))
+		})
+
+		It("Should return an error if a network name is invalid", func() {
+			na.Networks = []cce.NetworkAttachment{{Name: "SRIOV"}}
+			Expect(na.Validate()).To(MatchError(`networks[0]: name "SRIOV" must be [<namespace>/]<name>`))
+		})
+
+		It("Should not return an error for a valid Service", func() {
+			na.Service = &cce.NodeAppService{Type: "node_port", ExternalIPs: []string{"10.0.0.1"}}
+			Expect(na.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if the Service type is invalid", func() {
+			na.Service = &cce.NodeAppService{Type: "load_balancer"}
+			Expect(na.Validate()).To(MatchError("service: type must be one of [cluster_ip, node_port]"))
+		})
+
+		It("Should return an error if a Service external IP is invalid", func() {
+			na.Service = &cce.NodeAppService{Type: "cluster_ip", ExternalIPs: []string{"10.0.0"}}
+			Expect(na.Validate()).To(MatchError(`service: external ip "10.0.0" is invalid`))
+		})
+	})
+
+	Describe("ValidateOrchestration", func() {
+		It("Should accept a namespace and replicas in kubernetes mode", func() {
+			na.Namespace = "tenant-a"
+			na.Replicas = 3
+			Expect(na.ValidateOrchestration(cce.OrchestrationModeKubernetes)).To(Succeed())
+		})
+
+		It("Should reject a namespace in native mode", func() {
+			na.Namespace = "tenant-a"
+			Expect(na.ValidateOrchestration(cce.OrchestrationModeNative)).To(MatchError(
+				"namespace is only supported in kubernetes mode"))
+		})
+
+		It("Should reject replicas in native mode", func() {
+			na.Replicas = 3
+			Expect(na.ValidateOrchestration(cce.OrchestrationModeNative)).To(MatchError(
+				"replicas are only supported in kubernetes mode"))
+		})
+	})
+
+	Describe("ValidateApp", func() {
+		It("Should accept a service, networks and config for container apps", func() {
+			na.Service = &cce.NodeAppService{Type: "cluster_ip"}
+			na.Networks = []cce.NetworkAttachment{{Name: "sriov-net"}}
+			na.Config = &cce.AppConfig{Args: []string{"-v"}}
+			Expect(na.ValidateApp(&cce.App{Type: "container"})).To(Succeed())
+		})
+
+		It("Should reject a service for vm apps", func() {
+			na.Service = &cce.NodeAppService{Type: "cluster_ip"}
+			Expect(na.ValidateApp(&cce.App{Type: "vm"})).To(MatchError("service is not supported for vm apps"))
+		})
+
+		It("Should reject networks for vm apps", func() {
+			na.Networks = []cce.NetworkAttachment{{Name: "sriov-net"}}
+			Expect(na.ValidateApp(&cce.App{Type: "vm"})).To(MatchError("networks are not supported for vm apps"))
+		})
+
+		It("Should reject config for vm apps", func() {
+			na.Config = &cce.AppConfig{Args: []string{"-v"}}
+			Expect(na.ValidateApp(&cce.App{Type: "vm"})).To(MatchError("config is not supported for vm apps"))
+		})
+	})
+
+	Describe("ReplicaCount", func() {
+		It("Should default to 1 replica", func() {
+			Expect(na.ReplicaCount()).To(Equal(1))
+			na.Replicas = 3
+			Expect(na.ReplicaCount()).To(Equal(3))
+		})
+	})
+
+	Describe("References", func() {
+		It("Should reference the versions the app runs, is known by and rolls back to", func() {
+			v1 := &cce.App{ID: "27a6fa2a-6ad2-4e57-a9ad-8e4e1b5b5b01", Version: "1"}
+			v2 := &cce.App{ID: "27a6fa2a-6ad2-4e57-a9ad-8e4e1b5b5b02", Version: "2"}
+			v3 := &cce.App{ID: "27a6fa2a-6ad2-4e57-a9ad-8e4e1b5b5b03", Version: "3"}
+			na.AppID = v1.ID
+			na.Record(v1, "deploy", time.Now())
+			na.Record(v2, "upgrade", time.Now())
+			na.Record(v3, "upgrade", time.Now())
+
+			Expect(na.References(v1.ID)).To(BeTrue())
+			Expect(na.References(v2.ID)).To(BeTrue())
+			Expect(na.References(v3.ID)).To(BeTrue())
+			Expect(na.References("27a6fa2a-6ad2-4e57-a9ad-8e4e1b5b5b04")).To(BeFalse())
+		})
+	})
+
+	Describe("Record", func() {
+		var (
+			at = time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+			v1 = &cce.App{ID: "efcece3c-6b58-4993-8d45-bde6239d4baa", Version: "1.0"}
+			v2 = &cce.App{ID: "c9b4bc07-0d6b-4a6e-8e36-d1e0e7d6d7a1", Version: "2.0"}
+		)
+
+		It("Should keep the ID the node knows the app by across upgrades", func() {
+			na.Record(v1, "deploy", at)
+			Expect(na.Previous()).To(BeNil())
+			Expect(na.RemoteAppID()).To(Equal(v1.ID))
+
+			na.Record(v2, "upgrade", at.Add(time.Hour))
+			Expect(na.AppID).To(Equal(v2.ID))
+			Expect(na.RemoteAppID()).To(Equal(v1.ID))
+			Expect(na.AppIDs()).To(Equal([]string{v1.ID, v2.ID}))
+			Expect(na.Previous()).To(Equal(&cce.NodeAppDeployment{
+				AppID:   v1.ID,
+				Version: "1.0",
+				Cmd:     "deploy",
+				At:      at,
+			}))
+
+			na.Record(v1, "rollback", at.Add(2*time.Hour))
+			Expect(na.AppID).To(Equal(v1.ID))
+			Expect(na.RemoteAppID()).To(Equal(v1.ID))
+			Expect(na.Previous().AppID).To(Equal(v2.ID))
+			Expect(na.History).To(HaveLen(3))
+		})
+
+		It("Should bound the history", func() {
+			for i := 0; i < cce.MaxNodeAppHistory+5; i++ {
+				na.Record(v1, "deploy", at)
+			}
+			Expect(na.History).To(HaveLen(cce.MaxNodeAppHistory))
+		})
 	})
 
 	Describe("FilterFields", func() {
@@ -0,0 +1,104 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// NFD labels a node reports the resources allocatable to apps with, published
+// by a hook of the NFD local source. Memory is in MB.
+const (
+	NFDAllocatableCores  = "local-allocatable.cores"
+	NFDAllocatableMemory = "local-allocatable.memory"
+)
+
+// Resources are cores and memory (in MB).
+type Resources struct {
+	Cores  int `json:"cores"`
+	Memory int `json:"memory"`
+}
+
+// Validate validates the resources of a node.
+func (r *Resources) Validate() error {
+	if r.Cores < 1 {
+		return errors.New("cores must be greater than 0")
+	}
+	if r.Memory < 1 {
+		return errors.New("memory must be greater than 0")
+	}
+	return nil
+}
+
+// Allocatable returns the resources of the node apps can use: the ones set by
+// the operator, else the ones reported through the node's NFD features. It
+// returns nil if neither is known.
+func Allocatable(node *Node, features map[string]string) *Resources {
+	if node.Allocatable != nil {
+		allocatable := *node.Allocatable
+		return &allocatable
+	}
+
+	cores, err := strconv.Atoi(features[NFDAllocatableCores])
+	if err != nil || cores < 1 {
+		return nil
+	}
+	memory, err := strconv.Atoi(features[NFDAllocatableMemory])
+	if err != nil || memory < 1 {
+		return nil
+	}
+	return &Resources{Cores: cores, Memory: memory}
+}
+
+// CommittedResources returns the resources committed to the apps deployed to
+// each node, indexed by node ID, counting every replica of an app. Nodes
+// without apps are left out.
+func CommittedResources(ctx context.Context, ps PersistenceService) (map[string]Resources, error) {
+	apps, err := ps.ReadAll(ctx, &App{})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch apps from DB: %v", err)
+	}
+	appsByID := make(map[string]*App, len(apps))
+	for _, a := range apps {
+		appsByID[a.GetID()] = a.(*App)
+	}
+
+	nodeApps, err := ps.ReadAll(ctx, &NodeApp{})
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch node apps from DB: %v", err)
+	}
+
+	committed := make(map[string]Resources)
+	for _, na := range nodeApps {
+		nodeApp := na.(*NodeApp)
+		app, ok := appsByID[nodeApp.AppID]
+		if !ok {
+			continue
+		}
+		r := committed[nodeApp.NodeID]
+		r.Cores += app.Cores * nodeApp.ReplicaCount()
+		r.Memory += app.Memory * nodeApp.ReplicaCount()
+		committed[nodeApp.NodeID] = r
+	}
+	return committed, nil
+}
+
+// CheckCapacity returns an error if the replicas of the app do not fit in the
+// allocatable resources of a node left free by the committed ones. A node
+// whose allocatable resources are unknown is not checked.
+func CheckCapacity(app *App, replicas int, allocatable *Resources, committed Resources) error {
+	if allocatable == nil {
+		return nil
+	}
+	if required, free := app.Cores*replicas, allocatable.Cores-committed.Cores; required > free {
+		return fmt.Errorf("insufficient cores: %d required, %d free", required, free)
+	}
+	if required, free := app.Memory*replicas, allocatable.Memory-committed.Memory; required > free {
+		return fmt.Errorf("insufficient memory: %d MB required, %d MB free", required, free)
+	}
+	return nil
+}
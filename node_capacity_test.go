@@ -0,0 +1,112 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/internal/stubs"
+)
+
+var _ = Describe("Node capacity", func() {
+	var (
+		node *cce.Node
+		app  *cce.App
+	)
+
+	BeforeEach(func() {
+		node = &cce.Node{
+			ID:       "48606c73-3905-47e0-864f-14bc7466f5bb",
+			Name:     "test-node",
+			Location: "test-location",
+		}
+		app = &cce.App{
+			ID:     "b4d2a7fd-b41c-4e25-9b8c-8ca8d2e0d8c6",
+			Cores:  4,
+			Memory: 4096,
+		}
+	})
+
+	Describe("Allocatable", func() {
+		features := map[string]string{
+			cce.NFDAllocatableCores:  "16",
+			cce.NFDAllocatableMemory: "32768",
+		}
+
+		It("Should prefer the resources set by the operator", func() {
+			node.Allocatable = &cce.Resources{Cores: 8, Memory: 8192}
+			Expect(cce.Allocatable(node, features)).To(Equal(&cce.Resources{Cores: 8, Memory: 8192}))
+		})
+
+		It("Should fall back to the resources reported by NFD", func() {
+			Expect(cce.Allocatable(node, features)).To(Equal(&cce.Resources{Cores: 16, Memory: 32768}))
+		})
+
+		It("Should return nil if the resources are unknown", func() {
+			Expect(cce.Allocatable(node, nil)).To(BeNil())
+			Expect(cce.Allocatable(node, map[string]string{
+				cce.NFDAllocatableCores:  "16",
+				cce.NFDAllocatableMemory: "lots",
+			})).To(BeNil())
+		})
+	})
+
+	Describe("CommittedResources", func() {
+		It("Should sum the resources of the replicas of the apps deployed to each node", func() {
+			ctx := context.Background()
+			ps := stubs.NewMemoryPersistenceService()
+			Expect(ps.Create(ctx, app)).To(Succeed())
+			Expect(ps.Create(ctx, &cce.App{
+				ID:     "3d1d6c4f-3b8e-4d4e-9a9b-2f5d0e3c8a11",
+				Cores:  2,
+				Memory: 1024,
+			})).To(Succeed())
+			Expect(ps.Create(ctx, &cce.NodeApp{
+				ID:     "a6b2a4a6-54c9-4a59-8a5e-5b1a9f7d2f01",
+				NodeID: node.ID,
+				AppID:  app.ID,
+			})).To(Succeed())
+			Expect(ps.Create(ctx, &cce.NodeApp{
+				ID:       "0d6d5c0f-1f0e-4a55-a4c2-7a0a6a3d7e02",
+				NodeID:   node.ID,
+				AppID:    "3d1d6c4f-3b8e-4d4e-9a9b-2f5d0e3c8a11",
+				Replicas: 3,
+			})).To(Succeed())
+
+			Expect(cce.CommittedResources(ctx, ps)).To(Equal(map[string]cce.Resources{
+				node.ID: {Cores: 10, Memory: 7168},
+			}))
+		})
+	})
+
+	Describe("CheckCapacity", func() {
+		allocatable := &cce.Resources{Cores: 8, Memory: 8192}
+
+		It("Should accept an app that fits", func() {
+			Expect(cce.CheckCapacity(app, 1, allocatable, cce.Resources{Cores: 4, Memory: 4096})).To(Succeed())
+		})
+
+		It("Should reject an app that over-commits the cores", func() {
+			Expect(cce.CheckCapacity(app, 1, allocatable, cce.Resources{Cores: 6, Memory: 1024})).To(
+				MatchError("insufficient cores: 4 required, 2 free"))
+		})
+
+		It("Should reject an app that over-commits the memory", func() {
+			Expect(cce.CheckCapacity(app, 1, allocatable, cce.Resources{Cores: 1, Memory: 6144})).To(
+				MatchError("insufficient memory: 4096 MB required, 2048 MB free"))
+		})
+
+		It("Should count every replica of the app", func() {
+			Expect(cce.CheckCapacity(app, 2, allocatable, cce.Resources{Cores: 1, Memory: 1024})).To(
+				MatchError("insufficient cores: 8 required, 7 free"))
+		})
+
+		It("Should not check a node with unknown resources", func() {
+			Expect(cce.CheckCapacity(app, 1, nil, cce.Resources{Cores: 64, Memory: 65536})).To(Succeed())
+		})
+	})
+})
@@ -6,13 +6,44 @@ package cce
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
+// MaxGRPCTargetHistory is the number of previous targets kept for a node.
+const MaxGRPCTargetHistory = 10
+
 // NodeGRPCTarget is a node's GRPC target.
 type NodeGRPCTarget struct {
 	ID         string `json:"id"`
 	NodeID     string `json:"node_id"`
 	GRPCTarget string `json:"grpc_target"`
+	// Previous lists the targets the node had before, most recent first.
+	Previous []PreviousGRPCTarget `json:"previous,omitempty"`
+}
+
+// PreviousGRPCTarget is a target a node no longer has.
+type PreviousGRPCTarget struct {
+	GRPCTarget string `json:"grpc_target"`
+	// Until is the time the node changed target.
+	Until time.Time `json:"until"`
+}
+
+// Move changes the target, keeping the current one in the history. It returns
+// false if the target is unchanged.
+func (t *NodeGRPCTarget) Move(target string, at time.Time) bool {
+	if target == t.GRPCTarget {
+		return false
+	}
+
+	t.Previous = append([]PreviousGRPCTarget{{
+		GRPCTarget: t.GRPCTarget,
+		Until:      at,
+	}}, t.Previous...)
+	if len(t.Previous) > MaxGRPCTargetHistory {
+		t.Previous = t.Previous[:MaxGRPCTargetHistory]
+	}
+	t.GRPCTarget = target
+	return true
 }
 
 // GetTableName returns the name of the persistence table.
@@ -39,6 +70,7 @@ func (t *NodeGRPCTarget) GetNodeID() string {
 func (t *NodeGRPCTarget) FilterFields() []string {
 	return []string{
 		"node_id",
+		"grpc_target",
 	}
 }
 
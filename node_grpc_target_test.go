@@ -4,7 +4,9 @@
 package cce_test
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -54,10 +56,45 @@ var _ = Describe("Entities: NodeGRPCTarget", func() {
 		})
 	})
 
+	Describe("Move", func() {
+		It("Should keep the previous target", func() {
+			at := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+
+			Expect(target.Move("127.0.0.2", at)).To(BeTrue())
+			Expect(target.GRPCTarget).To(Equal("127.0.0.2"))
+			Expect(target.Previous).To(Equal([]cce.PreviousGRPCTarget{
+				{GRPCTarget: "127.0.0.1", Until: at},
+			}))
+
+			By("Moving to the same target")
+			Expect(target.Move("127.0.0.2", at.Add(time.Hour))).To(BeFalse())
+			Expect(target.Previous).To(HaveLen(1))
+
+			By("Moving again")
+			Expect(target.Move("127.0.0.3", at.Add(time.Hour))).To(BeTrue())
+			Expect(target.Previous).To(Equal([]cce.PreviousGRPCTarget{
+				{GRPCTarget: "127.0.0.2", Until: at.Add(time.Hour)},
+				{GRPCTarget: "127.0.0.1", Until: at},
+			}))
+		})
+
+		It("Should limit the history", func() {
+			at := time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+			for i := 0; i < cce.MaxGRPCTargetHistory+5; i++ {
+				Expect(target.Move(fmt.Sprintf("10.0.0.%d", i), at)).To(BeTrue())
+			}
+
+			Expect(target.Previous).To(HaveLen(cce.MaxGRPCTargetHistory))
+			Expect(target.Previous[0].GRPCTarget).To(Equal(
+				fmt.Sprintf("10.0.0.%d", cce.MaxGRPCTargetHistory+3)))
+		})
+	})
+
 	Describe("FilterFields", func() {
 		It("Should return the filterable fields", func() {
 			Expect(target.FilterFields()).To(Equal([]string{
 				"node_id",
+				"grpc_target",
 			}))
 		})
 	})
@@ -0,0 +1,105 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// Node identities are fingerprints of the node's public key: the
+// base64-encoded (w/o padding) hash of its DER-encoded SubjectPublicKeyInfo.
+const (
+	// FingerprintSHA256 is the algorithm of node identities.
+	FingerprintSHA256 = "sha256"
+	// FingerprintMD5 is the algorithm of node identities registered before
+	// SHA-256 was adopted. They are still matched until the node is migrated.
+	FingerprintMD5 = "md5"
+)
+
+// KeyFingerprint returns the SHA-256 fingerprint identifying a node's key.
+func KeyFingerprint(publicKeyInfo []byte) string {
+	hash := sha256.Sum256(publicKeyInfo)
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// LegacyKeyFingerprint returns the MD5 fingerprint identifying a node's key.
+func LegacyKeyFingerprint(publicKeyInfo []byte) string {
+	// gosec: not hashing user input/passwords
+	hash := md5.Sum(publicKeyInfo) //nolint:gosec
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// FingerprintAlgorithm returns the algorithm of a fingerprint, or an empty
+// string if it isn't one.
+func FingerprintAlgorithm(fingerprint string) string {
+	switch len(fingerprint) {
+	case base64.RawURLEncoding.EncodedLen(sha256.Size):
+		return FingerprintSHA256
+	case base64.RawURLEncoding.EncodedLen(md5.Size):
+		return FingerprintMD5
+	}
+	return ""
+}
+
+// MatchesKeyFingerprint reports whether the fingerprint, of either algorithm,
+// identifies the key.
+func MatchesKeyFingerprint(fingerprint string, publicKeyInfo []byte) bool {
+	switch FingerprintAlgorithm(fingerprint) {
+	case FingerprintSHA256:
+		return fingerprint == KeyFingerprint(publicKeyInfo)
+	case FingerprintMD5:
+		return fingerprint == LegacyKeyFingerprint(publicKeyInfo)
+	}
+	return false
+}
+
+// MigrateNodeSerials replaces the MD5 serials of enrolled nodes with the
+// SHA-256 fingerprint of the key they were issued a certificate for, and
+// returns the number of nodes migrated. Nodes that have not enrolled yet are
+// migrated when they do.
+func MigrateNodeSerials(ctx context.Context, ps PersistenceService) (int, error) {
+	persisted, err := ps.ReadAll(ctx, &Node{})
+	if err != nil {
+		return 0, err
+	}
+
+	var migrated []Persistable
+	for _, p := range persisted {
+		node := p.(*Node)
+		if FingerprintAlgorithm(node.Serial) != FingerprintMD5 {
+			continue
+		}
+
+		creds, err := ps.Read(ctx, node.ID, &Credentials{})
+		if err != nil {
+			return 0, err
+		}
+		if creds == nil {
+			continue
+		}
+		cert, err := creds.(*Credentials).ParseCertificate()
+		if err != nil {
+			return 0, err
+		}
+
+		// The node may have been registered again with another key
+		if LegacyKeyFingerprint(cert.RawSubjectPublicKeyInfo) != node.Serial {
+			continue
+		}
+
+		node.Serial = KeyFingerprint(cert.RawSubjectPublicKeyInfo)
+		migrated = append(migrated, node)
+	}
+
+	if len(migrated) == 0 {
+		return 0, nil
+	}
+	if err = ps.BulkUpdate(ctx, migrated); err != nil {
+		return 0, err
+	}
+	return len(migrated), nil
+}
@@ -0,0 +1,125 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/internal/stubs"
+)
+
+var _ = Describe("Node identity", func() {
+	var (
+		publicKeyInfo []byte
+	)
+
+	BeforeEach(func() {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		publicKeyInfo, err = x509.MarshalPKIXPublicKey(key.Public())
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	Describe("FingerprintAlgorithm", func() {
+		It("Should return the algorithm of a fingerprint", func() {
+			Expect(cce.FingerprintAlgorithm(cce.KeyFingerprint(publicKeyInfo))).To(
+				Equal(cce.FingerprintSHA256))
+			Expect(cce.FingerprintAlgorithm(cce.LegacyKeyFingerprint(publicKeyInfo))).To(
+				Equal(cce.FingerprintMD5))
+			Expect(cce.FingerprintAlgorithm("abc123")).To(BeEmpty())
+		})
+	})
+
+	Describe("MatchesKeyFingerprint", func() {
+		It("Should match fingerprints of either algorithm", func() {
+			Expect(cce.MatchesKeyFingerprint(cce.KeyFingerprint(publicKeyInfo), publicKeyInfo)).To(BeTrue())
+			Expect(cce.MatchesKeyFingerprint(cce.LegacyKeyFingerprint(publicKeyInfo), publicKeyInfo)).To(BeTrue())
+			Expect(cce.MatchesKeyFingerprint("abc123", publicKeyInfo)).To(BeFalse())
+		})
+	})
+
+	Describe("MigrateNodeSerials", func() {
+		var (
+			ctx = context.Background()
+			ps  *stubs.MemoryPersistenceService
+		)
+
+		// enroll stores a certificate issued for the key to the node
+		enroll := func(nodeID string, key *ecdsa.PrivateKey) {
+			template := &x509.Certificate{
+				SerialNumber: big.NewInt(1),
+				Subject:      pkix.Name{CommonName: nodeID},
+				NotBefore:    time.Now(),
+				NotAfter:     time.Now().Add(time.Hour),
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ps.Create(ctx, &cce.Credentials{
+				ID:          nodeID,
+				Certificate: string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+			})).To(Succeed())
+		}
+
+		newNode := func(id, serial string) *cce.Node {
+			node := &cce.Node{ID: id, Name: "node", Location: "lab", Serial: serial}
+			Expect(ps.Create(ctx, node)).To(Succeed())
+			return node
+		}
+
+		serialOf := func(id string) string {
+			persisted, err := ps.Read(ctx, id, &cce.Node{})
+			Expect(err).ToNot(HaveOccurred())
+			return persisted.(*cce.Node).Serial
+		}
+
+		BeforeEach(func() {
+			ps = stubs.NewMemoryPersistenceService()
+		})
+
+		It("Should migrate enrolled nodes with MD5 serials", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			spki, err := x509.MarshalPKIXPublicKey(key.Public())
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Storing an enrolled node, a node not enrolled yet and a migrated node")
+			newNode("enrolled", cce.LegacyKeyFingerprint(spki))
+			enroll("enrolled", key)
+			newNode("pending", cce.LegacyKeyFingerprint(publicKeyInfo))
+			newNode("migrated", cce.KeyFingerprint(publicKeyInfo))
+
+			migrated, err := cce.MigrateNodeSerials(ctx, ps)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(migrated).To(Equal(1))
+
+			Expect(serialOf("enrolled")).To(Equal(cce.KeyFingerprint(spki)))
+			Expect(serialOf("pending")).To(Equal(cce.LegacyKeyFingerprint(publicKeyInfo)))
+			Expect(serialOf("migrated")).To(Equal(cce.KeyFingerprint(publicKeyInfo)))
+		})
+
+		It("Should not migrate a node registered again with another key", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+
+			newNode("node", cce.LegacyKeyFingerprint(publicKeyInfo))
+			enroll("node", key)
+
+			migrated, err := cce.MigrateNodeSerials(ctx, ps)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(migrated).To(BeZero())
+			Expect(serialOf("node")).To(Equal(cce.LegacyKeyFingerprint(publicKeyInfo)))
+		})
+	})
+})
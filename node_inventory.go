@@ -0,0 +1,124 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// MaxInventoryHistory is the number of interface changes kept in a node's
+// inventory.
+const MaxInventoryHistory = 20
+
+// NodeInventory describes the hardware and network of a node and the apps
+// deployed to it, as last collected by the controller.
+type NodeInventory struct {
+	ID     string `json:"id"`
+	NodeID string `json:"node_id"`
+	// CollectedAt is the time of the last collection.
+	CollectedAt time.Time `json:"collected_at"`
+	// Interfaces are the network interfaces reported by the node.
+	Interfaces []*NetworkInterface `json:"interfaces"`
+	// Features are the NFD labels of the node.
+	Features map[string]string `json:"features"`
+	// Apps are the IDs of the apps deployed to the node.
+	Apps []string `json:"apps"`
+	// History lists the changes of the interfaces, most recent first.
+	History []InterfaceChange `json:"history,omitempty"`
+}
+
+// InterfaceChange is a change of a node's network interfaces between two
+// collections.
+type InterfaceChange struct {
+	At      time.Time           `json:"at"`
+	Added   []*NetworkInterface `json:"added,omitempty"`
+	Removed []*NetworkInterface `json:"removed,omitempty"`
+	// Changed holds the new state of the interfaces that changed.
+	Changed []*NetworkInterface `json:"changed,omitempty"`
+}
+
+// SetInterfaces replaces the interfaces, recording the difference in the
+// history. It returns the change, or nil if the interfaces are unchanged.
+func (i *NodeInventory) SetInterfaces(ifaces []*NetworkInterface, at time.Time) *InterfaceChange {
+	change := InterfaceChange{At: at}
+
+	prev := make(map[string]*NetworkInterface, len(i.Interfaces))
+	for _, iface := range i.Interfaces {
+		prev[iface.ID] = iface
+	}
+	for _, iface := range ifaces {
+		old, ok := prev[iface.ID]
+		switch {
+		case !ok:
+			change.Added = append(change.Added, iface)
+		case !reflect.DeepEqual(old, iface):
+			change.Changed = append(change.Changed, iface)
+		}
+		delete(prev, iface.ID)
+	}
+	for _, iface := range i.Interfaces {
+		if _, ok := prev[iface.ID]; ok {
+			change.Removed = append(change.Removed, iface)
+		}
+	}
+
+	i.Interfaces = ifaces
+	if len(change.Added) == 0 && len(change.Removed) == 0 && len(change.Changed) == 0 {
+		return nil
+	}
+
+	i.History = append([]InterfaceChange{change}, i.History...)
+	if len(i.History) > MaxInventoryHistory {
+		i.History = i.History[:MaxInventoryHistory]
+	}
+	return &change
+}
+
+// GetTableName returns the name of the persistence table.
+func (*NodeInventory) GetTableName() string {
+	return "node_inventories"
+}
+
+// GetID gets the ID.
+func (i *NodeInventory) GetID() string {
+	return i.ID
+}
+
+// SetID sets the ID.
+func (i *NodeInventory) SetID(id string) {
+	i.ID = id
+}
+
+// GetNodeID gets the node ID.
+func (i *NodeInventory) GetNodeID() string {
+	return i.NodeID
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*NodeInventory) FilterFields() []string {
+	return []string{
+		"node_id",
+	}
+}
+
+func (i *NodeInventory) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+NodeInventory[
+    ID: %s
+    NodeID: %s
+    CollectedAt: %s
+    Interfaces: %d
+    Features: %d
+    Apps: %v
+]`),
+		i.ID,
+		i.NodeID,
+		i.CollectedAt.Format(time.RFC3339),
+		len(i.Interfaces),
+		len(i.Features),
+		i.Apps)
+}
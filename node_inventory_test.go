@@ -0,0 +1,126 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: NodeInventory", func() {
+	var (
+		inv *cce.NodeInventory
+		at  = time.Date(2020, 3, 1, 12, 0, 0, 0, time.UTC)
+	)
+
+	BeforeEach(func() {
+		inv = &cce.NodeInventory{
+			ID:          "ca0fa495-1020-405b-a78c-9a1884349078",
+			NodeID:      "48606c73-3905-47e0-864f-14bc7466f5bb",
+			CollectedAt: at,
+			Interfaces: []*cce.NetworkInterface{
+				{ID: "if0", MACAddress: "mac0"},
+				{ID: "if1", MACAddress: "mac1"},
+			},
+			Features: map[string]string{"cpu-cpuid.AVX512F": "true"},
+			Apps:     []string{"b4d2a7fd-b41c-4e25-9b8c-8ca8d2e0d8c6"},
+		}
+	})
+
+	Describe("SetInterfaces", func() {
+		It("Should record added, removed and changed interfaces", func() {
+			change := inv.SetInterfaces([]*cce.NetworkInterface{
+				{ID: "if0", MACAddress: "mac0", VLAN: 1},
+				{ID: "if2", MACAddress: "mac2"},
+			}, at)
+
+			Expect(change).ToNot(BeNil())
+			Expect(*change).To(Equal(cce.InterfaceChange{
+				At:      at,
+				Added:   []*cce.NetworkInterface{{ID: "if2", MACAddress: "mac2"}},
+				Removed: []*cce.NetworkInterface{{ID: "if1", MACAddress: "mac1"}},
+				Changed: []*cce.NetworkInterface{{ID: "if0", MACAddress: "mac0", VLAN: 1}},
+			}))
+			Expect(inv.History).To(Equal([]cce.InterfaceChange{*change}))
+			Expect(inv.Interfaces).To(HaveLen(2))
+		})
+
+		It("Should not record unchanged interfaces", func() {
+			Expect(inv.SetInterfaces([]*cce.NetworkInterface{
+				{ID: "if1", MACAddress: "mac1"},
+				{ID: "if0", MACAddress: "mac0"},
+			}, at)).To(BeNil())
+			Expect(inv.History).To(BeEmpty())
+		})
+
+		It("Should limit the history", func() {
+			for i := 0; i < cce.MaxInventoryHistory+5; i++ {
+				Expect(inv.SetInterfaces([]*cce.NetworkInterface{
+					{ID: fmt.Sprintf("if%d", i)},
+				}, at.Add(time.Duration(i)*time.Minute))).ToNot(BeNil())
+			}
+
+			Expect(inv.History).To(HaveLen(cce.MaxInventoryHistory))
+			Expect(inv.History[0].At).To(Equal(at.Add(time.Duration(cce.MaxInventoryHistory+4) * time.Minute)))
+		})
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "node_inventories"`, func() {
+			Expect(inv.GetTableName()).To(Equal("node_inventories"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(inv.GetID()).To(Equal(
+				"ca0fa495-1020-405b-a78c-9a1884349078"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			inv.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(inv.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("GetNodeID", func() {
+		It("Should return the node ID", func() {
+			Expect(inv.GetNodeID()).To(Equal(
+				"48606c73-3905-47e0-864f-14bc7466f5bb"))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(inv.FilterFields()).To(Equal([]string{
+				"node_id",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(inv.String()).To(Equal(strings.TrimSpace(`
+NodeInventory[
+    ID: ca0fa495-1020-405b-a78c-9a1884349078
+    NodeID: 48606c73-3905-47e0-864f-14bc7466f5bb
+    CollectedAt: 2020-03-01T12:00:00Z
+    Interfaces: 2
+    Features: 1
+    Apps: [b4d2a7fd-b41c-4e25-9b8c-8ca8d2e0d8c6]
+]`,
+			)))
+		})
+	})
+})
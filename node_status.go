@@ -0,0 +1,87 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// NodeState is a node's reachability as last observed by the controller.
+type NodeState string
+
+const (
+	// NodeStateUnknown means the node has not been probed yet
+	NodeStateUnknown NodeState = "unknown"
+	// NodeStateOnline means both ELA and EVA answered the last probe
+	NodeStateOnline NodeState = "online"
+	// NodeStateDegraded means only one of ELA and EVA answered the last probe
+	NodeStateDegraded NodeState = "degraded"
+	// NodeStateOffline means neither ELA nor EVA answered the last probe
+	NodeStateOffline NodeState = "offline"
+)
+
+// NodeStatus is the liveness of a node as observed by periodic probes.
+type NodeStatus struct {
+	ID     string    `json:"id"`
+	NodeID string    `json:"node_id"`
+	Status NodeState `json:"status"`
+	// LastSeen is the time of the last probe answered by any node agent.
+	LastSeen time.Time `json:"last_seen"`
+	// LastProbed is the time of the last probe, answered or not.
+	LastProbed time.Time `json:"last_probed"`
+	// Latency is the round trip time (in ms) of the slowest answered probe.
+	Latency int64 `json:"latency"`
+	// Error describes why the last probe failed, if it did.
+	Error string `json:"error,omitempty"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*NodeStatus) GetTableName() string {
+	return "node_statuses"
+}
+
+// GetID gets the ID.
+func (s *NodeStatus) GetID() string {
+	return s.ID
+}
+
+// SetID sets the ID.
+func (s *NodeStatus) SetID(id string) {
+	s.ID = id
+}
+
+// GetNodeID gets the node ID.
+func (s *NodeStatus) GetNodeID() string {
+	return s.NodeID
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*NodeStatus) FilterFields() []string {
+	return []string{
+		"node_id",
+		"status",
+	}
+}
+
+func (s *NodeStatus) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+NodeStatus[
+    ID: %s
+    NodeID: %s
+    Status: %s
+    LastSeen: %s
+    LastProbed: %s
+    Latency: %d
+    Error: %s
+]`),
+		s.ID,
+		s.NodeID,
+		s.Status,
+		s.LastSeen.Format(time.RFC3339),
+		s.LastProbed.Format(time.RFC3339),
+		s.Latency,
+		s.Error)
+}
@@ -0,0 +1,86 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: NodeStatus", func() {
+	var (
+		status *cce.NodeStatus
+	)
+
+	BeforeEach(func() {
+		status = &cce.NodeStatus{
+			ID:         "ca0fa495-1020-405b-a78c-9a1884349078",
+			NodeID:     "48606c73-3905-47e0-864f-14bc7466f5bb",
+			Status:     cce.NodeStateDegraded,
+			LastSeen:   time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+			LastProbed: time.Date(2020, 1, 2, 3, 4, 35, 0, time.UTC),
+			Latency:    12,
+			Error:      "port 42102: connection refused",
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "node_statuses"`, func() {
+			Expect(status.GetTableName()).To(Equal("node_statuses"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(status.GetID()).To(Equal(
+				"ca0fa495-1020-405b-a78c-9a1884349078"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			status.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(status.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("GetNodeID", func() {
+		It("Should return the node ID", func() {
+			Expect(status.GetNodeID()).To(Equal(
+				"48606c73-3905-47e0-864f-14bc7466f5bb"))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(status.FilterFields()).To(Equal([]string{
+				"node_id",
+				"status",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(status.String()).To(Equal(strings.TrimSpace(`
+NodeStatus[
+    ID: ca0fa495-1020-405b-a78c-9a1884349078
+    NodeID: 48606c73-3905-47e0-864f-14bc7466f5bb
+    Status: degraded
+    LastSeen: 2020-01-02T03:04:05Z
+    LastProbed: 2020-01-02T03:04:35Z
+    Latency: 12
+    Error: port 42102: connection refused
+]`,
+			)))
+		})
+	})
+})
@@ -71,9 +71,22 @@ var _ = Describe("Entities: Node", func() {
 			Expect(node.Validate()).To(MatchError("location cannot be empty"))
 		})
 
-		It("Should return an error if Serial is empty", func() {
+		It("Should allow an empty Serial", func() {
 			node.Serial = ""
-			Expect(node.Validate()).To(MatchError("serial cannot be empty"))
+			Expect(node.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if a label key is empty", func() {
+			node.Labels = map[string]string{"": "edge"}
+			Expect(node.Validate()).To(MatchError("labels cannot have an empty key"))
+		})
+
+		It("Should return an error if the allocatable resources are not positive", func() {
+			node.Allocatable = &cce.Resources{Cores: 0, Memory: 4096}
+			Expect(node.Validate()).To(MatchError("allocatable.cores must be greater than 0"))
+
+			node.Allocatable = &cce.Resources{Cores: 4, Memory: 0}
+			Expect(node.Validate()).To(MatchError("allocatable.memory must be greater than 0"))
 		})
 	})
 
@@ -98,3 +111,39 @@ Node[
 		})
 	})
 })
+
+var _ = Describe("Entities: NodeReq", func() {
+	var (
+		nodeReq *cce.NodeReq
+	)
+
+	BeforeEach(func() {
+		nodeReq = &cce.NodeReq{
+			NetworkInterfaces: []*cce.NetworkInterface{
+				{
+					ID:    "if0",
+					Zones: []string{"84c1f7b9-53e7-408e-9223-deab73befc54"},
+				},
+				{
+					ID: "if1",
+				},
+			},
+		}
+	})
+
+	Describe("ValidateZones", func() {
+		It("Should allow zones assigned to the node", func() {
+			Expect(nodeReq.ValidateZones([]string{
+				"9d740ba5-6ba9-4e9b-a0fc-9ea5d84e1a4b",
+				"84c1f7b9-53e7-408e-9223-deab73befc54",
+			})).To(Succeed())
+		})
+
+		It("Should return an error if a zone is not assigned to the node", func() {
+			Expect(nodeReq.ValidateZones([]string{
+				"9d740ba5-6ba9-4e9b-a0fc-9ea5d84e1a4b",
+			})).To(MatchError("network_interfaces[0].zones[0] " +
+				"84c1f7b9-53e7-408e-9223-deab73befc54 is not a zone of the node"))
+		})
+	})
+})
@@ -0,0 +1,74 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+// NodeZone represents an association between a Node and a Zone.
+type NodeZone struct {
+	ID     string `json:"id"`
+	NodeID string `json:"node_id"`
+	ZoneID string `json:"zone_id"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*NodeZone) GetTableName() string {
+	return "nodes_zones"
+}
+
+// GetID gets the ID.
+func (n_z *NodeZone) GetID() string {
+	return n_z.ID
+}
+
+// SetID sets the ID.
+func (n_z *NodeZone) SetID(id string) {
+	n_z.ID = id
+}
+
+// GetNodeID gets the node ID.
+func (n_z *NodeZone) GetNodeID() string {
+	return n_z.NodeID
+}
+
+// Validate validates the model.
+func (n_z *NodeZone) Validate() error {
+	if !uuid.IsValid(n_z.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if !uuid.IsValid(n_z.NodeID) {
+		return errors.New("node_id not a valid uuid")
+	}
+	if !uuid.IsValid(n_z.ZoneID) {
+		return errors.New("zone_id not a valid uuid")
+	}
+
+	return nil
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*NodeZone) FilterFields() []string {
+	return []string{
+		"node_id",
+		"zone_id",
+	}
+}
+
+func (n_z *NodeZone) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+NodeZone[
+    ID: %s
+    NodeID: %s
+    ZoneID: %s
+]`),
+		n_z.ID,
+		n_z.NodeID,
+		n_z.ZoneID)
+}
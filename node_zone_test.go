@@ -0,0 +1,95 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Join Entities: NodeZone", func() {
+	var (
+		nz *cce.NodeZone
+	)
+
+	BeforeEach(func() {
+		nz = &cce.NodeZone{
+			ID:     "6c7eacb8-7b95-4541-940c-aa18a6204645",
+			NodeID: "48606c73-3905-47e0-864f-14bc7466f5bb",
+			ZoneID: "84c1f7b9-53e7-408e-9223-deab73befc54",
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "nodes_zones"`, func() {
+			Expect(nz.GetTableName()).To(Equal("nodes_zones"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(nz.GetID()).To(Equal(
+				"6c7eacb8-7b95-4541-940c-aa18a6204645"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			nz.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(nz.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("GetNodeID", func() {
+		It("Should return the node ID", func() {
+			Expect(nz.GetNodeID()).To(Equal(
+				"48606c73-3905-47e0-864f-14bc7466f5bb"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should return an error if ID is not a UUID", func() {
+			nz.ID = "123"
+			Expect(nz.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if NodeID is not a UUID", func() {
+			nz.NodeID = "123"
+			Expect(nz.Validate()).To(MatchError("node_id not a valid uuid"))
+		})
+
+		It("Should return an error if ZoneID is not a UUID", func() {
+			nz.ZoneID = "123"
+			Expect(nz.Validate()).To(MatchError(
+				"zone_id not a valid uuid"))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(nz.FilterFields()).To(Equal([]string{
+				"node_id",
+				"zone_id",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(nz.String()).To(Equal(strings.TrimSpace(`
+NodeZone[
+    ID: 6c7eacb8-7b95-4541-940c-aa18a6204645
+    NodeID: 48606c73-3905-47e0-864f-14bc7466f5bb
+    ZoneID: 84c1f7b9-53e7-408e-9223-deab73befc54
+]`,
+			)))
+		})
+	})
+})
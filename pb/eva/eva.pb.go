@@ -102,22 +102,22 @@ func (LifecycleStatus_Status) EnumDescriptor() ([]byte, []int) {
 // Image sources will be added over time. For example, pulling from external
 // Docker registries may be supported with a source such as:
 //
-//    // Image will be downloaded from a Docker registry
-//    message DockerRegistrySource {
-//        string repo = 1;
-//        string tag = 2;
+//	// Image will be downloaded from a Docker registry
+//	message DockerRegistrySource {
+//	    string repo = 1;
+//	    string tag = 2;
 //
-//        // authentication
-//        string user = 3;
-//        string token = 4;
-//    }
+//	    // authentication
+//	    string user = 3;
+//	    string token = 4;
+//	}
 //
 // And then adding to the source field:
 //
-//     oneof source {
-//         ...
-//         DockerRegistrySource docker_registry = 9 + N;
-//     }
+//	oneof source {
+//	    ...
+//	    DockerRegistrySource docker_registry = 9 + N;
+//	}
 type Application struct {
 	Id          string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
 	Name        string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
@@ -139,10 +139,23 @@ type Application struct {
 	// an array of string key-value pairs. Specific keys are defined by their respective features.
 	EACJsonBlob string `protobuf:"bytes,11,opt,name=EACJsonBlob,proto3" json:"EACJsonBlob,omitempty"`
 	// CNI configuration for the application
-	CniConf              *CNIConfiguration `protobuf:"bytes,12,opt,name=cniConf,proto3" json:"cniConf,omitempty"`
-	XXX_NoUnkeyedLiteral struct{}          `json:"-"`
-	XXX_unrecognized     []byte            `json:"-"`
-	XXX_sizecache        int32             `json:"-"`
+	CniConf *CNIConfiguration `protobuf:"bytes,12,opt,name=cniConf,proto3" json:"cniConf,omitempty"`
+	// Digest of the image retrieved from the source, as sha256:<hex>. The
+	// image is rejected if it does not match.
+	Digest string `protobuf:"bytes,13,opt,name=digest,proto3" json:"digest,omitempty"`
+	// Detached signature of the image by its publisher, verified with
+	// publisherKey.
+	Signature []byte `protobuf:"bytes,14,opt,name=signature,proto3" json:"signature,omitempty"`
+	// PEM-encoded public key of the publisher of the image.
+	PublisherKey []byte `protobuf:"bytes,15,opt,name=publisherKey,proto3" json:"publisherKey,omitempty"`
+	// Runtime configuration of the application as JSON: environment
+	// variables, command, arguments, config files and secret references.
+	ConfigJsonBlob string `protobuf:"bytes,16,opt,name=configJsonBlob,proto3" json:"configJsonBlob,omitempty"`
+	// Health probes and restart policy of the application as JSON.
+	HealthJsonBlob       string   `protobuf:"bytes,17,opt,name=healthJsonBlob,proto3" json:"healthJsonBlob,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
 func (m *Application) Reset()         { *m = Application{} }
@@ -271,6 +284,41 @@ func (m *Application) GetCniConf() *CNIConfiguration {
 	return nil
 }
 
+func (m *Application) GetDigest() string {
+	if m != nil {
+		return m.Digest
+	}
+	return ""
+}
+
+func (m *Application) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *Application) GetPublisherKey() []byte {
+	if m != nil {
+		return m.PublisherKey
+	}
+	return nil
+}
+
+func (m *Application) GetConfigJsonBlob() string {
+	if m != nil {
+		return m.ConfigJsonBlob
+	}
+	return ""
+}
+
+func (m *Application) GetHealthJsonBlob() string {
+	if m != nil {
+		return m.HealthJsonBlob
+	}
+	return ""
+}
+
 // XXX_OneofWrappers is for the internal use of the proto package.
 func (*Application) XXX_OneofWrappers() []interface{} {
 	return []interface{}{
@@ -695,61 +743,65 @@ func init() {
 func init() { proto.RegisterFile("eva.proto", fileDescriptor_78739cf76c9af146) }
 
 var fileDescriptor_78739cf76c9af146 = []byte{
-	// 855 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0xdd, 0x6e, 0xe2, 0x46,
-	0x14, 0xc6, 0xfc, 0x19, 0x0e, 0x24, 0x6b, 0x8d, 0xaa, 0x5d, 0x87, 0x6c, 0xb6, 0xc8, 0x5a, 0xb5,
-	0x48, 0x55, 0x1d, 0x89, 0xde, 0xac, 0xd4, 0x56, 0x2d, 0x01, 0x9a, 0xa5, 0xcd, 0x82, 0x35, 0x90,
-	0xad, 0xb6, 0x37, 0x95, 0x63, 0x06, 0x32, 0x92, 0x3d, 0x63, 0x8d, 0x07, 0x24, 0x7a, 0xd1, 0xfb,
-	0x3e, 0x4b, 0xef, 0xfa, 0x06, 0x7d, 0xa5, 0x3e, 0x41, 0x35, 0x63, 0x03, 0x0e, 0x15, 0x91, 0xba,
-	0xb9, 0xf2, 0x9c, 0xef, 0x9c, 0xf3, 0x9d, 0x9f, 0xf9, 0x3c, 0x50, 0x27, 0x6b, 0xdf, 0x8d, 0x05,
-	0x97, 0x1c, 0x35, 0x79, 0x4c, 0x18, 0x23, 0x49, 0xe2, 0x92, 0xb5, 0xdf, 0x3a, 0x5f, 0x72, 0xbe,
-	0x0c, 0xc9, 0xa5, 0xf6, 0xdd, 0xad, 0x16, 0x97, 0x24, 0x8a, 0xe5, 0x26, 0x0d, 0x75, 0xfe, 0x29,
-	0x41, 0xa3, 0x17, 0xc7, 0x21, 0x0d, 0x7c, 0x49, 0x39, 0x43, 0xa7, 0x50, 0xa4, 0x73, 0xdb, 0x68,
-	0x1b, 0x9d, 0x3a, 0x2e, 0xd2, 0x39, 0x42, 0x50, 0x66, 0x7e, 0x44, 0xec, 0xa2, 0x46, 0xf4, 0x19,
-	0xd9, 0x60, 0xae, 0x89, 0x48, 0x28, 0x67, 0x76, 0x49, 0xc3, 0x5b, 0x13, 0x3d, 0x87, 0xea, 0x9a,
-	0xb0, 0x39, 0x17, 0x76, 0x59, 0x3b, 0x32, 0x0b, 0xb5, 0xa1, 0x31, 0x27, 0x49, 0x20, 0x68, 0xac,
-	0x8a, 0xd8, 0x15, 0xed, 0xcc, 0x43, 0xe8, 0x13, 0xa8, 0x04, 0x5c, 0x90, 0xc4, 0xae, 0xb6, 0x8d,
-	0x4e, 0x05, 0xa7, 0x86, 0xe2, 0x8b, 0x48, 0xc4, 0xc5, 0xc6, 0x36, 0x35, 0x9c, 0x59, 0xe8, 0x4b,
-	0xa8, 0xc4, 0x5c, 0xc8, 0xc4, 0xae, 0xb5, 0x4b, 0x9d, 0x46, 0xf7, 0x85, 0x9b, 0x1f, 0xd8, 0xf5,
-	0xb8, 0x90, 0x9e, 0x9a, 0x0e, 0xa7, 0x51, 0xe8, 0x1b, 0xa8, 0x26, 0xd2, 0x97, 0xab, 0xc4, 0xae,
-	0xb7, 0x8d, 0xce, 0x69, 0xf7, 0xf5, 0xc3, 0xf8, 0x1b, 0xba, 0x20, 0xc1, 0x26, 0x08, 0xc9, 0x54,
-	0x07, 0xb9, 0xe9, 0x07, 0x67, 0x39, 0xa8, 0x07, 0xb5, 0x7b, 0x29, 0xe3, 0x5f, 0x57, 0x82, 0xda,
-	0xd0, 0x36, 0x3a, 0x8d, 0xc3, 0xfc, 0xdc, 0xfe, 0xdc, 0xb7, 0xb3, 0x99, 0x37, 0xe5, 0x2b, 0x11,
-	0x90, 0xb7, 0x05, 0x6c, 0xaa, 0xbc, 0x5b, 0x41, 0xd5, 0xfc, 0xc3, 0x5e, 0xff, 0xc7, 0x84, 0xb3,
-	0xab, 0x90, 0xdf, 0xd9, 0x8d, 0x74, 0xfe, 0x1c, 0x84, 0xde, 0x80, 0x19, 0x30, 0xda, 0xe7, 0x6c,
-	0x61, 0x37, 0x75, 0x8d, 0x57, 0x0f, 0x6b, 0xf4, 0xc7, 0x23, 0xe5, 0xa4, 0xcb, 0x95, 0xd0, 0x85,
-	0xf0, 0x36, 0xbc, 0xf5, 0x39, 0xc0, 0xbe, 0x28, 0x3a, 0xcb, 0x35, 0x9b, 0xde, 0xe2, 0xb6, 0x89,
-	0xab, 0x1a, 0x54, 0x13, 0x1d, 0xe4, 0xfc, 0x0e, 0xd6, 0x21, 0x1f, 0x7a, 0x09, 0xf5, 0x8c, 0x91,
-	0x2e, 0xb3, 0xcc, 0x3d, 0x80, 0x5e, 0xc3, 0x09, 0x65, 0x92, 0x88, 0x85, 0x1f, 0x90, 0xf1, 0x5e,
-	0x0f, 0x0f, 0x41, 0x25, 0x96, 0xd8, 0x97, 0xf7, 0x99, 0x2a, 0xf4, 0x59, 0x61, 0xbe, 0x58, 0x26,
-	0x99, 0x20, 0xf4, 0xd9, 0xf9, 0x14, 0x4e, 0x72, 0x3b, 0x1b, 0x0d, 0x0e, 0x55, 0xe7, 0xbc, 0x83,
-	0x66, 0x2e, 0x20, 0x41, 0xdf, 0x42, 0xd3, 0xcf, 0xd9, 0xb6, 0xa1, 0xaf, 0xfd, 0xec, 0xe8, 0x35,
-	0xe0, 0x07, 0xe1, 0xce, 0xd7, 0x50, 0xdf, 0x69, 0x42, 0x37, 0xc9, 0x85, 0xd4, 0xd5, 0x4e, 0xb0,
-	0x3e, 0xa3, 0x16, 0xd4, 0xf4, 0xef, 0x10, 0xf0, 0x30, 0x9b, 0x6c, 0x67, 0x3b, 0x7f, 0x18, 0x60,
-	0xed, 0x14, 0xd2, 0xe7, 0x51, 0xe4, 0xb3, 0xf9, 0x7f, 0x7e, 0x93, 0x37, 0x50, 0x0a, 0xa2, 0xb9,
-	0xce, 0x3d, 0xed, 0x7e, 0x76, 0x44, 0x5e, 0x59, 0xb2, 0x9b, 0x7d, 0xb1, 0x4a, 0x71, 0xbe, 0x00,
-	0x73, 0x4b, 0x5a, 0x87, 0xca, 0x74, 0xd6, 0xc3, 0x33, 0xab, 0x80, 0x6a, 0x50, 0x9e, 0xce, 0x26,
-	0x9e, 0x65, 0xa0, 0x06, 0x98, 0x78, 0x98, 0xc2, 0x45, 0xe7, 0x6f, 0x03, 0x9e, 0x1d, 0xa8, 0x35,
-	0x27, 0x6e, 0xe3, 0xff, 0x8b, 0xdb, 0x89, 0xa1, 0x9a, 0xf1, 0x34, 0xc0, 0xbc, 0x1d, 0xff, 0x34,
-	0x9e, 0xfc, 0x3c, 0xb6, 0x0a, 0xe8, 0x04, 0xea, 0x83, 0xa1, 0x77, 0x33, 0xf9, 0x30, 0x1a, 0x5f,
-	0x5b, 0x86, 0xea, 0x0c, 0x0f, 0x7b, 0x83, 0x0f, 0x56, 0x11, 0x35, 0xa1, 0xa6, 0xbb, 0x51, 0x8e,
-	0x92, 0xee, 0xee, 0x76, 0x3c, 0x56, 0x46, 0x39, 0x75, 0x4d, 0x3c, 0x4f, 0x59, 0x15, 0xe5, 0xd2,
-	0xd6, 0x70, 0x60, 0x55, 0x15, 0xc1, 0x10, 0xe3, 0x09, 0xb6, 0x4c, 0xe7, 0x02, 0x1a, 0x7d, 0xce,
-	0xa4, 0x4f, 0x19, 0x11, 0x23, 0x4f, 0x6f, 0x32, 0xde, 0x6d, 0x32, 0x56, 0xda, 0xd8, 0xbb, 0xd9,
-	0x82, 0x1f, 0xae, 0xba, 0xfb, 0x67, 0x11, 0x5e, 0xe6, 0xae, 0x7a, 0x40, 0xe2, 0x90, 0x6f, 0x22,
-	0xc2, 0xe4, 0x94, 0x88, 0x35, 0x0d, 0x08, 0xfa, 0x01, 0x9e, 0xa5, 0xe0, 0x8e, 0x07, 0x1d, 0x57,
-	0x4a, 0xeb, 0xb9, 0x9b, 0x3e, 0x8f, 0xee, 0xf6, 0x79, 0x74, 0x87, 0xea, 0x79, 0x74, 0x0a, 0xe8,
-	0x3b, 0xa8, 0xa5, 0x3c, 0xef, 0xdf, 0x7d, 0x34, 0x01, 0x26, 0x73, 0x4d, 0xf1, 0x71, 0x04, 0x3d,
-	0xa8, 0xdd, 0xb2, 0x8c, 0xe0, 0xfc, 0x28, 0xc1, 0x68, 0x70, 0x9c, 0xa2, 0xfb, 0x57, 0x11, 0xce,
-	0x73, 0xb1, 0x7b, 0x35, 0x64, 0xcb, 0xea, 0x41, 0x65, 0x2a, 0x7d, 0x21, 0xd1, 0xab, 0xc7, 0x45,
-	0xfb, 0x48, 0x97, 0xdf, 0x43, 0x79, 0x2a, 0x79, 0xfc, 0x04, 0x86, 0x3e, 0x98, 0x98, 0x24, 0x4f,
-	0x6c, 0x63, 0x04, 0xf5, 0x6b, 0x22, 0x33, 0x31, 0x3f, 0xba, 0xad, 0x8b, 0x47, 0xff, 0x10, 0xa7,
-	0xd0, 0x8d, 0xe0, 0x42, 0x69, 0x47, 0xf0, 0x30, 0x24, 0xe2, 0x3d, 0x15, 0x72, 0xe5, 0x87, 0xf4,
-	0x37, 0x9d, 0xde, 0x5b, 0x12, 0x26, 0xd1, 0x0d, 0x58, 0xd7, 0x44, 0xee, 0xf4, 0x75, 0xb5, 0x19,
-	0x79, 0x87, 0x37, 0x9c, 0xd3, 0x78, 0xeb, 0xfc, 0x98, 0x8b, 0x2d, 0xb8, 0x53, 0xb8, 0x3a, 0xfb,
-	0xe5, 0xc5, 0x92, 0xca, 0xfb, 0xd5, 0x9d, 0x1b, 0xf0, 0xe8, 0x92, 0xcb, 0x20, 0xb9, 0xf7, 0x05,
-	0xb9, 0x24, 0x6b, 0xff, 0xae, 0xaa, 0xc7, 0xfc, 0xea, 0xdf, 0x00, 0x00, 0x00, 0xff, 0xff, 0xbe,
-	0x65, 0x8e, 0x12, 0xdd, 0x07, 0x00, 0x00,
+	// 923 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x54, 0xdd, 0x8e, 0xda, 0x46,
+	0x14, 0xc6, 0xec, 0xf2, 0x77, 0xf8, 0x59, 0x77, 0x54, 0x25, 0x5e, 0x36, 0x9b, 0x22, 0x2b, 0x4a,
+	0x91, 0xaa, 0x7a, 0x25, 0x7a, 0x13, 0xa9, 0xad, 0x5a, 0x16, 0xe8, 0x86, 0x66, 0x03, 0x68, 0x60,
+	0x53, 0xa5, 0x37, 0x95, 0x31, 0x03, 0x8c, 0x64, 0xcf, 0x58, 0xe3, 0x01, 0x89, 0x5e, 0xf4, 0xbe,
+	0xef, 0xd0, 0x37, 0xe8, 0x5d, 0xdf, 0xa0, 0x6f, 0x56, 0xcd, 0xd8, 0x80, 0xa1, 0x62, 0xa5, 0x24,
+	0x57, 0xf6, 0xf9, 0xce, 0x39, 0xdf, 0xf9, 0xf1, 0x77, 0x0c, 0x25, 0xb2, 0x76, 0x9d, 0x50, 0x70,
+	0xc9, 0x51, 0x85, 0x87, 0x84, 0x31, 0x12, 0x45, 0x0e, 0x59, 0xbb, 0xf5, 0xab, 0x05, 0xe7, 0x0b,
+	0x9f, 0xdc, 0x68, 0xdf, 0x74, 0x35, 0xbf, 0x21, 0x41, 0x28, 0x37, 0x71, 0xa8, 0xfd, 0x57, 0x0e,
+	0xca, 0xed, 0x30, 0xf4, 0xa9, 0xe7, 0x4a, 0xca, 0x19, 0xaa, 0x41, 0x96, 0xce, 0x2c, 0xa3, 0x61,
+	0x34, 0x4b, 0x38, 0x4b, 0x67, 0x08, 0xc1, 0x39, 0x73, 0x03, 0x62, 0x65, 0x35, 0xa2, 0xdf, 0x91,
+	0x05, 0x85, 0x35, 0x11, 0x11, 0xe5, 0xcc, 0x3a, 0xd3, 0xf0, 0xd6, 0x44, 0x4f, 0x20, 0xbf, 0x26,
+	0x6c, 0xc6, 0x85, 0x75, 0xae, 0x1d, 0x89, 0x85, 0x1a, 0x50, 0x9e, 0x91, 0xc8, 0x13, 0x34, 0x54,
+	0x45, 0xac, 0x9c, 0x76, 0xa6, 0x21, 0xf4, 0x39, 0xe4, 0x3c, 0x2e, 0x48, 0x64, 0xe5, 0x1b, 0x46,
+	0x33, 0x87, 0x63, 0x43, 0xf1, 0x05, 0x24, 0xe0, 0x62, 0x63, 0x15, 0x34, 0x9c, 0x58, 0xe8, 0x6b,
+	0xc8, 0x85, 0x5c, 0xc8, 0xc8, 0x2a, 0x36, 0xce, 0x9a, 0xe5, 0xd6, 0x53, 0x27, 0x3d, 0xb0, 0x33,
+	0xe2, 0x42, 0x8e, 0xd4, 0x74, 0x38, 0x8e, 0x42, 0xdf, 0x41, 0x3e, 0x92, 0xae, 0x5c, 0x45, 0x56,
+	0xa9, 0x61, 0x34, 0x6b, 0xad, 0x17, 0x87, 0xf1, 0xf7, 0x74, 0x4e, 0xbc, 0x8d, 0xe7, 0x93, 0xb1,
+	0x0e, 0x72, 0xe2, 0x07, 0x4e, 0x72, 0x50, 0x1b, 0x8a, 0x4b, 0x29, 0xc3, 0xdf, 0x56, 0x82, 0x5a,
+	0xd0, 0x30, 0x9a, 0xe5, 0xe3, 0xfc, 0xd4, 0xfe, 0x9c, 0xd7, 0x93, 0xc9, 0x68, 0xcc, 0x57, 0xc2,
+	0x23, 0xaf, 0x33, 0xb8, 0xa0, 0xf2, 0x1e, 0x04, 0x55, 0xf3, 0xf7, 0xda, 0x9d, 0x9f, 0x23, 0xce,
+	0x6e, 0x7d, 0x3e, 0xb5, 0xca, 0xf1, 0xfc, 0x29, 0x08, 0xbd, 0x82, 0x82, 0xc7, 0x68, 0x87, 0xb3,
+	0xb9, 0x55, 0xd1, 0x35, 0x9e, 0x1f, 0xd6, 0xe8, 0x0c, 0xfa, 0xca, 0x49, 0x17, 0x2b, 0xa1, 0x0b,
+	0xe1, 0x6d, 0xb8, 0xda, 0xd1, 0x8c, 0x2e, 0x48, 0x24, 0xad, 0x6a, 0xbc, 0xf3, 0xd8, 0x42, 0xcf,
+	0xa0, 0x14, 0xd1, 0x05, 0x73, 0xe5, 0x4a, 0x10, 0xab, 0xd6, 0x30, 0x9a, 0x15, 0xbc, 0x07, 0x90,
+	0x0d, 0x95, 0x70, 0x35, 0xf5, 0x69, 0xb4, 0x24, 0xe2, 0x0d, 0xd9, 0x58, 0x17, 0x3a, 0xe0, 0x00,
+	0x43, 0x2f, 0xa1, 0xe6, 0xe9, 0x9a, 0xbb, 0xc6, 0x4d, 0x5d, 0xe1, 0x08, 0x55, 0x71, 0x4b, 0xe2,
+	0xfa, 0x72, 0xb9, 0x8b, 0xfb, 0x2c, 0x8e, 0x3b, 0x44, 0xeb, 0x5f, 0x02, 0xec, 0xd7, 0x83, 0x2e,
+	0x53, 0x6b, 0x8d, 0xf5, 0xb6, 0x5d, 0xd7, 0x6d, 0x11, 0xf2, 0x91, 0x0e, 0xb2, 0xff, 0x00, 0xf3,
+	0x78, 0x72, 0x35, 0x58, 0x32, 0x3b, 0x5d, 0x24, 0x99, 0x7b, 0x00, 0xbd, 0x80, 0x2a, 0x65, 0x92,
+	0x88, 0xb9, 0xeb, 0x91, 0xc1, 0x5e, 0xb9, 0x87, 0xa0, 0x92, 0x75, 0xe8, 0xca, 0x65, 0xa2, 0x5f,
+	0xfd, 0xae, 0x30, 0x57, 0x2c, 0xa2, 0x44, 0xba, 0xfa, 0xdd, 0xfe, 0x02, 0xaa, 0xa9, 0xaf, 0xdb,
+	0xef, 0x1e, 0xdf, 0x87, 0xfd, 0x16, 0x2a, 0xa9, 0x80, 0x08, 0x7d, 0x0f, 0x15, 0x37, 0x65, 0x5b,
+	0x86, 0x16, 0xe8, 0xe5, 0x49, 0xc1, 0xe0, 0x83, 0x70, 0xfb, 0x5b, 0x28, 0xed, 0xd4, 0xab, 0x9b,
+	0xe4, 0x42, 0xea, 0x6a, 0x55, 0xac, 0xdf, 0x51, 0x1d, 0x8a, 0xfa, 0x70, 0x3d, 0xee, 0x27, 0x93,
+	0xed, 0x6c, 0xfb, 0x4f, 0x03, 0xcc, 0x9d, 0x96, 0x3b, 0x3c, 0x08, 0x5c, 0x36, 0xfb, 0xdf, 0x41,
+	0xbf, 0x82, 0x33, 0x2f, 0x98, 0xe9, 0xdc, 0x5a, 0xeb, 0xe5, 0x89, 0x43, 0x48, 0x92, 0x9d, 0xe4,
+	0x89, 0x55, 0x8a, 0xfd, 0x15, 0x14, 0xb6, 0xa4, 0x25, 0xc8, 0x8d, 0x27, 0x6d, 0x3c, 0x31, 0x33,
+	0xa8, 0x08, 0xe7, 0xe3, 0xc9, 0x70, 0x64, 0x1a, 0xa8, 0x0c, 0x05, 0xdc, 0x8b, 0xe1, 0xac, 0xfd,
+	0xaf, 0x01, 0x17, 0x47, 0x77, 0x95, 0x3a, 0x43, 0xe3, 0xc3, 0xcf, 0xd0, 0x0e, 0x21, 0x9f, 0xf0,
+	0x94, 0xa1, 0xf0, 0x30, 0x78, 0x33, 0x18, 0xfe, 0x32, 0x30, 0x33, 0xa8, 0x0a, 0xa5, 0x6e, 0x6f,
+	0x74, 0x3f, 0x7c, 0xdf, 0x1f, 0xdc, 0x99, 0x86, 0xea, 0x0c, 0xf7, 0xda, 0xdd, 0xf7, 0x66, 0x16,
+	0x55, 0xa0, 0xa8, 0xbb, 0x51, 0x8e, 0x33, 0xdd, 0xdd, 0xc3, 0x60, 0xa0, 0x8c, 0xf3, 0xd8, 0x35,
+	0x1c, 0x8d, 0x94, 0x95, 0x53, 0x2e, 0x6d, 0xf5, 0xba, 0x66, 0x5e, 0x11, 0xf4, 0x30, 0x1e, 0x62,
+	0xb3, 0x60, 0x5f, 0x43, 0xb9, 0xc3, 0x99, 0x74, 0x29, 0x23, 0xa2, 0x3f, 0xd2, 0x9b, 0x0c, 0x77,
+	0x9b, 0x0c, 0x95, 0x36, 0xf6, 0x6e, 0x36, 0xe7, 0xc7, 0xab, 0x6e, 0xfd, 0x9d, 0x85, 0x67, 0xa9,
+	0x4f, 0xdd, 0x25, 0xa1, 0xcf, 0x37, 0x01, 0x61, 0x72, 0x4c, 0xc4, 0x9a, 0x7a, 0x04, 0xfd, 0x04,
+	0x17, 0x31, 0xb8, 0xe3, 0x41, 0xa7, 0x95, 0x52, 0x7f, 0xe2, 0xc4, 0x3f, 0x72, 0x67, 0xfb, 0x23,
+	0x77, 0x7a, 0xea, 0x47, 0x6e, 0x67, 0xd0, 0x0f, 0x50, 0x8c, 0x79, 0xde, 0xbd, 0xfd, 0x68, 0x02,
+	0x4c, 0x66, 0x9a, 0xe2, 0xe3, 0x08, 0xda, 0x50, 0x7c, 0x60, 0x09, 0xc1, 0xd5, 0x49, 0x82, 0x7e,
+	0xf7, 0x34, 0x45, 0xeb, 0x9f, 0x2c, 0x5c, 0xa5, 0x62, 0xf7, 0x6a, 0x48, 0x96, 0xd5, 0x86, 0xdc,
+	0x58, 0xba, 0x42, 0xa2, 0xe7, 0x8f, 0x8b, 0xf6, 0x91, 0x2e, 0x7f, 0x84, 0xf3, 0xb1, 0xe4, 0xe1,
+	0x27, 0x30, 0x74, 0xa0, 0x80, 0x49, 0xf4, 0x89, 0x6d, 0xf4, 0xa1, 0x74, 0x47, 0x64, 0x22, 0xe6,
+	0x47, 0xb7, 0x75, 0xfd, 0xe8, 0x85, 0xd8, 0x99, 0x56, 0x00, 0xd7, 0x4a, 0x3b, 0x82, 0xfb, 0x3e,
+	0x11, 0xef, 0xa8, 0x90, 0x2b, 0xd7, 0xa7, 0xbf, 0xeb, 0xf4, 0xf6, 0x82, 0x30, 0x89, 0xee, 0xc1,
+	0xbc, 0x23, 0x72, 0xa7, 0xaf, 0xdb, 0x4d, 0x7f, 0x74, 0xfc, 0x85, 0x53, 0x1a, 0xaf, 0x5f, 0x9d,
+	0x72, 0xb1, 0x39, 0xb7, 0x33, 0xb7, 0x97, 0xbf, 0x3e, 0x5d, 0x50, 0xb9, 0x5c, 0x4d, 0x1d, 0x8f,
+	0x07, 0x37, 0x5c, 0x7a, 0xd1, 0xd2, 0x15, 0xe4, 0x86, 0xac, 0xdd, 0x69, 0x5e, 0x8f, 0xf9, 0xcd,
+	0x7f, 0x03, 0x00, 0x41, 0x53, 0x6b, 0x38, 0x87, 0x08, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -12,7 +12,6 @@ import (
 	"crypto/x509"
 	"crypto/x509/pkix"
 	"math/big"
-	rdm "math/rand"
 	"os"
 	"path/filepath"
 	"time"
@@ -20,10 +19,28 @@ import (
 	"github.com/pkg/errors"
 )
 
-// RootCA manages digital certificates.
+// DefaultCertLifetime is the suggested lifetime of certificates signed by
+// SignCSR.
+const DefaultCertLifetime = 365 * 24 * time.Hour
+
+// maxSerial bounds the random serial numbers of issued certificates to 128
+// bits.
+var maxSerial = new(big.Int).Lsh(big.NewInt(1), 128)
+
+// RootCA manages digital certificates. Certificates are issued with Cert and
+// Key, which are either a self-signed root CA or an intermediate CA. For an
+// intermediate CA, Chain holds the certificates of its issuers.
 type RootCA struct {
 	Cert *x509.Certificate
 	Key  crypto.PrivateKey
+
+	// Chain lists the issuers of Cert, ending with the root CA. It is empty
+	// if Cert is the root CA.
+	Chain []*x509.Certificate
+
+	// CertLifetime bounds the validity of certificates signed by SignCSR. If
+	// it is zero, certificates are valid until the CA expires.
+	CertLifetime time.Duration
 }
 
 // InitRootCA creates a RootCA by loading the CA certificate and key from the
@@ -92,11 +109,20 @@ func InitRootCA(certsDir string) (*RootCA, error) {
 	}, nil
 }
 
-// CAChain returns the root CA certificate wrapped in a slice to satisfy the
-// interface. Since the root CA is the issuing CA and there are no intermediate
-// CAs, we only need to return the root CA certificate.
+// CAChain returns the issuing CA certificate followed by its issuers, ending
+// with the root CA.
 func (ca *RootCA) CAChain() ([]*x509.Certificate, error) {
-	return []*x509.Certificate{ca.Cert}, nil
+	return append([]*x509.Certificate{ca.Cert}, ca.Chain...), nil
+}
+
+// newSerial picks a random positive serial number.
+func newSerial() (*big.Int, error) {
+	serial, err := rand.Int(rand.Reader, maxSerial)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to generate serial number")
+	}
+	// Serial numbers must be positive
+	return serial.Add(serial, big.NewInt(1)), nil
 }
 
 // SignCSR signs a ASN.1 DER encoded certificate signing request.
@@ -107,15 +133,24 @@ func (ca *RootCA) SignCSR(der []byte, template *x509.Certificate) (*x509.Certifi
 	}
 
 	// Pick random serial number
-	source := rdm.NewSource(time.Now().UnixNano())
-	serial := big.NewInt(int64(rdm.New(source).Uint64()))
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	// Valid until CA expires, or for the certificate lifetime if shorter
+	notBefore := time.Now()
+	notAfter := ca.Cert.NotAfter
+	if ca.CertLifetime > 0 && notBefore.Add(ca.CertLifetime).Before(notAfter) {
+		notAfter = notBefore.Add(ca.CertLifetime)
+	}
 
 	// Sign certificate request
 	tmpl := &x509.Certificate{
 		SerialNumber: serial,
 		Subject:      template.Subject,
-		NotBefore:    time.Now(),
-		NotAfter:     ca.Cert.NotAfter, // Valid until CA expires
+		NotBefore:    notBefore,
+		NotAfter:     notAfter,
 	}
 	certDER, err := x509.CreateCertificate(
 		rand.Reader,
@@ -152,8 +187,10 @@ func (ca *RootCA) newTLSCert(
 	}
 
 	// Pick random serial number
-	source := rdm.NewSource(time.Now().UnixNano())
-	serial := big.NewInt(int64(rdm.New(source).Uint64()))
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
 
 	// Generate certificate
 	template := &x509.Certificate{
@@ -184,7 +221,6 @@ func generateRootCA(key crypto.PrivateKey) (*x509.Certificate, error) {
 		err      error
 		k        crypto.Signer
 		ok       bool
-		source   rdm.Source
 		serial   *big.Int
 		template *x509.Certificate
 		der      []byte
@@ -194,9 +230,9 @@ func generateRootCA(key crypto.PrivateKey) (*x509.Certificate, error) {
 		return nil, errors.Wrap(err, "unable to parse key")
 	}
 
-	source = rdm.NewSource(time.Now().UnixNano())
-
-	serial = big.NewInt(int64(rdm.New(source).Uint64()))
+	if serial, err = newSerial(); err != nil {
+		return nil, err
+	}
 
 	template = &x509.Certificate{
 		SerialNumber: serial,
@@ -206,10 +242,9 @@ func generateRootCA(key crypto.PrivateKey) (*x509.Certificate, error) {
 		NotBefore:             time.Now().Add(-15 * time.Second),
 		NotAfter:              time.Now().Add(3 * 365 * 24 * time.Hour),
 		IsCA:                  true,
-		KeyUsage:              x509.KeyUsageCertSign,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
 		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
-		MaxPathLen:            0,
-		MaxPathLenZero:        true,
+		MaxPathLen:            1, // Allow an intermediate CA
 		BasicConstraintsValid: true,
 	}
 
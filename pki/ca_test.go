@@ -13,6 +13,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"time"
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -131,4 +132,34 @@ var _ = Describe("Controller CA", func() {
 			})
 		})
 	})
+
+	Describe("SignCSR", func() {
+		var csrDER []byte
+
+		BeforeEach(func() {
+			key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			csrDER, err = x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, key)
+			Expect(err).ToNot(HaveOccurred())
+		})
+
+		It("Should sign certificates valid until the CA expires", func() {
+			rootCA, err := pki.InitRootCA(tmpDir)
+			Expect(err).ToNot(HaveOccurred())
+
+			cert, err := rootCA.SignCSR(csrDER, &x509.Certificate{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cert.NotAfter).To(Equal(rootCA.Cert.NotAfter))
+		})
+
+		It("Should limit certificates to the certificate lifetime", func() {
+			rootCA, err := pki.InitRootCA(tmpDir)
+			Expect(err).ToNot(HaveOccurred())
+			rootCA.CertLifetime = time.Hour
+
+			cert, err := rootCA.SignCSR(csrDER, &x509.Certificate{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(cert.NotAfter).To(BeTemporally("~", time.Now().Add(time.Hour), time.Minute))
+		})
+	})
 })
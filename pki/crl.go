@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package pki
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// CRLValidity is the time a CRL returned by CRL is valid for. CRLs are
+// generated on request, so clients are expected to fetch a new one before it
+// expires.
+const CRLValidity = 24 * time.Hour
+
+// CRL creates a ASN.1 DER encoded certificate revocation list of the revoked
+// certificates, signed by the CA.
+func (ca *RootCA) CRL(revoked []pkix.RevokedCertificate) ([]byte, error) {
+	now := time.Now()
+
+	// CA certificates generated before CRL support lack the CRL signing key
+	// usage, which x509.CreateRevocationList insists on.
+	if ca.Cert.KeyUsage&x509.KeyUsageCRLSign == 0 {
+		der, err := ca.Cert.CreateCRL(rand.Reader, ca.Key, revoked, now, now.Add(CRLValidity)) //nolint:staticcheck
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to create CRL")
+		}
+		return der, nil
+	}
+
+	var entries []x509.RevocationListEntry
+	for _, r := range revoked {
+		entries = append(entries, x509.RevocationListEntry{
+			SerialNumber:   r.SerialNumber,
+			RevocationTime: r.RevocationTime,
+		})
+	}
+
+	signer, ok := ca.Key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("invalid CA key type: %T", ca.Key)
+	}
+
+	der, err := x509.CreateRevocationList(
+		rand.Reader,
+		&x509.RevocationList{
+			RevokedCertificateEntries: entries,
+			// The CRL number only has to increase with every CRL issued
+			Number:     big.NewInt(now.UnixNano()),
+			ThisUpdate: now,
+			NextUpdate: now.Add(CRLValidity),
+		},
+		ca.Cert,
+		signer,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create CRL")
+	}
+	return der, nil
+}
@@ -0,0 +1,82 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package pki_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/open-ness/edgecontroller/pki"
+)
+
+var _ = Describe("CRL", func() {
+	var (
+		tmpDir  string
+		revoked []pkix.RevokedCertificate
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "crl_test")
+		Expect(err).ToNot(HaveOccurred())
+
+		revoked = []pkix.RevokedCertificate{{
+			SerialNumber:   big.NewInt(42),
+			RevocationTime: time.Now().Add(-time.Hour).UTC().Truncate(time.Second),
+		}}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	It("Should list the revoked certificates signed by the CA", func() {
+		rootCA, err := pki.InitRootCA(tmpDir)
+		Expect(err).ToNot(HaveOccurred())
+
+		der, err := rootCA.CRL(revoked)
+		Expect(err).ToNot(HaveOccurred())
+
+		crl, err := x509.ParseRevocationList(der)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(crl.CheckSignatureFrom(rootCA.Cert)).To(Succeed())
+		Expect(crl.RevokedCertificateEntries).To(HaveLen(1))
+		Expect(crl.RevokedCertificateEntries[0].SerialNumber).To(Equal(big.NewInt(42)))
+		Expect(crl.NextUpdate).To(BeTemporally("~", time.Now().Add(pki.CRLValidity), time.Minute))
+	})
+
+	It("Should be signed by a CA without the CRL signing key usage", func() {
+		key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		template := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{Organization: []string{"Controller Authority"}},
+			NotBefore:             time.Now(),
+			NotAfter:              time.Now().Add(time.Hour),
+			IsCA:                  true,
+			KeyUsage:              x509.KeyUsageCertSign,
+			BasicConstraintsValid: true,
+		}
+		der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+		Expect(err).ToNot(HaveOccurred())
+		cert, err := x509.ParseCertificate(der)
+		Expect(err).ToNot(HaveOccurred())
+
+		der, err = (&pki.RootCA{Cert: cert, Key: key}).CRL(revoked)
+		Expect(err).ToNot(HaveOccurred())
+
+		crl, err := x509.ParseRevocationList(der)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(crl.RevokedCertificateEntries).To(HaveLen(1))
+	})
+})
@@ -0,0 +1,225 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package pki
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// LoadIntermediateCA loads an intermediate CA supplied by the operator. The
+// chain file holds the issuers of the intermediate CA certificate up to and
+// including the root CA, whose key is not needed. The certificate must chain
+// to the root CA.
+func LoadIntermediateCA(certFile, keyFile, chainFile string) (*RootCA, error) {
+	cert, err := LoadCertificate(certFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load intermediate CA certificate")
+	}
+
+	key, err := LoadKey(keyFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load intermediate CA key")
+	}
+
+	issuers, err := LoadCertificateChain(chainFile)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to load intermediate CA chain")
+	}
+
+	if err = checkKey(cert, key); err != nil {
+		return nil, err
+	}
+
+	chain, err := verifyIntermediate(cert, issuers)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RootCA{
+		Cert:  cert,
+		Key:   key,
+		Chain: chain,
+	}, nil
+}
+
+// InitIntermediateCA creates an intermediate CA issued by the root CA by
+// loading its certificate and key from the certificates directory. If they
+// do not exist, the certificate was not signed with the key or does not chain
+// to the root CA, a new certificate and key will be generated.
+func InitIntermediateCA(certsDir string, root *RootCA) (*RootCA, error) {
+	var (
+		err error
+
+		keyFile string
+		key     crypto.PrivateKey
+
+		certFile string
+		cert     *x509.Certificate
+		chain    []*x509.Certificate
+	)
+
+	rootChain, err := root.CAChain()
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to get root CA chain")
+	}
+
+	if err = os.MkdirAll(certsDir, 0700); err != nil {
+		return nil, errors.Wrap(err, "unable to create intermediate CA directory")
+	}
+
+	keyFile = filepath.Join(certsDir, "key.pem")
+
+	if key, err = LoadKey(keyFile); err != nil {
+		if key, err = ecdsa.GenerateKey(elliptic.P384(), rand.Reader); err != nil {
+			return nil, errors.Wrap(err, "unable to generate intermediate CA key")
+		}
+
+		if err = StoreKey(key, keyFile); err != nil {
+			return nil, errors.Wrap(err, "unable to store intermediate CA key")
+		}
+
+		log.Debugf("Generated and stored intermediate CA key at: %s", keyFile)
+	}
+
+	certFile = filepath.Join(certsDir, "cert.pem")
+
+	if cert, err = LoadCertificate(certFile); err == nil {
+		if err = checkKey(cert, key); err == nil {
+			chain, err = verifyIntermediate(cert, rootChain)
+		}
+		if err != nil {
+			log.Noticef("Replacing intermediate CA certificate at %s: %v", certFile, err)
+		}
+	}
+
+	if err != nil {
+		if cert, err = generateIntermediateCA(key, root); err != nil {
+			return nil, errors.Wrap(err, "unable to generate intermediate CA")
+		}
+
+		// A root CA generated before intermediate CAs were supported does
+		// not allow any
+		if chain, err = verifyIntermediate(cert, rootChain); err != nil {
+			return nil, errors.Wrap(err, "root CA cannot issue an intermediate CA")
+		}
+
+		if err = StoreCertificate(certFile, cert); err != nil {
+			return nil, errors.Wrap(err, "unable to store intermediate CA certificate")
+		}
+
+		log.Debugf("Generated and stored intermediate CA certificate at: %s", certFile)
+	}
+
+	return &RootCA{
+		Cert:         cert,
+		Key:          key,
+		Chain:        chain,
+		CertLifetime: root.CertLifetime,
+	}, nil
+}
+
+// checkKey verifies the certificate was signed with the private key.
+func checkKey(cert *x509.Certificate, key crypto.PrivateKey) error {
+	signer, ok := key.(crypto.Signer)
+	if !ok {
+		return errors.Errorf("invalid private key type: %T", key)
+	}
+
+	der, err := x509.MarshalPKIXPublicKey(signer.Public())
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal public key")
+	}
+
+	if !bytes.Equal(cert.RawSubjectPublicKeyInfo, der) {
+		return errors.New("certificate does not match private key")
+	}
+	return nil
+}
+
+// verifyIntermediate verifies that the CA certificate chains to the root CA,
+// the last of the issuers, and returns its issuers in order.
+func verifyIntermediate(cert *x509.Certificate, issuers []*x509.Certificate) ([]*x509.Certificate, error) {
+	if !cert.IsCA {
+		return nil, errors.New("certificate is not a CA certificate")
+	}
+	if len(issuers) == 0 {
+		return nil, errors.New("no root CA certificate")
+	}
+
+	roots := x509.NewCertPool()
+	roots.AddCert(issuers[len(issuers)-1])
+	intermediates := x509.NewCertPool()
+	for _, issuer := range issuers[:len(issuers)-1] {
+		intermediates.AddCert(issuer)
+	}
+
+	chains, err := cert.Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "certificate does not chain to the root CA")
+	}
+
+	// Verify treats the CA certificate as the leaf, so check that the path
+	// length of each issuer also allows the certificates it will issue.
+	chain := chains[0]
+	for i, issuer := range chain[1:] {
+		if issuer.BasicConstraintsValid && issuer.MaxPathLen >= 0 && i+1 > issuer.MaxPathLen {
+			return nil, errors.Errorf("path length of %q does not allow another CA",
+				issuer.Subject.String())
+		}
+	}
+
+	return chain[1:], nil
+}
+
+// generateIntermediateCA creates an intermediate CA from the private key,
+// issued by the parent CA and valid until the parent CA expires.
+func generateIntermediateCA(key crypto.PrivateKey, parent *RootCA) (*x509.Certificate, error) {
+	k, ok := key.(crypto.Signer)
+	if !ok {
+		return nil, errors.Errorf("invalid private key type: %T", key)
+	}
+
+	serial, err := newSerial()
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{"Controller Authority"},
+			CommonName:   "Controller Intermediate CA",
+		},
+		NotBefore:             time.Now().Add(-15 * time.Second),
+		NotAfter:              parent.Cert.NotAfter,
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		MaxPathLen:            0,
+		MaxPathLenZero:        true,
+		BasicConstraintsValid: true,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent.Cert, k.Public(), parent.Key)
+	if err != nil {
+		return nil, errors.Wrap(err, "unable to create intermediate CA certificate")
+	}
+
+	return x509.ParseCertificate(der)
+}
@@ -0,0 +1,172 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package pki_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/open-ness/edgecontroller/pki"
+)
+
+var _ = Describe("Intermediate CA", func() {
+	var (
+		tmpDir string
+		rootCA *pki.RootCA
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "intermediate_test")
+		Expect(err).ToNot(HaveOccurred())
+
+		rootCA, err = pki.InitRootCA(filepath.Join(tmpDir, "ca"))
+		Expect(err).ToNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(tmpDir)
+	})
+
+	// verifyIssued signs a CSR and verifies the certificate against the
+	// root CA with the CA chain as intermediates.
+	verifyIssued := func(ca *pki.RootCA) {
+		key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+		Expect(err).ToNot(HaveOccurred())
+		csrDER, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{}, key)
+		Expect(err).ToNot(HaveOccurred())
+
+		cert, err := ca.SignCSR(csrDER, &x509.Certificate{Subject: pkix.Name{CommonName: "node"}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(cert.Issuer.CommonName).To(Equal(ca.Cert.Subject.CommonName))
+
+		chain, err := ca.CAChain()
+		Expect(err).ToNot(HaveOccurred())
+		roots := x509.NewCertPool()
+		roots.AddCert(chain[len(chain)-1])
+		intermediates := x509.NewCertPool()
+		for _, c := range chain[:len(chain)-1] {
+			intermediates.AddCert(c)
+		}
+		_, err = cert.Verify(x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		})
+		Expect(err).ToNot(HaveOccurred())
+	}
+
+	Describe("InitIntermediateCA", func() {
+		It("Should create an intermediate CA under the root CA", func() {
+			ca, err := pki.InitIntermediateCA(filepath.Join(tmpDir, "intermediate"), rootCA)
+			Expect(err).ToNot(HaveOccurred())
+
+			By("Verifying the CA chain ends with the root CA")
+			chain, err := ca.CAChain()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(chain).To(HaveLen(2))
+			Expect(chain[0]).To(Equal(ca.Cert))
+			Expect(chain[1]).To(Equal(rootCA.Cert))
+
+			By("Verifying certificates are issued from the intermediate CA")
+			verifyIssued(ca)
+		})
+
+		It("Should load the intermediate CA if one already exists", func() {
+			ca1, err := pki.InitIntermediateCA(filepath.Join(tmpDir, "intermediate"), rootCA)
+			Expect(err).ToNot(HaveOccurred())
+			ca2, err := pki.InitIntermediateCA(filepath.Join(tmpDir, "intermediate"), rootCA)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ca2.Cert).To(Equal(ca1.Cert))
+			Expect(ca2.Key).To(Equal(ca1.Key))
+		})
+
+		It("Should replace an intermediate CA of another root CA", func() {
+			ca1, err := pki.InitIntermediateCA(filepath.Join(tmpDir, "intermediate"), rootCA)
+			Expect(err).ToNot(HaveOccurred())
+
+			otherRootCA, err := pki.InitRootCA(filepath.Join(tmpDir, "other"))
+			Expect(err).ToNot(HaveOccurred())
+			ca2, err := pki.InitIntermediateCA(filepath.Join(tmpDir, "intermediate"), otherRootCA)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(ca2.Cert).ToNot(Equal(ca1.Cert))
+			verifyIssued(ca2)
+		})
+
+		It("Should return an error if the root CA does not allow intermediate CAs", func() {
+			key, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			template := &x509.Certificate{
+				SerialNumber:          big.NewInt(1),
+				Subject:               pkix.Name{Organization: []string{"Controller Authority"}},
+				NotBefore:             time.Now(),
+				NotAfter:              time.Now().Add(time.Hour),
+				IsCA:                  true,
+				KeyUsage:              x509.KeyUsageCertSign,
+				ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+				MaxPathLenZero:        true,
+				BasicConstraintsValid: true,
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, template, key.Public(), key)
+			Expect(err).ToNot(HaveOccurred())
+			cert, err := x509.ParseCertificate(der)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, err = pki.InitIntermediateCA(filepath.Join(tmpDir, "intermediate"), &pki.RootCA{Cert: cert, Key: key})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("LoadIntermediateCA", func() {
+		var (
+			certFile  string
+			keyFile   string
+			chainFile string
+		)
+
+		BeforeEach(func() {
+			ca, err := pki.InitIntermediateCA(filepath.Join(tmpDir, "intermediate"), rootCA)
+			Expect(err).ToNot(HaveOccurred())
+
+			certFile = filepath.Join(tmpDir, "intermediate", "cert.pem")
+			keyFile = filepath.Join(tmpDir, "intermediate", "key.pem")
+			chainFile = filepath.Join(tmpDir, "chain.pem")
+			Expect(pki.StoreCertificate(chainFile, ca.Chain...)).To(Succeed())
+		})
+
+		It("Should load an intermediate CA chaining to the root CA", func() {
+			ca, err := pki.LoadIntermediateCA(certFile, keyFile, chainFile)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(ca.Chain).To(Equal([]*x509.Certificate{rootCA.Cert}))
+
+			verifyIssued(ca)
+		})
+
+		It("Should return an error if the key does not match the certificate", func() {
+			_, err := pki.LoadIntermediateCA(certFile, filepath.Join(tmpDir, "ca", "key.pem"), chainFile)
+			Expect(err).To(MatchError("certificate does not match private key"))
+		})
+
+		It("Should return an error if the certificate does not chain to the root CA", func() {
+			otherRootCA, err := pki.InitRootCA(filepath.Join(tmpDir, "other"))
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pki.StoreCertificate(chainFile, otherRootCA.Cert)).To(Succeed())
+
+			_, err = pki.LoadIntermediateCA(certFile, keyFile, chainFile)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+})
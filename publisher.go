@@ -0,0 +1,71 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+// Publisher is a trusted publisher of app images. Apps naming a publisher
+// must carry a signature of their image verified with its public key.
+type Publisher struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// PublicKey is the PEM-encoded RSA or ECDSA public key of the publisher.
+	PublicKey string `json:"public_key"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*Publisher) GetTableName() string {
+	return "publishers"
+}
+
+// GetID gets the ID.
+func (p *Publisher) GetID() string {
+	return p.ID
+}
+
+// SetID sets the ID.
+func (p *Publisher) SetID(id string) {
+	p.ID = id
+}
+
+// Validate validates the model.
+func (p *Publisher) Validate() error {
+	if !uuid.IsValid(p.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if p.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+	if p.PublicKey == "" {
+		return errors.New("public_key cannot be empty")
+	}
+	if _, err := ParsePublicKey(p.PublicKey); err != nil {
+		return fmt.Errorf("public_key %v", err)
+	}
+
+	return nil
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*Publisher) FilterFields() []string {
+	return []string{
+		"name",
+	}
+}
+
+func (p *Publisher) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+Publisher[
+    ID: %s
+    Name: %s
+]`),
+		p.ID,
+		p.Name)
+}
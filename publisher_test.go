@@ -0,0 +1,107 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: Publisher", func() {
+	var (
+		publisher *cce.Publisher
+	)
+
+	BeforeEach(func() {
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		Expect(err).ToNot(HaveOccurred())
+
+		publisher = &cce.Publisher{
+			ID:        "6b1e0d3a-3a41-4fb5-9b6e-1b8f7b0e2c55",
+			Name:      "acme",
+			PublicKey: publicKeyPEM(&key.PublicKey),
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "publishers"`, func() {
+			Expect(publisher.GetTableName()).To(Equal("publishers"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(publisher.GetID()).To(Equal(
+				"6b1e0d3a-3a41-4fb5-9b6e-1b8f7b0e2c55"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			publisher.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(publisher.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for a valid publisher", func() {
+			Expect(publisher.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			publisher.ID = "123"
+			Expect(publisher.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if Name is empty", func() {
+			publisher.Name = ""
+			Expect(publisher.Validate()).To(MatchError("name cannot be empty"))
+		})
+
+		It("Should return an error if PublicKey is empty", func() {
+			publisher.PublicKey = ""
+			Expect(publisher.Validate()).To(MatchError("public_key cannot be empty"))
+		})
+
+		It("Should return an error if PublicKey is not a public key", func() {
+			publisher.PublicKey = "-----BEGIN PUBLIC KEY-----\nMTIz\n-----END PUBLIC KEY-----\n"
+			Expect(publisher.Validate()).To(MatchError("public_key cannot be parsed as a public key"))
+		})
+
+		It("Should return an error if PublicKey is neither RSA nor ECDSA", func() {
+			key, _, err := ed25519.GenerateKey(rand.Reader)
+			Expect(err).ToNot(HaveOccurred())
+			publisher.PublicKey = publicKeyPEM(key)
+			Expect(publisher.Validate()).To(MatchError("public_key must be an RSA or ECDSA key"))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(publisher.FilterFields()).To(Equal([]string{
+				"name",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(publisher.String()).To(Equal(strings.TrimSpace(`
+Publisher[
+    ID: 6b1e0d3a-3a41-4fb5-9b6e-1b8f7b0e2c55
+    Name: acme
+]`,
+			)))
+		})
+	})
+})
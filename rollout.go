@@ -0,0 +1,256 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+// Rollout defaults
+const (
+	// DefaultRolloutBatchSize is the number of nodes of a batch.
+	DefaultRolloutBatchSize = 1
+	// DefaultRolloutVerifyTimeout is the time in seconds a node app has to
+	// reach the running status once deployed.
+	DefaultRolloutVerifyTimeout = 300
+)
+
+// Rollout states
+const (
+	RolloutRunning    = "running"
+	RolloutPaused     = "paused"
+	RolloutCompleted  = "completed"
+	RolloutRolledBack = "rolled_back"
+)
+
+// Rollout target statuses
+const (
+	RolloutTargetPending    = "pending"
+	RolloutTargetVerifying  = "verifying"
+	RolloutTargetSucceeded  = "succeeded"
+	RolloutTargetFailed     = "failed"
+	RolloutTargetSkipped    = "skipped"
+	RolloutTargetRolledBack = "rolled_back"
+)
+
+// Actions taken on a failed rollout
+const (
+	RolloutOnFailurePause    = "pause"
+	RolloutOnFailureRollback = "rollback"
+)
+
+// Rollout deploys a version of an app to a fleet of nodes in waves: first a
+// canary set, then batches. A wave starts once every node of the previous one
+// runs the version. When the share of nodes of a wave that failed passes the
+// failure threshold, the rollout is paused or rolled back.
+type Rollout struct {
+	ID string `json:"id"`
+	// AppID is the version of the app to roll out.
+	AppID string `json:"app_id"`
+	// NodeIDs are the nodes to roll out to. If it is empty, the nodes
+	// running another version of the app are.
+	NodeIDs []string `json:"node_ids,omitempty"`
+	// Canary is the number of nodes of the first wave. If it is zero, the
+	// first wave is a batch.
+	Canary int `json:"canary"`
+	// BatchSize is the number of nodes of each wave after the canary.
+	BatchSize int `json:"batch_size"`
+	// FailureThreshold is the percentage of failed nodes a wave tolerates.
+	FailureThreshold int `json:"failure_threshold"`
+	// OnFailure is RolloutOnFailurePause or RolloutOnFailureRollback.
+	OnFailure string `json:"on_failure"`
+	// VerifyTimeout is the time in seconds a node app has to reach the
+	// running status.
+	VerifyTimeout int `json:"verify_timeout"`
+
+	State string `json:"state"`
+	// Reason tells why the rollout was paused or rolled back.
+	Reason string `json:"reason,omitempty"`
+	// Wave is the index of the wave in progress.
+	Wave    int             `json:"wave"`
+	Targets []RolloutTarget `json:"targets"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RolloutTarget is a node a rollout deploys to.
+type RolloutTarget struct {
+	NodeID string `json:"node_id"`
+	Wave   int    `json:"wave"`
+	// FromAppID is the version of the app the node ran before the rollout,
+	// empty if it ran none.
+	FromAppID string `json:"from_app_id,omitempty"`
+	Status    string `json:"status"`
+	// Cmd is how the version was deployed to the node, deploy or upgrade. It
+	// is empty until then.
+	Cmd string `json:"cmd,omitempty"`
+	// StartedAt is the time the version was deployed to the node.
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*Rollout) GetTableName() string {
+	return "rollouts"
+}
+
+// GetID gets the ID.
+func (r *Rollout) GetID() string {
+	return r.ID
+}
+
+// SetID sets the ID.
+func (r *Rollout) SetID(id string) {
+	r.ID = id
+}
+
+// Validate validates the model.
+func (r *Rollout) Validate() error {
+	if !uuid.IsValid(r.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if !uuid.IsValid(r.AppID) {
+		return errors.New("app_id not a valid uuid")
+	}
+	for _, id := range r.NodeIDs {
+		if !uuid.IsValid(id) {
+			return fmt.Errorf("node_ids contains an invalid uuid: %s", id)
+		}
+	}
+	if r.Canary < 0 {
+		return errors.New("canary cannot be negative")
+	}
+	if r.BatchSize < 1 {
+		return errors.New("batch_size must be greater than 0")
+	}
+	if r.FailureThreshold < 0 || r.FailureThreshold > 100 {
+		return errors.New("failure_threshold must be in [0..100]")
+	}
+	switch r.OnFailure {
+	case RolloutOnFailurePause, RolloutOnFailureRollback:
+	default:
+		return fmt.Errorf("on_failure must be one of [%s, %s]",
+			RolloutOnFailurePause, RolloutOnFailureRollback)
+	}
+	if r.VerifyTimeout < 1 {
+		return errors.New("verify_timeout must be greater than 0")
+	}
+	switch r.State {
+	case RolloutRunning, RolloutPaused, RolloutCompleted, RolloutRolledBack:
+	default:
+		return fmt.Errorf("state must be one of [%s, %s, %s, %s]",
+			RolloutRunning, RolloutPaused, RolloutCompleted, RolloutRolledBack)
+	}
+
+	return nil
+}
+
+// SetDefaults fills the settings left zero with their default.
+func (r *Rollout) SetDefaults() {
+	if r.BatchSize == 0 {
+		r.BatchSize = DefaultRolloutBatchSize
+	}
+	if r.OnFailure == "" {
+		r.OnFailure = RolloutOnFailurePause
+	}
+	if r.VerifyTimeout == 0 {
+		r.VerifyTimeout = DefaultRolloutVerifyTimeout
+	}
+	if r.State == "" {
+		r.State = RolloutRunning
+	}
+}
+
+// Active returns whether the rollout is running or paused.
+func (r *Rollout) Active() bool {
+	return r.State == RolloutRunning || r.State == RolloutPaused
+}
+
+// References returns whether the rollout deploys the app or may roll a node
+// back to it.
+func (r *Rollout) References(appID string) bool {
+	if r.AppID == appID {
+		return true
+	}
+	for _, t := range r.Targets {
+		if t.FromAppID == appID {
+			return true
+		}
+	}
+	return false
+}
+
+// rolloutMu serializes the updates of rollouts.
+var rolloutMu sync.Mutex
+
+// UpdateRollout reads a rollout, applies update to it and persists it. It
+// returns nil if the rollout does not exist. Updates are serialized, so that
+// pausing a rollout does not overwrite the progress the rollout manager saved
+// meanwhile, nor the other way round. If update fails, the rollout is not
+// persisted.
+func UpdateRollout(
+	ctx context.Context,
+	ps PersistenceService,
+	id string,
+	update func(r *Rollout) error,
+) (*Rollout, error) {
+	rolloutMu.Lock()
+	defer rolloutMu.Unlock()
+
+	persisted, err := ps.Read(ctx, id, &Rollout{})
+	if err != nil || persisted == nil {
+		return nil, err
+	}
+	r := persisted.(*Rollout)
+	if err = update(r); err != nil {
+		return nil, err
+	}
+	if err = ps.BulkUpdate(ctx, []Persistable{r}); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Waves returns the number of waves of the rollout.
+func (r *Rollout) Waves() int {
+	waves := 0
+	for _, t := range r.Targets {
+		if t.Status != RolloutTargetSkipped && t.Wave >= waves {
+			waves = t.Wave + 1
+		}
+	}
+	return waves
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*Rollout) FilterFields() []string {
+	return []string{
+		"app_id",
+	}
+}
+
+func (r *Rollout) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+Rollout[
+    ID: %s
+    AppID: %s
+    State: %s
+    Wave: %d/%d
+    Targets: %d
+]`),
+		r.ID,
+		r.AppID,
+		r.State,
+		r.Wave,
+		r.Waves(),
+		len(r.Targets))
+}
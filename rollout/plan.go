@@ -0,0 +1,136 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package rollout
+
+import (
+	"context"
+	"sort"
+
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/pkg/errors"
+)
+
+// Plan sets the targets of a rollout of app. The nodes are the ones listed by
+// the rollout, else the ones running another version of the app, ordered by
+// ID. Those already running app are skipped, the others are split into the
+// canary wave and batches.
+func Plan(ctx context.Context, ps cce.PersistenceService, r *cce.Rollout, app *cce.App) error {
+	versions, err := releaseIDs(ctx, ps, app)
+	if err != nil {
+		return err
+	}
+	deployed, err := releaseNodeApps(ctx, ps, versions)
+	if err != nil {
+		return err
+	}
+
+	nodeIDs := r.NodeIDs
+	if len(nodeIDs) == 0 {
+		for nodeID := range deployed {
+			nodeIDs = append(nodeIDs, nodeID)
+		}
+	}
+	nodeIDs = append([]string(nil), nodeIDs...)
+	sort.Strings(nodeIDs)
+
+	r.Targets = []cce.RolloutTarget{}
+	planned := 0
+	for i, nodeID := range nodeIDs {
+		if i > 0 && nodeID == nodeIDs[i-1] {
+			continue
+		}
+
+		t := cce.RolloutTarget{
+			NodeID: nodeID,
+			Status: cce.RolloutTargetPending,
+		}
+		if nodeApp, ok := deployed[nodeID]; ok {
+			t.FromAppID = nodeApp.AppID
+			if nodeApp.AppID == app.ID {
+				t.Status = cce.RolloutTargetSkipped
+			}
+		}
+		if t.Status == cce.RolloutTargetPending {
+			t.Wave = wave(r, planned)
+			planned++
+		}
+		r.Targets = append(r.Targets, t)
+	}
+	r.Wave = 0
+
+	return nil
+}
+
+// wave returns the wave of the i-th node deployed to.
+func wave(r *cce.Rollout, i int) int {
+	if i < r.Canary {
+		return 0
+	}
+	w := (i - r.Canary) / r.BatchSize
+	if r.Canary > 0 {
+		w++
+	}
+	return w
+}
+
+// releaseIDs returns the IDs of the versions of the app's release.
+func releaseIDs(ctx context.Context, ps cce.PersistenceService, app *cce.App) (map[string]bool, error) {
+	versions, err := cce.AppVersions(ctx, ps, app)
+	if err != nil {
+		return nil, err
+	}
+	ids := map[string]bool{app.ID: true}
+	for _, v := range versions {
+		ids[v.ID] = true
+	}
+	return ids, nil
+}
+
+// releaseNodeApps returns the node apps running a version of the release,
+// indexed by node ID.
+func releaseNodeApps(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	versions map[string]bool,
+) (map[string]*cce.NodeApp, error) {
+	nodeApps, err := ps.ReadAll(ctx, &cce.NodeApp{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch node apps from DB")
+	}
+
+	deployed := make(map[string]*cce.NodeApp)
+	for _, na := range nodeApps {
+		if nodeApp := na.(*cce.NodeApp); versions[nodeApp.AppID] {
+			deployed[nodeApp.NodeID] = nodeApp
+		}
+	}
+	return deployed, nil
+}
+
+// releaseNodeApp returns the node app of the node running a version of the
+// release or nil if there is none.
+func releaseNodeApp(
+	ctx context.Context,
+	ps cce.PersistenceService,
+	nodeID string,
+	versions map[string]bool,
+) (*cce.NodeApp, error) {
+	nodeApps, err := ps.Filter(ctx, &cce.NodeApp{},
+		[]cce.Filter{
+			{
+				Field: "node_id",
+				Value: nodeID,
+			},
+		})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch node apps from DB")
+	}
+
+	for _, na := range nodeApps {
+		if nodeApp := na.(*cce.NodeApp); versions[nodeApp.AppID] {
+			return nodeApp, nil
+		}
+	}
+	return nil, nil
+}
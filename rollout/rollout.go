@@ -0,0 +1,303 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	logger "github.com/open-ness/common/log"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/pkg/errors"
+)
+
+var log = logger.DefaultLogger.WithField("pkg", "rollout")
+
+// DefaultInterval is the default time between two steps of the rollouts.
+const DefaultInterval = 10 * time.Second
+
+// Operator operates the apps deployed to nodes. Each operation is persisted
+// along with the node app.
+type Operator interface {
+	// Deploy deploys app to the node.
+	Deploy(ctx context.Context, nodeID string, app *cce.App) error
+	// SetVersion replaces the version of the node app with app. Cmd is
+	// upgrade or rollback.
+	SetVersion(ctx context.Context, nodeApp *cce.NodeApp, app *cce.App, cmd string) error
+	// Undeploy removes the app from the node.
+	Undeploy(ctx context.Context, nodeApp *cce.NodeApp) error
+	// Start starts the app on the node.
+	Start(ctx context.Context, nodeApp *cce.NodeApp) error
+	// Status returns the lifecycle status of the app on the node.
+	Status(ctx context.Context, nodeApp *cce.NodeApp) (string, error)
+}
+
+// Manager periodically advances the running rollouts. Each step deploys the
+// version to the pending nodes of the current wave and checks that the ones
+// deployed to reach the running status. As the state of a rollout is
+// persisted after each step, the rollouts resume where they were after a
+// restart of the controller.
+type Manager struct {
+	Controller *cce.Controller
+	Operator   Operator
+
+	// Interval is the time between two steps. If it is zero, DefaultInterval
+	// is used.
+	Interval time.Duration
+}
+
+// Run advances the rollouts every Interval until the context is canceled.
+func (m *Manager) Run(ctx context.Context) error {
+	ticker := time.NewTicker(m.interval())
+	defer ticker.Stop()
+
+	for {
+		m.AdvanceAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// AdvanceAll advances every running rollout by one step.
+func (m *Manager) AdvanceAll(ctx context.Context) {
+	rollouts, err := m.Controller.PersistenceService.ReadAll(ctx, &cce.Rollout{})
+	if err != nil {
+		log.Errf("Error reading rollouts: %v", err)
+		return
+	}
+
+	for _, r := range rollouts {
+		if err := m.Advance(ctx, r.(*cce.Rollout)); err != nil {
+			log.Errf("Error advancing rollout %s: %v", r.GetID(), err)
+		}
+	}
+}
+
+// Advance advances a rollout by one step and persists it. A rollout that is
+// not running is left as is.
+func (m *Manager) Advance(ctx context.Context, r *cce.Rollout) error {
+	if r.State != cce.RolloutRunning {
+		return nil
+	}
+	ps := m.Controller.PersistenceService
+
+	persisted, err := ps.Read(ctx, r.AppID, &cce.App{})
+	if err != nil {
+		return errors.Wrap(err, "could not fetch app from DB")
+	}
+	if persisted == nil {
+		return errors.Errorf("app_id %s not found", r.AppID)
+	}
+	app := persisted.(*cce.App)
+	versions, err := releaseIDs(ctx, ps, app)
+	if err != nil {
+		return err
+	}
+
+	done := true
+	succeeded, failed := 0, 0
+	for i := range r.Targets {
+		t := &r.Targets[i]
+		if t.Wave != r.Wave || t.Status == cce.RolloutTargetSkipped {
+			continue
+		}
+
+		switch t.Status {
+		case cce.RolloutTargetPending:
+			err = m.deploy(ctx, t, app, versions)
+		case cce.RolloutTargetVerifying:
+			err = m.verify(ctx, r, t, versions)
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t.Status {
+		case cce.RolloutTargetSucceeded:
+			succeeded++
+		case cce.RolloutTargetFailed:
+			failed++
+		default:
+			done = false
+		}
+	}
+
+	if done {
+		m.endWave(ctx, r, versions, succeeded, failed)
+	}
+
+	r.UpdatedAt = time.Now().UTC()
+	return m.save(ctx, r)
+}
+
+// deploy deploys the version to the node of a pending target. An error is
+// only returned if the persistence fails, the target fails otherwise.
+func (m *Manager) deploy(
+	ctx context.Context,
+	t *cce.RolloutTarget,
+	app *cce.App,
+	versions map[string]bool,
+) error {
+	nodeApp, err := releaseNodeApp(ctx, m.Controller.PersistenceService, t.NodeID, versions)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case nodeApp == nil:
+		t.Cmd = "deploy"
+		err = m.Operator.Deploy(ctx, t.NodeID, app)
+	case nodeApp.AppID != app.ID:
+		t.Cmd = "upgrade"
+		err = m.Operator.SetVersion(ctx, nodeApp, app, "upgrade")
+	default:
+		// The version was deployed before the controller restarted
+		t.Cmd = "deploy"
+		if t.FromAppID != "" {
+			t.Cmd = "upgrade"
+		}
+	}
+	if err != nil {
+		log.Errf("Error deploying app %s to node %s: %v", app.ID, t.NodeID, err)
+		t.Cmd = ""
+		t.Status = cce.RolloutTargetFailed
+		t.Error = err.Error()
+		return nil
+	}
+
+	now := time.Now().UTC()
+	t.StartedAt = &now
+	t.Status = cce.RolloutTargetVerifying
+	log.Infof("App %s: %s to node %s", app.ID, t.Cmd, t.NodeID)
+	return nil
+}
+
+// verify checks that the app deployed to the node of a target is running,
+// starting it if needed. The target fails if it is not running by the end of
+// the verification timeout.
+func (m *Manager) verify(
+	ctx context.Context,
+	r *cce.Rollout,
+	t *cce.RolloutTarget,
+	versions map[string]bool,
+) error {
+	nodeApp, err := releaseNodeApp(ctx, m.Controller.PersistenceService, t.NodeID, versions)
+	if err != nil {
+		return err
+	}
+	if nodeApp == nil || nodeApp.AppID != r.AppID {
+		t.Status = cce.RolloutTargetFailed
+		t.Error = "app was removed from the node"
+		return nil
+	}
+
+	status, err := m.Operator.Status(ctx, nodeApp)
+	switch {
+	case err != nil:
+		log.Errf("Error getting status of app %s on node %s: %v", r.AppID, t.NodeID, err)
+		status = cce.Unknown.String()
+	case status == cce.Running.String():
+		t.Status = cce.RolloutTargetSucceeded
+		return nil
+	case status == cce.Deployed.String() || status == cce.Stopped.String():
+		if err = m.Operator.Start(ctx, nodeApp); err != nil {
+			log.Errf("Error starting app %s on node %s: %v", r.AppID, t.NodeID, err)
+		}
+	}
+
+	timeout := time.Duration(r.VerifyTimeout) * time.Second
+	if t.StartedAt == nil || time.Since(*t.StartedAt) > timeout {
+		t.Status = cce.RolloutTargetFailed
+		t.Error = fmt.Sprintf("app not running after %s: %s", timeout, status)
+	}
+	return nil
+}
+
+// endWave moves on to the next wave unless too many nodes of the current one
+// failed, in which case the rollout is paused or rolled back.
+func (m *Manager) endWave(
+	ctx context.Context,
+	r *cce.Rollout,
+	versions map[string]bool,
+	succeeded, failed int,
+) {
+	wave := r.Wave
+	r.Wave++
+
+	if total := succeeded + failed; failed > 0 && failed*100 > r.FailureThreshold*total {
+		r.Reason = fmt.Sprintf("wave %d failed on %d of %d nodes", wave, failed, total)
+		if r.OnFailure == cce.RolloutOnFailureRollback {
+			m.rollback(ctx, r, versions)
+			r.State = cce.RolloutRolledBack
+		} else {
+			r.State = cce.RolloutPaused
+		}
+		log.Infof("Rollout %s %s: %s", r.ID, r.State, r.Reason)
+		return
+	}
+
+	if r.Wave >= r.Waves() {
+		r.State = cce.RolloutCompleted
+		log.Infof("Rollout %s completed", r.ID)
+	}
+}
+
+// rollback restores the nodes deployed to so far: the previous version is
+// redeployed to the nodes upgraded and the app is removed from the others.
+func (m *Manager) rollback(ctx context.Context, r *cce.Rollout, versions map[string]bool) {
+	ps := m.Controller.PersistenceService
+
+	for i := range r.Targets {
+		t := &r.Targets[i]
+		if t.Cmd == "" || t.Status == cce.RolloutTargetRolledBack {
+			continue
+		}
+
+		nodeApp, err := releaseNodeApp(ctx, ps, t.NodeID, versions)
+		if err == nil && nodeApp != nil {
+			switch t.Cmd {
+			case "deploy":
+				err = m.Operator.Undeploy(ctx, nodeApp)
+			case "upgrade":
+				var from cce.Persistable
+				if from, err = ps.Read(ctx, t.FromAppID, &cce.App{}); err == nil && from == nil {
+					err = errors.Errorf("app_id %s not found", t.FromAppID)
+				}
+				if err == nil {
+					err = m.Operator.SetVersion(ctx, nodeApp, from.(*cce.App), "rollback")
+				}
+			}
+		}
+		if err != nil {
+			log.Errf("Error rolling back app %s on node %s: %v", r.AppID, t.NodeID, err)
+			t.Error = fmt.Sprintf("rollback failed: %v", err)
+			continue
+		}
+		t.Status = cce.RolloutTargetRolledBack
+	}
+}
+
+// save persists a rollout, keeping the pause requested meanwhile.
+func (m *Manager) save(ctx context.Context, r *cce.Rollout) error {
+	_, err := cce.UpdateRollout(ctx, m.Controller.PersistenceService, r.ID, func(persisted *cce.Rollout) error {
+		if persisted.State == cce.RolloutPaused && r.State == cce.RolloutRunning {
+			r.State = cce.RolloutPaused
+		}
+		*persisted = *r
+		return nil
+	})
+	return errors.Wrap(err, "could not store rollout")
+}
+
+func (m *Manager) interval() time.Duration {
+	if m.Interval == 0 {
+		return DefaultInterval
+	}
+	return m.Interval
+}
@@ -0,0 +1,364 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package rollout_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/internal/stubs"
+	"github.com/open-ness/edgecontroller/rollout"
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+var nodeIDs = []string{
+	"1a2b3c4d-0000-4000-8000-000000000001",
+	"1a2b3c4d-0000-4000-8000-000000000002",
+	"1a2b3c4d-0000-4000-8000-000000000003",
+	"1a2b3c4d-0000-4000-8000-000000000004",
+}
+
+// operator operates the node apps in memory.
+type operator struct {
+	ps       cce.PersistenceService
+	statuses map[string]string
+	errs     map[string]error
+	ops      []string
+}
+
+func (o *operator) Deploy(ctx context.Context, nodeID string, app *cce.App) error {
+	o.ops = append(o.ops, "deploy "+nodeID)
+	if err := o.errs[nodeID]; err != nil {
+		return err
+	}
+	nodeApp := &cce.NodeApp{ID: uuid.New(), NodeID: nodeID, AppID: app.ID}
+	nodeApp.Record(app, "deploy", time.Now())
+	return o.ps.Create(ctx, nodeApp)
+}
+
+func (o *operator) SetVersion(ctx context.Context, nodeApp *cce.NodeApp, app *cce.App, cmd string) error {
+	o.ops = append(o.ops, cmd+" "+nodeApp.NodeID)
+	if err := o.errs[nodeApp.NodeID]; err != nil && cmd == "upgrade" {
+		return err
+	}
+	nodeApp.Record(app, cmd, time.Now())
+	return o.ps.BulkUpdate(ctx, []cce.Persistable{nodeApp})
+}
+
+func (o *operator) Undeploy(ctx context.Context, nodeApp *cce.NodeApp) error {
+	o.ops = append(o.ops, "undeploy "+nodeApp.NodeID)
+	_, err := o.ps.Delete(ctx, nodeApp.ID, &cce.NodeApp{})
+	return err
+}
+
+func (o *operator) Start(ctx context.Context, nodeApp *cce.NodeApp) error {
+	o.ops = append(o.ops, "start "+nodeApp.NodeID)
+	o.statuses[nodeApp.NodeID] = cce.Running.String()
+	return nil
+}
+
+func (o *operator) Status(ctx context.Context, nodeApp *cce.NodeApp) (string, error) {
+	if status, ok := o.statuses[nodeApp.NodeID]; ok {
+		return status, nil
+	}
+	return cce.Running.String(), nil
+}
+
+var _ = Describe("Rollouts", func() {
+	var (
+		ctx     context.Context
+		ps      *stubs.MemoryPersistenceService
+		op      *operator
+		manager *rollout.Manager
+
+		v1, v2 *cce.App
+	)
+
+	version := func(id, version string) *cce.App {
+		return &cce.App{
+			ID:      id,
+			Type:    "container",
+			Name:    "test-app",
+			Vendor:  "test-vendor",
+			Version: version,
+		}
+	}
+
+	deployV1 := func(nodeIDs ...string) {
+		for _, nodeID := range nodeIDs {
+			nodeApp := &cce.NodeApp{ID: uuid.New(), NodeID: nodeID, AppID: v1.ID}
+			nodeApp.Record(v1, "deploy", time.Now())
+			Expect(ps.Create(ctx, nodeApp)).To(Succeed())
+		}
+	}
+
+	deployedVersions := func() map[string]string {
+		persisted, err := ps.ReadAll(ctx, &cce.NodeApp{})
+		Expect(err).ToNot(HaveOccurred())
+		versions := make(map[string]string)
+		for _, na := range persisted {
+			versions[na.(*cce.NodeApp).NodeID] = na.(*cce.NodeApp).AppID
+		}
+		return versions
+	}
+
+	newRollout := func(r *cce.Rollout) *cce.Rollout {
+		r.ID = uuid.New()
+		r.AppID = v2.ID
+		r.SetDefaults()
+		Expect(r.Validate()).To(Succeed())
+		Expect(rollout.Plan(ctx, ps, r, v2)).To(Succeed())
+		Expect(ps.Create(ctx, r)).To(Succeed())
+		return r
+	}
+
+	advance := func(r *cce.Rollout) *cce.Rollout {
+		Expect(manager.Advance(ctx, r)).To(Succeed())
+		persisted, err := ps.Read(ctx, r.ID, &cce.Rollout{})
+		Expect(err).ToNot(HaveOccurred())
+		return persisted.(*cce.Rollout)
+	}
+
+	statuses := func(r *cce.Rollout) []string {
+		var ss []string
+		for _, t := range r.Targets {
+			ss = append(ss, t.Status)
+		}
+		return ss
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		ps = stubs.NewMemoryPersistenceService()
+		op = &operator{ps: ps, statuses: map[string]string{}, errs: map[string]error{}}
+		manager = &rollout.Manager{
+			Controller: &cce.Controller{PersistenceService: ps},
+			Operator:   op,
+		}
+
+		v1 = version("c9b4bc07-0d6b-4a6e-8e36-d1e0e7d6d7a1", "1.0")
+		v2 = version("5e1f3b0a-48a2-4c8e-9f4d-6f0b2a9c3e12", "2.0")
+		Expect(ps.Create(ctx, v1)).To(Succeed())
+		Expect(ps.Create(ctx, v2)).To(Succeed())
+	})
+
+	Describe("Plan", func() {
+		It("Should split the nodes running another version into waves", func() {
+			deployV1(nodeIDs...)
+
+			r := &cce.Rollout{Canary: 1, BatchSize: 2}
+			r.SetDefaults()
+			Expect(rollout.Plan(ctx, ps, r, v2)).To(Succeed())
+
+			Expect(r.Targets).To(Equal([]cce.RolloutTarget{
+				{NodeID: nodeIDs[0], Wave: 0, FromAppID: v1.ID, Status: cce.RolloutTargetPending},
+				{NodeID: nodeIDs[1], Wave: 1, FromAppID: v1.ID, Status: cce.RolloutTargetPending},
+				{NodeID: nodeIDs[2], Wave: 1, FromAppID: v1.ID, Status: cce.RolloutTargetPending},
+				{NodeID: nodeIDs[3], Wave: 2, FromAppID: v1.ID, Status: cce.RolloutTargetPending},
+			}))
+			Expect(r.Waves()).To(Equal(3))
+		})
+
+		It("Should skip the nodes already running the version", func() {
+			deployV1(nodeIDs[0])
+
+			r := &cce.Rollout{NodeIDs: []string{nodeIDs[2], nodeIDs[0], nodeIDs[1], nodeIDs[2]}}
+			r.SetDefaults()
+			Expect(rollout.Plan(ctx, ps, r, v1)).To(Succeed())
+
+			Expect(r.Targets).To(Equal([]cce.RolloutTarget{
+				{NodeID: nodeIDs[0], FromAppID: v1.ID, Status: cce.RolloutTargetSkipped},
+				{NodeID: nodeIDs[1], Wave: 0, Status: cce.RolloutTargetPending},
+				{NodeID: nodeIDs[2], Wave: 1, Status: cce.RolloutTargetPending},
+			}))
+		})
+	})
+
+	Describe("Advance", func() {
+		It("Should deploy the version wave by wave", func() {
+			deployV1(nodeIDs[:3]...)
+			r := newRollout(&cce.Rollout{
+				NodeIDs:   nodeIDs,
+				Canary:    1,
+				BatchSize: 3,
+			})
+
+			By("Upgrading the canary")
+			r = advance(r)
+			Expect(statuses(r)).To(Equal([]string{"verifying", "pending", "pending", "pending"}))
+			Expect(op.ops).To(Equal([]string{"upgrade " + nodeIDs[0]}))
+
+			By("Verifying the canary")
+			r = advance(r)
+			Expect(statuses(r)).To(Equal([]string{"succeeded", "pending", "pending", "pending"}))
+			Expect(r.Wave).To(Equal(1))
+
+			By("Deploying to the batch")
+			op.statuses[nodeIDs[3]] = cce.Deployed.String()
+			r = advance(r)
+			Expect(statuses(r)).To(Equal([]string{"succeeded", "verifying", "verifying", "verifying"}))
+			Expect(op.ops[1:]).To(Equal([]string{
+				"upgrade " + nodeIDs[1],
+				"upgrade " + nodeIDs[2],
+				"deploy " + nodeIDs[3],
+			}))
+			Expect(r.Targets[3].Cmd).To(Equal("deploy"))
+
+			By("Starting the app not running")
+			r = advance(r)
+			Expect(statuses(r)).To(Equal([]string{"succeeded", "succeeded", "succeeded", "verifying"}))
+			Expect(op.ops[4:]).To(Equal([]string{"start " + nodeIDs[3]}))
+
+			By("Completing the rollout")
+			r = advance(r)
+			Expect(r.State).To(Equal(cce.RolloutCompleted))
+			Expect(deployedVersions()).To(Equal(map[string]string{
+				nodeIDs[0]: v2.ID,
+				nodeIDs[1]: v2.ID,
+				nodeIDs[2]: v2.ID,
+				nodeIDs[3]: v2.ID,
+			}))
+		})
+
+		It("Should pause when the canary fails", func() {
+			deployV1(nodeIDs...)
+			op.errs[nodeIDs[0]] = errors.New("image not found")
+			r := newRollout(&cce.Rollout{Canary: 1})
+
+			r = advance(r)
+			Expect(r.State).To(Equal(cce.RolloutPaused))
+			Expect(r.Reason).To(Equal("wave 0 failed on 1 of 1 nodes"))
+			Expect(r.Targets[0].Status).To(Equal(cce.RolloutTargetFailed))
+			Expect(r.Targets[0].Error).To(Equal("image not found"))
+
+			By("Not advancing while paused")
+			r = advance(r)
+			Expect(op.ops).To(HaveLen(1))
+
+			By("Continuing with the next wave once resumed")
+			r.State = cce.RolloutRunning
+			Expect(ps.BulkUpdate(ctx, []cce.Persistable{r})).To(Succeed())
+			r = advance(r)
+			Expect(r.State).To(Equal(cce.RolloutRunning))
+			Expect(r.Targets[1].Status).To(Equal(cce.RolloutTargetVerifying))
+		})
+
+		It("Should fail a node whose app is not running in time", func() {
+			deployV1(nodeIDs[0])
+			op.statuses[nodeIDs[0]] = cce.Error.String()
+			r := newRollout(&cce.Rollout{VerifyTimeout: 1})
+
+			r = advance(r)
+			Expect(r.Targets[0].Status).To(Equal(cce.RolloutTargetVerifying))
+
+			startedAt := time.Now().Add(-2 * time.Second)
+			r.Targets[0].StartedAt = &startedAt
+			r = advance(r)
+			Expect(r.Targets[0].Status).To(Equal(cce.RolloutTargetFailed))
+			Expect(r.Targets[0].Error).To(Equal("app not running after 1s: error"))
+			Expect(r.State).To(Equal(cce.RolloutPaused))
+		})
+
+		It("Should tolerate failures up to the threshold", func() {
+			deployV1(nodeIDs...)
+			op.errs[nodeIDs[1]] = errors.New("node unreachable")
+			r := newRollout(&cce.Rollout{BatchSize: 4, FailureThreshold: 25})
+
+			r = advance(r)
+			r = advance(r)
+			Expect(r.State).To(Equal(cce.RolloutCompleted))
+			Expect(statuses(r)).To(Equal([]string{"succeeded", "failed", "succeeded", "succeeded"}))
+		})
+
+		It("Should roll back the nodes deployed to", func() {
+			deployV1(nodeIDs[:3]...)
+			op.errs[nodeIDs[2]] = errors.New("node unreachable")
+			r := newRollout(&cce.Rollout{
+				NodeIDs:   nodeIDs,
+				Canary:    1,
+				BatchSize: 2,
+				OnFailure: cce.RolloutOnFailureRollback,
+			})
+
+			r = advance(r)
+			r = advance(r)
+			r = advance(r)
+			Expect(statuses(r)).To(Equal([]string{"succeeded", "verifying", "failed", "pending"}))
+			r = advance(r)
+
+			Expect(r.State).To(Equal(cce.RolloutRolledBack))
+			Expect(r.Reason).To(Equal("wave 1 failed on 1 of 2 nodes"))
+			Expect(statuses(r)).To(Equal([]string{"rolled_back", "rolled_back", "failed", "pending"}))
+			Expect(deployedVersions()).To(Equal(map[string]string{
+				nodeIDs[0]: v1.ID,
+				nodeIDs[1]: v1.ID,
+				nodeIDs[2]: v1.ID,
+			}))
+		})
+
+		It("Should undeploy the app from the nodes it was deployed to", func() {
+			r := newRollout(&cce.Rollout{
+				NodeIDs:   nodeIDs[:1],
+				OnFailure: cce.RolloutOnFailureRollback,
+			})
+			op.statuses[nodeIDs[0]] = cce.Error.String()
+
+			r = advance(r)
+			startedAt := time.Now().Add(-time.Hour)
+			r.Targets[0].StartedAt = &startedAt
+			r = advance(r)
+
+			Expect(r.State).To(Equal(cce.RolloutRolledBack))
+			Expect(op.ops).To(Equal([]string{"deploy " + nodeIDs[0], "undeploy " + nodeIDs[0]}))
+			Expect(deployedVersions()).To(BeEmpty())
+		})
+
+		It("Should not deploy again to a node already running the version", func() {
+			deployV1(nodeIDs[0])
+			r := newRollout(&cce.Rollout{})
+
+			By("Upgrading the node before the controller restarts")
+			na, err := ps.ReadAll(ctx, &cce.NodeApp{})
+			Expect(err).ToNot(HaveOccurred())
+			na[0].(*cce.NodeApp).Record(v2, "upgrade", time.Now())
+			Expect(ps.BulkUpdate(ctx, na)).To(Succeed())
+
+			r = advance(r)
+			Expect(op.ops).To(BeEmpty())
+			Expect(r.Targets[0].Status).To(Equal(cce.RolloutTargetVerifying))
+			Expect(r.Targets[0].Cmd).To(Equal("upgrade"))
+		})
+
+		It("Should keep a pause requested while advancing", func() {
+			deployV1(nodeIDs[0])
+			r := newRollout(&cce.Rollout{})
+
+			paused := *r
+			paused.State = cce.RolloutPaused
+			Expect(ps.BulkUpdate(ctx, []cce.Persistable{&paused})).To(Succeed())
+
+			r = advance(r)
+			Expect(r.State).To(Equal(cce.RolloutPaused))
+			Expect(r.Targets[0].Status).To(Equal(cce.RolloutTargetVerifying))
+		})
+	})
+
+	Describe("AdvanceAll", func() {
+		It("Should advance the running rollouts", func() {
+			deployV1(nodeIDs[0])
+			r := newRollout(&cce.Rollout{})
+
+			manager.AdvanceAll(ctx)
+			manager.AdvanceAll(ctx)
+
+			persisted, err := ps.Read(ctx, r.ID, &cce.Rollout{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(persisted.(*cce.Rollout).State).To(Equal(cce.RolloutCompleted))
+		})
+	})
+})
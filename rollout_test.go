@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/internal/stubs"
+)
+
+var _ = Describe("Entities: Rollout", func() {
+	var (
+		rollout *cce.Rollout
+	)
+
+	BeforeEach(func() {
+		rollout = &cce.Rollout{
+			ID:               "5a0d3a6c-0c1c-4b2a-a8a6-6f3d4e0b9c21",
+			AppID:            "0fe0a2a5-1f56-4c5c-9b4b-1cde2ad2f7ba",
+			NodeIDs:          []string{"8f1d1c2a-4b41-4d5e-a5d0-7a0f0e6b0c11"},
+			Canary:           1,
+			BatchSize:        2,
+			FailureThreshold: 50,
+			OnFailure:        cce.RolloutOnFailureRollback,
+			VerifyTimeout:    60,
+			State:            cce.RolloutRunning,
+			Targets: []cce.RolloutTarget{
+				{NodeID: "n1", Wave: 0, Status: cce.RolloutTargetSucceeded},
+				{NodeID: "n2", Wave: 1, Status: cce.RolloutTargetPending},
+				{NodeID: "n3", Wave: 0, Status: cce.RolloutTargetSkipped},
+			},
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "rollouts"`, func() {
+			Expect(rollout.GetTableName()).To(Equal("rollouts"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(rollout.GetID()).To(Equal(
+				"5a0d3a6c-0c1c-4b2a-a8a6-6f3d4e0b9c21"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			rollout.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(rollout.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for a valid rollout", func() {
+			Expect(rollout.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			rollout.ID = "123"
+			Expect(rollout.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if AppID is not a UUID", func() {
+			rollout.AppID = "123"
+			Expect(rollout.Validate()).To(MatchError("app_id not a valid uuid"))
+		})
+
+		It("Should return an error if a node ID is not a UUID", func() {
+			rollout.NodeIDs = append(rollout.NodeIDs, "123")
+			Expect(rollout.Validate()).To(MatchError("node_ids contains an invalid uuid: 123"))
+		})
+
+		It("Should return an error if Canary is negative", func() {
+			rollout.Canary = -1
+			Expect(rollout.Validate()).To(MatchError("canary cannot be negative"))
+		})
+
+		It("Should return an error if BatchSize is not positive", func() {
+			rollout.BatchSize = 0
+			Expect(rollout.Validate()).To(MatchError("batch_size must be greater than 0"))
+		})
+
+		It("Should return an error if FailureThreshold is out of range", func() {
+			rollout.FailureThreshold = 101
+			Expect(rollout.Validate()).To(MatchError("failure_threshold must be in [0..100]"))
+		})
+
+		It("Should return an error if OnFailure is invalid", func() {
+			rollout.OnFailure = "retry"
+			Expect(rollout.Validate()).To(MatchError("on_failure must be one of [pause, rollback]"))
+		})
+
+		It("Should return an error if VerifyTimeout is not positive", func() {
+			rollout.VerifyTimeout = 0
+			Expect(rollout.Validate()).To(MatchError("verify_timeout must be greater than 0"))
+		})
+
+		It("Should return an error if State is invalid", func() {
+			rollout.State = "done"
+			Expect(rollout.Validate()).To(MatchError(
+				"state must be one of [running, paused, completed, rolled_back]"))
+		})
+	})
+
+	Describe("SetDefaults", func() {
+		It("Should fill the settings left zero", func() {
+			rollout = &cce.Rollout{}
+			rollout.SetDefaults()
+			Expect(rollout.BatchSize).To(Equal(cce.DefaultRolloutBatchSize))
+			Expect(rollout.OnFailure).To(Equal(cce.RolloutOnFailurePause))
+			Expect(rollout.VerifyTimeout).To(Equal(cce.DefaultRolloutVerifyTimeout))
+			Expect(rollout.State).To(Equal(cce.RolloutRunning))
+		})
+
+		It("Should keep the settings given", func() {
+			rollout.SetDefaults()
+			Expect(rollout.BatchSize).To(Equal(2))
+			Expect(rollout.OnFailure).To(Equal(cce.RolloutOnFailureRollback))
+			Expect(rollout.VerifyTimeout).To(Equal(60))
+		})
+	})
+
+	Describe("Active", func() {
+		It("Should be active while running or paused", func() {
+			Expect(rollout.Active()).To(BeTrue())
+			rollout.State = cce.RolloutPaused
+			Expect(rollout.Active()).To(BeTrue())
+			rollout.State = cce.RolloutCompleted
+			Expect(rollout.Active()).To(BeFalse())
+			rollout.State = cce.RolloutRolledBack
+			Expect(rollout.Active()).To(BeFalse())
+		})
+	})
+
+	Describe("References", func() {
+		It("Should reference the version rolled out and the versions replaced", func() {
+			rollout.Targets[1].FromAppID = "9c6a9d1e-7b1f-4a55-8d6e-2f3b1c0d4e5a"
+			Expect(rollout.References(rollout.AppID)).To(BeTrue())
+			Expect(rollout.References("9c6a9d1e-7b1f-4a55-8d6e-2f3b1c0d4e5a")).To(BeTrue())
+			Expect(rollout.References("3d7e2c1b-5a4f-4e3d-9c2b-1a0f9e8d7c6b")).To(BeFalse())
+		})
+	})
+
+	Describe("UpdateRollout", func() {
+		var ps *stubs.MemoryPersistenceService
+
+		BeforeEach(func() {
+			ps = stubs.NewMemoryPersistenceService()
+			Expect(ps.Create(context.TODO(), rollout)).To(Succeed())
+		})
+
+		It("Should persist the update of the rollout", func() {
+			updated, err := cce.UpdateRollout(context.TODO(), ps, rollout.ID, func(r *cce.Rollout) error {
+				r.State = cce.RolloutPaused
+				return nil
+			})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(updated.State).To(Equal(cce.RolloutPaused))
+
+			persisted, err := ps.Read(context.TODO(), rollout.ID, &cce.Rollout{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(persisted.(*cce.Rollout).State).To(Equal(cce.RolloutPaused))
+			Expect(persisted.(*cce.Rollout).Targets).To(Equal(rollout.Targets))
+		})
+
+		It("Should not persist a failed update", func() {
+			_, err := cce.UpdateRollout(context.TODO(), ps, rollout.ID, func(r *cce.Rollout) error {
+				r.State = cce.RolloutPaused
+				return errors.New("rollout completed")
+			})
+			Expect(err).To(MatchError("rollout completed"))
+
+			persisted, err := ps.Read(context.TODO(), rollout.ID, &cce.Rollout{})
+			Expect(err).ToNot(HaveOccurred())
+			Expect(persisted.(*cce.Rollout).State).To(Equal(cce.RolloutRunning))
+		})
+
+		It("Should return nil for an unknown rollout", func() {
+			Expect(cce.UpdateRollout(context.TODO(), ps, "unknown", func(r *cce.Rollout) error {
+				Fail("unknown rollout updated")
+				return nil
+			})).To(BeNil())
+		})
+	})
+
+	Describe("Waves", func() {
+		It("Should count the waves of the targets not skipped", func() {
+			Expect(rollout.Waves()).To(Equal(2))
+			rollout.Targets = nil
+			Expect(rollout.Waves()).To(Equal(0))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(rollout.FilterFields()).To(Equal([]string{
+				"app_id",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(rollout.String()).To(Equal(strings.TrimSpace(`
+Rollout[
+    ID: 5a0d3a6c-0c1c-4b2a-a8a6-6f3d4e0b9c21
+    AppID: 0fe0a2a5-1f56-4c5c-9b4b-1cde2ad2f7ba
+    State: running
+    Wave: 0/2
+    Targets: 3
+]`,
+			)))
+		})
+	})
+})
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package scheduler
+
+import (
+	"context"
+
+	cce "github.com/open-ness/edgecontroller"
+	nfd "github.com/open-ness/edgecontroller/nfd-master"
+	"github.com/pkg/errors"
+)
+
+// Candidates loads every node as a candidate for scheduling.
+func Candidates(ctx context.Context, ps cce.PersistenceService) ([]*Candidate, error) {
+	nodes, err := ps.ReadAll(ctx, &cce.Node{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch nodes from DB")
+	}
+
+	candidates := make(map[string]*Candidate, len(nodes))
+	var ordered []*Candidate
+	for _, n := range nodes {
+		c := &Candidate{
+			NodeID:   n.GetID(),
+			Labels:   n.(*cce.Node).Labels,
+			Features: map[string]string{},
+			Status:   cce.NodeStateUnknown,
+		}
+		candidates[c.NodeID] = c
+		ordered = append(ordered, c)
+	}
+
+	targets, err := ps.ReadAll(ctx, &cce.NodeGRPCTarget{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch gRPC targets from DB")
+	}
+	for _, t := range targets {
+		if c, ok := candidates[t.(*cce.NodeGRPCTarget).NodeID]; ok {
+			c.Enrolled = true
+		}
+	}
+
+	statuses, err := ps.ReadAll(ctx, &cce.NodeStatus{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch node statuses from DB")
+	}
+	for _, s := range statuses {
+		if c, ok := candidates[s.(*cce.NodeStatus).NodeID]; ok {
+			c.Status = s.(*cce.NodeStatus).Status
+		}
+	}
+
+	features, err := ps.ReadAll(ctx, &nfd.NodeFeatureNFD{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch NFD features from DB")
+	}
+	for _, f := range features {
+		feature := f.(*nfd.NodeFeatureNFD)
+		if c, ok := candidates[feature.NodeID]; ok {
+			c.Features[feature.NfdID] = feature.NfdValue
+		}
+	}
+	for _, n := range nodes {
+		c := candidates[n.GetID()]
+		c.Allocatable = cce.Allocatable(n.(*cce.Node), c.Features)
+	}
+
+	apps, err := ps.ReadAll(ctx, &cce.App{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch apps from DB")
+	}
+	appsByID := make(map[string]*cce.App, len(apps))
+	for _, a := range apps {
+		appsByID[a.GetID()] = a.(*cce.App)
+	}
+
+	nodeApps, err := ps.ReadAll(ctx, &cce.NodeApp{})
+	if err != nil {
+		return nil, errors.Wrap(err, "could not fetch node apps from DB")
+	}
+	for _, na := range nodeApps {
+		nodeApp := na.(*cce.NodeApp)
+		c, ok := candidates[nodeApp.NodeID]
+		if !ok {
+			continue
+		}
+		c.Apps = append(c.Apps, nodeApp.AppID)
+		if app, ok := appsByID[nodeApp.AppID]; ok {
+			c.Committed.Cores += app.Cores
+			c.Committed.Memory += app.Memory
+		}
+	}
+
+	return ordered, nil
+}
@@ -0,0 +1,96 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package scheduler_test
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/internal/stubs"
+	nfd "github.com/open-ness/edgecontroller/nfd-master"
+	"github.com/open-ness/edgecontroller/scheduler"
+)
+
+var _ = Describe("Candidates", func() {
+	const (
+		nodeID      = "48606c73-3905-47e0-864f-14bc7466f5bb"
+		otherNodeID = "a0e0ec5f-6d2a-4bb0-a0b5-3a2b0dc5b0e4"
+	)
+
+	var ps *stubs.MemoryPersistenceService
+
+	create := func(e cce.Persistable) {
+		Expect(ps.Create(context.TODO(), e)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ps = stubs.NewMemoryPersistenceService()
+
+		create(&cce.Node{ID: nodeID, Labels: map[string]string{"region": "east"}})
+		create(&cce.Node{ID: otherNodeID, Allocatable: &cce.Resources{Cores: 4, Memory: 4096}})
+		create(&cce.NodeGRPCTarget{
+			ID:         "ca0fa495-1020-405b-a78c-9a1884349078",
+			NodeID:     nodeID,
+			GRPCTarget: "127.0.0.1",
+		})
+		create(&cce.NodeStatus{
+			ID:     "6c7eacb8-7b95-4541-940c-aa18a6204645",
+			NodeID: nodeID,
+			Status: cce.NodeStateOnline,
+		})
+		create(&nfd.NodeFeatureNFD{
+			ID:       "1f7c3ab5-2f5e-4d4c-8d5f-6b4a0e6e3c2a",
+			NodeID:   nodeID,
+			NfdID:    "cpu-cpuid.AVX512F",
+			NfdValue: "true",
+		})
+		create(&nfd.NodeFeatureNFD{
+			ID:       "0a4d6f7e-9c1b-4c55-8e0d-2b6f3f1e9a31",
+			NodeID:   nodeID,
+			NfdID:    cce.NFDAllocatableCores,
+			NfdValue: "16",
+		})
+		create(&nfd.NodeFeatureNFD{
+			ID:       "5b2e8c0d-7a3f-4e61-9d2c-4c8a1b7f6e42",
+			NodeID:   nodeID,
+			NfdID:    cce.NFDAllocatableMemory,
+			NfdValue: "32768",
+		})
+		create(&cce.App{ID: appID, Cores: 2, Memory: 1024})
+		create(&cce.NodeApp{
+			ID:     "3ad5b2e4-2b64-4d3c-9a77-0a6c5a1f0f5e",
+			NodeID: nodeID,
+			AppID:  appID,
+		})
+	})
+
+	It("Should load the nodes with their state and deployed apps", func() {
+		candidates, err := scheduler.Candidates(context.TODO(), ps)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(candidates).To(ConsistOf(
+			&scheduler.Candidate{
+				NodeID: nodeID,
+				Labels: map[string]string{"region": "east"},
+				Features: map[string]string{
+					"cpu-cpuid.AVX512F":      "true",
+					cce.NFDAllocatableCores:  "16",
+					cce.NFDAllocatableMemory: "32768",
+				},
+				Apps:        []string{appID},
+				Enrolled:    true,
+				Status:      cce.NodeStateOnline,
+				Allocatable: &cce.Resources{Cores: 16, Memory: 32768},
+				Committed:   cce.Resources{Cores: 2, Memory: 1024},
+			},
+			&scheduler.Candidate{
+				NodeID:      otherNodeID,
+				Features:    map[string]string{},
+				Status:      cce.NodeStateUnknown,
+				Allocatable: &cce.Resources{Cores: 4, Memory: 4096},
+			},
+		))
+	})
+})
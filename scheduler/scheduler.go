@@ -0,0 +1,226 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+
+	cce "github.com/open-ness/edgecontroller"
+)
+
+// Scheduling strategies
+const (
+	// StrategySpread prefers the nodes with the most free resources.
+	StrategySpread = "spread"
+	// StrategyBinpack prefers the nodes with the least free resources the
+	// app still fits in.
+	StrategyBinpack = "binpack"
+)
+
+// affinityWeight is the score given for each affinity app deployed to a node
+// and taken for each anti-affinity app. It outweighs the free resources, which
+// score at most 100.
+const affinityWeight = 100
+
+// Request describes how to place an app.
+type Request struct {
+	// Replicas is the number of nodes to deploy the app to. If it is zero,
+	// one node is chosen.
+	Replicas int
+	// Strategy is StrategySpread or StrategyBinpack. If it is empty,
+	// StrategySpread is used.
+	Strategy string
+	// NodeSelector lists the labels a node must have.
+	NodeSelector map[string]string
+	// Affinity lists the apps the node should already run.
+	Affinity []string
+	// AntiAffinity lists the apps the node should not run.
+	AntiAffinity []string
+}
+
+// Validate validates the request.
+func (r *Request) Validate() error {
+	if r.Replicas < 0 {
+		return fmt.Errorf("replicas cannot be negative")
+	}
+	switch r.Strategy {
+	case "", StrategySpread, StrategyBinpack:
+	default:
+		return fmt.Errorf("strategy must be one of [%s, %s]", StrategySpread, StrategyBinpack)
+	}
+	return nil
+}
+
+func (r *Request) replicas() int {
+	if r.Replicas == 0 {
+		return 1
+	}
+	return r.Replicas
+}
+
+// Candidate is a node an app may be placed on.
+type Candidate struct {
+	NodeID string
+	Labels map[string]string
+	// Features are the NFD labels of the node.
+	Features map[string]string
+	// Apps are the IDs of the apps deployed to the node.
+	Apps []string
+	// Enrolled is whether the node has enrolled and can be reached.
+	Enrolled bool
+	Status   cce.NodeState
+
+	// Allocatable are the resources of the node apps can use, or nil if
+	// they are unknown.
+	Allocatable *cce.Resources
+	// Committed are the resources of the deployed apps.
+	Committed cce.Resources
+}
+
+// Placement is a node chosen for an app.
+type Placement struct {
+	NodeID string
+	Score  int
+}
+
+// Exclusion is a node not chosen for an app, and why.
+type Exclusion struct {
+	NodeID  string
+	Reasons []string
+}
+
+// Decision is the outcome of scheduling an app. Selected is ordered by
+// preference and holds fewer nodes than requested if not enough fit.
+type Decision struct {
+	Selected []Placement
+	Excluded []Exclusion
+}
+
+// Schedule chooses the nodes to deploy an app to. Nodes the app does not fit
+// are filtered out, the others are scored by the strategy and the affinity of
+// the request, and the best ones are selected.
+func Schedule(app *cce.App, candidates []*Candidate, req Request) (*Decision, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	decision := &Decision{
+		Selected: []Placement{},
+		Excluded: []Exclusion{},
+	}
+
+	var feasible []Placement
+	for _, c := range candidates {
+		if reasons := filter(app, c, &req); len(reasons) > 0 {
+			decision.Excluded = append(decision.Excluded, Exclusion{NodeID: c.NodeID, Reasons: reasons})
+			continue
+		}
+		feasible = append(feasible, Placement{NodeID: c.NodeID, Score: score(app, c, &req)})
+	}
+
+	sort.Slice(feasible, func(i, j int) bool {
+		if feasible[i].Score != feasible[j].Score {
+			return feasible[i].Score > feasible[j].Score
+		}
+		return feasible[i].NodeID < feasible[j].NodeID
+	})
+
+	for i, p := range feasible {
+		if i < req.replicas() {
+			decision.Selected = append(decision.Selected, p)
+			continue
+		}
+		decision.Excluded = append(decision.Excluded, Exclusion{
+			NodeID:  p.NodeID,
+			Reasons: []string{fmt.Sprintf("scored %d, below the selected nodes", p.Score)},
+		})
+	}
+
+	sort.Slice(decision.Excluded, func(i, j int) bool {
+		return decision.Excluded[i].NodeID < decision.Excluded[j].NodeID
+	})
+
+	return decision, nil
+}
+
+// filter returns the reasons the app can't be placed on the node.
+func filter(app *cce.App, c *Candidate, req *Request) []string {
+	var reasons []string
+
+	if !c.Enrolled {
+		reasons = append(reasons, "node has not enrolled")
+	}
+	if c.Status == cce.NodeStateOffline {
+		reasons = append(reasons, "node is offline")
+	}
+	if contains(c.Apps, app.ID) {
+		reasons = append(reasons, "app is already deployed to the node")
+	}
+
+	keys := make([]string, 0, len(req.NodeSelector))
+	for key := range req.NodeSelector {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, ok := c.Labels[key]
+		switch {
+		case !ok:
+			reasons = append(reasons, fmt.Sprintf("label %s=%s required, node does not have it",
+				key, req.NodeSelector[key]))
+		case value != req.NodeSelector[key]:
+			reasons = append(reasons, fmt.Sprintf("label %s=%s required, node has %s=%s",
+				key, req.NodeSelector[key], key, value))
+		}
+	}
+
+	if err := app.EPAValidate(c.Features); err != nil {
+		reasons = append(reasons, err.Error())
+	}
+
+	if err := cce.CheckCapacity(app, 1, c.Allocatable, c.Committed); err != nil {
+		reasons = append(reasons, err.Error())
+	}
+
+	return reasons
+}
+
+// score rates a node the app fits on, the higher the better.
+func score(app *cce.App, c *Candidate, req *Request) int {
+	// Share of the resources left free once the app is placed, in [0..100].
+	// A node whose resources are unknown is taken as half free.
+	free := 50
+	if a := c.Allocatable; a != nil {
+		free = (100*(a.Cores-c.Committed.Cores-app.Cores)/a.Cores +
+			100*(a.Memory-c.Committed.Memory-app.Memory)/a.Memory) / 2
+	}
+
+	s := free
+	if req.Strategy == StrategyBinpack {
+		s = 100 - free
+	}
+
+	for _, id := range req.Affinity {
+		if contains(c.Apps, id) {
+			s += affinityWeight
+		}
+	}
+	for _, id := range req.AntiAffinity {
+		if contains(c.Apps, id) {
+			s -= affinityWeight
+		}
+	}
+
+	return s
+}
+
+func contains(ids []string, id string) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package scheduler_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+	"github.com/open-ness/edgecontroller/scheduler"
+)
+
+const (
+	appID      = "9d740ba5-6ba9-4e9b-a0fc-9ea5d84e1a4b"
+	otherAppID = "84c1f7b9-53e7-408e-9223-deab73befc54"
+)
+
+var _ = Describe("Schedule", func() {
+	var (
+		app        *cce.App
+		candidates []*scheduler.Candidate
+	)
+
+	candidate := func(id string, usedCores int) *scheduler.Candidate {
+		return &scheduler.Candidate{
+			NodeID:      id,
+			Labels:      map[string]string{"region": "east"},
+			Features:    map[string]string{},
+			Enrolled:    true,
+			Status:      cce.NodeStateOnline,
+			Allocatable: &cce.Resources{Cores: 8, Memory: 8192},
+			Committed:   cce.Resources{Cores: usedCores, Memory: usedCores * 1024},
+		}
+	}
+
+	BeforeEach(func() {
+		app = &cce.App{ID: appID, Cores: 2, Memory: 2048}
+		candidates = []*scheduler.Candidate{
+			candidate("node-a", 4),
+			candidate("node-b", 0),
+			candidate("node-c", 2),
+		}
+	})
+
+	It("Should spread apps to the node with the most free resources", func() {
+		d, err := scheduler.Schedule(app, candidates, scheduler.Request{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(d.Selected).To(Equal([]scheduler.Placement{{NodeID: "node-b", Score: 75}}))
+		Expect(d.Excluded).To(Equal([]scheduler.Exclusion{
+			{NodeID: "node-a", Reasons: []string{"scored 25, below the selected nodes"}},
+			{NodeID: "node-c", Reasons: []string{"scored 50, below the selected nodes"}},
+		}))
+	})
+
+	It("Should binpack apps to the node with the least free resources", func() {
+		d, err := scheduler.Schedule(app, candidates, scheduler.Request{
+			Strategy: scheduler.StrategyBinpack,
+			Replicas: 2,
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(d.Selected).To(Equal([]scheduler.Placement{
+			{NodeID: "node-a", Score: 75},
+			{NodeID: "node-c", Score: 50},
+		}))
+	})
+
+	It("Should prefer nodes running affinity apps and avoid anti-affinity apps", func() {
+		candidates[0].Apps = []string{otherAppID}
+
+		d, err := scheduler.Schedule(app, candidates, scheduler.Request{Affinity: []string{otherAppID}})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(d.Selected[0].NodeID).To(Equal("node-a"))
+
+		d, err = scheduler.Schedule(app, candidates, scheduler.Request{
+			Strategy:     scheduler.StrategyBinpack,
+			AntiAffinity: []string{otherAppID},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(d.Selected[0].NodeID).To(Equal("node-c"))
+	})
+
+	It("Should explain why nodes the app does not fit are excluded", func() {
+		app.EPAFeatures = []cce.EPAFeature{{Key: "nfd:cpu-cpuid.AVX512F", Value: "true"}}
+		candidates[0].Features["cpu-cpuid.AVX512F"] = "true"
+		candidates[0].Committed.Cores = 7
+		candidates[1].Enrolled = false
+		candidates[1].Status = cce.NodeStateOffline
+		candidates[1].Features["cpu-cpuid.AVX512F"] = "true"
+		candidates[2].Labels["region"] = "west"
+		candidates[2].Features["cpu-cpuid.AVX512F"] = "true"
+		candidates[2].Apps = []string{appID}
+
+		d, err := scheduler.Schedule(app, candidates, scheduler.Request{
+			NodeSelector: map[string]string{"region": "east"},
+		})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(d.Selected).To(BeEmpty())
+		Expect(d.Excluded).To(Equal([]scheduler.Exclusion{
+			{NodeID: "node-a", Reasons: []string{
+				"insufficient cores: 2 required, 1 free",
+			}},
+			{NodeID: "node-b", Reasons: []string{
+				"node has not enrolled",
+				"node is offline",
+			}},
+			{NodeID: "node-c", Reasons: []string{
+				"app is already deployed to the node",
+				"label region=east required, node has region=west",
+			}},
+		}))
+	})
+
+	It("Should not check the capacity of nodes with unknown resources", func() {
+		candidates[0].Allocatable = nil
+		candidates[0].Committed = cce.Resources{Cores: 64, Memory: 65536}
+
+		d, err := scheduler.Schedule(app, candidates[:1], scheduler.Request{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(d.Selected).To(Equal([]scheduler.Placement{{NodeID: "node-a", Score: 50}}))
+	})
+
+	It("Should exclude nodes missing EPA features", func() {
+		app.EPAFeatures = []cce.EPAFeature{{Key: "nfd:cpu-cpuid.AVX512F", Value: "true"}}
+
+		d, err := scheduler.Schedule(app, candidates[:1], scheduler.Request{})
+		Expect(err).ToNot(HaveOccurred())
+		Expect(d.Excluded).To(Equal([]scheduler.Exclusion{
+			{NodeID: "node-a", Reasons: []string{
+				"Missing EPA Feature: [cpu-cpuid.AVX512F] required by app",
+			}},
+		}))
+	})
+
+	It("Should return an error for an invalid request", func() {
+		_, err := scheduler.Schedule(app, candidates, scheduler.Request{Strategy: "random"})
+		Expect(err).To(MatchError("strategy must be one of [spread, binpack]"))
+
+		_, err = scheduler.Schedule(app, candidates, scheduler.Request{Replicas: -1})
+		Expect(err).To(MatchError("replicas cannot be negative"))
+	})
+})
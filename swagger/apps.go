@@ -25,6 +25,27 @@ type AppDetail struct {
 	Ports       []cce.PortProto  `json:"ports"`
 	Source      string           `json:"source"`
 	EPAFeatures []cce.EPAFeature `json:"epafeatures,omitempty"`
+	Config      *cce.AppConfig   `json:"config,omitempty"`
+	Probes      *cce.AppProbes   `json:"probes,omitempty"`
+	// RestartPolicy is one of always (the default), on_failure or never.
+	// Only always is supported in the Kubernetes modes.
+	RestartPolicy string `json:"restart_policy,omitempty"`
+	// DiskSize is the size in GB of the persistent volume of a VM app.
+	DiskSize int `json:"disk_size,omitempty"`
+	// Digest is the digest of the image, as sha256:<hex>.
+	Digest string `json:"digest,omitempty"`
+	// Signature is a base64-encoded detached signature of the image by the
+	// publisher.
+	Signature   []byte `json:"signature,omitempty"`
+	PublisherID string `json:"publisher_id,omitempty"`
+}
+
+// AppVerification is the result of verifying the image of an app.
+type AppVerification struct {
+	ID     string `json:"id"`
+	Digest string `json:"digest"`
+	// Signed tells whether the signature of the publisher was verified too.
+	Signed bool `json:"signed"`
 }
 
 // AppList is a list representation of apps.
@@ -0,0 +1,35 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+import "time"
+
+// RevokedCredentials is the certificate of a node that was revoked.
+type RevokedCredentials struct {
+	NodeID    string    `json:"node_id"`
+	Serial    string    `json:"serial"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
+
+// NodeCredentials describes the current certificate of a node.
+type NodeCredentials struct {
+	NodeID    string    `json:"node_id"`
+	Serial    string    `json:"serial"`
+	NotBefore time.Time `json:"not_before"`
+	NotAfter  time.Time `json:"not_after"`
+	Revoked   bool      `json:"revoked"`
+}
+
+// NodeCredentialsList is a list representation of node credentials.
+type NodeCredentialsList struct {
+	Credentials []NodeCredentials `json:"credentials"`
+}
+
+// EnrollmentToken is a single-use token a node enrolls with. The token is
+// only returned when it is created.
+type EnrollmentToken struct {
+	NodeID    string    `json:"node_id"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,20 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+import "time"
+
+// Event is a notable change in the state of a node.
+type Event struct {
+	ID      string    `json:"id"`
+	NodeID  string    `json:"node_id"`
+	Type    string    `json:"type"`
+	Message string    `json:"message"`
+	Time    time.Time `json:"time"`
+}
+
+// EventList is a list representation of events.
+type EventList struct {
+	Events []Event `json:"events"`
+}
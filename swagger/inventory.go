@@ -0,0 +1,27 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+import "time"
+
+// NodeInventory is the cached inventory of a node.
+type NodeInventory struct {
+	NodeID      string             `json:"node_id"`
+	CollectedAt time.Time          `json:"collected_at"`
+	Interfaces  []InterfaceSummary `json:"interfaces"`
+	// Features are the NFD labels of the node.
+	Features map[string]string `json:"features"`
+	// Apps are the IDs of the apps deployed to the node.
+	Apps []string `json:"apps"`
+	// History lists the changes of the interfaces, most recent first.
+	History []InterfaceChange `json:"history"`
+}
+
+// InterfaceChange is a change of a node's interfaces between two collections.
+type InterfaceChange struct {
+	At      time.Time          `json:"at"`
+	Added   []InterfaceSummary `json:"added,omitempty"`
+	Removed []InterfaceSummary `json:"removed,omitempty"`
+	Changed []InterfaceSummary `json:"changed,omitempty"`
+}
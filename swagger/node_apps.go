@@ -3,16 +3,78 @@
 
 package swagger
 
+import (
+	"time"
+
+	cce "github.com/open-ness/edgecontroller"
+)
+
 // NodeAppSummary is a summary representation of the node app.
 type NodeAppSummary struct {
 	ID string `json:"id"`
 }
 
+// NodeAppRequest deploys an app to a node.
+type NodeAppRequest struct {
+	ID string `json:"id"`
+	// Config overrides the runtime configuration of the app on the node.
+	Config *cce.AppConfig `json:"config,omitempty"`
+	// Namespace is the Kubernetes namespace of the tenant or project the app
+	// is deployed for, created on demand.
+	Namespace string `json:"namespace,omitempty"`
+	// Replicas is the number of pods the app runs in Kubernetes mode, 1 by
+	// default.
+	Replicas int `json:"replicas,omitempty"`
+	// Networks attaches the app to networks besides the default pod network
+	// in Kubernetes mode.
+	Networks []cce.NetworkAttachment `json:"networks,omitempty"`
+	// Service exposes the ports of the app at a stable address in Kubernetes
+	// mode.
+	Service *cce.NodeAppService `json:"service,omitempty"`
+}
+
 // NodeAppDetail is a detailed representation of the node app.
 type NodeAppDetail struct {
 	NodeAppSummary
-	Status  string `json:"status"`
-	Command string `json:"command"`
+	Status string `json:"status"`
+	// Readiness tells whether the app passes its readiness probe: ready,
+	// not_ready or unknown.
+	Readiness string `json:"readiness,omitempty"`
+	Command   string `json:"command"`
+	// Version is the version to upgrade the app to with the upgrade command.
+	Version string `json:"version,omitempty"`
+	// Config overrides the runtime configuration of the app on the node.
+	Config    *cce.AppConfig `json:"config,omitempty"`
+	Namespace string         `json:"namespace,omitempty"`
+	// Replicas is the number of pods to scale the app to with the scale
+	// command.
+	Replicas int `json:"replicas,omitempty"`
+	// Networks attaches the app to networks besides the default pod network.
+	Networks []cce.NetworkAttachment `json:"networks,omitempty"`
+	// NetworkStatus lists the interfaces of the app in Kubernetes mode, with
+	// their assigned addresses.
+	NetworkStatus []cce.NetworkStatus `json:"network_status,omitempty"`
+	Service       *cce.NodeAppService `json:"service,omitempty"`
+	// Endpoints lists the addresses at which the service of the app exposes
+	// its ports.
+	Endpoints []cce.AppEndpoint `json:"endpoints,omitempty"`
+}
+
+// NodeAppHistory lists the versions of an app deployed to a node, most
+// recent first.
+type NodeAppHistory struct {
+	ID          string              `json:"id"`
+	Version     string              `json:"version"`
+	Deployments []NodeAppDeployment `json:"deployments"`
+}
+
+// NodeAppDeployment is a version of an app deployed to a node. Command is one
+// of deploy, upgrade or rollback.
+type NodeAppDeployment struct {
+	ID      string    `json:"id"`
+	Version string    `json:"version"`
+	Command string    `json:"command"`
+	At      time.Time `json:"at"`
 }
 
 // NodeAppList is a list representation of node apps.
@@ -3,20 +3,67 @@
 
 package swagger
 
+import (
+	"time"
+
+	cce "github.com/open-ness/edgecontroller"
+)
+
 // NodeSummary is a summary representation of the node.
 type NodeSummary struct {
 	ID       string `json:"id"`
 	Name     string `json:"name"`
 	Location string `json:"location"`
 	Serial   string `json:"serial"`
+	// SerialAlgorithm is the fingerprint algorithm of the serial, sha256 or
+	// the legacy md5.
+	SerialAlgorithm string     `json:"serial_algorithm,omitempty"`
+	Status          string     `json:"status,omitempty"`
+	LastSeen        *time.Time `json:"last_seen,omitempty"`
+	// Labels are used to select nodes when scheduling apps.
+	Labels map[string]string `json:"labels,omitempty"`
+	// Allocatable are the resources of the node apps can use, as set by the
+	// operator or reported by NFD. It is omitted if they are unknown.
+	Allocatable *cce.Resources `json:"allocatable,omitempty"`
+	// Committed are the resources of the apps deployed to the node. It is
+	// ignored in requests.
+	Committed cce.Resources `json:"committed"`
 }
 
 // NodeDetail is a detailed representation of the node.
 type NodeDetail struct {
 	NodeSummary
+	// Latency is the round trip time (in ms) of the last answered probe.
+	Latency int64 `json:"latency,omitempty"`
+	// Address is the address the node enrolled from.
+	Address string `json:"address,omitempty"`
+	// PreviousAddresses lists the addresses the node had before, most
+	// recent first.
+	PreviousAddresses []NodeAddress `json:"previous_addresses,omitempty"`
+}
+
+// NodeAddress is an address a node had until it enrolled from another one.
+type NodeAddress struct {
+	Address string    `json:"address"`
+	Until   time.Time `json:"until"`
 }
 
 // NodeList is a list representation of nodes.
 type NodeList struct {
 	Nodes []NodeSummary `json:"nodes"`
 }
+
+// NodeDecommission reports the progress of a node decommission.
+type NodeDecommission struct {
+	NodeID    string             `json:"node_id"`
+	Completed bool               `json:"completed"`
+	Steps     []DecommissionStep `json:"steps"`
+}
+
+// DecommissionStep is a step of a node decommission. Status is one of
+// pending, done, skipped or failed.
+type DecommissionStep struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
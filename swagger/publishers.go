@@ -0,0 +1,22 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+// PublisherSummary is a summary representation of the trusted publisher.
+type PublisherSummary struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// PublisherDetail is a detailed representation of the trusted publisher.
+type PublisherDetail struct {
+	PublisherSummary
+	// PublicKey is the PEM-encoded public key of the publisher.
+	PublicKey string `json:"public_key"`
+}
+
+// PublisherList is a list representation of trusted publishers.
+type PublisherList struct {
+	Publishers []PublisherSummary `json:"publishers"`
+}
@@ -0,0 +1,69 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+import "time"
+
+// RolloutRequest describes how to roll out a version of an app.
+type RolloutRequest struct {
+	// AppID is the version of the app to roll out.
+	AppID string `json:"app_id"`
+	// NodeIDs are the nodes to roll out to, by default the ones running
+	// another version of the app.
+	NodeIDs []string `json:"node_ids,omitempty"`
+	// Canary is the number of nodes of the first wave, none by default.
+	Canary int `json:"canary,omitempty"`
+	// BatchSize is the number of nodes of the following waves, one by
+	// default.
+	BatchSize int `json:"batch_size,omitempty"`
+	// FailureThreshold is the percentage of failed nodes a wave tolerates,
+	// none by default.
+	FailureThreshold int `json:"failure_threshold,omitempty"`
+	// OnFailure is pause (the default) or rollback.
+	OnFailure string `json:"on_failure,omitempty"`
+	// VerifyTimeout is the time in seconds a node app has to reach the
+	// running status, 300 by default.
+	VerifyTimeout int `json:"verify_timeout,omitempty"`
+}
+
+// RolloutSummary is a summary representation of the rollout.
+type RolloutSummary struct {
+	ID    string `json:"id"`
+	AppID string `json:"app_id"`
+	State string `json:"state"`
+	// Wave is the index of the wave in progress out of Waves.
+	Wave  int `json:"wave"`
+	Waves int `json:"waves"`
+}
+
+// RolloutDetail is a detailed representation of the rollout.
+type RolloutDetail struct {
+	RolloutSummary
+	Canary           int             `json:"canary"`
+	BatchSize        int             `json:"batch_size"`
+	FailureThreshold int             `json:"failure_threshold"`
+	OnFailure        string          `json:"on_failure"`
+	VerifyTimeout    int             `json:"verify_timeout"`
+	Reason           string          `json:"reason,omitempty"`
+	Targets          []RolloutTarget `json:"targets"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// RolloutTarget is a node a rollout deploys to. Command is deploy or upgrade
+// once the version is deployed to the node.
+type RolloutTarget struct {
+	NodeID    string     `json:"node_id"`
+	Wave      int        `json:"wave"`
+	FromAppID string     `json:"from_app_id,omitempty"`
+	Status    string     `json:"status"`
+	Command   string     `json:"command,omitempty"`
+	StartedAt *time.Time `json:"started_at,omitempty"`
+	Error     string     `json:"error,omitempty"`
+}
+
+// RolloutList is a list representation of rollouts.
+type RolloutList struct {
+	Rollouts []RolloutSummary `json:"rollouts"`
+}
@@ -0,0 +1,41 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+// ScheduleRequest describes how to place an app on nodes.
+type ScheduleRequest struct {
+	// Replicas is the number of nodes to deploy the app to, one by default.
+	Replicas int `json:"replicas,omitempty"`
+	// Strategy is spread (the default) or binpack.
+	Strategy     string            `json:"strategy,omitempty"`
+	NodeSelector map[string]string `json:"node_selector,omitempty"`
+	// Affinity lists the IDs of apps the nodes should already run.
+	Affinity []string `json:"affinity,omitempty"`
+	// AntiAffinity lists the IDs of apps the nodes should not run.
+	AntiAffinity []string `json:"anti_affinity,omitempty"`
+	// DryRun only reports the nodes that would be chosen.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ScheduleResult reports the nodes an app was scheduled to and why the other
+// nodes were excluded.
+type ScheduleResult struct {
+	AppID     string          `json:"app_id"`
+	Scheduled []ScheduledNode `json:"scheduled"`
+	Excluded  []ExcludedNode  `json:"excluded"`
+}
+
+// ScheduledNode is a node chosen for an app. Error is set if the deployment
+// to the node failed.
+type ScheduledNode struct {
+	NodeID string `json:"node_id"`
+	Score  int    `json:"score"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ExcludedNode is a node not chosen for an app.
+type ExcludedNode struct {
+	NodeID  string   `json:"node_id"`
+	Reasons []string `json:"reasons"`
+}
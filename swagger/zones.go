@@ -0,0 +1,23 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package swagger
+
+// ZoneSummary is a summary representation of the network zone.
+type ZoneSummary struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// ZoneDetail is a detailed representation of the network zone.
+type ZoneDetail struct {
+	ZoneSummary
+	// Nodes are the IDs of the nodes the zone is assigned to.
+	Nodes []string `json:"nodes"`
+}
+
+// ZoneList is a list representation of network zones.
+type ZoneList struct {
+	Zones []ZoneSummary `json:"zones"`
+}
@@ -0,0 +1,66 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/open-ness/edgecontroller/uuid"
+)
+
+// Zone is a network zone. It is defined once and can be assigned to several
+// nodes, whose network interfaces may then reference it by ID.
+type Zone struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// GetTableName returns the name of the persistence table.
+func (*Zone) GetTableName() string {
+	return "zones"
+}
+
+// GetID gets the ID.
+func (z *Zone) GetID() string {
+	return z.ID
+}
+
+// SetID sets the ID.
+func (z *Zone) SetID(id string) {
+	z.ID = id
+}
+
+// Validate validates the model.
+func (z *Zone) Validate() error {
+	if !uuid.IsValid(z.ID) {
+		return errors.New("id not a valid uuid")
+	}
+	if z.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+
+	return nil
+}
+
+// FilterFields returns the filterable fields for this model.
+func (*Zone) FilterFields() []string {
+	return []string{
+		"name",
+	}
+}
+
+func (z *Zone) String() string {
+	return fmt.Sprintf(strings.TrimSpace(`
+Zone[
+    ID: %s
+    Name: %s
+    Description: %s
+]`),
+		z.ID,
+		z.Name,
+		z.Description)
+}
@@ -0,0 +1,90 @@
+// SPDX-License-Identifier: Apache-2.0
+// Copyright (c) 2020 Intel Corporation
+
+package cce_test
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	cce "github.com/open-ness/edgecontroller"
+)
+
+var _ = Describe("Entities: Zone", func() {
+	var (
+		zone *cce.Zone
+	)
+
+	BeforeEach(func() {
+		zone = &cce.Zone{
+			ID:          "9d740ba5-6ba9-4e9b-a0fc-9ea5d84e1a4b",
+			Name:        "edge",
+			Description: "edge network zone",
+		}
+	})
+
+	Describe("GetTableName", func() {
+		It(`Should return "zones"`, func() {
+			Expect(zone.GetTableName()).To(Equal("zones"))
+		})
+	})
+
+	Describe("GetID", func() {
+		It("Should return the ID", func() {
+			Expect(zone.GetID()).To(Equal(
+				"9d740ba5-6ba9-4e9b-a0fc-9ea5d84e1a4b"))
+		})
+	})
+
+	Describe("SetID", func() {
+		It("Should set and return the updated ID", func() {
+			By("Setting the ID")
+			zone.SetID("456")
+
+			By("Getting the updated ID")
+			Expect(zone.ID).To(Equal("456"))
+		})
+	})
+
+	Describe("Validate", func() {
+		It("Should not return an error for a valid zone", func() {
+			Expect(zone.Validate()).To(Succeed())
+		})
+
+		It("Should not require a description", func() {
+			zone.Description = ""
+			Expect(zone.Validate()).To(Succeed())
+		})
+
+		It("Should return an error if ID is not a UUID", func() {
+			zone.ID = "123"
+			Expect(zone.Validate()).To(MatchError("id not a valid uuid"))
+		})
+
+		It("Should return an error if Name is empty", func() {
+			zone.Name = ""
+			Expect(zone.Validate()).To(MatchError("name cannot be empty"))
+		})
+	})
+
+	Describe("FilterFields", func() {
+		It("Should return the filterable fields", func() {
+			Expect(zone.FilterFields()).To(Equal([]string{
+				"name",
+			}))
+		})
+	})
+
+	Describe("String", func() {
+		It("Should return the string value", func() {
+			Expect(zone.String()).To(Equal(strings.TrimSpace(`
+Zone[
+    ID: 9d740ba5-6ba9-4e9b-a0fc-9ea5d84e1a4b
+    Name: edge
+    Description: edge network zone
+]`,
+			)))
+		})
+	})
+})